@@ -8,6 +8,7 @@ import (
 	"os"
 	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -141,7 +142,7 @@ func runDownMigrations(ctx context.Context, conn *pgx.Conn, migrationsPath, migr
 	// For DOWN migrations, we need to reverse the order and only apply those that have been applied
 	// Sort in reverse order for rollback
 	sort.Slice(migrationFiles, func(i, j int) bool {
-		return migrationFiles[i].Name > migrationFiles[j].Name
+		return migrationLess(migrationFiles[j].Name, migrationFiles[i].Name)
 	})
 
 	count := 0
@@ -269,14 +270,37 @@ func getMigrationFiles(path, direction string) ([]Migration, error) {
 		})
 	}
 
-	// Sort files by name to ensure they're applied in the right order
+	// Sort files by version to ensure they're applied in the right order
 	sort.Slice(files, func(i, j int) bool {
-		return files[i].Name < files[j].Name
+		return migrationLess(files[i].Name, files[j].Name)
 	})
 
 	return files, nil
 }
 
+// migrationLess orders migrations by the number their name starts with,
+// so 10_x comes after 9_x, names without a number go last by name
+func migrationLess(a, b string) bool {
+	va, okA := migrationVersion(a)
+	vb, okB := migrationVersion(b)
+
+	switch {
+	case okA && okB && va != vb:
+		return va < vb
+	case okA != okB:
+		return okA
+	}
+
+	return a < b
+}
+
+// migrationVersion parses the number before the first underscore of the name
+func migrationVersion(name string) (int, bool) {
+	prefix, _, _ := strings.Cut(name, "_")
+	version, err := strconv.Atoi(prefix)
+	return version, err == nil
+}
+
 // getAppliedMigrations returns a list of already applied migrations
 func getAppliedMigrations(ctx context.Context, conn *pgx.Conn, tableName string) ([]string, error) {
 	rows, err := conn.Query(ctx, fmt.Sprintf("SELECT version FROM %s ORDER BY version", tableName))
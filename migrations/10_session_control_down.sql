@@ -0,0 +1,5 @@
+ALTER TABLE participants DROP COLUMN IF EXISTS kicked_at;
+
+ALTER TABLE game_sessions
+    DROP COLUMN IF EXISTS paused_at,
+    DROP COLUMN IF EXISTS question_started_at;
@@ -0,0 +1,10 @@
+-- Description:
+-- Question timer and kicked participants of the live session monitor.
+-- The timer of a paused question stops at paused_at, resuming moves
+-- question_started_at forward by the pause
+
+ALTER TABLE game_sessions
+    ADD COLUMN question_started_at TIMESTAMPTZ,
+    ADD COLUMN paused_at TIMESTAMPTZ;
+
+ALTER TABLE participants ADD COLUMN kicked_at TIMESTAMPTZ;
@@ -0,0 +1,5 @@
+-- Description:
+-- Password hashes of users signing in to the admin panel without Telegram,
+-- NULL for users who never set a password
+
+ALTER TABLE users ADD COLUMN password_hash VARCHAR(72);
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_users_telegram_user_id;
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS telegram_user_id,
+    DROP COLUMN IF EXISTS telegram_chat_id,
+    DROP COLUMN IF EXISTS telegram_username;
@@ -0,0 +1,11 @@
+-- Description:
+-- Telegram account linked to a user. Players of the Telegram Web App are
+-- resolved to their BSU login by the account id, the bot messages the chat.
+-- Telegram usernames are at most 32 characters
+
+ALTER TABLE users
+    ADD COLUMN telegram_user_id BIGINT,
+    ADD COLUMN telegram_chat_id BIGINT,
+    ADD COLUMN telegram_username VARCHAR(32);
+
+CREATE UNIQUE INDEX idx_users_telegram_user_id ON users(telegram_user_id);
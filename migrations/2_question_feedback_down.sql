@@ -0,0 +1,3 @@
+ALTER TABLE game_sessions DROP COLUMN IF EXISTS exam_mode;
+ALTER TABLE options DROP COLUMN IF EXISTS feedback;
+ALTER TABLE questions DROP COLUMN IF EXISTS explanation;
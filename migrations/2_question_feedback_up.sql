@@ -0,0 +1,13 @@
+-- Description:
+-- Explanations for questions and feedback for options,
+-- shown to participants once a question closes
+
+ALTER TABLE questions
+    ADD COLUMN explanation TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE options
+    ADD COLUMN feedback TEXT NOT NULL DEFAULT '';
+
+-- Exam mode hides explanations and feedback until the session is finished
+ALTER TABLE game_sessions
+    ADD COLUMN exam_mode BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_quiz_collaborators_user;
+DROP TABLE IF EXISTS quiz_collaborators;
@@ -0,0 +1,18 @@
+-- Description:
+-- Quiz collaborators. The quiz creator (quizzes.user_id) is always an owner,
+-- other teachers are invited by login with one of the roles:
+-- owner  - edits the quiz and manages collaborators
+-- editor - edits the quiz and hosts sessions
+-- viewer - views the quiz and results of its sessions
+
+CREATE TABLE quiz_collaborators (
+    quiz_id UUID NOT NULL REFERENCES quizzes(id) ON DELETE CASCADE,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role VARCHAR(16) NOT NULL CHECK (role IN ('owner', 'editor', 'viewer')),
+    invited_by BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (quiz_id, user_id)
+);
+
+CREATE INDEX idx_quiz_collaborators_user ON quiz_collaborators(user_id);
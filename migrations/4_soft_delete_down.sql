@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS idx_game_sessions_deleted_at;
+DROP INDEX IF EXISTS idx_quizzes_deleted_at;
+
+ALTER TABLE game_sessions
+    DROP COLUMN IF EXISTS deleted_by,
+    DROP COLUMN IF EXISTS deleted_at;
+
+ALTER TABLE quizzes
+    DROP COLUMN IF EXISTS deleted_by,
+    DROP COLUMN IF EXISTS deleted_at;
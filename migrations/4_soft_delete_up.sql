@@ -0,0 +1,14 @@
+-- Description:
+-- Soft delete for quizzes and game sessions. Deleted rows stay in the trash
+-- for the retention window and are purged by a background job afterwards
+
+ALTER TABLE quizzes
+    ADD COLUMN deleted_at TIMESTAMPTZ,
+    ADD COLUMN deleted_by BIGINT REFERENCES users(id) ON DELETE SET NULL;
+
+ALTER TABLE game_sessions
+    ADD COLUMN deleted_at TIMESTAMPTZ,
+    ADD COLUMN deleted_by BIGINT REFERENCES users(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_quizzes_deleted_at ON quizzes(deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX idx_game_sessions_deleted_at ON game_sessions(deleted_at) WHERE deleted_at IS NOT NULL;
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_quizzes_publish_at;
+DROP INDEX IF EXISTS idx_quizzes_status;
+
+ALTER TABLE quizzes
+    DROP COLUMN IF EXISTS published_at,
+    DROP COLUMN IF EXISTS publish_at,
+    DROP COLUMN IF EXISTS status;
@@ -0,0 +1,15 @@
+-- Description:
+-- Publishing lifecycle of quizzes: draft, scheduled, published and archived.
+-- Scheduled quizzes are published at publish_at by a background worker
+
+ALTER TABLE quizzes
+    ADD COLUMN status VARCHAR(16) NOT NULL DEFAULT 'draft'
+        CHECK (status IN ('draft', 'scheduled', 'published', 'archived')),
+    ADD COLUMN publish_at TIMESTAMPTZ,
+    ADD COLUMN published_at TIMESTAMPTZ;
+
+-- Existing quizzes are already in use, keep them live
+UPDATE quizzes SET status = 'published', published_at = created_at;
+
+CREATE INDEX idx_quizzes_status ON quizzes(status);
+CREATE INDEX idx_quizzes_publish_at ON quizzes(publish_at) WHERE status = 'scheduled';
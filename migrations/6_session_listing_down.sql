@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_game_sessions_quiz;
+DROP INDEX IF EXISTS idx_game_sessions_started_at_id;
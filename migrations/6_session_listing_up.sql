@@ -0,0 +1,8 @@
+-- Description:
+-- Keyset pagination of the admin session listing over (started_at, id)
+
+CREATE INDEX idx_game_sessions_started_at_id
+    ON game_sessions(started_at DESC NULLS FIRST, id DESC)
+    WHERE deleted_at IS NULL;
+
+CREATE INDEX idx_game_sessions_quiz ON game_sessions(quiz_id);
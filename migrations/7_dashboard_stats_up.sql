@@ -0,0 +1,4 @@
+-- Description:
+-- Per day time series on the admin dashboard
+
+CREATE INDEX idx_answers_answered_at ON answers(answered_at);
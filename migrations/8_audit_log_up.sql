@@ -0,0 +1,19 @@
+-- Description:
+-- Audit log of administrative and ownership actions
+
+CREATE TABLE audit_log (
+    id BIGINT PRIMARY KEY GENERATED BY DEFAULT AS IDENTITY,
+    actor_id BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    action VARCHAR(64) NOT NULL,
+    target_type VARCHAR(32) NOT NULL,
+    target_id VARCHAR(64) NOT NULL,
+    diff JSONB, -- {"field": {"before": ..., "after": ...}}
+    ip VARCHAR(45) NOT NULL DEFAULT '',
+    user_agent TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_audit_log_created_at ON audit_log(created_at DESC, id DESC);
+CREATE INDEX idx_audit_log_actor ON audit_log(actor_id);
+CREATE INDEX idx_audit_log_target ON audit_log(target_type, target_id);
+CREATE INDEX idx_audit_log_action ON audit_log(action);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_users_academic_group;
+
+ALTER TABLE users DROP COLUMN IF EXISTS academic_group;
@@ -0,0 +1,6 @@
+-- Description:
+-- Academic group of students, filled by the bulk import
+
+ALTER TABLE users ADD COLUMN academic_group VARCHAR(32) NOT NULL DEFAULT '';
+
+CREATE INDEX idx_users_academic_group ON users(academic_group);
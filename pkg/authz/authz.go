@@ -0,0 +1,93 @@
+// Package authz is the permission model shared by the quiz services and the
+// Telegram bot: users hold role flags, each role grants named permissions
+package authz
+
+import "slices"
+
+// Role flags, stored in users.role_flags
+const (
+	RoleUser    = 1 << iota // 1 (0001) - Regular User
+	RoleAdmin               // 2 (0010) - Administrator
+	RoleTeacher             // 4 (0100) - Teacher
+	RoleBlocked             // 8 (1000) - Blocked User, has no permissions
+)
+
+// Permission is something a role allows to do, named resource.action
+type Permission string
+
+const (
+	// QuizCreate allows creating and importing quizzes
+	QuizCreate Permission = "quiz.create"
+	// QuizModerate allows viewing, editing and deleting quizzes of others
+	QuizModerate Permission = "quiz.moderate"
+	// SessionHost allows starting sessions of quizzes one may host
+	SessionHost Permission = "session.host"
+	// SessionJoin allows playing in sessions
+	SessionJoin Permission = "session.join"
+	// SessionModerate allows viewing and controlling sessions of others
+	SessionModerate Permission = "session.moderate"
+	// UserManage allows creating, editing, blocking and deleting users
+	UserManage Permission = "user.manage"
+	// UserImpersonate allows viewing the panel as another user
+	UserImpersonate Permission = "user.impersonate"
+	// AuditView allows reading the audit log
+	AuditView Permission = "audit.view"
+)
+
+// rolePermissions maps each role to the permissions it grants,
+// a user has the permissions of all their roles
+var rolePermissions = map[int][]Permission{
+	RoleUser: {
+		SessionJoin,
+	},
+	RoleTeacher: {
+		QuizCreate,
+		SessionHost,
+		SessionJoin,
+	},
+	RoleAdmin: {
+		QuizCreate,
+		QuizModerate,
+		SessionHost,
+		SessionJoin,
+		SessionModerate,
+		UserManage,
+		UserImpersonate,
+		AuditView,
+	},
+}
+
+// roles are the roles granting permissions in flag order
+var roles = []int{RoleUser, RoleAdmin, RoleTeacher}
+
+// Can reports whether the role flags grant the permission,
+// blocked users can do nothing whatever their other roles
+func Can(roleFlags int, permission Permission) bool {
+	return slices.Contains(Permissions(roleFlags), permission)
+}
+
+// Permissions returns the permissions the role flags grant without duplicates,
+// blocked users have none
+func Permissions(roleFlags int) []Permission {
+	if roleFlags&RoleBlocked != 0 {
+		return nil
+	}
+
+	var permissions []Permission
+	seen := make(map[Permission]bool)
+
+	for _, role := range roles {
+		if roleFlags&role == 0 {
+			continue
+		}
+
+		for _, permission := range rolePermissions[role] {
+			if !seen[permission] {
+				seen[permission] = true
+				permissions = append(permissions, permission)
+			}
+		}
+	}
+
+	return permissions
+}
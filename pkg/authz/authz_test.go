@@ -0,0 +1,92 @@
+package authz
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestPermissions(t *testing.T) {
+	tests := []struct {
+		name      string
+		roleFlags int
+		want      []Permission
+	}{
+		{name: "no roles", roleFlags: 0},
+		{name: "user", roleFlags: RoleUser, want: []Permission{SessionJoin}},
+		{
+			name:      "teacher",
+			roleFlags: RoleUser | RoleTeacher,
+			want:      []Permission{SessionJoin, QuizCreate, SessionHost},
+		},
+		{
+			name:      "admin",
+			roleFlags: RoleUser | RoleAdmin,
+			want: []Permission{
+				SessionJoin, QuizCreate, QuizModerate, SessionHost,
+				SessionModerate, UserManage, UserImpersonate, AuditView,
+			},
+		},
+		{
+			name:      "admin and teacher without duplicates",
+			roleFlags: RoleAdmin | RoleTeacher,
+			want: []Permission{
+				QuizCreate, QuizModerate, SessionHost, SessionJoin,
+				SessionModerate, UserManage, UserImpersonate, AuditView,
+			},
+		},
+		{name: "blocked", roleFlags: RoleUser | RoleAdmin | RoleTeacher | RoleBlocked},
+		{name: "unknown flag", roleFlags: 1 << 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Permissions(tt.roleFlags)
+
+			// Permissions come in role flag order, each role in its own order
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCan(t *testing.T) {
+	tests := []struct {
+		name       string
+		roleFlags  int
+		permission Permission
+		want       bool
+	}{
+		{name: "student joins", roleFlags: RoleUser, permission: SessionJoin, want: true},
+		{name: "student hosts", roleFlags: RoleUser, permission: SessionHost},
+		{name: "teacher hosts", roleFlags: RoleUser | RoleTeacher, permission: SessionHost, want: true},
+		{name: "teacher moderates", roleFlags: RoleUser | RoleTeacher, permission: QuizModerate},
+		{name: "admin reads the audit log", roleFlags: RoleAdmin, permission: AuditView, want: true},
+		{name: "blocked admin", roleFlags: RoleAdmin | RoleBlocked, permission: AuditView},
+		{name: "blocked student joins", roleFlags: RoleUser | RoleBlocked, permission: SessionJoin},
+		{name: "unknown permission", roleFlags: RoleAdmin, permission: Permission("quiz.publish")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Can(tt.roleFlags, tt.permission); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// Every permission must be granted by some role, or nobody can use it
+func TestEveryPermissionIsGranted(t *testing.T) {
+	all := []Permission{
+		QuizCreate, QuizModerate, SessionHost, SessionJoin,
+		SessionModerate, UserManage, UserImpersonate, AuditView,
+	}
+
+	granted := Permissions(RoleUser | RoleAdmin | RoleTeacher)
+	for _, permission := range all {
+		if !slices.Contains(granted, permission) {
+			t.Errorf("no role grants %s", permission)
+		}
+	}
+}
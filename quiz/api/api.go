@@ -0,0 +1,10 @@
+// Package api embeds the API specifications, the admin panel validates
+// requests and responses of its JSON API against them at runtime
+package api
+
+import _ "embed"
+
+// AdminSpec is the OpenAPI document of the admin panel JSON API
+//
+//go:embed openapi/admin.yml
+var AdminSpec []byte
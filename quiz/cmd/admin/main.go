@@ -6,10 +6,8 @@ import (
 )
 
 func main() {
-	// NOTE: handle context in this place 
-	// or in the level above and give context with Timeout 
-	// for correct handle locking database or someone else strucure 
 	app := admin.NewAdminApp()
 
+	// Start serves until SIGINT or SIGTERM and shuts down gracefully
 	admin.Start(context.Background(), app)
 }
\ No newline at end of file
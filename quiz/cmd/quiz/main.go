@@ -1 +1,13 @@
-package main
\ No newline at end of file
+package main
+
+import (
+	"bsu-quiz/quiz/internal/app/quiz"
+	"context"
+)
+
+func main() {
+	app := quiz.NewQuizApp()
+
+	// Start serves until SIGINT or SIGTERM and shuts down gracefully
+	quiz.Start(context.Background(), app)
+}
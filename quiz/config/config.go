@@ -16,6 +16,12 @@ type Config struct {
 	EmailConfig      EmailConfig      `yaml:"email" env-required:"true"`
 	RedisConfig      RedisConfig      `yaml:"redis" env-required:"true"`
 	AdminPanelConfig AdminPanelConfig `yaml:"amdin_panel" env-required:"true"`
+	WebAppConfig     WebAppConfig     `yaml:"web_app"`
+	RenderConfig     RenderConfig     `yaml:"render"`
+	TrashConfig      TrashConfig      `yaml:"trash"`
+	PublishConfig    PublishConfig    `yaml:"publish"`
+	StatsConfig      StatsConfig      `yaml:"stats"`
+	TokenConfig      TokenConfig      `yaml:"tokens"`
 }
 
 type StorageConfig struct {
@@ -23,9 +29,18 @@ type StorageConfig struct {
 }
 
 type BotConfig struct {
-	Token   string `yaml:"token" env-required:"true"`
+	// Token is a secret, it's only read from the environment
+	Token   string `env:"BOT_TOKEN" env-required:"true"`
 	Timeout int    `yaml:"timeout" env-default:"60"`
 	Debug   bool   `yaml:"debug" env-default:"false"`
+	// InitDataMaxAge is how old the init data of the Web App may be, older
+	// data was replayed or the app has been open for too long
+	InitDataMaxAge time.Duration `yaml:"init_data_max_age" env-default:"1h"`
+	// Username of the bot, the admin panel offers the Login Widget of the
+	// bot when it's set. The panel's domain must be linked to the bot
+	Username string `yaml:"username"`
+	// LoginWidgetMaxAge is how old the data of the Login Widget may be
+	LoginWidgetMaxAge time.Duration `yaml:"login_widget_max_age" env-default:"10m"`
 }
 
 type EmailConfig struct {
@@ -42,15 +57,94 @@ type EmailConfig struct {
 }
 
 type RedisConfig struct {
-	Addr          string
-	Password      string
-	DB            int
-	KeyPrefix     string
-	DefaultExpiry time.Duration
+	Addr          string        `yaml:"addr" env-default:"localhost:6379"`
+	Password      string        `yaml:"password"`
+	DB            int           `yaml:"db"`
+	KeyPrefix     string        `yaml:"key_prefix"`
+	DefaultExpiry time.Duration `yaml:"default_expiry" env-default:"24h"`
 }
 
 type AdminPanelConfig struct {
-	Port int `yaml:"port" env-default:"8888"`
+	ServerConfig `yaml:",inline"`
+	// ImpersonationTTL is how long an admin may view the panel as another user
+	ImpersonationTTL time.Duration `yaml:"impersonation_ttl" env-default:"1h"`
+	// SessionTTL is how long a browser stays signed in to the panel
+	SessionTTL time.Duration `yaml:"session_ttl" env-default:"12h"`
+	// PasswordResetTTL is how long a password reset link works
+	PasswordResetTTL time.Duration `yaml:"password_reset_ttl" env-default:"30m"`
+	// RegistrationTTL is how long the link to finish signing up works
+	RegistrationTTL time.Duration `yaml:"registration_ttl" env-default:"24h"`
+	// PublicURL is where users reach the panel, links in emails point to it
+	PublicURL string `yaml:"public_url" env-default:"http://localhost:8888"`
+}
+
+// WebAppConfig holds settings of the quiz app, the server of the
+// Telegram Web App players open from the bot
+type WebAppConfig struct {
+	ServerConfig `yaml:",inline"`
+}
+
+// ServerConfig holds settings of an HTTP server
+type ServerConfig struct {
+	// Host is the interface to listen on, all interfaces when empty
+	Host         string        `yaml:"host"`
+	Port         int           `yaml:"port" env-default:"8888"`
+	ReadTimeout  time.Duration `yaml:"read_timeout" env-default:"10s"`
+	WriteTimeout time.Duration `yaml:"write_timeout" env-default:"30s"`
+	IdleTimeout  time.Duration `yaml:"idle_timeout" env-default:"60s"`
+	// ShutdownTimeout is how long in-flight requests may take to finish on shutdown
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" env-default:"15s"`
+}
+
+// RenderConfig holds settings of question text rendering
+type RenderConfig struct {
+	// FormulaImageURL is the public address of /formula.png of the quiz app, formula
+	// images are used by clients without MathML support such as the Telegram bot.
+	// Formulas get no images when it's empty
+	FormulaImageURL string `yaml:"formula_image_url"`
+	// FormulaCacheSize is how many drawn formula images are kept in memory
+	FormulaCacheSize int `yaml:"formula_cache_size" env-default:"1000"`
+	// FormulaRateLimit is how many formula images a client may fetch a minute
+	FormulaRateLimit int `yaml:"formula_rate_limit" env-default:"120"`
+}
+
+// TrashConfig holds settings of deleted quizzes and sessions
+type TrashConfig struct {
+	// RetentionDays is how long deleted items can be restored before they're purged
+	RetentionDays int `yaml:"retention_days" env-default:"30"`
+	// PurgeInterval is how often expired items are purged
+	PurgeInterval time.Duration `yaml:"purge_interval" env-default:"1h"`
+}
+
+// Retention returns the retention period as a duration
+func (c TrashConfig) Retention() time.Duration {
+	return time.Duration(c.RetentionDays) * 24 * time.Hour
+}
+
+// PublishConfig holds settings of scheduled quiz publishing
+type PublishConfig struct {
+	// CheckInterval is how often scheduled quizzes are checked
+	CheckInterval time.Duration `yaml:"check_interval" env-default:"1m"`
+}
+
+// StatsConfig holds settings of the admin dashboard statistics
+type StatsConfig struct {
+	// Days is the length of the per day time series
+	Days int `yaml:"days" env-default:"14"`
+	// CacheTTL is how long computed statistics are reused
+	CacheTTL time.Duration `yaml:"cache_ttl" env-default:"30s"`
+}
+
+// TokenConfig holds settings of the bearer tokens of the API
+type TokenConfig struct {
+	// Secret signs access tokens with HS256
+	Secret string `yaml:"secret" env:"JWT_SECRET" env-required:"true"`
+	Issuer string `yaml:"issuer" env-default:"bsu-quiz"`
+	// AccessTTL is how long an access token works, revoked ones stay on the
+	// revocation list as long
+	AccessTTL time.Duration `yaml:"access_ttl" env-default:"15m"`
+	// RefreshTTL is how long a client may refresh without signing in again
+	RefreshTTL time.Duration `yaml:"refresh_ttl" env-default:"720h"`
 }
 
 func MustLoad() *Config {
@@ -101,14 +195,6 @@ func fetchConfigPath() string {
 
 // TODO: integrate to logic
 
-type ServerConfig struct {
-	Host            string
-	Port            int
-	ReadTimeout     int
-	WriteTimeout    int
-	ShutdownTimeout int
-}
-
 // DatabaseConfig holds database configuration
 type DatabaseConfig struct {
 	Driver   string
@@ -1,23 +1,42 @@
 package admin
 
 import (
+	"bsu-quiz/quiz/api"
 	"bsu-quiz/quiz/config"
+	"bsu-quiz/quiz/internal/domain/rules"
+	"bsu-quiz/quiz/internal/infra/health"
+	"bsu-quiz/quiz/internal/infra/mail"
+	"bsu-quiz/quiz/internal/infra/markup"
+	"bsu-quiz/quiz/internal/infra/openapi"
 	"bsu-quiz/quiz/internal/infra/repository"
 	"bsu-quiz/quiz/internal/infra/service"
+	"bsu-quiz/quiz/internal/infra/worker"
 	"bsu-quiz/quiz/internal/interfaces/http/hanlders"
+	"bsu-quiz/quiz/internal/interfaces/http/middleware"
+	"bsu-quiz/quiz/web"
 	"context"
 	"log/slog"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
 )
 
 type AdminApp struct {
 	Config *config.Config
 	Conn   *pgxpool.Pool
+	Redis  *redis.Client
 	Router *gin.Engine
 	Log    *slog.Logger
+	Health *handlers.HealthHandler
+	// Monitor streams live sessions, its streams are closed on shutdown
+	Monitor *handlers.SessionMonitorHandler
+
+	// Background workers
+	TrashPurger   *worker.TrashPurger
+	QuizPublisher *worker.QuizPublisher
 }
 
 func NewAdminApp() *AdminApp{
@@ -26,97 +45,122 @@ func NewAdminApp() *AdminApp{
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 	db := newPgxConn(ctx, cfg.StorageConfig)
+	rdb := newRedisClient(ctx, cfg.RedisConfig)
 	
 	userRepo := repository.NewPgUserRepository(db)
 	quizRepo := repository.NewPgQuizRepository(db)
 	sessionRepo := repository.NewPgSessionRepository(db)
+	collabRepo := repository.NewPgCollaboratorRepository(db)
+	statsRepo := repository.NewPgStatsRepository(db)
+	auditRepo := repository.NewPgAuditRepository(db)
+	impersonationRepo := repository.NewRedisImpersonationRepository(rdb, cfg.RedisConfig.KeyPrefix)
+	webSessionRepo := repository.NewRedisWebSessionRepository(rdb, cfg.RedisConfig.KeyPrefix)
+	passwordResetRepo := repository.NewRedisPasswordResetRepository(rdb, cfg.RedisConfig.KeyPrefix)
+	registrationRepo := repository.NewRedisRegistrationRepository(rdb, cfg.RedisConfig.KeyPrefix)
+	tokenRepo := repository.NewRedisTokenRepository(rdb, cfg.RedisConfig.KeyPrefix)
+	tx := repository.NewPgTransactor(db)
 	
-	// Services
-	quizService := service.NewQuizService(quizRepo, userRepo)
-	sessionService := service.NewSessionService(sessionRepo, quizRepo, userRepo)
-	adminService := service.NewAdminService(userRepo, quizRepo, sessionRepo)
-	
-	// Handlers
-	quizHandler := handlers.NewQuizHandler(quizService)
-	adminHandler := handlers.NewAdminHandler(adminService, quizService, sessionService)
+	// Emails are embedded like the pages
+	emails, err := web.EmailTemplates()
+	if err != nil {
+		panic(err)
+	}
 	
-	// Initialize Gin
-	router := gin.Default()
+	// Authorization policy
+	policy := rules.NewPolicy(quizRepo, userRepo, collabRepo, sessionRepo)
 	
-	// Load templates
-	router.LoadHTMLGlob("web/templates/**/*")
+	// Services
+	auditService := service.NewAuditService(auditRepo)
+	authService := service.NewAuthService(
+		userRepo,
+		webSessionRepo,
+		tokenRepo,
+		passwordResetRepo,
+		registrationRepo,
+		mail.NewSender(cfg.EmailConfig, emails),
+		cfg.AdminPanelConfig.SessionTTL,
+		cfg.AdminPanelConfig.PasswordResetTTL,
+		cfg.AdminPanelConfig.RegistrationTTL,
+		strings.TrimSuffix(cfg.AdminPanelConfig.PublicURL, "/")+"/password/reset",
+		strings.TrimSuffix(cfg.AdminPanelConfig.PublicURL, "/")+"/register/confirm",
+	)
+	tokenService := service.NewTokenService(
+		authService,
+		userRepo,
+		tokenRepo,
+		cfg.TokenConfig.Secret,
+		cfg.TokenConfig.Issuer,
+		cfg.TokenConfig.AccessTTL,
+		cfg.TokenConfig.RefreshTTL,
+	)
+	quizService := service.NewQuizService(quizRepo, userRepo, collabRepo, policy, tx, auditService)
+	sessionService := service.NewSessionService(sessionRepo, quizRepo, userRepo, policy, tx, auditService)
+	adminService := service.NewAdminService(userRepo, quizRepo, sessionRepo, policy, tx, auditService)
+	renderService := service.NewRenderService(markup.NewRenderer(cfg.RenderConfig.FormulaImageURL), cfg.RenderConfig.FormulaCacheSize)
+	impersonationService := service.NewImpersonationService(impersonationRepo, userRepo, auditService, cfg.AdminPanelConfig.ImpersonationTTL)
+	trashService := service.NewTrashService(quizRepo, sessionRepo, userRepo, policy, cfg.TrashConfig.Retention())
+	pgChecker := health.NewPostgresChecker(db)
+	redisChecker := health.NewRedisChecker(rdb)
+	statsService := service.NewStatsService(
+		userRepo,
+		quizRepo,
+		sessionRepo,
+		statsRepo,
+		cfg.StatsConfig.Days,
+		cfg.StatsConfig.CacheTTL,
+		pgChecker,
+		redisChecker,
+	)
 	
-	// Static files
-	router.Static("/static", "./web/static")
+	// The API spec the JSON API is validated against
+	spec, err := openapi.Load(api.AdminSpec)
+	if err != nil {
+		panic(err)
+	}
 	
-	// Public routes
-	router.GET("/", func(c *gin.Context) {
-		c.Redirect(302, "/login")
+	router, err := newRouter(cfg, log, spec, adminServices{
+		auth:          authService,
+		token:         tokenService,
+		quiz:          quizService,
+		session:       sessionService,
+		admin:         adminService,
+		render:        renderService,
+		impersonation: impersonationService,
+		trash:         trashService,
+		stats:         statsService,
+		audit:         auditService,
+		checkers:      []health.Checker{pgChecker, redisChecker},
 	})
-	// router.POST("/login", authHandler.Login)
-	// router.GET("/register", authHandler.RegisterForm)
-	// router.POST("/register", authHandler.Register)
-	// router.GET("/logout", authHandler.Logout)
+	if err != nil {
+		panic(err)
+	}
 	
-	// Authenticated routes
-	authenticated := router.Group("/")
-	// authenticated.Use(middleware.AuthMiddleware(authService))
-	{
-		// User dashboard
-		authenticated.GET("/dashboard", func(c *gin.Context) {
-			username, _ := c.Get("username")
-			c.HTML(200, "dashboard.html", gin.H{
-				"Title":    "Dashboard",
-				"Username": username,
-				"CurrentTime": "2025-06-22 12:51:00", // Using provided timestamp
-			})
-		})
-		
-		// Quiz management
-		quizRoutes := authenticated.Group("/quizzes")
-		{
-			quizRoutes.GET("/", quizHandler.ListQuizzes)
-			quizRoutes.GET("/new", quizHandler.NewQuizForm)
-			quizRoutes.POST("/", quizHandler.CreateQuiz)
-			quizRoutes.GET("/:id/edit", quizHandler.EditQuizForm)
-			quizRoutes.PUT("/:id", quizHandler.UpdateQuiz)
-			quizRoutes.DELETE("/:id", quizHandler.DeleteQuiz)
-			
-			// Question management
-			quizRoutes.POST("/:id/questions", quizHandler.AddQuestion)
-			quizRoutes.PUT("/:id/questions/:questionId", quizHandler.UpdateQuestion)
-			quizRoutes.DELETE("/:id/questions/:questionId", quizHandler.DeleteQuestion)
-			
-			// Option management
-			quizRoutes.POST("/:id/questions/:questionId/options", quizHandler.AddOption)
-			quizRoutes.PUT("/:id/questions/:questionId/options/:optionId", quizHandler.UpdateOption)
-			quizRoutes.DELETE("/:id/questions/:questionId/options/:optionId", quizHandler.DeleteOption)
-		}
-		
-		// Admin routes
-		adminRoutes := authenticated.Group("/admin")
-		// adminRoutes.Use(middleware.RequireAdmin())
-		{
-			adminRoutes.GET("/dashboard", adminHandler.Dashboard)
-			
-			// User management
-			adminRoutes.GET("/users", adminHandler.Users)
-			adminRoutes.POST("/users/:id/role", adminHandler.UpdateUserRole)
-			
-			// Quiz management
-			adminRoutes.GET("/quizzes", adminHandler.Quizzes)
-			adminRoutes.DELETE("/quizzes/:id", adminHandler.DeleteQuiz)
-			
-			// Session management
-			adminRoutes.GET("/sessions", adminHandler.Sessions)
-			adminRoutes.POST("/sessions/:id/end", adminHandler.EndSession)
-		}
+	// Every API route must be described in the spec, TestRoutesMatchAPISpec
+	// fails the tests before it gets here
+	if err := middleware.CheckRoutes(spec, router.Routes()); err != nil {
+		panic(err)
 	}
 
 	return &AdminApp{
 		Config: cfg,
 		Conn:   db,
-		Router: router,
+		Redis:  rdb,
+		Router: router.Engine,
 		Log:    log,
+		Health: router.health,
+		Monitor: router.monitor,
+		
+		TrashPurger:   worker.NewTrashPurger(trashService, cfg.TrashConfig.PurgeInterval, log),
+		QuizPublisher: worker.NewQuizPublisher(quizService, cfg.PublishConfig.CheckInterval, log),
 	}
-}
\ No newline at end of file
+}
+
+// Close closes the Postgres pool and then Redis,
+// it must be called once the server and the workers are stopped
+func (app *AdminApp) Close() {
+	app.Conn.Close()
+
+	if err := app.Redis.Close(); err != nil {
+		app.Log.Error("failed to close redis", slog.String("error", err.Error()))
+	}
+}
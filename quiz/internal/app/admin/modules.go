@@ -4,11 +4,18 @@ import (
 	"bsu-quiz/quiz/config"
 	"bsu-quiz/quiz/internal/infra/logger/handlers/slogpretty"
 	"context"
-	"fmt"
+	"errors"
 	"log/slog"
+	"net"
+	"net/http"
 	"os"
+	"os/signal"
+	"strconv"
+	"sync"
+	"syscall"
 
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
 )
 
 const (
@@ -17,6 +24,10 @@ const (
 	envProd  = "prod"
 )
 
+// levelFatal logs errors the app can't run after, slog has no such level
+// https://betterstack.com/community/guides/logging/logging-in-go/
+const levelFatal = slog.Level(12)
+
 func newPgxConn(ctx context.Context, cfg config.StorageConfig) *pgxpool.Pool {
 	db, err := pgxpool.New(ctx, cfg.DatabaseUrl)
 	if err != nil {
@@ -27,21 +38,88 @@ func newPgxConn(ctx context.Context, cfg config.StorageConfig) *pgxpool.Pool {
 		db.Close()
 		panic(err)
 	}
-	defer db.Close()
 
 	return db
 }
 
+// newRedisClient connects to Redis. Signing in needs Redis like it needs
+// Postgres, so the panel doesn't start without it
+func newRedisClient(ctx context.Context, cfg config.RedisConfig) *redis.Client {
+	client := redis.NewClient(&redis.Options{
+		Addr:     cfg.Addr,
+		Password: cfg.Password,
+		DB:       cfg.DB,
+	})
+
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		panic(err)
+	}
+
+	return client
+}
+
+// Start serves the admin panel until ctx is cancelled or the process gets
+// SIGINT or SIGTERM. Then the readiness probe starts failing, requests in
+// flight get ShutdownTimeout to finish, workers stop and the connections
+// are closed
 func Start(ctx context.Context, app *AdminApp) {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Background workers
+	var workers sync.WaitGroup
+	for _, run := range []func(context.Context){app.TrashPurger.Run, app.QuizPublisher.Run} {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			run(ctx)
+		}()
+	}
+
+	cfg := app.Config.AdminPanelConfig
+	server := &http.Server{
+		Addr:         net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.Port)),
+		Handler:      app.Router,
+		ReadTimeout:  cfg.ReadTimeout,
+		WriteTimeout: cfg.WriteTimeout,
+		IdleTimeout:  cfg.IdleTimeout,
+	}
+
+	// Shutdown waits for requests in flight, monitor streams only end once closed
+	server.RegisterOnShutdown(app.Monitor.Close)
+
+	serverErr := make(chan error, 1)
+	go func() {
+		app.Log.Info("server starting", slog.String("addr", server.Addr))
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serverErr <- err
+		}
+	}()
+
+	failed := false
+	select {
+	case <-ctx.Done():
+		app.Log.Info("shutting down")
+	case err := <-serverErr:
+		app.Log.Log(ctx, levelFatal, "failed to start server", slog.String("error", err.Error()))
+		failed = true
+	}
+
+	app.Health.Drain()
+	stop()
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		app.Log.Error("failed to shut down server gracefully", slog.String("error", err.Error()))
+	}
+
+	workers.Wait()
+	app.Close()
 
-	port := app.Config.AdminPanelConfig.Port
-	
-	host := fmt.Sprintf(":%d", port)
-	app.Log.Info("Server starting on port %s", port)
-	if err := app.Router.Run(":" + host); err != nil {
-		// Add constan for fatal log level
-		// https://betterstack.com/community/guides/logging/logging-in-go/
-		app.Log.Log(ctx, slog.Level(12), "Failed to start server: %v", err)
+	if failed {
 		os.Exit(1)
 	}
 }
@@ -0,0 +1,274 @@
+package admin
+
+import (
+	"bsu-quiz/quiz/config"
+	"bsu-quiz/quiz/internal/infra/health"
+	"bsu-quiz/quiz/internal/infra/openapi"
+	"bsu-quiz/quiz/internal/infra/service"
+	"bsu-quiz/quiz/internal/interfaces/http/hanlders"
+	"bsu-quiz/quiz/internal/interfaces/http/middleware"
+	"bsu-quiz/quiz/web"
+	"log/slog"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// adminServices are the services the routes of the admin panel use
+type adminServices struct {
+	auth          service.AuthProvider
+	token         service.TokenProvider
+	quiz          service.QuizProvider
+	session       service.SessionProvider
+	admin         service.AdminProvider
+	render        service.RenderProvider
+	impersonation service.ImpersonationProvider
+	trash         service.TrashProvider
+	stats         service.StatsProvider
+	audit         service.AuditProvider
+	checkers      []health.Checker
+}
+
+// adminRouter is the router of the admin panel with the handlers the app
+// needs on shutdown
+type adminRouter struct {
+	*gin.Engine
+	health  *handlers.HealthHandler
+	monitor *handlers.SessionMonitorHandler
+}
+
+// newRouter registers the routes of the admin panel. It needs no
+// connections, so routes_test.go checks the routes against the API spec
+func newRouter(cfg *config.Config, log *slog.Logger, spec *openapi.Document, services adminServices) (*adminRouter, error) {
+	// Handlers
+	authHandler := handlers.NewAuthHandler(
+		services.auth,
+		cfg.Env == envProd,
+		cfg.BotConfig.Username,
+		cfg.BotConfig.Token,
+		cfg.BotConfig.LoginWidgetMaxAge,
+	)
+	quizHandler := handlers.NewQuizHandler(services.quiz, services.render)
+	adminHandler := handlers.NewAdminHandler(services.admin, services.quiz, services.session, services.stats, services.audit)
+	sessionHandler := handlers.NewSessionHandler(services.session)
+	trashHandler := handlers.NewTrashHandler(services.trash)
+	adminAPIHandler := handlers.NewAdminAPIHandler(services.admin, services.session, services.stats, services.audit)
+	monitorHandler := handlers.NewSessionMonitorHandler(services.session, time.Second, log)
+	impersonationHandler := handlers.NewImpersonationHandler(services.impersonation)
+	authAPIHandler := handlers.NewAuthAPIHandler(services.token)
+	healthHandler := handlers.NewHealthHandler(2*time.Second, services.checkers...)
+
+	// Initialize Gin
+	router := gin.Default()
+
+	// Let services read request context values, such as the audit actor,
+	// through the gin context handlers pass them
+	router.ContextWithFallback = true
+
+	// Render errors handlers attach with c.Error
+	router.Use(middleware.Errors(log))
+
+	// Load templates embedded into the binary
+	tmpl, err := web.Templates()
+	if err != nil {
+		return nil, err
+	}
+	router.SetHTMLTemplate(tmpl)
+
+	// Static files
+	router.StaticFS("/static", web.Static())
+
+	// Probes of the deployment
+	router.GET("/healthz", healthHandler.Liveness)
+	router.GET("/readyz", healthHandler.Readiness)
+
+	// Public routes
+	router.GET("/", func(c *gin.Context) {
+		c.Redirect(302, "/login")
+	})
+	router.GET("/login", authHandler.LoginForm)
+	router.POST("/login", authHandler.Login)
+	router.GET("/login/telegram", authHandler.TelegramLogin)
+	router.GET("/register", authHandler.RegisterForm)
+	router.POST("/register", authHandler.Register)
+	router.GET("/register/confirm", authHandler.ConfirmRegistrationForm)
+	router.POST("/register/confirm", authHandler.ConfirmRegistration)
+	router.GET("/logout", authHandler.Logout)
+	router.GET("/password/forgot", authHandler.ForgotPasswordForm)
+	router.POST("/password/forgot", authHandler.ForgotPassword)
+	router.GET("/password/reset", authHandler.ResetPasswordForm)
+	router.POST("/password/reset", authHandler.ResetPassword)
+
+	// Authenticated routes, impersonation replaces the signed in user
+	// before Actor records who acts
+	authenticated := router.Group("/")
+	authenticated.Use(middleware.Auth(services.auth))
+	authenticated.Use(middleware.Impersonation(services.impersonation, log))
+	authenticated.Use(middleware.Actor())
+	{
+		// User dashboard
+		authenticated.GET("/dashboard", func(c *gin.Context) {
+			username, _ := c.Get("username")
+			roles, _ := c.Get("roles")
+			impersonation, _ := c.Get("impersonation")
+			c.HTML(200, "dashboard.html", gin.H{
+				"Title":         "Dashboard",
+				"Username":      username,
+				"Roles":         roles,
+				"Impersonation": impersonation,
+				"CurrentNav":    "dashboard",
+				"CurrentTime":   time.Now().Format("2006-01-02 15:04:05"),
+			})
+		})
+
+		// Quiz management
+		quizRoutes := authenticated.Group("/quizzes")
+		quizRoutes.Use(middleware.RequireTeacher())
+		{
+			quizRoutes.GET("/", quizHandler.ListQuizzes)
+			quizRoutes.GET("/new", quizHandler.NewQuizForm)
+			quizRoutes.POST("/", quizHandler.CreateQuiz)
+			quizRoutes.GET("/:id/edit", quizHandler.EditQuizForm)
+			quizRoutes.PUT("/:id", quizHandler.UpdateQuiz)
+			quizRoutes.DELETE("/:id", quizHandler.DeleteQuiz)
+
+			// Publishing lifecycle
+			quizRoutes.POST("/:id/status", quizHandler.ChangeStatus)
+
+			// Import and export
+			quizRoutes.GET("/:id/export", quizHandler.ExportQuiz)
+			quizRoutes.POST("/import", quizHandler.ImportQuiz)
+
+			// Live preview of question and option markup
+			quizRoutes.POST("/preview", quizHandler.Preview)
+
+			// Collaborators
+			quizRoutes.GET("/:id/collaborators", quizHandler.ListCollaborators)
+			quizRoutes.POST("/:id/collaborators", quizHandler.InviteCollaborator)
+			quizRoutes.DELETE("/:id/collaborators/:userId", quizHandler.RemoveCollaborator)
+
+			// Question management
+			quizRoutes.POST("/:id/questions", quizHandler.AddQuestion)
+			quizRoutes.PUT("/:id/questions/:questionId", quizHandler.UpdateQuestion)
+			quizRoutes.DELETE("/:id/questions/:questionId", quizHandler.DeleteQuestion)
+
+			// Option management
+			quizRoutes.POST("/:id/questions/:questionId/options", quizHandler.AddOption)
+			quizRoutes.PUT("/:id/questions/:questionId/options/:optionId", quizHandler.UpdateOption)
+			quizRoutes.DELETE("/:id/questions/:questionId/options/:optionId", quizHandler.DeleteOption)
+		}
+
+		// Sessions, feedback and review
+		sessionRoutes := authenticated.Group("/sessions")
+		sessionRoutes.Use(middleware.RequireTeacher())
+		{
+			sessionRoutes.POST("/", sessionHandler.CreateSession)
+			sessionRoutes.GET("/:id/results", sessionHandler.Results)
+			sessionRoutes.GET("/:id/questions/:questionId/feedback", sessionHandler.QuestionFeedback)
+			sessionRoutes.GET("/:id/participants/:participantId/review", sessionHandler.Review)
+			sessionRoutes.DELETE("/:id", sessionHandler.DeleteSession)
+		}
+
+		// Leaving "view as user"
+		authenticated.POST(middleware.ImpersonationStopPath, impersonationHandler.Stop)
+
+		// Deleted quizzes and sessions
+		trashRoutes := authenticated.Group("/trash")
+		trashRoutes.Use(middleware.RequireTeacher())
+		{
+			trashRoutes.GET("/", trashHandler.Trash)
+			trashRoutes.POST("/quizzes/:id/restore", trashHandler.RestoreQuiz)
+			trashRoutes.POST("/sessions/:id/restore", trashHandler.RestoreSession)
+		}
+
+		// Admin routes
+		adminRoutes := authenticated.Group("/admin")
+		adminRoutes.Use(middleware.RequireAdmin())
+		{
+			adminRoutes.GET("/dashboard", adminHandler.Dashboard)
+
+			// User management
+			adminRoutes.GET("/users", adminHandler.Users)
+			adminRoutes.POST("/users/:id/role", adminHandler.UpdateUserRole)
+			adminRoutes.GET("/users/import", adminHandler.ImportUsersForm)
+			adminRoutes.POST("/users/import", adminHandler.ImportUsers)
+			adminRoutes.POST("/users/bulk", adminHandler.BulkUpdateUsers)
+			adminRoutes.POST("/users/:id/impersonate", impersonationHandler.Start)
+
+			// Quiz management
+			adminRoutes.GET("/quizzes", adminHandler.Quizzes)
+			adminRoutes.DELETE("/quizzes/:id", adminHandler.DeleteQuiz)
+
+			// Session management
+			adminRoutes.GET("/sessions", adminHandler.Sessions)
+			adminRoutes.POST("/sessions/:id/end", adminHandler.EndSession)
+
+			// Live monitor and remote control
+			adminRoutes.GET("/sessions/:id/monitor", adminHandler.SessionMonitor)
+			adminRoutes.GET("/sessions/:id/monitor/events", monitorHandler.Events)
+			adminRoutes.POST("/sessions/:id/pause", adminHandler.PauseSession)
+			adminRoutes.POST("/sessions/:id/resume", adminHandler.ResumeSession)
+			adminRoutes.POST("/sessions/:id/skip", adminHandler.SkipQuestion)
+			adminRoutes.POST("/sessions/:id/participants/:participantId/kick", adminHandler.KickParticipant)
+
+			// Audit log
+			adminRoutes.GET("/audit", adminHandler.AuditLog)
+		}
+	}
+
+	// JSON API, see api/openapi/admin.yml. Requests are validated against
+	// the spec, responses too everywhere but in production
+	openAPI := middleware.OpenAPI(spec, cfg.Env != envProd, log)
+
+	v1 := router.Group("/v1")
+	{
+		// Bearer tokens, signing in needs no token
+		apiAuth := v1.Group("/auth")
+		apiAuth.Use(openAPI)
+		{
+			apiAuth.POST("/login", authAPIHandler.Login)
+			apiAuth.POST("/refresh", authAPIHandler.Refresh)
+			apiAuth.POST("/logout", authAPIHandler.Logout)
+		}
+
+		// The token is checked before the request is validated
+		apiAdmin := v1.Group("/admin")
+		apiAdmin.Use(middleware.Bearer(services.token))
+		apiAdmin.Use(openAPI)
+		apiAdmin.Use(middleware.RequireAdmin())
+		apiAdmin.Use(middleware.Actor())
+		{
+			// User management
+			apiAdmin.GET("/users", adminAPIHandler.GetAllUsers)
+			apiAdmin.POST("/users", adminAPIHandler.CreateUser)
+			apiAdmin.GET("/users/:id", adminAPIHandler.GetUserByID)
+			apiAdmin.PUT("/users/:id", adminAPIHandler.UpdateUser)
+			apiAdmin.DELETE("/users/:id", adminAPIHandler.DeleteUser)
+			apiAdmin.PUT("/users/:id/role", adminAPIHandler.UpdateUserRole)
+			apiAdmin.POST("/users/import", adminAPIHandler.ImportUsers)
+			apiAdmin.POST("/users/bulk", adminAPIHandler.BulkUpdateUsers)
+
+			// Quiz management
+			apiAdmin.GET("/quizzes", adminAPIHandler.GetAllQuizzes)
+			apiAdmin.GET("/quizzes/:id", adminAPIHandler.GetQuizByID)
+			apiAdmin.DELETE("/quizzes/:id", adminAPIHandler.DeleteQuiz)
+
+			// Session management
+			apiAdmin.GET("/sessions", adminAPIHandler.GetAllSessions)
+			apiAdmin.GET("/sessions/:id", adminAPIHandler.GetSessionByID)
+			apiAdmin.POST("/sessions/:id/end", adminAPIHandler.EndSession)
+
+			// Dashboard
+			apiAdmin.GET("/dashboard/stats", adminAPIHandler.GetDashboardStats)
+
+			// Audit log
+			apiAdmin.GET("/audit", adminAPIHandler.GetAuditLog)
+		}
+	}
+
+	return &adminRouter{
+		Engine:  router,
+		health:  healthHandler,
+		monitor: monitorHandler,
+	}, nil
+}
@@ -0,0 +1,69 @@
+package admin
+
+import (
+	"bsu-quiz/quiz/api"
+	"bsu-quiz/quiz/config"
+	"bsu-quiz/quiz/internal/infra/openapi"
+	"bsu-quiz/quiz/internal/interfaces/http/middleware"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// newTestRouter registers the routes without services, no request is served
+func newTestRouter(t *testing.T) (*adminRouter, *openapi.Document) {
+	t.Helper()
+
+	gin.SetMode(gin.TestMode)
+
+	spec, err := openapi.Load(api.AdminSpec)
+	if err != nil {
+		t.Fatalf("failed to load the API spec: %v", err)
+	}
+
+	router, err := newRouter(&config.Config{Env: envLocal}, slog.New(slog.NewTextHandler(io.Discard, nil)), spec, adminServices{})
+	if err != nil {
+		t.Fatalf("failed to register routes: %v", err)
+	}
+
+	return router, spec
+}
+
+func TestRoutesMatchAPISpec(t *testing.T) {
+	router, spec := newTestRouter(t)
+
+	if err := middleware.CheckRoutes(spec, router.Routes()); err != nil {
+		t.Fatal(err)
+	}
+
+	api := 0
+	for _, route := range router.Routes() {
+		if strings.HasPrefix(route.Path, spec.BasePath()+"/") {
+			api++
+		}
+	}
+	if api == 0 {
+		t.Fatalf("no routes under %s, the check checked nothing", spec.BasePath())
+	}
+}
+
+func TestCheckRoutesFindsUndescribedRoutes(t *testing.T) {
+	router, spec := newTestRouter(t)
+
+	router.GET(spec.BasePath()+"/admin/users/:id/secrets", func(*gin.Context) {})
+	router.PATCH(spec.BasePath()+"/admin/users/:id", func(*gin.Context) {})
+
+	err := middleware.CheckRoutes(spec, router.Routes())
+	if err == nil {
+		t.Fatal("got no error for routes missing in the spec")
+	}
+
+	for _, want := range []string{"GET /v1/admin/users/:id/secrets", "PATCH /v1/admin/users/:id"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error doesn't name %s: %v", want, err)
+		}
+	}
+}
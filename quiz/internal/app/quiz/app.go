@@ -0,0 +1,147 @@
+// Package quiz is the quiz app, the server of the Telegram Web App players
+// open from the bot
+package quiz
+
+import (
+	"bsu-quiz/quiz/config"
+	"bsu-quiz/quiz/internal/domain/rules"
+	"bsu-quiz/quiz/internal/infra/health"
+	"bsu-quiz/quiz/internal/infra/mail"
+	"bsu-quiz/quiz/internal/infra/markup"
+	"bsu-quiz/quiz/internal/infra/repository"
+	"bsu-quiz/quiz/internal/infra/service"
+	"bsu-quiz/quiz/internal/interfaces/http/hanlders"
+	"bsu-quiz/quiz/internal/interfaces/http/middleware"
+	"bsu-quiz/quiz/web"
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
+)
+
+type QuizApp struct {
+	Config *config.Config
+	Conn   *pgxpool.Pool
+	Redis  *redis.Client
+	Router *gin.Engine
+	Log    *slog.Logger
+	Health *handlers.HealthHandler
+	// Feedback streams revealed feedback, its streams are closed on shutdown
+	Feedback *handlers.PlayFeedbackHandler
+}
+
+func NewQuizApp() *QuizApp {
+	cfg := config.MustLoad()
+	log := setupLogger(cfg.Env)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	db := newPgxConn(ctx, cfg.StorageConfig)
+	rdb := newRedisClient(ctx, cfg.RedisConfig)
+
+	userRepo := repository.NewPgUserRepository(db)
+	quizRepo := repository.NewPgQuizRepository(db)
+	sessionRepo := repository.NewPgSessionRepository(db)
+	collabRepo := repository.NewPgCollaboratorRepository(db)
+	auditRepo := repository.NewPgAuditRepository(db)
+	webSessionRepo := repository.NewRedisWebSessionRepository(rdb, cfg.RedisConfig.KeyPrefix)
+	passwordResetRepo := repository.NewRedisPasswordResetRepository(rdb, cfg.RedisConfig.KeyPrefix)
+	registrationRepo := repository.NewRedisRegistrationRepository(rdb, cfg.RedisConfig.KeyPrefix)
+	tokenRepo := repository.NewRedisTokenRepository(rdb, cfg.RedisConfig.KeyPrefix)
+	tx := repository.NewPgTransactor(db)
+
+	emails, err := web.EmailTemplates()
+	if err != nil {
+		panic(err)
+	}
+
+	// Authorization policy
+	policy := rules.NewPolicy(quizRepo, userRepo, collabRepo, sessionRepo)
+
+	// Services, emails link to the admin panel
+	auditService := service.NewAuditService(auditRepo)
+	authService := service.NewAuthService(
+		userRepo,
+		webSessionRepo,
+		tokenRepo,
+		passwordResetRepo,
+		registrationRepo,
+		mail.NewSender(cfg.EmailConfig, emails),
+		cfg.AdminPanelConfig.SessionTTL,
+		cfg.AdminPanelConfig.PasswordResetTTL,
+		cfg.AdminPanelConfig.RegistrationTTL,
+		strings.TrimSuffix(cfg.AdminPanelConfig.PublicURL, "/")+"/password/reset",
+		strings.TrimSuffix(cfg.AdminPanelConfig.PublicURL, "/")+"/register/confirm",
+	)
+	sessionService := service.NewSessionService(sessionRepo, quizRepo, userRepo, policy, tx, auditService)
+	renderService := service.NewRenderService(markup.NewRenderer(cfg.RenderConfig.FormulaImageURL), cfg.RenderConfig.FormulaCacheSize)
+	pgChecker := health.NewPostgresChecker(db)
+	redisChecker := health.NewRedisChecker(rdb)
+
+	// Handlers
+	playHandler := handlers.NewPlayHandler(authService, sessionService)
+	playFeedbackHandler := handlers.NewPlayFeedbackHandler(sessionService, renderService, time.Second, log)
+	formulaHandler := handlers.NewFormulaHandler(renderService)
+	healthHandler := handlers.NewHealthHandler(2*time.Second, pgChecker, redisChecker)
+
+	router := gin.Default()
+
+	// Let services read request context values through the gin context
+	router.ContextWithFallback = true
+
+	// Render errors handlers attach with c.Error, pages opened outside
+	// of Telegram get the error page
+	router.Use(middleware.Errors(log))
+
+	tmpl, err := web.Templates()
+	if err != nil {
+		panic(err)
+	}
+	router.SetHTMLTemplate(tmpl)
+	router.StaticFS("/static", web.Static())
+
+	// Probes of the deployment
+	router.GET("/healthz", healthHandler.Liveness)
+	router.GET("/readyz", healthHandler.Readiness)
+
+	// Formula images of rendered texts, render.formula_image_url points here.
+	// Drawing is slow, so clients are limited
+	router.GET("/formula.png", middleware.RateLimit(cfg.RenderConfig.FormulaRateLimit, time.Minute), formulaHandler.Image)
+
+	// Players sign in with the init data Telegram signs for the bot
+	play := router.Group("/play")
+	play.Use(middleware.TelegramWebApp(authService, cfg.BotConfig.Token, cfg.BotConfig.InitDataMaxAge))
+	{
+		play.GET("/me", playHandler.Me)
+		play.POST("/sessions/join", playHandler.JoinSession)
+
+		// Feedback of the questions the player answered
+		play.GET("/sessions/:id/review", playFeedbackHandler.Review)
+		play.GET("/sessions/:id/questions/:questionId/feedback", playFeedbackHandler.QuestionFeedback)
+		play.GET("/sessions/:id/feedback/events", playFeedbackHandler.Events)
+	}
+
+	return &QuizApp{
+		Config: cfg,
+		Conn:   db,
+		Redis:  rdb,
+		Router: router,
+		Log:    log,
+		Health: healthHandler,
+
+		Feedback: playFeedbackHandler,
+	}
+}
+
+// Close closes the Postgres pool and then Redis,
+// it must be called once the server is stopped
+func (app *QuizApp) Close() {
+	app.Conn.Close()
+
+	if err := app.Redis.Close(); err != nil {
+		app.Log.Error("failed to close redis", slog.String("error", err.Error()))
+	}
+}
@@ -0,0 +1,142 @@
+package quiz
+
+import (
+	"bsu-quiz/quiz/config"
+	"bsu-quiz/quiz/internal/infra/logger/handlers/slogpretty"
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	envLocal = "local"
+	envDev   = "dev"
+	envProd  = "prod"
+)
+
+// levelFatal logs errors the app can't run after, slog has no such level
+const levelFatal = slog.Level(12)
+
+func newPgxConn(ctx context.Context, cfg config.StorageConfig) *pgxpool.Pool {
+	db, err := pgxpool.New(ctx, cfg.DatabaseUrl)
+	if err != nil {
+		panic(err)
+	}
+
+	if err := db.Ping(ctx); err != nil {
+		db.Close()
+		panic(err)
+	}
+
+	return db
+}
+
+// newRedisClient connects to Redis, the auth service players are signed
+// in with keeps its state there
+func newRedisClient(ctx context.Context, cfg config.RedisConfig) *redis.Client {
+	client := redis.NewClient(&redis.Options{
+		Addr:     cfg.Addr,
+		Password: cfg.Password,
+		DB:       cfg.DB,
+	})
+
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		panic(err)
+	}
+
+	return client
+}
+
+// Start serves the quiz app until ctx is cancelled or the process gets
+// SIGINT or SIGTERM. Then the readiness probe starts failing, requests in
+// flight get ShutdownTimeout to finish and the connections are closed
+func Start(ctx context.Context, app *QuizApp) {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	cfg := app.Config.WebAppConfig
+	server := &http.Server{
+		Addr:         net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.Port)),
+		Handler:      app.Router,
+		ReadTimeout:  cfg.ReadTimeout,
+		WriteTimeout: cfg.WriteTimeout,
+		IdleTimeout:  cfg.IdleTimeout,
+	}
+
+	// Shutdown waits for requests in flight, feedback streams only end once closed
+	server.RegisterOnShutdown(app.Feedback.Close)
+
+	serverErr := make(chan error, 1)
+	go func() {
+		app.Log.Info("server starting", slog.String("addr", server.Addr))
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serverErr <- err
+		}
+	}()
+
+	failed := false
+	select {
+	case <-ctx.Done():
+		app.Log.Info("shutting down")
+	case err := <-serverErr:
+		app.Log.Log(ctx, levelFatal, "failed to start server", slog.String("error", err.Error()))
+		failed = true
+	}
+
+	app.Health.Drain()
+	stop()
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		app.Log.Error("failed to shut down server gracefully", slog.String("error", err.Error()))
+	}
+
+	app.Close()
+
+	if failed {
+		os.Exit(1)
+	}
+}
+
+func setupLogger(env string) *slog.Logger {
+	var log *slog.Logger
+
+	switch env {
+	case envLocal:
+		log = setupPrettySlog()
+	case envDev:
+		log = slog.New(
+			slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}),
+		)
+	case envProd:
+		log = slog.New(
+			slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo}),
+		)
+	}
+
+	return log
+}
+
+func setupPrettySlog() *slog.Logger {
+	opts := slogpretty.PrettyHandlerOptions{
+		SlogOpts: &slog.HandlerOptions{
+			Level: slog.LevelDebug,
+		},
+	}
+
+	handler := opts.NewPrettyHandler(os.Stdout)
+
+	return slog.New(handler)
+}
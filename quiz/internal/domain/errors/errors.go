@@ -0,0 +1,103 @@
+// Package errors defines the error kinds of the quiz module. Repositories and
+// services return them so the HTTP layer can choose the status code, use
+// errors.Is(err, ErrNotFound) and friends to check the kind of an error.
+// The standard library helpers are re-exported so callers need one import
+package errors
+
+import (
+	stderrors "errors"
+)
+
+// Error kinds
+var (
+	ErrNotFound        = stderrors.New("not found")
+	ErrForbidden       = stderrors.New("forbidden")
+	ErrConflict        = stderrors.New("conflict")
+	ErrValidation      = stderrors.New("validation failed")
+	ErrUnauthorized    = stderrors.New("unauthorized")
+	ErrTooManyRequests = stderrors.New("too many requests")
+)
+
+// Error is an error of a known kind with a message safe to show to the user
+type Error struct {
+	Kind    error
+	Message string
+	// Fields maps invalid fields to what is wrong with them, set for validation errors
+	Fields map[string]string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Kind
+}
+
+// WithField adds an invalid field to the error
+func (e *Error) WithField(field, message string) *Error {
+	if e.Fields == nil {
+		e.Fields = make(map[string]string)
+	}
+	e.Fields[field] = message
+	return e
+}
+
+// NotFound reports a missing object, the message is "<what> not found"
+func NotFound(what string) *Error {
+	return &Error{Kind: ErrNotFound, Message: what + " not found"}
+}
+
+func Forbidden(message string) *Error {
+	return &Error{Kind: ErrForbidden, Message: message}
+}
+
+// Conflict reports a change clashing with the current state, e.g. a taken login
+func Conflict(message string) *Error {
+	return &Error{Kind: ErrConflict, Message: message}
+}
+
+// Validation reports invalid input not tied to a single field
+func Validation(message string) *Error {
+	return &Error{Kind: ErrValidation, Message: message}
+}
+
+// Invalid reports an invalid field
+func Invalid(field, message string) *Error {
+	return Validation(message).WithField(field, message)
+}
+
+func Unauthorized(message string) *Error {
+	return &Error{Kind: ErrUnauthorized, Message: message}
+}
+
+// TooManyRequests reports a client that went over its rate limit
+func TooManyRequests(message string) *Error {
+	return &Error{Kind: ErrTooManyRequests, Message: message}
+}
+
+// Fields returns the invalid fields of a validation error in the chain
+func Fields(err error) map[string]string {
+	var e *Error
+	if As(err, &e) {
+		return e.Fields
+	}
+
+	return nil
+}
+
+func New(text string) error {
+	return stderrors.New(text)
+}
+
+func Is(err, target error) bool {
+	return stderrors.Is(err, target)
+}
+
+func As(err error, target any) bool {
+	return stderrors.As(err, target)
+}
+
+func Join(errs ...error) error {
+	return stderrors.Join(errs...)
+}
@@ -0,0 +1,144 @@
+package models
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"time"
+)
+
+// Audited actions
+const (
+	AuditUserCreate     = "user.create"
+	AuditUserUpdate     = "user.update"
+	AuditUserRoleUpdate = "user.role_update"
+	AuditUserDelete     = "user.delete"
+	AuditUserImport     = "user.import"
+	AuditUserBulkUpdate = "user.bulk_update"
+
+	AuditQuizDelete       = "quiz.delete"
+	AuditQuizStatusChange = "quiz.status_change"
+
+	AuditCollaboratorInvite = "collaborator.invite"
+	AuditCollaboratorRemove = "collaborator.remove"
+
+	AuditSessionStart  = "session.start"
+	AuditSessionPause  = "session.pause"
+	AuditSessionResume = "session.resume"
+	AuditSessionSkip   = "session.skip_question"
+	AuditSessionKick   = "session.kick"
+	AuditSessionEnd    = "session.end"
+
+	AuditImpersonationStart = "impersonation.start"
+	AuditImpersonationStop  = "impersonation.stop"
+)
+
+// Audit target types
+const (
+	AuditTargetUser    = "user"
+	AuditTargetQuiz    = "quiz"
+	AuditTargetSession = "session"
+)
+
+// AuditEntry records who did what to which object and what changed
+type AuditEntry struct {
+	ID         int64           `json:"id" db:"id"`
+	ActorID    *int64          `json:"actor_id" db:"actor_id"`
+	ActorLogin string          `json:"actor_login,omitempty" db:"actor_login"`
+	Action     string          `json:"action" db:"action"`
+	TargetType string          `json:"target_type" db:"target_type"`
+	TargetID   string          `json:"target_id" db:"target_id"`
+	Diff       json.RawMessage `json:"diff,omitempty" db:"diff"`
+	IP         string          `json:"ip" db:"ip"`
+	UserAgent  string          `json:"user_agent" db:"user_agent"`
+	CreatedAt  time.Time       `json:"created_at" db:"created_at"`
+}
+
+// AuditFilter selects audit entries, empty fields match everything
+type AuditFilter struct {
+	ActorID    *int64
+	Action     string
+	TargetType string
+	TargetID   string
+	From       *time.Time
+	To         *time.Time
+	Offset     int
+	Limit      int
+}
+
+// FieldChange is a changed field in an audit diff
+type FieldChange struct {
+	Before any `json:"before"`
+	After  any `json:"after"`
+}
+
+// NewAuditDiff returns the fields that differ between the JSON forms of
+// before and after. Either may be nil for created and deleted objects
+func NewAuditDiff(before, after any) (json.RawMessage, error) {
+	beforeFields, err := jsonFields(before)
+	if err != nil {
+		return nil, err
+	}
+
+	afterFields, err := jsonFields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := make(map[string]FieldChange)
+	for name, value := range beforeFields {
+		if other, ok := afterFields[name]; !ok || !reflect.DeepEqual(value, other) {
+			diff[name] = FieldChange{Before: value, After: afterFields[name]}
+		}
+	}
+	for name, value := range afterFields {
+		if _, ok := beforeFields[name]; !ok {
+			diff[name] = FieldChange{After: value}
+		}
+	}
+
+	if len(diff) == 0 {
+		return nil, nil
+	}
+
+	return json.Marshal(diff)
+}
+
+func jsonFields(v any) (map[string]any, error) {
+	if v == nil || (reflect.ValueOf(v).Kind() == reflect.Ptr && reflect.ValueOf(v).IsNil()) {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+
+	return fields, nil
+}
+
+// Actor is the user performing a request together with request details
+// recorded in the audit log
+type Actor struct {
+	UserID    int64
+	IP        string
+	UserAgent string
+}
+
+type actorKey struct{}
+
+// WithActor returns a copy of ctx carrying the actor
+func WithActor(ctx context.Context, actor Actor) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext returns the actor stored by WithActor
+func ActorFromContext(ctx context.Context) (Actor, bool) {
+	actor, ok := ctx.Value(actorKey{}).(Actor)
+	return actor, ok
+}
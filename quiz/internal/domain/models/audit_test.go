@@ -0,0 +1,96 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestNewAuditDiff(t *testing.T) {
+	chatID := int64(100)
+	user := &User{ID: 1, Login: "ivanov", Password: "hash", RoleFlags: RoleUser, Group: "ПМ-21"}
+
+	tests := []struct {
+		name   string
+		before any
+		after  any
+		// want is the expected diff, empty if nothing changed
+		want string
+	}{
+		{
+			name:   "update",
+			before: user,
+			after:  &User{ID: 1, Login: "ivanov", RoleFlags: RoleUser | RoleTeacher, Group: "ПМ-22"},
+			want:   `{"group":{"before":"ПМ-21","after":"ПМ-22"},"role_flags":{"before":1,"after":5}}`,
+		},
+		{
+			name:  "create",
+			after: user,
+			want:  `{"group":{"before":null,"after":"ПМ-21"},"id":{"before":null,"after":1},"login":{"before":null,"after":"ivanov"},"role_flags":{"before":null,"after":1}}`,
+		},
+		{
+			name:   "delete",
+			before: user,
+			after:  (*User)(nil),
+			want:   `{"group":{"before":"ПМ-21","after":null},"id":{"before":1,"after":null},"login":{"before":"ivanov","after":null},"role_flags":{"before":1,"after":null}}`,
+		},
+		{
+			name:   "field left out when empty",
+			before: user,
+			after:  &User{ID: 1, Login: "ivanov", RoleFlags: RoleUser, Group: "ПМ-21", TelegramChatID: &chatID},
+			want:   `{"telegram_chat_id":{"before":null,"after":100}}`,
+		},
+		{
+			name:   "hidden field",
+			before: user,
+			after:  &User{ID: 1, Login: "ivanov", Password: "other hash", RoleFlags: RoleUser, Group: "ПМ-21"},
+		},
+		{
+			name:   "nested values",
+			before: map[string]any{"options": []string{"a", "b"}, "settings": map[string]int{"time": 30}},
+			after:  map[string]any{"options": []string{"a", "b"}, "settings": map[string]int{"time": 60}},
+			want:   `{"settings":{"before":{"time":30},"after":{"time":60}}}`,
+		},
+		{
+			name: "nothing",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff, err := NewAuditDiff(tt.before, tt.after)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if tt.want == "" {
+				if diff != nil {
+					t.Fatalf("got diff %s, want none", diff)
+				}
+				return
+			}
+
+			// Compare decoded values so the order of fields in want doesn't matter
+			var got, want any
+			if err := json.Unmarshal(diff, &got); err != nil {
+				t.Fatalf("diff isn't JSON: %v", err)
+			}
+			json.Unmarshal([]byte(tt.want), &want)
+
+			gotJSON, _ := json.Marshal(got)
+			wantJSON, _ := json.Marshal(want)
+			if string(gotJSON) != string(wantJSON) {
+				t.Errorf("got diff %s, want %s", gotJSON, wantJSON)
+			}
+		})
+	}
+}
+
+func TestNewAuditDiffErrors(t *testing.T) {
+	// Only objects have fields to compare
+	if _, err := NewAuditDiff("ivanov", nil); err == nil {
+		t.Errorf("got no error for a string")
+	}
+	if _, err := NewAuditDiff(nil, map[string]any{"f": func() {}}); err == nil {
+		t.Errorf("got no error for a value JSON can't encode")
+	}
+}
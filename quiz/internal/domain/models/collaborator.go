@@ -0,0 +1,51 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// QuizRole is the role of a user in a particular quiz
+type QuizRole string
+
+const (
+	QuizRoleNone   QuizRole = ""
+	QuizRoleOwner  QuizRole = "owner"
+	QuizRoleEditor QuizRole = "editor"
+	QuizRoleViewer QuizRole = "viewer"
+)
+
+type Collaborator struct {
+	QuizID    uuid.UUID `json:"quiz_id" db:"quiz_id"`
+	UserID    int64     `json:"user_id" db:"user_id"`
+	Login     string    `json:"login" db:"login"`
+	Role      QuizRole  `json:"role" db:"role"`
+	InvitedBy *int64    `json:"invited_by" db:"invited_by"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// IsValid checks if the role can be assigned to a collaborator
+func (r QuizRole) IsValid() bool {
+	return r == QuizRoleOwner || r == QuizRoleEditor || r == QuizRoleViewer
+}
+
+// CanView checks if the role allows viewing the quiz and its session results
+func (r QuizRole) CanView() bool {
+	return r.IsValid()
+}
+
+// CanEdit checks if the role allows editing the quiz, its questions and options
+func (r QuizRole) CanEdit() bool {
+	return r == QuizRoleOwner || r == QuizRoleEditor
+}
+
+// CanHost checks if the role allows starting sessions from the quiz
+func (r QuizRole) CanHost() bool {
+	return r == QuizRoleOwner || r == QuizRoleEditor
+}
+
+// CanManage checks if the role allows deleting the quiz and managing collaborators
+func (r QuizRole) CanManage() bool {
+	return r == QuizRoleOwner
+}
@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// Impersonation is an admin viewing the panel as another user. While it's
+// active requests are served with the user's permissions and changes are refused
+type Impersonation struct {
+	AdminID    int64  `json:"admin_id"`
+	AdminLogin string `json:"admin_login"`
+	UserID     int64  `json:"user_id"`
+	UserLogin  string `json:"user_login"`
+	// UserRoles are the role flags of the user when the impersonation started
+	UserRoles int       `json:"user_roles"`
+	StartedAt time.Time `json:"started_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
@@ -0,0 +1,110 @@
+package models
+
+import (
+	"bsu-quiz/quiz/internal/domain/errors"
+	"encoding/base64"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Page is a page of a listing with the number of items matching it overall
+type Page[T any] struct {
+	Items    []T
+	Total    int
+	PageSize int
+	// NextCursor is an opaque position of the next page, empty on the last page
+	NextCursor string
+}
+
+type SortDirection string
+
+const (
+	SortAsc  SortDirection = "asc"
+	SortDesc SortDirection = "desc"
+)
+
+// Sort orders a listing by one of its sort fields, ties are broken by ID
+// in the same direction
+type Sort struct {
+	Field     string
+	Direction SortDirection
+}
+
+// Desc checks if the listing is sorted in descending order
+func (s Sort) Desc() bool {
+	return s.Direction == SortDesc
+}
+
+// ParseSort reads the sort requested by a client, the field must be one of
+// allowed. An empty field falls back to def, an empty direction to the
+// direction of def for its field and ascending for other fields
+func ParseSort(field, direction string, allowed []string, def Sort) (Sort, error) {
+	sort := def
+	if field != "" {
+		if !slices.Contains(allowed, field) {
+			return sort, errors.Invalid("sort", "must be one of "+strings.Join(allowed, ", "))
+		}
+		if field != def.Field {
+			sort = Sort{Field: field, Direction: SortAsc}
+		}
+	}
+
+	switch SortDirection(direction) {
+	case "":
+	case SortAsc, SortDesc:
+		sort.Direction = SortDirection(direction)
+	default:
+		return sort, errors.Invalid("order", "must be asc or desc")
+	}
+
+	return sort, nil
+}
+
+// ListOptions selects a page of a listing paginated by offset
+type ListOptions struct {
+	Offset int
+	Limit  int
+	Sort   Sort
+}
+
+// NewOffsetPage returns the page of items fetched with the options, the next
+// cursor is set while there are items after it
+func NewOffsetPage[T any](items []T, total int, opts ListOptions) *Page[T] {
+	page := &Page[T]{
+		Items:    items,
+		Total:    total,
+		PageSize: opts.Limit,
+	}
+
+	if next := opts.Offset + len(items); len(items) > 0 && next < total {
+		page.NextCursor = EncodeOffsetCursor(next)
+	}
+
+	return page
+}
+
+// EncodeOffsetCursor returns an opaque cursor pointing at the offset
+func EncodeOffsetCursor(offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte("o|" + strconv.Itoa(offset)))
+}
+
+// DecodeOffsetCursor parses a cursor returned by EncodeOffsetCursor
+func DecodeOffsetCursor(s string) (int, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return 0, ErrInvalidCursor
+	}
+
+	value, ok := strings.CutPrefix(string(raw), "o|")
+	if !ok {
+		return 0, ErrInvalidCursor
+	}
+
+	offset, err := strconv.Atoi(value)
+	if err != nil || offset < 0 {
+		return 0, ErrInvalidCursor
+	}
+
+	return offset, nil
+}
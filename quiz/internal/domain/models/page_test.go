@@ -0,0 +1,116 @@
+package models
+
+import (
+	"bsu-quiz/quiz/internal/domain/errors"
+	"encoding/base64"
+	"testing"
+)
+
+func TestParseSort(t *testing.T) {
+	allowed := []string{"login", "created_at"}
+	def := Sort{Field: "created_at", Direction: SortDesc}
+
+	tests := []struct {
+		name      string
+		field     string
+		direction string
+		want      Sort
+		// invalid is the field of the expected error, empty if the sort is valid
+		invalid string
+	}{
+		{name: "default", want: def},
+		{name: "default field", field: "created_at", want: def},
+		{name: "default field ascending", field: "created_at", direction: "asc", want: Sort{Field: "created_at", Direction: SortAsc}},
+		{name: "other field", field: "login", want: Sort{Field: "login", Direction: SortAsc}},
+		{name: "other field descending", field: "login", direction: "desc", want: Sort{Field: "login", Direction: SortDesc}},
+		{name: "direction only", direction: "asc", want: Sort{Field: "created_at", Direction: SortAsc}},
+		{name: "unknown field", field: "password", invalid: "sort"},
+		{name: "unknown direction", field: "login", direction: "up", invalid: "order"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSort(tt.field, tt.direction, allowed, def)
+
+			if tt.invalid != "" {
+				if _, ok := errors.Fields(err)[tt.invalid]; !ok {
+					t.Fatalf("got error %v, want %s to be invalid", err, tt.invalid)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOffsetCursor(t *testing.T) {
+	for _, offset := range []int{0, 1, 20, 12345} {
+		got, err := DecodeOffsetCursor(EncodeOffsetCursor(offset))
+		if err != nil {
+			t.Fatalf("offset %d: unexpected error: %v", offset, err)
+		}
+		if got != offset {
+			t.Errorf("got offset %d, want %d", got, offset)
+		}
+	}
+
+	encode := base64.RawURLEncoding.EncodeToString
+	invalid := map[string]string{
+		"not base64":     "!!!",
+		"no prefix":      encode([]byte("20")),
+		"session cursor": encode([]byte("started_at|desc|-|00000000-0000-0000-0000-000000000000")),
+		"not a number":   encode([]byte("o|x")),
+		"negative":       encode([]byte("o|-5")),
+	}
+	for name, cursor := range invalid {
+		if _, err := DecodeOffsetCursor(cursor); !errors.Is(err, errors.ErrValidation) {
+			t.Errorf("%s: got error %v, want an invalid cursor", name, err)
+		}
+	}
+}
+
+func TestNewOffsetPage(t *testing.T) {
+	tests := []struct {
+		name   string
+		items  int
+		total  int
+		offset int
+		// next is the offset of the next page, -1 on the last page
+		next int
+	}{
+		{name: "first page", items: 10, total: 25, next: 10},
+		{name: "middle page", items: 10, total: 25, offset: 10, next: 20},
+		{name: "last page", items: 5, total: 25, offset: 20, next: -1},
+		{name: "exactly full", items: 10, total: 20, offset: 10, next: -1},
+		{name: "empty", total: 0, next: -1},
+		{name: "past the end", total: 25, offset: 40, next: -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page := NewOffsetPage(make([]int, tt.items), tt.total, ListOptions{Offset: tt.offset, Limit: 10})
+
+			if page.Total != tt.total || page.PageSize != 10 || len(page.Items) != tt.items {
+				t.Errorf("got page %+v", page)
+			}
+
+			if tt.next < 0 {
+				if page.NextCursor != "" {
+					t.Errorf("got next cursor %q on the last page", page.NextCursor)
+				}
+				return
+			}
+
+			next, err := DecodeOffsetCursor(page.NextCursor)
+			if err != nil || next != tt.next {
+				t.Errorf("got next offset %d (%v), want %d", next, err, tt.next)
+			}
+		})
+	}
+}
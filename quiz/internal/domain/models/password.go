@@ -0,0 +1,36 @@
+package models
+
+import (
+	"bsu-quiz/quiz/internal/domain/errors"
+	"strings"
+	"unicode"
+)
+
+const (
+	MinPasswordLength = 8
+	// MaxPasswordLength is the most bcrypt hashes, longer passwords would
+	// be cut silently
+	MaxPasswordLength = 72
+)
+
+// ValidatePassword checks a new password of the user with the login: it's
+// 8 to 72 bytes long, has a letter and a digit and isn't the login itself
+func ValidatePassword(password, login string) error {
+	if len(password) < MinPasswordLength {
+		return errors.Invalid("password", "password must be at least 8 characters long")
+	}
+
+	if len(password) > MaxPasswordLength {
+		return errors.Invalid("password", "password must be at most 72 bytes long")
+	}
+
+	if !strings.ContainsFunc(password, unicode.IsLetter) || !strings.ContainsFunc(password, unicode.IsDigit) {
+		return errors.Invalid("password", "password must contain a letter and a digit")
+	}
+
+	if strings.EqualFold(password, login) {
+		return errors.Invalid("password", "password must differ from the login")
+	}
+
+	return nil
+}
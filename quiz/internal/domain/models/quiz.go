@@ -6,14 +6,26 @@ import (
 	"github.com/google/uuid"
 )
 
+// QuizSortFields are the fields quiz listings can be sorted by
+var QuizSortFields = []string{"created_at", "updated_at", "title", "status"}
+
+// DefaultQuizSort orders quizzes newest first
+var DefaultQuizSort = Sort{Field: "created_at", Direction: SortDesc}
+
 type Quiz struct {
 	ID        uuid.UUID `json:"id" db:"id"`
 	UserID    int64     `json:"user_id" db:"user_id"`
 	Title     string    `json:"title" db:"title"`
 	IsPublic  bool      `json:"is_public" db:"is_public"`
+	// Status is the publishing state, only published public quizzes are listed
+	Status      QuizStatus `json:"status" db:"status"`
+	PublishAt   *time.Time `json:"publish_at,omitempty" db:"publish_at"`
+	PublishedAt *time.Time `json:"published_at,omitempty" db:"published_at"`
 	CreatedBy string    `json:"created_by" db:"created_by"`
 	CreatedAt time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
+	DeletedBy *int64     `json:"deleted_by,omitempty" db:"deleted_by"`
 	Questions []Question `json:"questions,omitempty"`
 }
 
@@ -24,7 +36,9 @@ type Question struct {
 	TimeLimit int       `json:"time_limit" db:"time_limit"`
 	Points    int       `json:"points" db:"points"`
 	Position  int       `json:"position" db:"position"`
-	Options   []Option  `json:"options,omitempty"`
+	// Explanation is shown to participants once the question closes
+	Explanation string   `json:"explanation,omitempty" db:"explanation"`
+	Options     []Option `json:"options,omitempty"`
 }
 
 type Option struct {
@@ -33,4 +47,6 @@ type Option struct {
 	Text       string    `json:"text" db:"text"`
 	IsCorrect  bool      `json:"is_correct" db:"is_correct"`
 	Position   int       `json:"position" db:"position"`
+	// Feedback explains why this option is right or wrong
+	Feedback string `json:"feedback,omitempty" db:"feedback"`
 }
\ No newline at end of file
@@ -0,0 +1,53 @@
+package models
+
+// QuizStatus is a state of the quiz publishing lifecycle:
+// draft -> scheduled -> published -> archived
+type QuizStatus string
+
+const (
+	QuizStatusDraft     QuizStatus = "draft"
+	QuizStatusScheduled QuizStatus = "scheduled"
+	QuizStatusPublished QuizStatus = "published"
+	QuizStatusArchived  QuizStatus = "archived"
+)
+
+// quizTransitions lists the states every state can move to
+var quizTransitions = map[QuizStatus][]QuizStatus{
+	QuizStatusDraft:     {QuizStatusScheduled, QuizStatusPublished, QuizStatusArchived},
+	QuizStatusScheduled: {QuizStatusDraft, QuizStatusPublished, QuizStatusArchived},
+	QuizStatusPublished: {QuizStatusDraft, QuizStatusArchived},
+	QuizStatusArchived:  {QuizStatusDraft, QuizStatusPublished},
+}
+
+// IsValid checks if the status is a known lifecycle state
+func (s QuizStatus) IsValid() bool {
+	_, ok := quizTransitions[s]
+	return ok
+}
+
+// CanTransitionTo checks if the quiz may move from s to the next status
+func (s QuizStatus) CanTransitionTo(next QuizStatus) bool {
+	for _, allowed := range quizTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsPublished checks if the quiz is live
+func (q *Quiz) IsPublished() bool {
+	return q.Status == QuizStatusPublished
+}
+
+// IsArchived checks if the quiz is archived
+func (q *Quiz) IsArchived() bool {
+	return q.Status == QuizStatusArchived
+}
+
+// CanStartSession checks if new sessions may be started from the quiz.
+// Archived quizzes keep their old sessions but can't be hosted again
+func (q *Quiz) CanStartSession() bool {
+	return !q.IsArchived()
+}
@@ -0,0 +1,77 @@
+package models
+
+import "github.com/google/uuid"
+
+// QuestionReview is a closed question together with its explanation,
+// option feedback and, when known, the participant's own answer
+type QuestionReview struct {
+	QuestionID       uuid.UUID  `json:"question_id"`
+	Text             string     `json:"text"`
+	Explanation      string     `json:"explanation,omitempty"`
+	Options          []Option   `json:"options"`
+	SelectedOptionID *uuid.UUID `json:"selected_option_id,omitempty"`
+	IsCorrect        bool       `json:"is_correct"`
+	PointsAwarded    int        `json:"points_awarded"`
+}
+
+// NewQuestionReview builds a review of the question for the given answer.
+// answer may be nil if the participant didn't answer
+func NewQuestionReview(question *Question, answer *Answer) QuestionReview {
+	review := QuestionReview{
+		QuestionID:  question.ID,
+		Text:        question.Text,
+		Explanation: question.Explanation,
+		Options:     question.Options,
+	}
+
+	if answer != nil {
+		review.SelectedOptionID = answer.OptionID
+		review.IsCorrect = answer.IsCorrect
+		review.PointsAwarded = answer.PointsAwarded
+	}
+
+	return review
+}
+
+// PlayerFeedback is the feedback revealed to a player so far, the questions
+// are reviewed with the player's answers
+type PlayerFeedback struct {
+	Finished  bool             `json:"finished"`
+	Questions []QuestionReview `json:"questions"`
+}
+
+// NewSessionReview reviews every question of the quiz with the participant's
+// answers. participant may be nil to review the questions alone
+func NewSessionReview(quiz *Quiz, participant *Participant) []QuestionReview {
+	answers := make(map[uuid.UUID]*Answer)
+	if participant != nil {
+		for i := range participant.Answers {
+			answers[participant.Answers[i].QuestionID] = &participant.Answers[i]
+		}
+	}
+
+	reviews := make([]QuestionReview, 0, len(quiz.Questions))
+	for i := range quiz.Questions {
+		question := &quiz.Questions[i]
+		reviews = append(reviews, NewQuestionReview(question, answers[question.ID]))
+	}
+
+	return reviews
+}
+
+// NewPlayerFeedback reviews the questions of the session whose feedback may
+// be revealed, see GameSession.CanRevealFeedback
+func NewPlayerFeedback(session *GameSession, participant *Participant) *PlayerFeedback {
+	feedback := &PlayerFeedback{
+		Finished:  session.IsFinished(),
+		Questions: []QuestionReview{},
+	}
+
+	for i, review := range NewSessionReview(session.Quiz, participant) {
+		if session.CanRevealFeedback(i) {
+			feedback.Questions = append(feedback.Questions, review)
+		}
+	}
+
+	return feedback
+}
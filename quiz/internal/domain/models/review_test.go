@@ -0,0 +1,131 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// reviewSession returns a session of a quiz with three questions, the
+// player answered the first one correctly and the second one wrong
+func reviewSession(examMode bool) (*GameSession, *Participant) {
+	quiz := &Quiz{}
+	for range 3 {
+		question := Question{ID: uuid.New(), Explanation: "because"}
+		question.Options = []Option{{ID: uuid.New(), IsCorrect: true}, {ID: uuid.New()}}
+		quiz.Questions = append(quiz.Questions, question)
+	}
+
+	userID := int64(7)
+	participant := Participant{
+		ID:     uuid.New(),
+		UserID: &userID,
+		Answers: []Answer{
+			{QuestionID: quiz.Questions[0].ID, OptionID: &quiz.Questions[0].Options[0].ID, IsCorrect: true, PointsAwarded: 100},
+			{QuestionID: quiz.Questions[1].ID, OptionID: &quiz.Questions[1].Options[1].ID},
+		},
+	}
+
+	session := &GameSession{
+		StatusFlags:  GameStatusActive,
+		ExamMode:     examMode,
+		Quiz:         quiz,
+		Participants: []Participant{participant},
+	}
+
+	return session, &session.Participants[0]
+}
+
+func TestNewSessionReview(t *testing.T) {
+	session, participant := reviewSession(false)
+
+	reviews := NewSessionReview(session.Quiz, participant)
+	if len(reviews) != 3 {
+		t.Fatalf("got %d reviews, want 3", len(reviews))
+	}
+
+	first := reviews[0]
+	if !first.IsCorrect || first.PointsAwarded != 100 || *first.SelectedOptionID != session.Quiz.Questions[0].Options[0].ID {
+		t.Errorf("first review doesn't carry the correct answer: %+v", first)
+	}
+
+	second := reviews[1]
+	if second.IsCorrect || *second.SelectedOptionID != session.Quiz.Questions[1].Options[1].ID {
+		t.Errorf("second review doesn't carry the wrong answer: %+v", second)
+	}
+
+	if reviews[2].SelectedOptionID != nil {
+		t.Errorf("unanswered question has a selected option")
+	}
+
+	for i, review := range NewSessionReview(session.Quiz, nil) {
+		if review.SelectedOptionID != nil || review.Explanation != "because" {
+			t.Errorf("review %d without a participant: %+v", i, review)
+		}
+	}
+}
+
+func TestNewPlayerFeedback(t *testing.T) {
+	tests := []struct {
+		name     string
+		examMode bool
+		current  int
+		finished bool
+		// revealed is the number of questions whose feedback is shown
+		revealed int
+	}{
+		{name: "first question open", current: 0},
+		{name: "second question open", current: 1, revealed: 1},
+		{name: "last question open", current: 2, revealed: 2},
+		{name: "finished", current: 2, finished: true, revealed: 3},
+		{name: "exam second question open", examMode: true, current: 1},
+		{name: "exam last question open", examMode: true, current: 2},
+		{name: "exam finished", examMode: true, current: 2, finished: true, revealed: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session, participant := reviewSession(tt.examMode)
+			session.CurrentQuestionIndex = tt.current
+			if tt.finished {
+				session.StatusFlags = GameStatusFinished
+			}
+
+			feedback := NewPlayerFeedback(session, participant)
+
+			if feedback.Finished != tt.finished {
+				t.Errorf("got finished %v, want %v", feedback.Finished, tt.finished)
+			}
+			if feedback.Questions == nil {
+				t.Fatalf("questions must be an empty list, not null")
+			}
+			if len(feedback.Questions) != tt.revealed {
+				t.Fatalf("got %d questions, want %d", len(feedback.Questions), tt.revealed)
+			}
+			for i, review := range feedback.Questions {
+				if review.QuestionID != session.Quiz.Questions[i].ID {
+					t.Errorf("question %d is out of order", i)
+				}
+			}
+		})
+	}
+}
+
+func TestGameSessionParticipantByUser(t *testing.T) {
+	session, participant := reviewSession(false)
+	anonymous := Participant{ID: uuid.New()}
+	session.Participants = append(session.Participants, anonymous)
+
+	if got := session.ParticipantByUser(*participant.UserID); got == nil || got.ID != participant.ID {
+		t.Errorf("got participant %v, want %v", got, participant.ID)
+	}
+	if got := session.ParticipantByUser(8); got != nil {
+		t.Errorf("got participant %v for a user who doesn't take part", got.ID)
+	}
+	if got := session.Participant(anonymous.ID); got == nil || got.ID != anonymous.ID {
+		t.Errorf("participant isn't found by id")
+	}
+	if got := session.Participant(uuid.New()); got != nil {
+		t.Errorf("got participant %v for an unknown id", got.ID)
+	}
+}
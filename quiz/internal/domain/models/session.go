@@ -1,6 +1,7 @@
 package models
 
 import (
+	"bsu-quiz/quiz/internal/domain/errors"
 	"time"
 
 	"github.com/google/uuid"
@@ -20,8 +21,16 @@ type GameSession struct {
 	JoinCode            string     `json:"join_code" db:"join_code"`
 	StatusFlags         int        `json:"status_flags" db:"status_flags"`
 	CurrentQuestionIndex int       `json:"current_question_index" db:"current_question_index"`
+	// ExamMode hides explanations and feedback until the session is finished
+	ExamMode            bool       `json:"exam_mode" db:"exam_mode"`
 	StartedAt           *time.Time `json:"started_at" db:"started_at"`
 	EndedAt             *time.Time `json:"ended_at" db:"ended_at"`
+	// QuestionStartedAt is when the current question was opened, PausedAt
+	// is when the session was paused, the question timer stops meanwhile
+	QuestionStartedAt   *time.Time `json:"question_started_at,omitempty" db:"question_started_at"`
+	PausedAt            *time.Time `json:"paused_at,omitempty" db:"paused_at"`
+	DeletedAt           *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
+	DeletedBy           *int64     `json:"deleted_by,omitempty" db:"deleted_by"`
 	Participants        []Participant `json:"participants,omitempty"`
 	Quiz                *Quiz      `json:"quiz,omitempty"`
 }
@@ -33,6 +42,9 @@ type Participant struct {
 	Login     string     `json:"login" db:"login"`
 	Score     int        `json:"score" db:"score"`
 	JoinedAt  time.Time  `json:"joined_at" db:"joined_at"`
+	// KickedAt is when the host or an admin removed the participant from
+	// the session, kicked participants are left out of the session
+	KickedAt  *time.Time `json:"kicked_at,omitempty" db:"kicked_at"`
 	Answers   []Answer   `json:"answers,omitempty"`
 }
 
@@ -70,4 +82,153 @@ func (gs *GameSession) IsPaused() bool {
 // IsFinished checks if a session is finished
 func (gs *GameSession) IsFinished() bool {
 	return gs.HasStatus(GameStatusFinished)
-}
\ No newline at end of file
+}
+
+// IsQuestionClosed checks if the question at the given index is already closed
+func (gs *GameSession) IsQuestionClosed(index int) bool {
+	return gs.IsFinished() || index < gs.CurrentQuestionIndex
+}
+
+// CanRevealFeedback checks if explanations for the question at the given index
+// may be shown to participants
+func (gs *GameSession) CanRevealFeedback(index int) bool {
+	if gs.ExamMode {
+		return gs.IsFinished()
+	}
+
+	return gs.IsQuestionClosed(index)
+}
+
+// Participant returns the participant with the id, nil if there is none
+func (gs *GameSession) Participant(id uuid.UUID) *Participant {
+	for i := range gs.Participants {
+		if gs.Participants[i].ID == id {
+			return &gs.Participants[i]
+		}
+	}
+
+	return nil
+}
+
+// ParticipantByUser returns the participant of the user, nil if the user
+// doesn't take part in the session
+func (gs *GameSession) ParticipantByUser(userID int64) *Participant {
+	for i := range gs.Participants {
+		if id := gs.Participants[i].UserID; id != nil && *id == userID {
+			return &gs.Participants[i]
+		}
+	}
+
+	return nil
+}
+
+// StatusName names the status flags of a game session
+func StatusName(flags int) string {
+	switch {
+	case flags&GameStatusFinished != 0:
+		return "finished"
+	case flags&GameStatusPaused != 0:
+		return "paused"
+	case flags&GameStatusActive != 0:
+		return "active"
+	case flags&GameStatusWaiting != 0:
+		return "waiting"
+	}
+
+	return "unknown"
+}
+
+// IsRunning checks if a session has started and isn't finished
+func (gs *GameSession) IsRunning() bool {
+	return !gs.IsFinished() && (gs.IsActive() || gs.IsPaused())
+}
+
+// Start opens the first question of a waiting session
+func (gs *GameSession) Start(now time.Time) error {
+	if !gs.IsWaiting() || gs.IsFinished() {
+		return errors.Conflict("session has already started")
+	}
+
+	gs.StatusFlags = GameStatusActive
+	gs.StartedAt = &now
+	gs.CurrentQuestionIndex = 0
+	gs.QuestionStartedAt = &now
+
+	return nil
+}
+
+// Pause stops the question timer of an active session
+func (gs *GameSession) Pause(now time.Time) error {
+	if gs.IsFinished() || gs.IsPaused() || !gs.IsActive() {
+		return errors.Conflict("session is not active")
+	}
+
+	gs.StatusFlags = GameStatusPaused
+	gs.PausedAt = &now
+
+	return nil
+}
+
+// Resume restarts the question timer where the pause stopped it
+func (gs *GameSession) Resume(now time.Time) error {
+	if gs.IsFinished() || !gs.IsPaused() {
+		return errors.Conflict("session is not paused")
+	}
+
+	if gs.QuestionStartedAt != nil && gs.PausedAt != nil {
+		startedAt := gs.QuestionStartedAt.Add(now.Sub(*gs.PausedAt))
+		gs.QuestionStartedAt = &startedAt
+	}
+
+	gs.StatusFlags = GameStatusActive
+	gs.PausedAt = nil
+
+	return nil
+}
+
+// SkipQuestion closes the current question and opens the next one, skipping
+// the last question ends the session. A paused session stays paused
+func (gs *GameSession) SkipQuestion(now time.Time, questionCount int) error {
+	if !gs.IsRunning() {
+		return errors.Conflict("session is not running")
+	}
+
+	gs.CurrentQuestionIndex++
+	if gs.CurrentQuestionIndex >= questionCount {
+		return gs.End(now)
+	}
+
+	gs.QuestionStartedAt = &now
+	if gs.IsPaused() {
+		gs.PausedAt = &now
+	}
+
+	return nil
+}
+
+// End finishes the session, its questions are closed for good
+func (gs *GameSession) End(now time.Time) error {
+	if gs.IsFinished() {
+		return errors.Conflict("session is already finished")
+	}
+
+	gs.StatusFlags = GameStatusFinished
+	gs.EndedAt = &now
+	gs.PausedAt = nil
+
+	return nil
+}
+
+// QuestionElapsed returns how long the current question has been open,
+// not counting the current pause
+func (gs *GameSession) QuestionElapsed(now time.Time) time.Duration {
+	if gs.QuestionStartedAt == nil {
+		return 0
+	}
+
+	if gs.PausedAt != nil {
+		now = *gs.PausedAt
+	}
+
+	return now.Sub(*gs.QuestionStartedAt)
+}
@@ -0,0 +1,112 @@
+package models
+
+import (
+	"bsu-quiz/quiz/internal/domain/errors"
+	"encoding/base64"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SessionFilter selects game sessions for the admin listing
+type SessionFilter struct {
+	// StatusFlags matches sessions with any of the given status bits, 0 matches all
+	StatusFlags int
+	HostID      *int64
+	QuizID      *uuid.UUID
+	// From and To limit started_at to [From, To), sessions that haven't
+	// started are excluded when either is set
+	From *time.Time
+	To   *time.Time
+	// Sort orders the sessions by one of SessionSortFields
+	Sort Sort
+	// After is the position of the last session of the previous page
+	After *SessionCursor
+	Limit int
+}
+
+// SessionSortFields are the fields the session listing can be sorted by,
+// sessions without the time come first in descending order and last in ascending
+var SessionSortFields = []string{"started_at", "ended_at"}
+
+// DefaultSessionSort orders sessions by start time, newest first
+var DefaultSessionSort = Sort{Field: "started_at", Direction: SortDesc}
+
+// SessionCursor is a keyset position in the session listing, it's only
+// valid for the sort it was created with
+type SessionCursor struct {
+	Sort Sort
+	// Value is the sort field of the session, nil if it isn't set
+	Value *time.Time
+	ID    uuid.UUID
+}
+
+// SessionListItem is a row of the admin session listing
+type SessionListItem struct {
+	GameSession
+	QuizTitle        string `json:"quiz_title" db:"quiz_title"`
+	ParticipantCount int    `json:"participant_count" db:"participant_count"`
+}
+
+var ErrInvalidCursor = errors.Invalid("cursor", "invalid cursor")
+
+// NewSessionCursor returns the cursor pointing at the session in the listing
+// with the given sort
+func NewSessionCursor(session *GameSession, sort Sort) *SessionCursor {
+	cursor := &SessionCursor{
+		Sort: sort,
+		ID:   session.ID,
+	}
+
+	switch sort.Field {
+	case "started_at":
+		cursor.Value = session.StartedAt
+	case "ended_at":
+		cursor.Value = session.EndedAt
+	}
+
+	return cursor
+}
+
+// Encode returns an opaque string representation of the cursor
+func (c *SessionCursor) Encode() string {
+	value := "-"
+	if c.Value != nil {
+		value = c.Value.UTC().Format(time.RFC3339Nano)
+	}
+
+	raw := strings.Join([]string{c.Sort.Field, string(c.Sort.Direction), value, c.ID.String()}, "|")
+
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeSessionCursor parses a cursor returned by Encode
+func DecodeSessionCursor(s string) (*SessionCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	parts := strings.Split(string(raw), "|")
+	if len(parts) != 4 {
+		return nil, ErrInvalidCursor
+	}
+
+	cursor := &SessionCursor{
+		Sort: Sort{Field: parts[0], Direction: SortDirection(parts[1])},
+	}
+	if cursor.ID, err = uuid.Parse(parts[3]); err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	if parts[2] != "-" {
+		t, err := time.Parse(time.RFC3339Nano, parts[2])
+		if err != nil {
+			return nil, ErrInvalidCursor
+		}
+		cursor.Value = &t
+	}
+
+	return cursor, nil
+}
@@ -0,0 +1,71 @@
+package models
+
+import (
+	"bsu-quiz/quiz/internal/domain/errors"
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestSessionCursor(t *testing.T) {
+	started := time.Date(2025, 6, 22, 10, 0, 0, 123456789, time.FixedZone("MSK", 3*60*60))
+	session := &GameSession{ID: uuid.New(), StartedAt: &started}
+
+	tests := []struct {
+		name string
+		sort Sort
+		// value is the expected sort value, nil if the session hasn't it
+		value *time.Time
+	}{
+		{name: "by start", sort: DefaultSessionSort, value: &started},
+		{name: "by end of a running session", sort: Sort{Field: "ended_at", Direction: SortAsc}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cursor := NewSessionCursor(session, tt.sort)
+
+			decoded, err := DecodeSessionCursor(cursor.Encode())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if decoded.Sort != tt.sort || decoded.ID != session.ID {
+				t.Errorf("got cursor %+v, want sort %+v and id %v", decoded, tt.sort, session.ID)
+			}
+
+			switch {
+			case tt.value == nil && decoded.Value != nil:
+				t.Errorf("got value %v, want none", decoded.Value)
+			case tt.value != nil && (decoded.Value == nil || !decoded.Value.Equal(*tt.value)):
+				t.Errorf("got value %v, want %v", decoded.Value, tt.value)
+			}
+		})
+	}
+}
+
+func TestDecodeSessionCursorErrors(t *testing.T) {
+	encode := func(raw string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(raw))
+	}
+	id := uuid.New().String()
+
+	invalid := map[string]string{
+		"not base64":     "!!!",
+		"offset cursor":  EncodeOffsetCursor(20),
+		"too few parts":  encode("started_at|desc|" + id),
+		"too many parts": encode("started_at|desc|-|" + id + "|x"),
+		"bad id":         encode("started_at|desc|-|42"),
+		"bad time":       encode("started_at|desc|yesterday|" + id),
+	}
+
+	for name, cursor := range invalid {
+		t.Run(name, func(t *testing.T) {
+			if _, err := DecodeSessionCursor(cursor); !errors.Is(err, errors.ErrValidation) {
+				t.Errorf("got error %v, want an invalid cursor", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package models
+
+import "time"
+
+// SessionMonitor is a snapshot of a session for the live monitor
+type SessionMonitor struct {
+	Session *GameSession
+	// Question is the open question, nil before the start and after the end
+	Question      *Question
+	QuestionCount int
+	// Answered is the number of participants who answered the open question
+	Answered int
+	// TimeLeft is the time left to answer the open question, nil when there
+	// is no open question or it has no time limit
+	TimeLeft *time.Duration
+}
+
+// NewSessionMonitor takes a snapshot of the session, the session must
+// have its quiz with questions and its participants with answers
+func NewSessionMonitor(session *GameSession, now time.Time) *SessionMonitor {
+	monitor := &SessionMonitor{Session: session}
+
+	if session.Quiz == nil {
+		return monitor
+	}
+
+	monitor.QuestionCount = len(session.Quiz.Questions)
+
+	index := session.CurrentQuestionIndex
+	if !session.IsRunning() || index < 0 || index >= monitor.QuestionCount {
+		return monitor
+	}
+
+	question := &session.Quiz.Questions[index]
+	monitor.Question = question
+
+	for _, participant := range session.Participants {
+		for _, answer := range participant.Answers {
+			if answer.QuestionID == question.ID {
+				monitor.Answered++
+				break
+			}
+		}
+	}
+
+	if question.TimeLimit > 0 {
+		left := time.Duration(question.TimeLimit)*time.Second - session.QuestionElapsed(now)
+		if left < 0 {
+			left = 0
+		}
+		monitor.TimeLeft = &left
+	}
+
+	return monitor
+}
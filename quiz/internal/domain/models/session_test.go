@@ -0,0 +1,206 @@
+package models
+
+import (
+	"bsu-quiz/quiz/internal/domain/errors"
+	"testing"
+	"time"
+)
+
+var sessionTestStart = time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+
+// sessionAt returns a session in the given status, running sessions have
+// their first question open since sessionTestStart
+func sessionAt(status int) *GameSession {
+	session := &GameSession{StatusFlags: status}
+	if status != GameStatusWaiting {
+		started := sessionTestStart
+		session.StartedAt = &started
+		session.QuestionStartedAt = &started
+	}
+
+	if status == GameStatusPaused {
+		paused := sessionTestStart.Add(10 * time.Second)
+		session.PausedAt = &paused
+	}
+
+	return session
+}
+
+func TestGameSessionTransitions(t *testing.T) {
+	const questions = 3
+	now := sessionTestStart.Add(time.Minute)
+
+	transitions := map[string]func(*GameSession) error{
+		"start":  func(gs *GameSession) error { return gs.Start(now) },
+		"pause":  func(gs *GameSession) error { return gs.Pause(now) },
+		"resume": func(gs *GameSession) error { return gs.Resume(now) },
+		"skip":   func(gs *GameSession) error { return gs.SkipQuestion(now, questions) },
+		"end":    func(gs *GameSession) error { return gs.End(now) },
+	}
+
+	tests := []struct {
+		from       int
+		transition string
+		// to is the status after the transition, 0 if it's refused
+		to int
+	}{
+		{GameStatusWaiting, "start", GameStatusActive},
+		{GameStatusWaiting, "pause", 0},
+		{GameStatusWaiting, "resume", 0},
+		{GameStatusWaiting, "skip", 0},
+		{GameStatusWaiting, "end", GameStatusFinished},
+
+		{GameStatusActive, "start", 0},
+		{GameStatusActive, "pause", GameStatusPaused},
+		{GameStatusActive, "resume", 0},
+		{GameStatusActive, "skip", GameStatusActive},
+		{GameStatusActive, "end", GameStatusFinished},
+
+		{GameStatusPaused, "start", 0},
+		{GameStatusPaused, "pause", 0},
+		{GameStatusPaused, "resume", GameStatusActive},
+		{GameStatusPaused, "skip", GameStatusPaused},
+		{GameStatusPaused, "end", GameStatusFinished},
+
+		{GameStatusFinished, "start", 0},
+		{GameStatusFinished, "pause", 0},
+		{GameStatusFinished, "resume", 0},
+		{GameStatusFinished, "skip", 0},
+		{GameStatusFinished, "end", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(StatusName(tt.from)+"/"+tt.transition, func(t *testing.T) {
+			session := sessionAt(tt.from)
+			err := transitions[tt.transition](session)
+
+			if tt.to == 0 {
+				if !errors.Is(err, errors.ErrConflict) {
+					t.Fatalf("got error %v, want a conflict", err)
+				}
+				if session.StatusFlags != tt.from {
+					t.Errorf("refused transition changed the status to %s", StatusName(session.StatusFlags))
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if session.StatusFlags != tt.to {
+				t.Errorf("got status %s, want %s", StatusName(session.StatusFlags), StatusName(tt.to))
+			}
+		})
+	}
+}
+
+func TestGameSessionStart(t *testing.T) {
+	session := sessionAt(GameStatusWaiting)
+	session.CurrentQuestionIndex = 2
+
+	if err := session.Start(sessionTestStart); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if session.CurrentQuestionIndex != 0 {
+		t.Errorf("got question %d, want the first one", session.CurrentQuestionIndex)
+	}
+	if session.StartedAt == nil || !session.StartedAt.Equal(sessionTestStart) {
+		t.Errorf("got start time %v, want %v", session.StartedAt, sessionTestStart)
+	}
+	if session.QuestionElapsed(sessionTestStart.Add(5*time.Second)) != 5*time.Second {
+		t.Errorf("question timer doesn't run from the start")
+	}
+}
+
+func TestGameSessionPauseStopsTimer(t *testing.T) {
+	session := sessionAt(GameStatusActive)
+
+	if err := session.Pause(sessionTestStart.Add(10 * time.Second)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if elapsed := session.QuestionElapsed(sessionTestStart.Add(time.Hour)); elapsed != 10*time.Second {
+		t.Errorf("got %v elapsed while paused, want 10s", elapsed)
+	}
+
+	// The pause lasts 50s, the timer goes on from 10s
+	if err := session.Resume(sessionTestStart.Add(time.Minute)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if session.PausedAt != nil {
+		t.Errorf("resumed session keeps its pause time")
+	}
+	if elapsed := session.QuestionElapsed(sessionTestStart.Add(65 * time.Second)); elapsed != 15*time.Second {
+		t.Errorf("got %v elapsed after the pause, want 15s", elapsed)
+	}
+}
+
+func TestGameSessionSkipQuestion(t *testing.T) {
+	now := sessionTestStart.Add(time.Minute)
+
+	t.Run("opens the next question", func(t *testing.T) {
+		session := sessionAt(GameStatusActive)
+
+		if err := session.SkipQuestion(now, 3); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if session.CurrentQuestionIndex != 1 {
+			t.Errorf("got question %d, want 1", session.CurrentQuestionIndex)
+		}
+		if session.QuestionElapsed(now) != 0 {
+			t.Errorf("timer of the next question doesn't start at zero")
+		}
+		if !session.IsQuestionClosed(0) || session.IsQuestionClosed(1) {
+			t.Errorf("only the skipped question should be closed")
+		}
+	})
+
+	t.Run("keeps a paused session paused", func(t *testing.T) {
+		session := sessionAt(GameStatusPaused)
+
+		if err := session.SkipQuestion(now, 3); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if !session.IsPaused() {
+			t.Fatalf("got status %s, want paused", StatusName(session.StatusFlags))
+		}
+		if session.QuestionElapsed(now.Add(time.Hour)) != 0 {
+			t.Errorf("timer of the next question runs while paused")
+		}
+	})
+
+	t.Run("skipping the last question ends the session", func(t *testing.T) {
+		session := sessionAt(GameStatusActive)
+		session.CurrentQuestionIndex = 2
+
+		if err := session.SkipQuestion(now, 3); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if !session.IsFinished() {
+			t.Fatalf("got status %s, want finished", StatusName(session.StatusFlags))
+		}
+		if session.EndedAt == nil || !session.EndedAt.Equal(now) {
+			t.Errorf("got end time %v, want %v", session.EndedAt, now)
+		}
+	})
+}
+
+func TestGameSessionEndClearsPause(t *testing.T) {
+	session := sessionAt(GameStatusPaused)
+
+	if err := session.End(sessionTestStart.Add(time.Minute)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if session.PausedAt != nil {
+		t.Errorf("finished session keeps its pause time")
+	}
+	if !session.IsQuestionClosed(session.CurrentQuestionIndex) {
+		t.Errorf("questions of a finished session should be closed")
+	}
+}
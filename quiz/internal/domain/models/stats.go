@@ -0,0 +1,49 @@
+package models
+
+import "time"
+
+const (
+	SystemStatusHealthy = "healthy"
+	SystemStatusWarning = "warning"
+	SystemStatusError   = "error"
+)
+
+// DashboardStats is a summary of the platform shown on the admin dashboard
+type DashboardStats struct {
+	TotalUsers        int               `json:"total_users"`
+	TotalQuizzes      int               `json:"total_quizzes"`
+	TotalSessions     int               `json:"total_sessions"`
+	ActiveSessions    int               `json:"active_sessions"`
+	RecentUsers       []*User           `json:"recent_users"`
+	RecentQuizzes     []*Quiz           `json:"recent_quizzes"`
+	UsersByRole       *UsersByRole      `json:"users_by_role"`
+	SessionsPerDay    []DailyCount      `json:"sessions_per_day"`
+	AnswersPerDay     []DailyCount      `json:"answers_per_day"`
+	SystemStatus      string            `json:"system_status"`
+	Components        []ComponentStatus `json:"components"`
+	CurrentServerTime time.Time         `json:"current_server_time"`
+	// GeneratedAt is when the numbers were computed, they may be cached
+	GeneratedAt time.Time `json:"generated_at"`
+}
+
+// UsersByRole counts users having each role, a user may have several roles
+type UsersByRole struct {
+	Users    int `json:"users"`
+	Admins   int `json:"admins"`
+	Teachers int `json:"teachers"`
+	Blocked  int `json:"blocked"`
+}
+
+// DailyCount is a point of a per day time series
+type DailyCount struct {
+	Date  time.Time `json:"date"`
+	Count int       `json:"count"`
+}
+
+// ComponentStatus is the health of a dependency such as Postgres or Redis
+type ComponentStatus struct {
+	Name      string `json:"name"`
+	Healthy   bool   `json:"healthy"`
+	Error     string `json:"error,omitempty"`
+	LatencyMS int64  `json:"latency_ms"`
+}
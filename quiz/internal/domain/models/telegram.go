@@ -0,0 +1,11 @@
+package models
+
+// TelegramUser is a Telegram account as Telegram vouches for it, in the
+// init data of the Web App or the data of the Login Widget
+type TelegramUser struct {
+	ID        int64  `json:"id"`
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name,omitempty"`
+	Username  string `json:"username,omitempty"`
+	PhotoURL  string `json:"photo_url,omitempty"`
+}
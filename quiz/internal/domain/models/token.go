@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// TokenPair is what API clients are signed in with: a short-lived access
+// token and a refresh token to get the next pair with
+type TokenPair struct {
+	AccessToken      string
+	AccessExpiresAt  time.Time
+	RefreshToken     string
+	RefreshExpiresAt time.Time
+}
+
+// AccessClaims are the claims of a verified access token
+type AccessClaims struct {
+	TokenID   string
+	UserID    int64
+	Login     string
+	RoleFlags int
+	ExpiresAt time.Time
+}
+
+// RefreshToken is a refresh token kept on the server. It works once,
+// refreshing replaces it with a new one
+type RefreshToken struct {
+	Token     string    `json:"-"`
+	UserID    int64     `json:"user_id"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
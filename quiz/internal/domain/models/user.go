@@ -1,19 +1,38 @@
 package models
 
-// NOTE: review and compare with telegram/internal/domain/models/user.go
+import (
+	"bsu-quiz/pkg/authz"
+	"strings"
+)
 
+// Role flags are shared with the Telegram bot, see package authz
 const (
-	RoleUser    = 1 // 0001
-	RoleAdmin   = 2 // 0010
-	RoleTeacher = 4 // 0100
-	RoleBlocked = 8 // 1000
+	RoleUser    = authz.RoleUser    // 0001
+	RoleAdmin   = authz.RoleAdmin   // 0010
+	RoleTeacher = authz.RoleTeacher // 0100
+	RoleBlocked = authz.RoleBlocked // 1000
+
+	// RoleAll has every known role flag set
+	RoleAll = RoleUser | RoleAdmin | RoleTeacher | RoleBlocked
 )
 
+// UserSortFields are the fields the user listing can be sorted by
+var UserSortFields = []string{"id", "login", "group"}
+
+// DefaultUserSort orders users by registration
+var DefaultUserSort = Sort{Field: "id", Direction: SortAsc}
+
 type User struct {
 	ID        int64  `json:"id" db:"id"`
 	Login     string `json:"login" db:"login"`
-	Password  string `json:"-" db:"password"` // Not in DB schema but needed for auth
+	Password  string `json:"-" db:"password_hash"` // bcrypt hash, empty if the user has no password
 	RoleFlags int    `json:"role_flags" db:"role_flags"`
+	Group     string `json:"group" db:"academic_group"` // Academic group of a student
+	// TelegramUserID is the Telegram account linked to the login, nil until linked
+	TelegramUserID *int64 `json:"telegram_user_id,omitempty" db:"telegram_user_id"`
+	// TelegramChatID is the private chat of the bot with the user
+	TelegramChatID   *int64 `json:"telegram_chat_id,omitempty" db:"telegram_chat_id"`
+	TelegramUsername string `json:"telegram_username,omitempty" db:"telegram_username"`
 }
 
 // HasRole checks if a user has a specific role
@@ -34,4 +53,38 @@ func (u *User) IsTeacher() bool {
 // IsBlocked checks if a user is blocked
 func (u *User) IsBlocked() bool {
 	return u.HasRole(RoleBlocked)
+}
+
+// Can reports whether the roles of the user grant the permission
+func (u *User) Can(permission authz.Permission) bool {
+	return authz.Can(u.RoleFlags, permission)
+}
+
+// roleNames maps role names used in imports and forms to role flags
+var roleNames = map[string]int{
+	"user":    RoleUser,
+	"admin":   RoleAdmin,
+	"teacher": RoleTeacher,
+	"blocked": RoleBlocked,
+}
+
+// roleOrder lists role names in the order of their flags
+var roleOrder = []string{"user", "admin", "teacher", "blocked"}
+
+// RoleNames returns the names of the roles in the flags, e.g. for token claims
+func RoleNames(flags int) []string {
+	names := make([]string, 0, len(roleOrder))
+	for _, name := range roleOrder {
+		if flags&roleNames[name] != 0 {
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
+
+// ParseRole returns the flag of the role with the given name
+func ParseRole(name string) (int, bool) {
+	role, ok := roleNames[strings.ToLower(strings.TrimSpace(name))]
+	return role, ok
 }
\ No newline at end of file
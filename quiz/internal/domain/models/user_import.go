@@ -0,0 +1,45 @@
+package models
+
+// UserImportRow is a row of the student import with its line in the file
+type UserImportRow struct {
+	Line      int    `json:"line"`
+	ID        int64  `json:"id,omitempty"` // Set for created users
+	Login     string `json:"login"`
+	Group     string `json:"group,omitempty"`
+	RoleFlags int    `json:"role_flags"`
+	Reason    string `json:"reason,omitempty"` // Why the row was skipped
+}
+
+// UserImportReport lists created users and skipped rows of an import
+type UserImportReport struct {
+	Created    []UserImportRow `json:"created"`
+	Duplicates []UserImportRow `json:"duplicates"`
+	Invalid    []UserImportRow `json:"invalid"`
+}
+
+// BulkUserAction is an action applied to several users at once
+type BulkUserAction string
+
+const (
+	BulkAssignRole BulkUserAction = "assign_role"
+	BulkRemoveRole BulkUserAction = "remove_role"
+	BulkBlock      BulkUserAction = "block"
+	BulkUnblock    BulkUserAction = "unblock"
+)
+
+// RoleChange returns the flags the action sets and clears, role is used
+// by assign_role and remove_role. ok is false for unknown actions
+func (a BulkUserAction) RoleChange(role int) (set, clear int, ok bool) {
+	switch a {
+	case BulkAssignRole:
+		return role, 0, true
+	case BulkRemoveRole:
+		return 0, role, true
+	case BulkBlock:
+		return RoleBlocked, 0, true
+	case BulkUnblock:
+		return 0, RoleBlocked, true
+	}
+
+	return 0, 0, false
+}
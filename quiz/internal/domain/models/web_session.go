@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// WebSession is a browser signed in to the admin panel, the session cookie
+// holds its token and the session itself is kept on the server
+type WebSession struct {
+	Token     string    `json:"-"`
+	UserID    int64     `json:"user_id"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
@@ -0,0 +1,110 @@
+package rules
+
+import (
+	"bsu-quiz/quiz/internal/domain/models"
+	"bsu-quiz/quiz/internal/ports"
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// The fakes keep their data in maps and implement the methods the policy
+// calls, the embedded interfaces panic on any other call
+
+type fakeUserRepo struct {
+	ports.UserRepositorier
+	users map[int64]*models.User
+}
+
+func (r *fakeUserRepo) GetByID(_ context.Context, id int64) (*models.User, error) {
+	user, ok := r.users[id]
+	if !ok {
+		return nil, nil
+	}
+
+	copied := *user
+	return &copied, nil
+}
+
+type fakeQuizRepo struct {
+	ports.QuizRepositorier
+	quizzes map[uuid.UUID]*models.Quiz
+	deleted map[uuid.UUID]*models.Quiz
+	// questions and options map their ids to the id of their quiz
+	questions map[uuid.UUID]uuid.UUID
+	options   map[uuid.UUID]uuid.UUID
+	// loaded counts the quizzes loaded with their questions
+	loaded int
+}
+
+func (r *fakeQuizRepo) GetByID(_ context.Context, id uuid.UUID) (*models.Quiz, error) {
+	r.loaded++
+	return copyQuiz(r.quizzes[id]), nil
+}
+
+// GetHeader drops the questions like the repository does
+func (r *fakeQuizRepo) GetHeader(_ context.Context, id uuid.UUID) (*models.Quiz, error) {
+	quiz := copyQuiz(r.quizzes[id])
+	if quiz != nil {
+		quiz.Questions = nil
+	}
+	return quiz, nil
+}
+
+func (r *fakeQuizRepo) GetDeleted(_ context.Context, id uuid.UUID) (*models.Quiz, error) {
+	return copyQuiz(r.deleted[id]), nil
+}
+
+func (r *fakeQuizRepo) GetQuestionQuizID(_ context.Context, questionID uuid.UUID) (uuid.UUID, error) {
+	return r.questions[questionID], nil
+}
+
+func (r *fakeQuizRepo) GetOptionQuizID(_ context.Context, optionID uuid.UUID) (uuid.UUID, error) {
+	return r.options[optionID], nil
+}
+
+func copyQuiz(quiz *models.Quiz) *models.Quiz {
+	if quiz == nil {
+		return nil
+	}
+
+	copied := *quiz
+	return &copied
+}
+
+type fakeSessionRepo struct {
+	ports.SessionRepositorier
+	sessions map[uuid.UUID]*models.GameSession
+	deleted  map[uuid.UUID]*models.GameSession
+}
+
+func (r *fakeSessionRepo) GetByID(_ context.Context, id uuid.UUID) (*models.GameSession, error) {
+	return copySession(r.sessions[id]), nil
+}
+
+func (r *fakeSessionRepo) GetDeleted(_ context.Context, id uuid.UUID) (*models.GameSession, error) {
+	return copySession(r.deleted[id]), nil
+}
+
+func copySession(session *models.GameSession) *models.GameSession {
+	if session == nil {
+		return nil
+	}
+
+	copied := *session
+	return &copied
+}
+
+type collaboratorKey struct {
+	quizID uuid.UUID
+	userID int64
+}
+
+type fakeCollaboratorRepo struct {
+	ports.CollaboratorRepositorier
+	roles map[collaboratorKey]models.QuizRole
+}
+
+func (r *fakeCollaboratorRepo) GetRole(_ context.Context, quizID uuid.UUID, userID int64) (models.QuizRole, error) {
+	return r.roles[collaboratorKey{quizID, userID}], nil
+}
@@ -0,0 +1,305 @@
+package rules
+
+import (
+	"bsu-quiz/pkg/authz"
+	"bsu-quiz/quiz/internal/domain/errors"
+	"bsu-quiz/quiz/internal/domain/models"
+	"bsu-quiz/quiz/internal/ports"
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// ErrForbidden is returned when the user isn't allowed to perform an action
+var ErrForbidden = errors.ErrForbidden
+
+// Action is something a user wants to do with a quiz or a resource inside it
+type Action string
+
+const (
+	ActionView   Action = "view"
+	ActionEdit   Action = "edit"
+	ActionHost   Action = "host"
+	ActionManage Action = "manage"
+)
+
+// ForbiddenError describes a denied action, errors.Is(err, ErrForbidden) reports true for it
+type ForbiddenError struct {
+	UserID     int64
+	Action     Action
+	Resource   string
+	ResourceID string
+}
+
+func (e *ForbiddenError) Error() string {
+	return fmt.Sprintf("you don't have permission to %s this %s", e.Action, e.Resource)
+}
+
+func (e *ForbiddenError) Unwrap() error {
+	return ErrForbidden
+}
+
+// PermissionError describes a missing permission, errors.Is(err, ErrForbidden) reports true for it
+type PermissionError struct {
+	UserID     int64
+	Permission authz.Permission
+}
+
+func (e *PermissionError) Error() string {
+	return fmt.Sprintf("you don't have the %s permission", e.Permission)
+}
+
+func (e *PermissionError) Unwrap() error {
+	return ErrForbidden
+}
+
+// Policy resolves a resource to its quiz and decides whether the user may
+// perform an action on it: moderators (admins) may do anything, otherwise the
+// decision is made by the user's role in the quiz (owner, editor, viewer).
+// Actions outside of quizzes are checked against permissions with Require
+type Policy struct {
+	quizRepo    ports.QuizRepositorier
+	userRepo    ports.UserRepositorier
+	collabRepo  ports.CollaboratorRepositorier
+	sessionRepo ports.SessionRepositorier
+}
+
+func NewPolicy(
+	quizRepo ports.QuizRepositorier,
+	userRepo ports.UserRepositorier,
+	collabRepo ports.CollaboratorRepositorier,
+	sessionRepo ports.SessionRepositorier,
+) *Policy {
+	return &Policy{
+		quizRepo:    quizRepo,
+		userRepo:    userRepo,
+		collabRepo:  collabRepo,
+		sessionRepo: sessionRepo,
+	}
+}
+
+// Require checks the roles of the user grant the permission, see package authz
+func (p *Policy) Require(ctx context.Context, userID int64, permission authz.Permission) error {
+	user, err := p.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if user == nil || !user.Can(permission) {
+		return &PermissionError{UserID: userID, Permission: permission}
+	}
+
+	return nil
+}
+
+// QuizRole returns the role of the user in the quiz.
+// The creator of the quiz is always an owner
+func (p *Policy) QuizRole(ctx context.Context, quiz *models.Quiz, userID int64) (models.QuizRole, error) {
+	if quiz.UserID == userID {
+		return models.QuizRoleOwner, nil
+	}
+
+	return p.collabRepo.GetRole(ctx, quiz.ID, userID)
+}
+
+// AuthorizeQuiz checks the action on the quiz and returns the quiz
+func (p *Policy) AuthorizeQuiz(ctx context.Context, userID int64, quizID uuid.UUID, action Action) (*models.Quiz, error) {
+	quiz, err := p.quizRepo.GetByID(ctx, quizID)
+	if err != nil {
+		return nil, err
+	}
+
+	if quiz == nil {
+		return nil, errors.NotFound("quiz")
+	}
+
+	if err := p.authorize(ctx, userID, quiz, action, "quiz", quizID.String()); err != nil {
+		return nil, err
+	}
+
+	return quiz, nil
+}
+
+// AuthorizeQuestion checks the action on the quiz the question belongs to
+func (p *Policy) AuthorizeQuestion(ctx context.Context, userID int64, questionID uuid.UUID, action Action) error {
+	quizID, err := p.quizRepo.GetQuestionQuizID(ctx, questionID)
+	if err != nil {
+		return err
+	}
+
+	if quizID == uuid.Nil {
+		return errors.NotFound("question")
+	}
+
+	return p.authorizeInQuiz(ctx, userID, quizID, action, "question", questionID.String())
+}
+
+// AuthorizeOption checks the action on the quiz the option belongs to
+func (p *Policy) AuthorizeOption(ctx context.Context, userID int64, optionID uuid.UUID, action Action) error {
+	quizID, err := p.quizRepo.GetOptionQuizID(ctx, optionID)
+	if err != nil {
+		return err
+	}
+
+	if quizID == uuid.Nil {
+		return errors.NotFound("option")
+	}
+
+	return p.authorizeInQuiz(ctx, userID, quizID, action, "option", optionID.String())
+}
+
+// AuthorizeSession checks the action on the session and returns the session
+// with its quiz. The host may do anything with their own session,
+// others are checked against the quiz
+func (p *Policy) AuthorizeSession(ctx context.Context, userID int64, sessionID uuid.UUID, action Action) (*models.GameSession, error) {
+	session, err := p.sessionRepo.GetByID(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if session == nil {
+		return nil, errors.NotFound("session")
+	}
+
+	quiz, err := p.quizRepo.GetByID(ctx, session.QuizID)
+	if err != nil {
+		return nil, err
+	}
+
+	if quiz == nil {
+		return nil, errors.NotFound("quiz")
+	}
+	session.Quiz = quiz
+
+	if err := p.authorizeSession(ctx, userID, session, quiz, action); err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
+
+// AuthorizeDeletedQuiz checks the action on a quiz in the trash and returns the quiz
+func (p *Policy) AuthorizeDeletedQuiz(ctx context.Context, userID int64, quizID uuid.UUID, action Action) (*models.Quiz, error) {
+	quiz, err := p.quizRepo.GetDeleted(ctx, quizID)
+	if err != nil {
+		return nil, err
+	}
+
+	if quiz == nil {
+		return nil, errors.NotFound("deleted quiz")
+	}
+
+	if err := p.authorize(ctx, userID, quiz, action, "quiz", quizID.String()); err != nil {
+		return nil, err
+	}
+
+	return quiz, nil
+}
+
+// AuthorizeDeletedSession checks the action on a session in the trash and
+// returns the session. The quiz of the session must not be deleted
+func (p *Policy) AuthorizeDeletedSession(ctx context.Context, userID int64, sessionID uuid.UUID, action Action) (*models.GameSession, error) {
+	session, err := p.sessionRepo.GetDeleted(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if session == nil {
+		return nil, errors.NotFound("deleted session")
+	}
+
+	quiz, err := p.quizRepo.GetByID(ctx, session.QuizID)
+	if err != nil {
+		return nil, err
+	}
+
+	if quiz == nil {
+		return nil, errors.Conflict("quiz of the session is deleted, restore the quiz first")
+	}
+	session.Quiz = quiz
+
+	if err := p.authorizeSession(ctx, userID, session, quiz, action); err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
+
+// authorizeInQuiz checks the action on a resource inside the quiz. Only
+// the owner of the quiz is needed, so its questions aren't loaded
+func (p *Policy) authorizeInQuiz(ctx context.Context, userID int64, quizID uuid.UUID, action Action, resource, resourceID string) error {
+	quiz, err := p.quizRepo.GetHeader(ctx, quizID)
+	if err != nil {
+		return err
+	}
+
+	if quiz == nil {
+		return errors.NotFound("quiz")
+	}
+
+	return p.authorize(ctx, userID, quiz, action, resource, resourceID)
+}
+
+// authorizeSession lets the host do anything with their own session
+// and checks others against the quiz
+func (p *Policy) authorizeSession(ctx context.Context, userID int64, session *models.GameSession, quiz *models.Quiz, action Action) error {
+	user, err := p.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if session.HostID == userID && user != nil && !user.IsBlocked() {
+		return nil
+	}
+
+	return p.authorize(ctx, userID, quiz, action, "session", session.ID.String())
+}
+
+func (p *Policy) authorize(ctx context.Context, userID int64, quiz *models.Quiz, action Action, resource, resourceID string) error {
+	forbidden := &ForbiddenError{
+		UserID:     userID,
+		Action:     action,
+		Resource:   resource,
+		ResourceID: resourceID,
+	}
+
+	user, err := p.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if user == nil || user.IsBlocked() {
+		return forbidden
+	}
+
+	if user.Can(authz.QuizModerate) {
+		return nil
+	}
+
+	role, err := p.QuizRole(ctx, quiz, userID)
+	if err != nil {
+		return err
+	}
+
+	if !allows(role, action) {
+		return forbidden
+	}
+
+	return nil
+}
+
+func allows(role models.QuizRole, action Action) bool {
+	switch action {
+	case ActionView:
+		return role.CanView()
+	case ActionEdit:
+		return role.CanEdit()
+	case ActionHost:
+		return role.CanHost()
+	case ActionManage:
+		return role.CanManage()
+	}
+
+	return false
+}
@@ -0,0 +1,330 @@
+package rules
+
+import (
+	"bsu-quiz/pkg/authz"
+	"bsu-quiz/quiz/internal/domain/errors"
+	"bsu-quiz/quiz/internal/domain/models"
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// Users of the policy tests
+const (
+	policyOwner int64 = iota + 1
+	policyEditor
+	policyViewer
+	policyAdmin
+	policyBlockedAdmin
+	policyTeacher
+	policyStudent
+	// policyBlockedHost hosts the session but is blocked, with no role in the quiz
+	policyBlockedHost
+)
+
+// policyFixture is a quiz with a question, an option, a session and their
+// deleted counterparts
+type policyFixture struct {
+	policy  *Policy
+	quizzes *fakeQuizRepo
+
+	quiz           *models.Quiz
+	deletedQuiz    *models.Quiz
+	questionID     uuid.UUID
+	optionID       uuid.UUID
+	session        *models.GameSession
+	blockedSession *models.GameSession
+	deletedSession *models.GameSession
+	// orphanSession is in the trash with its quiz
+	orphanSession *models.GameSession
+}
+
+func newPolicyFixture() *policyFixture {
+	quiz := &models.Quiz{ID: uuid.New(), UserID: policyOwner}
+	deletedQuiz := &models.Quiz{ID: uuid.New(), UserID: policyOwner}
+
+	f := &policyFixture{
+		quiz:           quiz,
+		deletedQuiz:    deletedQuiz,
+		questionID:     uuid.New(),
+		optionID:       uuid.New(),
+		session:        &models.GameSession{ID: uuid.New(), QuizID: quiz.ID, HostID: policyTeacher},
+		blockedSession: &models.GameSession{ID: uuid.New(), QuizID: quiz.ID, HostID: policyBlockedHost},
+		deletedSession: &models.GameSession{ID: uuid.New(), QuizID: quiz.ID, HostID: policyTeacher},
+		orphanSession:  &models.GameSession{ID: uuid.New(), QuizID: deletedQuiz.ID, HostID: policyTeacher},
+	}
+
+	users := &fakeUserRepo{users: map[int64]*models.User{
+		policyOwner:        {ID: policyOwner, RoleFlags: models.RoleUser | models.RoleTeacher},
+		policyEditor:       {ID: policyEditor, RoleFlags: models.RoleUser | models.RoleTeacher},
+		policyViewer:       {ID: policyViewer, RoleFlags: models.RoleUser | models.RoleTeacher},
+		policyAdmin:        {ID: policyAdmin, RoleFlags: models.RoleUser | models.RoleAdmin},
+		policyBlockedAdmin: {ID: policyBlockedAdmin, RoleFlags: models.RoleAdmin | models.RoleBlocked},
+		policyTeacher:      {ID: policyTeacher, RoleFlags: models.RoleUser | models.RoleTeacher},
+		policyStudent:      {ID: policyStudent, RoleFlags: models.RoleUser},
+		policyBlockedHost:  {ID: policyBlockedHost, RoleFlags: models.RoleTeacher | models.RoleBlocked},
+	}}
+	quizzes := &fakeQuizRepo{
+		quizzes:   map[uuid.UUID]*models.Quiz{quiz.ID: quiz},
+		deleted:   map[uuid.UUID]*models.Quiz{deletedQuiz.ID: deletedQuiz},
+		questions: map[uuid.UUID]uuid.UUID{f.questionID: quiz.ID},
+		options:   map[uuid.UUID]uuid.UUID{f.optionID: quiz.ID},
+	}
+	sessions := &fakeSessionRepo{
+		sessions: map[uuid.UUID]*models.GameSession{
+			f.session.ID:        f.session,
+			f.blockedSession.ID: f.blockedSession,
+		},
+		deleted: map[uuid.UUID]*models.GameSession{
+			f.deletedSession.ID: f.deletedSession,
+			f.orphanSession.ID:  f.orphanSession,
+		},
+	}
+	collaborators := &fakeCollaboratorRepo{roles: map[collaboratorKey]models.QuizRole{
+		{quiz.ID, policyEditor}:        models.QuizRoleEditor,
+		{quiz.ID, policyViewer}:        models.QuizRoleViewer,
+		{deletedQuiz.ID, policyEditor}: models.QuizRoleEditor,
+	}}
+
+	f.policy = NewPolicy(quizzes, users, collaborators, sessions)
+	f.quizzes = quizzes
+
+	return f
+}
+
+func TestAuthorizeQuiz(t *testing.T) {
+	// allowed lists the actions each user may perform on the quiz
+	allowed := map[int64][]Action{
+		policyOwner:        {ActionView, ActionEdit, ActionHost, ActionManage},
+		policyEditor:       {ActionView, ActionEdit, ActionHost},
+		policyViewer:       {ActionView},
+		policyAdmin:        {ActionView, ActionEdit, ActionHost, ActionManage},
+		policyBlockedAdmin: {},
+		policyTeacher:      {},
+		policyStudent:      {},
+		100:                {},
+	}
+	actions := []Action{ActionView, ActionEdit, ActionHost, ActionManage}
+
+	f := newPolicyFixture()
+	for userID, userActions := range allowed {
+		for _, action := range actions {
+			want := false
+			for _, a := range userActions {
+				want = want || a == action
+			}
+
+			quiz, err := f.policy.AuthorizeQuiz(context.Background(), userID, f.quiz.ID, action)
+
+			if !want {
+				if !errors.Is(err, ErrForbidden) {
+					t.Errorf("user %d %s: got error %v, want forbidden", userID, action, err)
+				}
+				continue
+			}
+
+			if err != nil {
+				t.Errorf("user %d %s: unexpected error: %v", userID, action, err)
+			} else if quiz.ID != f.quiz.ID {
+				t.Errorf("user %d %s: got quiz %v", userID, action, quiz.ID)
+			}
+		}
+	}
+}
+
+func TestAllowsUnknownAction(t *testing.T) {
+	roles := []models.QuizRole{models.QuizRoleOwner, models.QuizRoleEditor, models.QuizRoleViewer, models.QuizRoleNone}
+	for _, role := range roles {
+		if allows(role, Action("publish")) {
+			t.Errorf("role %q is allowed an unknown action", role)
+		}
+	}
+}
+
+func TestAuthorizeNotFound(t *testing.T) {
+	f := newPolicyFixture()
+	ctx := context.Background()
+	unknown := uuid.New()
+
+	checks := map[string]func() error{
+		"quiz": func() error {
+			_, err := f.policy.AuthorizeQuiz(ctx, policyAdmin, unknown, ActionView)
+			return err
+		},
+		"deleted quiz in the listing": func() error {
+			_, err := f.policy.AuthorizeQuiz(ctx, policyAdmin, f.deletedQuiz.ID, ActionView)
+			return err
+		},
+		"question": func() error {
+			return f.policy.AuthorizeQuestion(ctx, policyAdmin, unknown, ActionView)
+		},
+		"option": func() error {
+			return f.policy.AuthorizeOption(ctx, policyAdmin, unknown, ActionView)
+		},
+		"session": func() error {
+			_, err := f.policy.AuthorizeSession(ctx, policyAdmin, unknown, ActionView)
+			return err
+		},
+		"deleted session": func() error {
+			_, err := f.policy.AuthorizeDeletedSession(ctx, policyAdmin, f.session.ID, ActionView)
+			return err
+		},
+	}
+
+	for name, check := range checks {
+		if err := check(); !errors.Is(err, errors.ErrNotFound) {
+			t.Errorf("%s: got error %v, want not found", name, err)
+		}
+	}
+}
+
+func TestAuthorizeQuestionAndOption(t *testing.T) {
+	f := newPolicyFixture()
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		userID  int64
+		action  Action
+		allowed bool
+	}{
+		{name: "editor edits", userID: policyEditor, action: ActionEdit, allowed: true},
+		{name: "viewer views", userID: policyViewer, action: ActionView, allowed: true},
+		{name: "viewer edits", userID: policyViewer, action: ActionEdit},
+		{name: "outsider views", userID: policyStudent, action: ActionView},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := map[string]error{
+				"question": f.policy.AuthorizeQuestion(ctx, tt.userID, f.questionID, tt.action),
+				"option":   f.policy.AuthorizeOption(ctx, tt.userID, f.optionID, tt.action),
+			}
+
+			for resource, err := range errs {
+				if tt.allowed && err != nil {
+					t.Errorf("%s: unexpected error: %v", resource, err)
+				}
+				if !tt.allowed {
+					var forbidden *ForbiddenError
+					if !errors.As(err, &forbidden) || forbidden.Resource != resource {
+						t.Errorf("%s: got error %v, want forbidden on the %s", resource, err, resource)
+					}
+				}
+			}
+		})
+	}
+
+	// Only the owner is needed, the questions of the quiz aren't loaded
+	if f.quizzes.loaded != 0 {
+		t.Errorf("the quiz was loaded with its questions %d times", f.quizzes.loaded)
+	}
+}
+
+func TestAuthorizeSession(t *testing.T) {
+	f := newPolicyFixture()
+	ctx := context.Background()
+
+	tests := []struct {
+		name      string
+		userID    int64
+		sessionID uuid.UUID
+		action    Action
+		allowed   bool
+	}{
+		{name: "host manages their session", userID: policyTeacher, sessionID: f.session.ID, action: ActionManage, allowed: true},
+		{name: "owner of the quiz manages", userID: policyOwner, sessionID: f.session.ID, action: ActionManage, allowed: true},
+		{name: "viewer views", userID: policyViewer, sessionID: f.session.ID, action: ActionView, allowed: true},
+		{name: "viewer hosts", userID: policyViewer, sessionID: f.session.ID, action: ActionHost},
+		{name: "admin manages", userID: policyAdmin, sessionID: f.session.ID, action: ActionManage, allowed: true},
+		{name: "student views", userID: policyStudent, sessionID: f.session.ID, action: ActionView},
+		{name: "blocked host views", userID: policyBlockedHost, sessionID: f.blockedSession.ID, action: ActionView},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session, err := f.policy.AuthorizeSession(ctx, tt.userID, tt.sessionID, tt.action)
+
+			if !tt.allowed {
+				if !errors.Is(err, ErrForbidden) {
+					t.Fatalf("got error %v, want forbidden", err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if session.Quiz == nil || session.Quiz.ID != f.quiz.ID {
+				t.Errorf("session doesn't carry its quiz")
+			}
+		})
+	}
+}
+
+func TestAuthorizeDeleted(t *testing.T) {
+	f := newPolicyFixture()
+	ctx := context.Background()
+
+	quiz, err := f.policy.AuthorizeDeletedQuiz(ctx, policyOwner, f.deletedQuiz.ID, ActionManage)
+	if err != nil || quiz.ID != f.deletedQuiz.ID {
+		t.Errorf("owner: got %v, %v, want the deleted quiz", quiz, err)
+	}
+	if _, err := f.policy.AuthorizeDeletedQuiz(ctx, policyEditor, f.deletedQuiz.ID, ActionManage); !errors.Is(err, ErrForbidden) {
+		t.Errorf("editor: got error %v, want forbidden", err)
+	}
+	if _, err := f.policy.AuthorizeDeletedQuiz(ctx, policyOwner, f.quiz.ID, ActionManage); !errors.Is(err, errors.ErrNotFound) {
+		t.Errorf("quiz outside the trash: got error %v, want not found", err)
+	}
+
+	session, err := f.policy.AuthorizeDeletedSession(ctx, policyTeacher, f.deletedSession.ID, ActionManage)
+	if err != nil || session.Quiz == nil {
+		t.Errorf("host: got %v, %v, want the deleted session with its quiz", session, err)
+	}
+	if _, err := f.policy.AuthorizeDeletedSession(ctx, policyStudent, f.deletedSession.ID, ActionManage); !errors.Is(err, ErrForbidden) {
+		t.Errorf("student: got error %v, want forbidden", err)
+	}
+
+	// The quiz has to be restored before its sessions
+	if _, err := f.policy.AuthorizeDeletedSession(ctx, policyTeacher, f.orphanSession.ID, ActionManage); !errors.Is(err, errors.ErrConflict) {
+		t.Errorf("session of a deleted quiz: got error %v, want a conflict", err)
+	}
+}
+
+func TestRequire(t *testing.T) {
+	f := newPolicyFixture()
+	ctx := context.Background()
+
+	tests := []struct {
+		name       string
+		userID     int64
+		permission authz.Permission
+		allowed    bool
+	}{
+		{name: "admin manages users", userID: policyAdmin, permission: authz.UserManage, allowed: true},
+		{name: "teacher creates quizzes", userID: policyTeacher, permission: authz.QuizCreate, allowed: true},
+		{name: "teacher manages users", userID: policyTeacher, permission: authz.UserManage},
+		{name: "student joins", userID: policyStudent, permission: authz.SessionJoin, allowed: true},
+		{name: "blocked admin", userID: policyBlockedAdmin, permission: authz.UserManage},
+		{name: "unknown user", userID: 100, permission: authz.SessionJoin},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := f.policy.Require(ctx, tt.userID, tt.permission)
+
+			if tt.allowed {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+
+			var permissionErr *PermissionError
+			if !errors.As(err, &permissionErr) || permissionErr.Permission != tt.permission || !errors.Is(err, ErrForbidden) {
+				t.Fatalf("got error %v, want the missing %s permission", err, tt.permission)
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package health
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
+)
+
+// Checker checks that a dependency of the app is reachable
+type Checker interface {
+	Name() string
+	// Critical reports whether the app can't work without the dependency
+	Critical() bool
+	Check(ctx context.Context) error
+}
+
+// Result is the outcome of a single check
+type Result struct {
+	Name     string        `json:"name"`
+	Healthy  bool          `json:"healthy"`
+	Critical bool          `json:"critical"`
+	Error    string        `json:"error,omitempty"`
+	Latency  time.Duration `json:"latency"`
+}
+
+// Run runs every checker, each one is limited by timeout
+func Run(ctx context.Context, timeout time.Duration, checkers ...Checker) []Result {
+	results := make([]Result, 0, len(checkers))
+
+	for _, checker := range checkers {
+		checkCtx, cancel := context.WithTimeout(ctx, timeout)
+		start := time.Now()
+		err := checker.Check(checkCtx)
+		cancel()
+
+		result := Result{
+			Name:     checker.Name(),
+			Healthy:  err == nil,
+			Critical: checker.Critical(),
+			Latency:  time.Since(start),
+		}
+		if err != nil {
+			result.Error = err.Error()
+		}
+		results = append(results, result)
+	}
+
+	return results
+}
+
+// PostgresName is the name of the Postgres check
+const PostgresName = "postgres"
+
+// PostgresChecker pings the connection pool
+type PostgresChecker struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresChecker(pool *pgxpool.Pool) *PostgresChecker {
+	return &PostgresChecker{pool: pool}
+}
+
+func (c *PostgresChecker) Name() string {
+	return PostgresName
+}
+
+func (c *PostgresChecker) Critical() bool {
+	return true
+}
+
+func (c *PostgresChecker) Check(ctx context.Context) error {
+	return c.pool.Ping(ctx)
+}
+
+// RedisChecker pings the Redis server. It's critical: sessions of the panel,
+// impersonation and refresh tokens live in Redis, nobody can sign in without it
+type RedisChecker struct {
+	client *redis.Client
+}
+
+func NewRedisChecker(client *redis.Client) *RedisChecker {
+	return &RedisChecker{client: client}
+}
+
+func (c *RedisChecker) Name() string {
+	return "redis"
+}
+
+func (c *RedisChecker) Critical() bool {
+	return true
+}
+
+func (c *RedisChecker) Check(ctx context.Context) error {
+	return c.client.Ping(ctx).Err()
+}
@@ -0,0 +1,58 @@
+package health
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// hangingChecker blocks until its context is done
+type hangingChecker struct{}
+
+func (hangingChecker) Name() string {
+	return "hanging"
+}
+
+func (hangingChecker) Critical() bool {
+	return false
+}
+
+func (hangingChecker) Check(ctx context.Context) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+type upChecker struct{}
+
+func (upChecker) Name() string {
+	return "up"
+}
+
+func (upChecker) Critical() bool {
+	return true
+}
+
+func (upChecker) Check(_ context.Context) error {
+	return nil
+}
+
+func TestRunTimeout(t *testing.T) {
+	start := time.Now()
+	results := Run(context.Background(), 10*time.Millisecond, hangingChecker{}, upChecker{})
+
+	// Each check gets its own timeout, a hanging one doesn't stop the rest
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("the checks took %v", elapsed)
+	}
+	if len(results) != 2 {
+		t.Fatalf("got %d results, want 2", len(results))
+	}
+
+	hanging, up := results[0], results[1]
+	if hanging.Healthy || hanging.Error == "" || hanging.Critical {
+		t.Errorf("got %+v for the hanging check", hanging)
+	}
+	if !up.Healthy || up.Error != "" || !up.Critical {
+		t.Errorf("got %+v for the working check", up)
+	}
+}
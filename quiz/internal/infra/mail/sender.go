@@ -0,0 +1,49 @@
+// Package mail sends emails to BSU mailboxes with the SMTP server of
+// EmailConfig, the address of a user is their login at the BSU domain
+package mail
+
+import (
+	"bsu-quiz/quiz/config"
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"html/template"
+
+	"gopkg.in/gomail.v2"
+)
+
+type Sender struct {
+	cfg       config.EmailConfig
+	templates *template.Template
+}
+
+func NewSender(cfg config.EmailConfig, templates *template.Template) *Sender {
+	return &Sender{
+		cfg:       cfg,
+		templates: templates,
+	}
+}
+
+func (s *Sender) Send(ctx context.Context, login, subject, name string, data map[string]any) error {
+	var body bytes.Buffer
+	if err := s.templates.ExecuteTemplate(&body, name, data); err != nil {
+		return fmt.Errorf("failed to render email %s: %w", name, err)
+	}
+
+	m := gomail.NewMessage()
+	m.SetHeader("From", m.FormatAddress(s.cfg.FromEmail, s.cfg.FromName))
+	m.SetHeader("To", fmt.Sprintf("%s@%s", login, s.cfg.Domain))
+	m.SetHeader("Subject", subject)
+	m.SetBody("text/html", body.String())
+
+	dialer := gomail.NewDialer(s.cfg.Host, s.cfg.Port, s.cfg.Username, s.cfg.Password)
+	dialer.TLSConfig = &tls.Config{ServerName: s.cfg.Host}
+
+	// gomail doesn't take a context, at least don't dial for a request that's gone
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	return dialer.DialAndSend(m)
+}
@@ -0,0 +1,597 @@
+package markup
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/png"
+	"io"
+	"math"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/go-fonts/dejavu/dejavumathtexgyre"
+	"github.com/go-fonts/dejavu/dejavuserif"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/font/sfnt"
+	"golang.org/x/image/math/fixed"
+	"golang.org/x/image/vector"
+)
+
+var (
+	ErrFormulaTooLong  = errors.New("formula is too long")
+	ErrFormulaTooLarge = errors.New("formula image is too large")
+)
+
+const (
+	// maxFormulaLength limits the TeX of a formula drawn as an image, in bytes
+	maxFormulaLength = 2000
+	// maxImageSide limits the width and the height of a formula image, in pixels
+	maxImageSide = 2048
+	// formulaFontSize is the font size of formula images, in pixels
+	formulaFontSize = 24
+	// scriptScale is how much smaller scripts and inline fractions are set
+	scriptScale = 0.7
+	minFontSize = 8
+)
+
+// spacedOperators get a medium space on both sides outside of scripts
+const spacedOperators = "+−=<>±∓×÷·≤≥≠≈≡∼∝→←⇒⇐⇔↦∈∉⊂⊆∪∩∧∨⊥∥∘"
+
+// largeSymbols are set bigger in display mode
+const largeSymbols = "∑∏∫∬∮"
+
+// formulaFonts are DejaVu Math TeX Gyre and DejaVu Serif for the letters
+// it lacks, such as Cyrillic in \text
+var formulaFonts = sync.OnceValues(func() ([]*sfnt.Font, error) {
+	var fonts []*sfnt.Font
+	for _, ttf := range [][]byte{dejavumathtexgyre.TTF, dejavuserif.TTF} {
+		f, err := opentype.Parse(ttf)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse formula font: %w", err)
+		}
+		fonts = append(fonts, f)
+	}
+
+	return fonts, nil
+})
+
+// FormulaImage draws a TeX formula as a PNG image on a white background,
+// for clients without MathML support such as the Telegram bot. The formula
+// is converted with LatexToMathML and is drawn from the MathML
+func FormulaImage(tex string, display bool) ([]byte, error) {
+	if len(tex) > maxFormulaLength {
+		return nil, ErrFormulaTooLong
+	}
+
+	mathml, err := LatexToMathML(tex, display)
+	if err != nil {
+		return nil, err
+	}
+
+	root, err := parseMathML(mathml)
+	if err != nil {
+		return nil, err
+	}
+
+	fonts, err := formulaFonts()
+	if err != nil {
+		return nil, err
+	}
+
+	p := &formulaPainter{fonts: fonts, faces: make(map[faceKey]font.Face)}
+	b := p.layout(root, mathStyle{size: formulaFontSize, display: display})
+	if p.err != nil {
+		return nil, p.err
+	}
+
+	pad := formulaFontSize * 0.25
+	ascent, descent := math.Max(b.ascent, 0), math.Max(b.descent, 0)
+	width := int(math.Ceil(b.width + 2*pad))
+	height := int(math.Ceil(ascent + descent + 2*pad))
+	if width > maxImageSide || height > maxImageSide {
+		return nil, ErrFormulaTooLarge
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(dst, dst.Bounds(), image.White, image.Point{}, draw.Src)
+	b.paint(dst, pad, pad+ascent)
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, dst); err != nil {
+		return nil, fmt.Errorf("failed to encode formula image: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// mathNode is an element of the MathML LatexToMathML produces
+type mathNode struct {
+	name     string
+	attrs    map[string]string
+	text     string
+	children []*mathNode
+}
+
+func (n *mathNode) child(i int) *mathNode {
+	if i >= len(n.children) {
+		return &mathNode{name: "mrow"}
+	}
+
+	return n.children[i]
+}
+
+func parseMathML(src string) (*mathNode, error) {
+	dec := xml.NewDecoder(strings.NewReader(src))
+
+	var root *mathNode
+	var stack []*mathNode
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse MathML: %w", err)
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			n := &mathNode{name: t.Name.Local, attrs: make(map[string]string)}
+			for _, attr := range t.Attr {
+				n.attrs[attr.Name.Local] = attr.Value
+			}
+			if len(stack) == 0 {
+				root = n
+			} else {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, n)
+			}
+			stack = append(stack, n)
+
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+
+		case xml.CharData:
+			if len(stack) > 0 {
+				stack[len(stack)-1].text += string(t)
+			}
+		}
+	}
+
+	if root == nil {
+		return nil, errors.New("failed to parse MathML: no elements")
+	}
+
+	return root, nil
+}
+
+// mathStyle is the font size of a part of a formula and whether it's set
+// in display mode, fractions and scripts are set smaller
+type mathStyle struct {
+	size    float64
+	display bool
+}
+
+func (s mathStyle) script() mathStyle {
+	return mathStyle{size: math.Max(s.size*scriptScale, minFontSize)}
+}
+
+// box is a laid out part of a formula, sizes are in pixels. paint draws it
+// with the left end of its baseline at x, y
+type box struct {
+	width   float64
+	ascent  float64
+	descent float64
+	paint   func(dst *image.RGBA, x, y float64)
+}
+
+func space(width float64) *box {
+	return &box{width: width, paint: func(*image.RGBA, float64, float64) {}}
+}
+
+// hbox sets boxes side by side on a common baseline
+func hbox(boxes ...*box) *box {
+	b := &box{ascent: math.Inf(-1), descent: math.Inf(-1)}
+	offsets := make([]float64, len(boxes))
+	for i, c := range boxes {
+		offsets[i] = b.width
+		b.width += c.width
+		b.ascent = math.Max(b.ascent, c.ascent)
+		b.descent = math.Max(b.descent, c.descent)
+	}
+	if len(boxes) == 0 {
+		b.ascent, b.descent = 0, 0
+	}
+
+	b.paint = func(dst *image.RGBA, x, y float64) {
+		for i, c := range boxes {
+			c.paint(dst, x+offsets[i], y)
+		}
+	}
+
+	return b
+}
+
+type faceKey struct {
+	font int
+	size float64
+}
+
+// formulaPainter lays out one formula, faces aren't safe for concurrent use
+type formulaPainter struct {
+	fonts []*sfnt.Font
+	faces map[faceKey]font.Face
+	buf   sfnt.Buffer
+	err   error
+}
+
+func (p *formulaPainter) face(i int, size float64) font.Face {
+	key := faceKey{font: i, size: size}
+	if face, ok := p.faces[key]; ok {
+		return face
+	}
+
+	face, err := opentype.NewFace(p.fonts[i], &opentype.FaceOptions{
+		Size:    size,
+		DPI:     72,
+		Hinting: font.HintingNone,
+	})
+	if err != nil {
+		if p.err == nil {
+			p.err = fmt.Errorf("failed to load formula font: %w", err)
+		}
+		// The formula is dropped, the layout just has to finish
+		face = basicfont.Face7x13
+	}
+	p.faces[key] = face
+
+	return face
+}
+
+// fontFor returns the first font with a glyph for r
+func (p *formulaPainter) fontFor(r rune) int {
+	for i, f := range p.fonts {
+		if index, err := f.GlyphIndex(&p.buf, r); err == nil && index != 0 {
+			return i
+		}
+	}
+
+	return 0
+}
+
+func (p *formulaPainter) layout(n *mathNode, st mathStyle) *box {
+	switch n.name {
+	case "mi":
+		if utf8.RuneCountInString(n.text) == 1 && n.attrs["mathvariant"] != "normal" {
+			r, _ := utf8.DecodeRuneInString(n.text)
+			return p.text(string(mathItalic(r)), st.size)
+		}
+		// Functions such as \sin are set apart from their argument
+		return hbox(p.text(n.text, st.size), space(st.size*0.17))
+
+	case "mn", "mtext":
+		return p.text(n.text, st.size)
+
+	case "mo":
+		return p.operator(n.text, st)
+
+	case "mfrac":
+		return p.fraction(n.child(0), n.child(1), st)
+
+	case "msqrt":
+		return p.radical(p.row(n.children, st), nil, st)
+
+	case "mroot":
+		index := p.layout(n.child(1), st.script().script())
+		return p.radical(p.layout(n.child(0), st), index, st)
+
+	case "msub", "msup", "msubsup":
+		return p.scripts(n, st)
+
+	case "munder", "mover", "munderover":
+		return p.limits(n, st)
+	}
+
+	return p.row(n.children, st)
+}
+
+func (p *formulaPainter) row(nodes []*mathNode, st mathStyle) *box {
+	boxes := make([]*box, 0, len(nodes))
+	for _, n := range nodes {
+		boxes = append(boxes, p.layout(n, st))
+	}
+
+	return hbox(boxes...)
+}
+
+// text sets s in the first font that has each glyph. The box is as tall as
+// the ink, so its descent is negative for text above the baseline
+func (p *formulaPainter) text(s string, size float64) *box {
+	type glyph struct {
+		face font.Face
+		r    string
+		x    float64
+	}
+
+	var glyphs []glyph
+	b := &box{ascent: math.Inf(-1), descent: math.Inf(-1)}
+	for _, r := range s {
+		face := p.face(p.fontFor(r), size)
+		bounds, advance := font.BoundString(face, string(r))
+		if !bounds.Empty() {
+			b.ascent = math.Max(b.ascent, -fromFixed(bounds.Min.Y))
+			b.descent = math.Max(b.descent, fromFixed(bounds.Max.Y))
+		}
+		glyphs = append(glyphs, glyph{face: face, r: string(r), x: b.width})
+		b.width += fromFixed(advance)
+	}
+	if math.IsInf(b.ascent, -1) {
+		b.ascent, b.descent = 0, 0
+	}
+
+	b.paint = func(dst *image.RGBA, x, y float64) {
+		for _, g := range glyphs {
+			d := font.Drawer{
+				Dst:  dst,
+				Src:  image.Black,
+				Face: g.face,
+				Dot:  fixed.Point26_6{X: toFixed(x + g.x), Y: toFixed(y)},
+			}
+			d.DrawString(g.r)
+		}
+	}
+
+	return b
+}
+
+func (p *formulaPainter) operator(text string, st mathStyle) *box {
+	if text == "-" {
+		text = "−"
+	}
+
+	size := st.size
+	if st.display && strings.Contains(largeSymbols, text) {
+		size *= 1.5
+	}
+	b := p.text(text, size)
+
+	// Scripts are set tight
+	if st.size < formulaFontSize {
+		return b
+	}
+
+	switch {
+	case strings.Contains(spacedOperators, text):
+		return hbox(space(st.size*0.22), b, space(st.size*0.22))
+	case text == "," || text == ";":
+		return hbox(b, space(st.size*0.17))
+	}
+
+	return b
+}
+
+// fraction sets the numerator over the denominator centred on the math axis
+func (p *formulaPainter) fraction(num, den *mathNode, st mathStyle) *box {
+	inner := st.script()
+	if st.display {
+		inner = mathStyle{size: st.size}
+	}
+	n, d := p.layout(num, inner), p.layout(den, inner)
+
+	rule := ruleThickness(st.size)
+	axis, gap, pad := st.size*0.25, st.size*0.15, st.size*0.1
+	width := math.Max(n.width, d.width) + 2*pad
+
+	return &box{
+		width:   width,
+		ascent:  axis + rule/2 + gap + n.ascent + n.descent,
+		descent: d.ascent + d.descent + gap + rule/2 - axis,
+		paint: func(dst *image.RGBA, x, y float64) {
+			line := y - axis
+			n.paint(dst, x+(width-n.width)/2, line-rule/2-gap-n.descent)
+			d.paint(dst, x+(width-d.width)/2, line+rule/2+gap+d.ascent)
+			drawLine(dst, x, line, x+width, line, rule)
+		},
+	}
+}
+
+// radical draws the root sign over body, index is nil for a square root
+func (p *formulaPainter) radical(body, index *box, st mathStyle) *box {
+	rule := ruleThickness(st.size)
+	gap, sign, pad := st.size*0.12, st.size*0.6, st.size*0.1
+	top := math.Max(body.ascent, st.size*0.5) + gap + rule
+	bottom := math.Max(body.descent, 0)
+	height := top + bottom
+
+	// The index sits over the short rising stroke of the sign
+	offset, ascent, indexRaise := 0.0, top, 0.0
+	if index != nil {
+		offset = math.Max(0, index.width-sign*0.35)
+		indexRaise = height*0.55 - bottom
+		ascent = math.Max(top, indexRaise+index.ascent)
+	}
+
+	return &box{
+		width:   offset + sign + body.width + pad,
+		ascent:  ascent,
+		descent: bottom,
+		paint: func(dst *image.RGBA, x, y float64) {
+			if index != nil {
+				index.paint(dst, x+offset+sign*0.35-index.width, y-indexRaise)
+			}
+
+			sx, bar := x+offset, y-top+rule/2
+			drawLine(dst, sx, y+bottom-height*0.4, sx+sign*0.35, y+bottom, rule)
+			drawLine(dst, sx+sign*0.35, y+bottom, sx+sign, bar, rule)
+			drawLine(dst, sx+sign, bar, sx+sign+body.width+pad, bar, rule)
+			body.paint(dst, sx+sign, y)
+		},
+	}
+}
+
+// scripts sets subscripts and superscripts right of the base
+func (p *formulaPainter) scripts(n *mathNode, st mathStyle) *box {
+	base := p.layout(n.child(0), st)
+
+	var sub, sup *box
+	switch n.name {
+	case "msub":
+		sub = p.layout(n.child(1), st.script())
+	case "msup":
+		sup = p.layout(n.child(1), st.script())
+	case "msubsup":
+		sub = p.layout(n.child(1), st.script())
+		sup = p.layout(n.child(2), st.script())
+	}
+
+	b := &box{width: base.width, ascent: base.ascent, descent: base.descent}
+
+	var raise, lower float64
+	if sup != nil {
+		raise = math.Max(base.ascent-st.size*0.25, st.size*0.42)
+		b.ascent = math.Max(b.ascent, raise+sup.ascent)
+	}
+	if sub != nil {
+		lower = math.Max(math.Max(base.descent, 0)+st.size*0.05, st.size*0.2)
+		lower = math.Max(lower, sub.ascent-st.size*0.35)
+		if sup != nil {
+			// Keep a gap between the scripts
+			if clash := (sub.ascent - lower) - (raise - sup.descent) + st.size*0.1; clash > 0 {
+				lower += clash
+			}
+		}
+		b.descent = math.Max(b.descent, lower+sub.descent)
+	}
+
+	scriptWidth := 0.0
+	for _, s := range []*box{sub, sup} {
+		if s != nil {
+			scriptWidth = math.Max(scriptWidth, s.width)
+		}
+	}
+	b.width += scriptWidth + st.size*0.05
+
+	b.paint = func(dst *image.RGBA, x, y float64) {
+		base.paint(dst, x, y)
+		if sup != nil {
+			sup.paint(dst, x+base.width, y-raise)
+		}
+		if sub != nil {
+			sub.paint(dst, x+base.width, y+lower)
+		}
+	}
+
+	return b
+}
+
+// limits sets limits under and over the base centred on it, or an accent
+// over it
+func (p *formulaPainter) limits(n *mathNode, st mathStyle) *box {
+	base := p.layout(n.child(0), st)
+
+	var under, over *box
+	switch n.name {
+	case "munder":
+		under = p.layout(n.child(1), st.script())
+	case "mover":
+		over = p.layout(n.child(1), st.script())
+	case "munderover":
+		under = p.layout(n.child(1), st.script())
+		over = p.layout(n.child(2), st.script())
+	}
+
+	gap := st.size * 0.12
+	if n.attrs["accent"] == "true" {
+		gap = st.size * 0.05
+	}
+
+	b := &box{width: base.width, ascent: base.ascent, descent: base.descent}
+	for _, s := range []*box{under, over} {
+		if s != nil {
+			b.width = math.Max(b.width, s.width)
+		}
+	}
+	if over != nil {
+		b.ascent += gap + over.ascent + over.descent
+	}
+	if under != nil {
+		b.descent += gap + under.ascent + under.descent
+	}
+
+	width := b.width
+	b.paint = func(dst *image.RGBA, x, y float64) {
+		base.paint(dst, x+(width-base.width)/2, y)
+		if over != nil {
+			over.paint(dst, x+(width-over.width)/2, y-base.ascent-gap-over.descent)
+		}
+		if under != nil {
+			under.paint(dst, x+(width-under.width)/2, y+base.descent+gap+under.ascent)
+		}
+	}
+
+	return b
+}
+
+// mathItalic returns the mathematical italic form of Latin and lowercase
+// Greek letters, TeX sets single letter identifiers in italic
+func mathItalic(r rune) rune {
+	switch {
+	case r == 'h':
+		// The italic block has a hole where ℎ was encoded before it
+		return 'ℎ'
+	case r >= 'a' && r <= 'z':
+		return 0x1D44E + r - 'a'
+	case r >= 'A' && r <= 'Z':
+		return 0x1D434 + r - 'A'
+	case r >= 'α' && r <= 'ω':
+		return 0x1D6FC + r - 'α'
+	}
+
+	return r
+}
+
+func ruleThickness(size float64) float64 {
+	return math.Max(1, size/16)
+}
+
+// drawLine draws a straight line of the given width, anti-aliased
+func drawLine(dst *image.RGBA, x0, y0, x1, y1, width float64) {
+	length := math.Hypot(x1-x0, y1-y0)
+	if length == 0 {
+		return
+	}
+	nx, ny := -(y1-y0)/length*width/2, (x1-x0)/length*width/2
+
+	// Rasterise only the bounds of the line
+	bounds := image.Rect(
+		int(math.Floor(math.Min(x0, x1)-width)), int(math.Floor(math.Min(y0, y1)-width)),
+		int(math.Ceil(math.Max(x0, x1)+width)), int(math.Ceil(math.Max(y0, y1)+width)),
+	).Intersect(dst.Bounds())
+	if bounds.Empty() {
+		return
+	}
+
+	ox, oy := float64(bounds.Min.X), float64(bounds.Min.Y)
+	z := vector.NewRasterizer(bounds.Dx(), bounds.Dy())
+	z.MoveTo(float32(x0+nx-ox), float32(y0+ny-oy))
+	z.LineTo(float32(x1+nx-ox), float32(y1+ny-oy))
+	z.LineTo(float32(x1-nx-ox), float32(y1-ny-oy))
+	z.LineTo(float32(x0-nx-ox), float32(y0-ny-oy))
+	z.ClosePath()
+	z.Draw(dst, bounds, image.Black, image.Point{})
+}
+
+func fromFixed(v fixed.Int26_6) float64 {
+	return float64(v) / 64
+}
+
+func toFixed(v float64) fixed.Int26_6 {
+	return fixed.Int26_6(math.Round(v * 64))
+}
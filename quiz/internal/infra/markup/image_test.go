@@ -0,0 +1,159 @@
+package markup
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func decodeFormulaImage(t *testing.T, tex string, display bool) image.Image {
+	t.Helper()
+
+	data, err := FormulaImage(tex, display)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("formula image isn't a PNG: %v", err)
+	}
+
+	return img
+}
+
+// inkRows returns the number of rows of img with dark pixels
+func inkRows(img image.Image) int {
+	rows := 0
+	bounds := img.Bounds()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			if gray := color.GrayModel.Convert(img.At(x, y)).(color.Gray); gray.Y < 128 {
+				rows++
+				break
+			}
+		}
+	}
+
+	return rows
+}
+
+func TestFormulaImage(t *testing.T) {
+	tests := []string{
+		`x`,
+		`x^2 + y^2 = r^2`,
+		`x_i^2`,
+		`\frac{a+b}{2}`,
+		`\sqrt{x^2+1}`,
+		`\sqrt[3]{x}`,
+		`\sum_{i=1}^n i = \frac{n(n+1)}{2}`,
+		`\lim_{x \to 0} \frac{\sin x}{x} = 1`,
+		`\int_0^1 f(x)\,dx`,
+		`\vec{F} = m\vec{a}`,
+		`\text{скорость } v = \frac{s}{t}`,
+		`\left( \frac{1}{2} \right)`,
+		`a \leq b \Rightarrow \forall x \in \emptyset`,
+	}
+
+	for _, tex := range tests {
+		for _, display := range []bool{false, true} {
+			t.Run(tex, func(t *testing.T) {
+				img := decodeFormulaImage(t, tex, display)
+
+				if corner := color.GrayModel.Convert(img.At(0, 0)).(color.Gray); corner.Y != 0xff {
+					t.Errorf("background isn't white: %v", corner)
+				}
+				if inkRows(img) == 0 {
+					t.Error("nothing is drawn")
+				}
+			})
+		}
+	}
+}
+
+func TestFormulaImageLayout(t *testing.T) {
+	x := decodeFormulaImage(t, `x`, false)
+	square := decodeFormulaImage(t, `x^2`, false)
+	if square.Bounds().Dx() <= x.Bounds().Dx() {
+		t.Error("superscript takes no room")
+	}
+
+	inline := decodeFormulaImage(t, `\frac{a}{b}`, false)
+	display := decodeFormulaImage(t, `\frac{a}{b}`, true)
+	if inkRows(display) <= inkRows(inline) {
+		t.Error("display fraction isn't taller than the inline one")
+	}
+	if inkRows(inline) <= inkRows(x) {
+		t.Error("fraction isn't taller than its parts")
+	}
+}
+
+func TestFormulaImageErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		tex  string
+		want error
+	}{
+		{name: "unknown command", tex: `\foo`, want: ErrUnknownCommand},
+		{name: "unbalanced braces", tex: `\frac{1}{2`, want: ErrUnbalancedBraces},
+		{name: "too long", tex: strings.Repeat("x", maxFormulaLength+1), want: ErrFormulaTooLong},
+		{name: "too wide", tex: strings.Repeat("x", maxFormulaLength), want: ErrFormulaTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := FormulaImage(tt.tex, true); !errors.Is(err, tt.want) {
+				t.Errorf("got error %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+// Formulas are drawn for concurrent requests with the fonts parsed once
+func TestFormulaImageConcurrent(t *testing.T) {
+	want, err := FormulaImage(`\frac{x^2}{\sqrt{2}}`, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			got, err := FormulaImage(`\frac{x^2}{\sqrt{2}}`, true)
+			if err != nil || !bytes.Equal(got, want) {
+				t.Errorf("got a different image, error %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestMathItalic(t *testing.T) {
+	tests := []struct {
+		r    rune
+		want rune
+	}{
+		{r: 'a', want: '𝑎'},
+		{r: 'h', want: 'ℎ'},
+		{r: 'z', want: '𝑧'},
+		{r: 'F', want: '𝐹'},
+		{r: 'α', want: '𝛼'},
+		{r: 'ω', want: '𝜔'},
+		{r: 'Ω', want: 'Ω'},
+		{r: '∞', want: '∞'},
+		{r: 'ж', want: 'ж'},
+	}
+
+	for _, tt := range tests {
+		if got := mathItalic(tt.r); got != tt.want {
+			t.Errorf("mathItalic(%q) = %q, want %q", tt.r, got, tt.want)
+		}
+	}
+}
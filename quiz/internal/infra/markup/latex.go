@@ -0,0 +1,388 @@
+package markup
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"strings"
+	"unicode"
+)
+
+var (
+	ErrUnbalancedBraces = errors.New("unbalanced braces in formula")
+	ErrUnknownCommand   = errors.New("unknown command in formula")
+	ErrMissingArgument  = errors.New("missing argument in formula")
+)
+
+// identifiers are commands rendered as <mi>
+var identifiers = map[string]string{
+	"alpha": "α", "beta": "β", "gamma": "γ", "delta": "δ", "epsilon": "ε",
+	"varepsilon": "ε", "zeta": "ζ", "eta": "η", "theta": "θ", "vartheta": "ϑ",
+	"iota": "ι", "kappa": "κ", "lambda": "λ", "mu": "μ", "nu": "ν", "xi": "ξ",
+	"pi": "π", "rho": "ρ", "sigma": "σ", "tau": "τ", "upsilon": "υ", "phi": "ϕ",
+	"varphi": "φ", "chi": "χ", "psi": "ψ", "omega": "ω",
+	"Gamma": "Γ", "Delta": "Δ", "Theta": "Θ", "Lambda": "Λ", "Xi": "Ξ",
+	"Pi": "Π", "Sigma": "Σ", "Phi": "Φ", "Psi": "Ψ", "Omega": "Ω",
+	"infty": "∞", "hbar": "ℏ", "ell": "ℓ", "partial": "∂", "nabla": "∇",
+	"emptyset": "∅",
+}
+
+// functions are upright multi-letter identifiers such as \sin
+var functions = map[string]bool{
+	"sin": true, "cos": true, "tan": true, "cot": true, "sec": true, "csc": true,
+	"arcsin": true, "arccos": true, "arctan": true, "sinh": true, "cosh": true,
+	"tanh": true, "log": true, "ln": true, "lg": true, "exp": true, "lim": true,
+	"max": true, "min": true, "det": true, "dim": true, "deg": true, "gcd": true,
+}
+
+// operators are commands rendered as <mo>
+var operators = map[string]string{
+	"cdot": "·", "times": "×", "div": "÷", "pm": "±", "mp": "∓",
+	"leq": "≤", "le": "≤", "geq": "≥", "ge": "≥", "neq": "≠", "ne": "≠",
+	"approx": "≈", "equiv": "≡", "sim": "∼", "propto": "∝",
+	"to": "→", "rightarrow": "→", "leftarrow": "←", "Rightarrow": "⇒",
+	"Leftarrow": "⇐", "Leftrightarrow": "⇔", "mapsto": "↦",
+	"in": "∈", "notin": "∉", "subset": "⊂", "subseteq": "⊆", "cup": "∪",
+	"cap": "∩", "forall": "∀", "exists": "∃", "neg": "¬", "land": "∧",
+	"lor": "∨", "perp": "⊥", "parallel": "∥", "angle": "∠", "circ": "∘",
+	"sum": "∑", "prod": "∏", "int": "∫", "iint": "∬", "oint": "∮",
+	"ldots": "…", "cdots": "⋯", "langle": "⟨", "rangle": "⟩",
+	"lbrace": "{", "rbrace": "}", "{": "{", "}": "}", "|": "‖",
+	",": " ", ";": " ", "quad": " ", "qquad": "  ",
+}
+
+// largeOperators take their limits under and over them in display mode
+var largeOperators = map[string]bool{
+	"sum": true, "prod": true, "lim": true, "max": true, "min": true,
+}
+
+// LatexToMathML converts a TeX formula to presentation MathML.
+// Only a safe subset of TeX is supported; anything else is an error
+func LatexToMathML(tex string, display bool) (string, error) {
+	p := &latexParser{src: []rune(tex), display: display}
+
+	body, err := p.parseList(false)
+	if err != nil {
+		return "", err
+	}
+
+	mode := "inline"
+	if display {
+		mode = "block"
+	}
+
+	return fmt.Sprintf(`<math xmlns="http://www.w3.org/1998/Math/MathML" display="%s"><mrow>%s</mrow></math>`, mode, body), nil
+}
+
+type latexParser struct {
+	src     []rune
+	pos     int
+	display bool
+}
+
+func (p *latexParser) eof() bool {
+	return p.pos >= len(p.src)
+}
+
+func (p *latexParser) peek() rune {
+	return p.src[p.pos]
+}
+
+func (p *latexParser) skipSpaces() {
+	for !p.eof() && unicode.IsSpace(p.peek()) {
+		p.pos++
+	}
+}
+
+// parseList parses atoms until the end of input or a closing brace
+func (p *latexParser) parseList(inGroup bool) (string, error) {
+	var sb strings.Builder
+
+	for {
+		p.skipSpaces()
+		if p.eof() {
+			if inGroup {
+				return "", ErrUnbalancedBraces
+			}
+			return sb.String(), nil
+		}
+
+		if p.peek() == '}' {
+			if !inGroup {
+				return "", ErrUnbalancedBraces
+			}
+			p.pos++
+			return sb.String(), nil
+		}
+
+		atom, large, err := p.parseAtom()
+		if err != nil {
+			return "", err
+		}
+
+		atom, err = p.parseScripts(atom, large)
+		if err != nil {
+			return "", err
+		}
+
+		sb.WriteString(atom)
+	}
+}
+
+// parseScripts wraps base into sub/superscript elements if any follow it
+func (p *latexParser) parseScripts(base string, large bool) (string, error) {
+	var sub, sup string
+	hasSub, hasSup := false, false
+
+	for {
+		p.skipSpaces()
+		if p.eof() {
+			break
+		}
+
+		r := p.peek()
+		if r != '_' && r != '^' {
+			break
+		}
+		p.pos++
+
+		arg, err := p.parseArgument()
+		if err != nil {
+			return "", err
+		}
+
+		if r == '_' {
+			sub, hasSub = arg, true
+		} else {
+			sup, hasSup = arg, true
+		}
+	}
+
+	under, over, both := "msub", "msup", "msubsup"
+	if large && p.display {
+		under, over, both = "munder", "mover", "munderover"
+	}
+
+	switch {
+	case hasSub && hasSup:
+		return fmt.Sprintf("<%s>%s%s%s</%s>", both, base, sub, sup, both), nil
+	case hasSub:
+		return fmt.Sprintf("<%s>%s%s</%s>", under, base, sub, under), nil
+	case hasSup:
+		return fmt.Sprintf("<%s>%s%s</%s>", over, base, sup, over), nil
+	}
+
+	return base, nil
+}
+
+// parseArgument parses a single atom or a braced group used as a command argument
+func (p *latexParser) parseArgument() (string, error) {
+	p.skipSpaces()
+	if p.eof() {
+		return "", ErrMissingArgument
+	}
+
+	if p.peek() == '{' {
+		p.pos++
+		body, err := p.parseList(true)
+		if err != nil {
+			return "", err
+		}
+		return "<mrow>" + body + "</mrow>", nil
+	}
+
+	if p.peek() == '}' {
+		return "", ErrMissingArgument
+	}
+
+	// A single digit is an argument, \frac12 is a half and x^23 is x²3
+	if unicode.IsDigit(p.peek()) {
+		p.pos++
+		return "<mn>" + string(p.src[p.pos-1]) + "</mn>", nil
+	}
+
+	atom, _, err := p.parseAtom()
+	return atom, err
+}
+
+// parseRawGroup returns the raw text of a braced group, used by \text
+func (p *latexParser) parseRawGroup() (string, error) {
+	p.skipSpaces()
+	if p.eof() || p.peek() != '{' {
+		return "", ErrMissingArgument
+	}
+	p.pos++
+
+	start := p.pos
+	depth := 1
+	for !p.eof() {
+		switch p.peek() {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				text := string(p.src[start:p.pos])
+				p.pos++
+				return text, nil
+			}
+		}
+		p.pos++
+	}
+
+	return "", ErrUnbalancedBraces
+}
+
+// parseAtom parses one element; large reports whether it is a large operator
+func (p *latexParser) parseAtom() (atom string, large bool, err error) {
+	r := p.peek()
+
+	switch {
+	case r == '{':
+		p.pos++
+		body, err := p.parseList(true)
+		if err != nil {
+			return "", false, err
+		}
+		return "<mrow>" + body + "</mrow>", false, nil
+
+	case r == '\\':
+		return p.parseCommand()
+
+	case unicode.IsDigit(r) || r == '.':
+		start := p.pos
+		for !p.eof() && (unicode.IsDigit(p.peek()) || p.peek() == '.') {
+			p.pos++
+		}
+		return "<mn>" + escape(string(p.src[start:p.pos])) + "</mn>", false, nil
+
+	case unicode.IsLetter(r):
+		p.pos++
+		return "<mi>" + escape(string(r)) + "</mi>", false, nil
+
+	case r == '_' || r == '^':
+		return "", false, ErrMissingArgument
+	}
+
+	p.pos++
+	return "<mo>" + escape(string(r)) + "</mo>", false, nil
+}
+
+func (p *latexParser) parseCommand() (string, bool, error) {
+	// skip backslash
+	p.pos++
+	if p.eof() {
+		return "", false, ErrUnknownCommand
+	}
+
+	start := p.pos
+	if unicode.IsLetter(p.peek()) {
+		for !p.eof() && unicode.IsLetter(p.peek()) {
+			p.pos++
+		}
+	} else {
+		// single symbol commands such as \{ or \,
+		p.pos++
+	}
+	name := string(p.src[start:p.pos])
+
+	switch name {
+	case "frac", "dfrac", "tfrac":
+		num, err := p.parseArgument()
+		if err != nil {
+			return "", false, err
+		}
+		den, err := p.parseArgument()
+		if err != nil {
+			return "", false, err
+		}
+		return "<mfrac>" + num + den + "</mfrac>", false, nil
+
+	case "sqrt":
+		p.skipSpaces()
+		if !p.eof() && p.peek() == '[' {
+			p.pos++
+			start := p.pos
+			for !p.eof() && p.peek() != ']' {
+				p.pos++
+			}
+			if p.eof() {
+				return "", false, ErrUnbalancedBraces
+			}
+			index, err := LatexToMathML(string(p.src[start:p.pos]), false)
+			if err != nil {
+				return "", false, err
+			}
+			p.pos++
+			radicand, err := p.parseArgument()
+			if err != nil {
+				return "", false, err
+			}
+			return "<mroot>" + radicand + innerRow(index) + "</mroot>", false, nil
+		}
+		radicand, err := p.parseArgument()
+		if err != nil {
+			return "", false, err
+		}
+		return "<msqrt>" + radicand + "</msqrt>", false, nil
+
+	case "text", "mathrm", "textrm":
+		text, err := p.parseRawGroup()
+		if err != nil {
+			return "", false, err
+		}
+		return "<mtext>" + escape(text) + "</mtext>", false, nil
+
+	case "vec", "bar", "hat", "dot":
+		accents := map[string]string{"vec": "→", "bar": "¯", "hat": "^", "dot": "˙"}
+		arg, err := p.parseArgument()
+		if err != nil {
+			return "", false, err
+		}
+		return `<mover accent="true">` + arg + "<mo>" + accents[name] + "</mo></mover>", false, nil
+
+	case "left", "right":
+		p.skipSpaces()
+		if p.eof() {
+			return "", false, ErrMissingArgument
+		}
+		if p.peek() == '\\' {
+			return p.parseCommand()
+		}
+		delim := p.peek()
+		p.pos++
+		if delim == '.' {
+			return "", false, nil
+		}
+		return "<mo>" + escape(string(delim)) + "</mo>", false, nil
+	}
+
+	if v, ok := identifiers[name]; ok {
+		return "<mi>" + v + "</mi>", false, nil
+	}
+
+	if functions[name] {
+		return `<mi mathvariant="normal">` + name + "</mi>", largeOperators[name], nil
+	}
+
+	if v, ok := operators[name]; ok {
+		return "<mo>" + escape(v) + "</mo>", largeOperators[name], nil
+	}
+
+	return "", false, fmt.Errorf("%w: \\%s", ErrUnknownCommand, name)
+}
+
+// innerRow strips the <math> wrapper from a converted formula
+func innerRow(mathml string) string {
+	start := strings.Index(mathml, "<mrow>")
+	end := strings.LastIndex(mathml, "</math>")
+	if start < 0 || end < 0 {
+		return mathml
+	}
+
+	return mathml[start:end]
+}
+
+func escape(s string) string {
+	return html.EscapeString(s)
+}
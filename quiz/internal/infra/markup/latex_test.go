@@ -0,0 +1,112 @@
+package markup
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestLatexToMathML(t *testing.T) {
+	tests := []struct {
+		name    string
+		tex     string
+		display bool
+		// want is the content of the formula's <mrow>
+		want string
+	}{
+		{name: "identifier", tex: `x`, want: `<mi>x</mi>`},
+		{name: "number", tex: `12.5`, want: `<mn>12.5</mn>`},
+		{name: "spaces are dropped", tex: ` a  +  b `, want: `<mi>a</mi><mo>+</mo><mi>b</mi>`},
+		{name: "superscript", tex: `x^2`, want: `<msup><mi>x</mi><mn>2</mn></msup>`},
+		{name: "subscript group", tex: `a_{n+1}`, want: `<msub><mi>a</mi><mrow><mi>n</mi><mo>+</mo><mn>1</mn></mrow></msub>`},
+		{name: "both scripts", tex: `x_i^2`, want: `<msubsup><mi>x</mi><mi>i</mi><mn>2</mn></msubsup>`},
+		{name: "fraction", tex: `\frac{a}{b}`, want: `<mfrac><mrow><mi>a</mi></mrow><mrow><mi>b</mi></mrow></mfrac>`},
+		{name: "fraction of digits", tex: `\dfrac12`, want: `<mfrac><mn>1</mn><mn>2</mn></mfrac>`},
+		{name: "script takes one digit", tex: `x^23`, want: `<msup><mi>x</mi><mn>2</mn></msup><mn>3</mn>`},
+		{name: "square root", tex: `\sqrt x`, want: `<msqrt><mi>x</mi></msqrt>`},
+		{name: "root with index", tex: `\sqrt[3]{x}`, want: `<mroot><mrow><mi>x</mi></mrow><mrow><mn>3</mn></mrow></mroot>`},
+		{name: "greek letter", tex: `\alpha + \Omega`, want: `<mi>α</mi><mo>+</mo><mi>Ω</mi>`},
+		{name: "function", tex: `\sin x`, want: `<mi mathvariant="normal">sin</mi><mi>x</mi>`},
+		{name: "relation", tex: `a \leq b`, want: `<mi>a</mi><mo>≤</mo><mi>b</mi>`},
+		{name: "inline sum", tex: `\sum_{i=1}^n`, want: `<msubsup><mo>∑</mo><mrow><mi>i</mi><mo>=</mo><mn>1</mn></mrow><mi>n</mi></msubsup>`},
+		{name: "display sum", tex: `\sum_{i=1}^n`, display: true, want: `<munderover><mo>∑</mo><mrow><mi>i</mi><mo>=</mo><mn>1</mn></mrow><mi>n</mi></munderover>`},
+		{name: "display limit", tex: `\lim_{x \to 0}`, display: true, want: `<munder><mi mathvariant="normal">lim</mi><mrow><mi>x</mi><mo>→</mo><mn>0</mn></mrow></munder>`},
+		{name: "display integral keeps scripts", tex: `\int_0^1`, display: true, want: `<msubsup><mo>∫</mo><mn>0</mn><mn>1</mn></msubsup>`},
+		{name: "accent", tex: `\vec{v}`, want: `<mover accent="true"><mrow><mi>v</mi></mrow><mo>→</mo></mover>`},
+		{name: "text", tex: `\text{speed of light}`, want: `<mtext>speed of light</mtext>`},
+		{name: "delimiters", tex: `\left\{ x \right.`, want: `<mo>{</mo><mi>x</mi>`},
+		{name: "group", tex: `{a}`, want: `<mrow><mi>a</mi></mrow>`},
+		{name: "empty", tex: ``, want: ``},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := LatexToMathML(tt.tex, tt.display)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			mode := "inline"
+			if tt.display {
+				mode = "block"
+			}
+			want := `<math xmlns="http://www.w3.org/1998/Math/MathML" display="` + mode + `"><mrow>` + tt.want + `</mrow></math>`
+			if got != want {
+				t.Errorf("got  %s\nwant %s", got, want)
+			}
+		})
+	}
+}
+
+func TestLatexToMathMLErrors(t *testing.T) {
+	tests := []struct {
+		tex  string
+		want error
+	}{
+		{tex: `{x`, want: ErrUnbalancedBraces},
+		{tex: `x}`, want: ErrUnbalancedBraces},
+		{tex: `\frac{a}{b`, want: ErrUnbalancedBraces},
+		{tex: `\sqrt[3{x}`, want: ErrUnbalancedBraces},
+		{tex: `\text{a`, want: ErrUnbalancedBraces},
+		{tex: `\foo`, want: ErrUnknownCommand},
+		{tex: `\`, want: ErrUnknownCommand},
+		{tex: `\sqrt[\foo]{x}`, want: ErrUnknownCommand},
+		{tex: `x^`, want: ErrMissingArgument},
+		{tex: `^2`, want: ErrMissingArgument},
+		{tex: `x_}`, want: ErrMissingArgument},
+		{tex: `\frac{a}`, want: ErrMissingArgument},
+		{tex: `\text x`, want: ErrMissingArgument},
+		{tex: `\left`, want: ErrMissingArgument},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tex, func(t *testing.T) {
+			if _, err := LatexToMathML(tt.tex, false); !errors.Is(err, tt.want) {
+				t.Errorf("got error %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLatexToMathMLEscapes(t *testing.T) {
+	tests := []string{
+		`\text{<script>alert(1)</script>}`,
+		`<img src=x onerror=alert(1)>`,
+		`a<b>c`,
+		`\text{" onclick="x}`,
+		`x & y`,
+	}
+
+	for _, tex := range tests {
+		t.Run(tex, func(t *testing.T) {
+			got, err := LatexToMathML(tex, false)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			checkTags(t, got)
+			if strings.Contains(got, `"`+" onclick") || strings.Contains(got, " & ") {
+				t.Errorf("unescaped input in %s", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,388 @@
+package markup
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Formula is a TeX formula found in a text
+type Formula struct {
+	TeX      string `json:"tex"`
+	Display  bool   `json:"display"`
+	MathML   string `json:"mathml,omitempty"`
+	ImageURL string `json:"image_url,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// Rendered is a text rendered for the web and for Telegram
+type Rendered struct {
+	// HTML is sanitised HTML with formulas as MathML
+	HTML string `json:"html"`
+	// TelegramHTML uses only tags supported by the Telegram Bot API,
+	// formulas are left as TeX and should be sent as images from Formulas
+	TelegramHTML string    `json:"telegram_html"`
+	Formulas     []Formula `json:"formulas,omitempty"`
+}
+
+type target int
+
+const (
+	targetWeb target = iota
+	targetTelegram
+)
+
+var (
+	orderedItem   = regexp.MustCompile(`^\d{1,3}\. `)
+	unorderedItem = regexp.MustCompile(`^[-*+] `)
+)
+
+// Renderer renders the Markdown subset used in question and option texts:
+// paragraphs, lists, code, bold, italic, http(s) links and TeX formulas
+// between $...$ (inline) and $$...$$ (display). Raw HTML is always escaped
+type Renderer struct {
+	imageURL string
+}
+
+// NewRenderer creates a renderer. imageURL is the public address of the
+// endpoint serving FormulaImage, the formula is passed in the tex query
+// parameter. Leave it empty to skip formula images
+func NewRenderer(imageURL string) *Renderer {
+	return &Renderer{
+		imageURL: imageURL,
+	}
+}
+
+// Render renders src for both the web and Telegram
+func (r *Renderer) Render(src string) *Rendered {
+	web := &renderState{renderer: r, target: targetWeb}
+	telegram := &renderState{renderer: r, target: targetTelegram}
+
+	return &Rendered{
+		HTML:         web.renderBlocks(src),
+		TelegramHTML: telegram.renderBlocks(src),
+		Formulas:     web.formulas,
+	}
+}
+
+// FormulaImageURL returns the address of a PNG image of the formula
+func (r *Renderer) FormulaImageURL(tex string, display bool) string {
+	if r.imageURL == "" {
+		return ""
+	}
+
+	query := url.Values{"tex": {tex}}
+	if display {
+		query.Set("display", "1")
+	}
+
+	return r.imageURL + "?" + query.Encode()
+}
+
+// Validate checks that every formula in src is well formed
+func Validate(src string) error {
+	state := &renderState{renderer: NewRenderer(""), target: targetWeb}
+	state.renderBlocks(src)
+
+	for _, f := range state.formulas {
+		if f.Error != "" {
+			return fmt.Errorf("invalid formula %q: %s", f.TeX, f.Error)
+		}
+	}
+
+	return nil
+}
+
+type renderState struct {
+	renderer *Renderer
+	target   target
+	formulas []Formula
+}
+
+func (s *renderState) renderBlocks(src string) string {
+	src = strings.ReplaceAll(src, "\r\n", "\n")
+	lines := strings.Split(src, "\n")
+
+	var sb strings.Builder
+	var paragraph []string
+	var listItems []string
+	listTag := ""
+
+	flushParagraph := func() {
+		if len(paragraph) == 0 {
+			return
+		}
+		text := s.renderInline(strings.Join(paragraph, "\n"))
+		if s.target == targetWeb {
+			sb.WriteString("<p>" + text + "</p>")
+		} else {
+			sb.WriteString(text + "\n\n")
+		}
+		paragraph = nil
+	}
+
+	flushList := func() {
+		if len(listItems) == 0 {
+			return
+		}
+		for i, item := range listItems {
+			text := s.renderInline(item)
+			switch {
+			case s.target == targetWeb:
+				if i == 0 {
+					sb.WriteString("<" + listTag + ">")
+				}
+				sb.WriteString("<li>" + text + "</li>")
+			case listTag == "ol":
+				sb.WriteString(fmt.Sprintf("%d. %s\n", i+1, text))
+			default:
+				sb.WriteString("• " + text + "\n")
+			}
+		}
+		if s.target == targetWeb {
+			sb.WriteString("</" + listTag + ">")
+		} else {
+			sb.WriteString("\n")
+		}
+		listItems = nil
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+
+		// Fenced code block
+		if strings.HasPrefix(trimmed, "```") {
+			flushParagraph()
+			flushList()
+
+			var code []string
+			for i++; i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), "```"); i++ {
+				code = append(code, lines[i])
+			}
+			sb.WriteString("<pre><code>" + escape(strings.Join(code, "\n")) + "</code></pre>")
+			if s.target == targetTelegram {
+				sb.WriteString("\n\n")
+			}
+			continue
+		}
+
+		if trimmed == "" {
+			flushParagraph()
+			flushList()
+			continue
+		}
+
+		if tag, item, ok := listItem(trimmed); ok {
+			flushParagraph()
+			if tag != listTag {
+				flushList()
+			}
+			listTag = tag
+			listItems = append(listItems, item)
+			continue
+		}
+
+		flushList()
+		paragraph = append(paragraph, line)
+	}
+
+	flushParagraph()
+	flushList()
+
+	return strings.TrimSpace(sb.String())
+}
+
+func listItem(line string) (tag, item string, ok bool) {
+	if loc := orderedItem.FindStringIndex(line); loc != nil {
+		return "ol", line[loc[1]:], true
+	}
+
+	if loc := unorderedItem.FindStringIndex(line); loc != nil {
+		return "ul", line[loc[1]:], true
+	}
+
+	return "", "", false
+}
+
+// renderInline renders inline markup. Everything that isn't recognised
+// markup is escaped, so the output never contains user supplied tags
+func (s *renderState) renderInline(text string) string {
+	var sb strings.Builder
+
+	for i := 0; i < len(text); {
+		c := text[i]
+
+		switch {
+		case c == '\\' && i+1 < len(text) && strings.IndexByte("\\`*_$[]()", text[i+1]) >= 0:
+			sb.WriteString(escape(text[i+1 : i+2]))
+			i += 2
+			continue
+
+		case c == '\n':
+			if s.target == targetWeb {
+				sb.WriteString("<br>")
+			} else {
+				sb.WriteString("\n")
+			}
+			i++
+			continue
+
+		case strings.HasPrefix(text[i:], "$$"):
+			if end := findClosing(text, i+2, "$$"); end >= 0 {
+				sb.WriteString(s.formula(text[i+2:end], true))
+				i = end + 2
+				continue
+			}
+
+		case c == '$':
+			if end := findClosing(text, i+1, "$"); end > i+1 {
+				sb.WriteString(s.formula(text[i+1:end], false))
+				i = end + 1
+				continue
+			}
+
+		case c == '`':
+			if end := strings.IndexByte(text[i+1:], '`'); end >= 0 {
+				sb.WriteString("<code>" + escape(text[i+1:i+1+end]) + "</code>")
+				i += end + 2
+				continue
+			}
+
+		case strings.HasPrefix(text[i:], "**"):
+			if end := findEmphasis(text, i+2, "**"); end > i+2 {
+				sb.WriteString("<b>" + s.renderInline(text[i+2:end]) + "</b>")
+				i = end + 2
+				continue
+			}
+
+		case c == '*' || c == '_':
+			if end := findEmphasis(text, i+1, string(c)); end > i+1 {
+				sb.WriteString("<i>" + s.renderInline(text[i+1:end]) + "</i>")
+				i = end + 1
+				continue
+			}
+
+		case c == '[':
+			if label, href, n, ok := parseLink(text[i:]); ok {
+				sb.WriteString(`<a href="` + escape(href) + `"`)
+				if s.target == targetWeb {
+					sb.WriteString(` rel="nofollow noopener" target="_blank"`)
+				}
+				sb.WriteString(">" + s.renderInline(label) + "</a>")
+				i += n
+				continue
+			}
+		}
+
+		sb.WriteString(escape(text[i : i+1]))
+		i++
+	}
+
+	return sb.String()
+}
+
+func (s *renderState) formula(tex string, display bool) string {
+	tex = strings.TrimSpace(tex)
+	f := Formula{
+		TeX:     tex,
+		Display: display,
+	}
+
+	// Formulas too long to be drawn are invalid, Validate refuses them
+	mathml, err := LatexToMathML(tex, display)
+	if err == nil && len(tex) > maxFormulaLength {
+		err = ErrFormulaTooLong
+	}
+	if err != nil {
+		f.Error = err.Error()
+	} else {
+		f.MathML = mathml
+		f.ImageURL = s.renderer.FormulaImageURL(tex, display)
+	}
+	s.formulas = append(s.formulas, f)
+
+	if s.target == targetTelegram || err != nil {
+		return `<code>` + escape(tex) + `</code>`
+	}
+
+	return mathml
+}
+
+// findClosing returns the index of the next unescaped delimiter starting at from
+func findClosing(text string, from int, delim string) int {
+	for i := from; i <= len(text)-len(delim); i++ {
+		if text[i] == '\\' {
+			i++
+			continue
+		}
+		if strings.HasPrefix(text[i:], delim) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// findEmphasis returns the index of the delimiter closing emphasis opened
+// right before from. As in Markdown the delimiters hug the text, so 2 * 3 * 4
+// is left alone, and underscores inside words such as snake_case don't count
+func findEmphasis(text string, from int, delim string) int {
+	if from >= len(text) || isSpace(text[from]) {
+		return -1
+	}
+
+	if delim == "_" && from >= 2 && isWordByte(text[from-2]) {
+		return -1
+	}
+
+	for i := from + 1; ; i++ {
+		i = findClosing(text, i, delim)
+		if i < 0 {
+			return -1
+		}
+
+		if isSpace(text[i-1]) {
+			continue
+		}
+
+		if after := i + len(delim); delim == "_" && after < len(text) && isWordByte(text[after]) {
+			continue
+		}
+
+		return i
+	}
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n'
+}
+
+// isWordByte reports whether c is part of a word, bytes of multibyte
+// characters such as Cyrillic letters count
+func isWordByte(c byte) bool {
+	return c >= 0x80 || c == '_' || '0' <= c && c <= '9' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
+
+// parseLink parses [label](href) at the start of text. Only http and https
+// links are accepted
+func parseLink(text string) (label, href string, n int, ok bool) {
+	closeLabel := findClosing(text, 1, "](")
+	if closeLabel < 0 {
+		return "", "", 0, false
+	}
+
+	closeHref := strings.IndexByte(text[closeLabel+2:], ')')
+	if closeHref < 0 {
+		return "", "", 0, false
+	}
+
+	href = strings.TrimSpace(text[closeLabel+2 : closeLabel+2+closeHref])
+	u, err := url.Parse(href)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", "", 0, false
+	}
+
+	return text[1:closeLabel], u.String(), closeLabel + 3 + closeHref, true
+}
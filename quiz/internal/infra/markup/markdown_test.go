@@ -0,0 +1,244 @@
+package markup
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// webTags are the elements and attributes HTML for the web may contain
+var webTags = map[string][]string{
+	"p": nil, "br": nil, "b": nil, "i": nil, "code": nil, "pre": nil,
+	"ul": nil, "ol": nil, "li": nil, "a": {"href", "rel", "target"},
+	"math": {"xmlns", "display"}, "mrow": nil, "mi": {"mathvariant"}, "mn": nil,
+	"mo": nil, "mtext": nil, "mfrac": nil, "msqrt": nil, "mroot": nil,
+	"msub": nil, "msup": nil, "msubsup": nil, "munder": nil, "mover": {"accent"},
+	"munderover": nil,
+}
+
+// telegramTags are the ones of the Bot API HTML style we use
+var telegramTags = map[string][]string{
+	"b": nil, "i": nil, "code": nil, "pre": nil, "a": {"href"},
+}
+
+var (
+	tagPattern       = regexp.MustCompile(`<(/?)([^\s>/]*)([^>]*)>`)
+	attributePattern = regexp.MustCompile(`([a-z]+)="([^"]*)"`)
+)
+
+// checkTags fails the test if html has markup it must never have: an
+// element or attribute not in allowed, a link that isn't http(s) or a
+// stray "<"
+func checkTags(t *testing.T, html string, allowed ...map[string][]string) {
+	t.Helper()
+
+	tags := webTags
+	if len(allowed) > 0 {
+		tags = allowed[0]
+	}
+
+	matches := tagPattern.FindAllStringSubmatch(html, -1)
+	if strings.Count(html, "<") != len(matches) {
+		t.Errorf("unescaped < in %s", html)
+	}
+
+	for _, m := range matches {
+		attributes, ok := tags[m[2]]
+		if !ok {
+			t.Errorf("unexpected element %s in %s", m[0], html)
+			continue
+		}
+
+		rest := m[3]
+		for _, a := range attributePattern.FindAllStringSubmatch(m[3], -1) {
+			if !slices.Contains(attributes, a[1]) {
+				t.Errorf("unexpected attribute %s in %s", a[0], html)
+			}
+			if a[1] == "href" && !strings.HasPrefix(a[2], "http://") && !strings.HasPrefix(a[2], "https://") {
+				t.Errorf("unexpected link %s in %s", a[0], html)
+			}
+			rest = strings.Replace(rest, a[0], "", 1)
+		}
+		if strings.TrimSpace(rest) != "" {
+			t.Errorf("unexpected attribute %q in %s", rest, m[0])
+		}
+	}
+}
+
+func TestRenderHTML(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{name: "paragraph", src: "Hello", want: "<p>Hello</p>"},
+		{name: "paragraphs", src: "a\n\n\nb", want: "<p>a</p><p>b</p>"},
+		{name: "windows line ends", src: "a\r\n\r\nb", want: "<p>a</p><p>b</p>"},
+		{name: "line break", src: "a\nb", want: "<p>a<br>b</p>"},
+		{name: "emphasis", src: "**b** *i* _i_", want: "<p><b>b</b> <i>i</i> <i>i</i></p>"},
+		{name: "nested emphasis", src: "**a _b_**", want: "<p><b>a <i>b</i></b></p>"},
+		{name: "unclosed emphasis", src: "2 * 3 and **4", want: "<p>2 * 3 and **4</p>"},
+		{name: "arithmetic", src: "2 * 3 * 4", want: "<p>2 * 3 * 4</p>"},
+		{name: "snake case", src: "snake_case_name", want: "<p>snake_case_name</p>"},
+		{name: "indices", src: "x_1 and y_2", want: "<p>x_1 and y_2</p>"},
+		{name: "emphasis in a word", src: "un*frigging*believable", want: "<p>un<i>frigging</i>believable</p>"},
+		{name: "emphasis before punctuation", src: "_a_, **b**.", want: "<p><i>a</i>, <b>b</b>.</p>"},
+		{name: "code", src: "`a*b*<c>`", want: "<p><code>a*b*&lt;c&gt;</code></p>"},
+		{
+			name: "fenced code",
+			src:  "```go\n<b>x</b>\n**y**\n```\nafter",
+			want: "<pre><code>&lt;b&gt;x&lt;/b&gt;\n**y**</code></pre><p>after</p>",
+		},
+		{name: "unclosed fence", src: "```\nx", want: "<pre><code>x</code></pre>"},
+		{name: "unordered list", src: "- a\n* b\n+ c", want: "<ul><li>a</li><li>b</li><li>c</li></ul>"},
+		{name: "ordered list", src: "1. a\n2. **b**", want: "<ol><li>a</li><li><b>b</b></li></ol>"},
+		{name: "list after paragraph", src: "Pick:\n1. a\n2. b", want: "<p>Pick:</p><ol><li>a</li><li>b</li></ol>"},
+		{name: "list kinds", src: "- a\n1. b", want: "<ul><li>a</li></ul><ol><li>b</li></ol>"},
+		{name: "not a list", src: "2020. year", want: "<p>2020. year</p>"},
+		{
+			name: "link",
+			src:  "[BSU](https://bsu.by/?a=1&b=2)",
+			want: `<p><a href="https://bsu.by/?a=1&amp;b=2" rel="nofollow noopener" target="_blank">BSU</a></p>`,
+		},
+		{name: "script link", src: "[x](javascript:alert(1))", want: "<p>[x](javascript:alert(1))</p>"},
+		{name: "relative link", src: "[x](/admin)", want: "<p>[x](/admin)</p>"},
+		{
+			name: "raw html",
+			src:  `<script>alert("x")</script>`,
+			want: "<p>&lt;script&gt;alert(&#34;x&#34;)&lt;/script&gt;</p>",
+		},
+		{name: "escapes", src: `\*not italic\* costs \$5`, want: "<p>*not italic* costs $5</p>"},
+		{name: "lone dollar", src: "costs $5", want: "<p>costs $5</p>"},
+		{
+			name: "inline formula",
+			src:  "$x$",
+			want: `<p><math xmlns="http://www.w3.org/1998/Math/MathML" display="inline"><mrow><mi>x</mi></mrow></math></p>`,
+		},
+		{
+			name: "display formula",
+			src:  "$$ x $$",
+			want: `<p><math xmlns="http://www.w3.org/1998/Math/MathML" display="block"><mrow><mi>x</mi></mrow></math></p>`,
+		},
+		{name: "invalid formula", src: `$\foo<b>$`, want: `<p><code>\foo&lt;b&gt;</code></p>`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewRenderer("").Render(tt.src).HTML
+			if got != tt.want {
+				t.Errorf("got  %s\nwant %s", got, tt.want)
+			}
+			checkTags(t, got)
+		})
+	}
+}
+
+func TestRenderTelegramHTML(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{name: "paragraphs", src: "a\nb\n\nc", want: "a\nb\n\nc"},
+		{name: "formulas are code", src: "**b** $x^2$ $$y$$", want: "<b>b</b> <code>x^2</code> <code>y</code>"},
+		{name: "lists", src: "- a\n- b\n\n1. c\n2. d", want: "• a\n• b\n\n1. c\n2. d"},
+		{name: "link", src: "[x](https://bsu.by)", want: `<a href="https://bsu.by">x</a>`},
+		{name: "fenced code", src: "```\nx\n```\nafter", want: "<pre><code>x</code></pre>\n\nafter"},
+		{name: "raw html", src: "<u>x</u>", want: "&lt;u&gt;x&lt;/u&gt;"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewRenderer("").Render(tt.src).TelegramHTML
+			if got != tt.want {
+				t.Errorf("got  %q\nwant %q", got, tt.want)
+			}
+			checkTags(t, got, telegramTags)
+		})
+	}
+}
+
+func TestRenderHostileInput(t *testing.T) {
+	tests := []string{
+		`<img src=x onerror=alert(1)>`,
+		`[<b>x</b>](https://bsu.by/"onmouseover="alert(1))`,
+		`[x](https://bsu.by/)"><script>alert(1)</script>`,
+		`[x](javascript://bsu.by/%0aalert(1))`,
+		`[x](data:text/html,<script>alert(1)</script>)`,
+		"**<i>x</i>** _<a>_ `</code><script>`",
+		`$\text{</math><script>alert(1)</script>}$`,
+		`$$<svg onload=alert(1)>$$`,
+		"- <li>x\n1. </ol><script>",
+		"```\n</code></pre><script>alert(1)</script>\n```",
+		`\<script>`,
+		`&lt;script&gt; &#60;`,
+	}
+
+	for _, src := range tests {
+		t.Run(src, func(t *testing.T) {
+			rendered := NewRenderer("https://quiz.example/formula.png").Render(src)
+			checkTags(t, rendered.HTML)
+			checkTags(t, rendered.TelegramHTML, telegramTags)
+		})
+	}
+}
+
+func TestRenderFormulas(t *testing.T) {
+	src := `$x^2$ and $$\frac12$$ and $\foo$`
+
+	formulas := NewRenderer("https://quiz.example/formula.png").Render(src).Formulas
+	if len(formulas) != 3 {
+		t.Fatalf("got %d formulas, want 3", len(formulas))
+	}
+
+	inline, display, invalid := formulas[0], formulas[1], formulas[2]
+	if inline.TeX != "x^2" || inline.Display || inline.MathML == "" || inline.Error != "" {
+		t.Errorf("inline formula: %+v", inline)
+	}
+	if want := "https://quiz.example/formula.png?tex=x%5E2"; inline.ImageURL != want {
+		t.Errorf("got image URL %s, want %s", inline.ImageURL, want)
+	}
+	if want := "https://quiz.example/formula.png?display=1&tex=%5Cfrac12"; !display.Display || display.ImageURL != want {
+		t.Errorf("got display formula %+v, want image URL %s", display, want)
+	}
+	if invalid.Error == "" || invalid.MathML != "" || invalid.ImageURL != "" {
+		t.Errorf("invalid formula: %+v", invalid)
+	}
+
+	for _, f := range NewRenderer("").Render(src).Formulas {
+		if f.ImageURL != "" {
+			t.Errorf("got image URL %s without an image service", f.ImageURL)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		src     string
+		invalid bool
+	}{
+		{name: "empty", src: ""},
+		{name: "text", src: "**What** is _it_?"},
+		{name: "formulas", src: `$x^2$ and $$\frac{1}{2}$$`},
+		{name: "price", src: "costs $5"},
+		{name: "formula in code", src: "`$\\foo$`"},
+		{name: "unknown command", src: `$\foo$`, invalid: true},
+		{name: "unbalanced braces", src: `$$\frac{1}{2$$`, invalid: true},
+		{name: "invalid formula in a list", src: "- $x^$", invalid: true},
+		{name: "too long", src: "$" + strings.Repeat("x", maxFormulaLength+1) + "$", invalid: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.src)
+			if tt.invalid && err == nil {
+				t.Error("got no error for an invalid text")
+			}
+			if !tt.invalid && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,333 @@
+// Package openapi loads an OpenAPI 3 document and validates requests and
+// responses against it. Only the parts of the specification the project uses
+// are supported: path, query and header parameters, JSON and form bodies and
+// schemas with local $ref, allOf, oneOf and anyOf
+package openapi
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Document is a parsed OpenAPI document
+type Document struct {
+	Servers    []Server             `yaml:"servers"`
+	Paths      map[string]*PathItem `yaml:"paths"`
+	Components Components           `yaml:"components"`
+
+	basePath string
+	routes   []*Route
+}
+
+type Server struct {
+	URL string `yaml:"url"`
+}
+
+type Components struct {
+	Schemas map[string]*Schema `yaml:"schemas"`
+}
+
+// PathItem holds the operations of a path, parameters declared on the
+// path apply to all of them
+type PathItem struct {
+	Parameters []*Parameter `yaml:"parameters"`
+	Get        *Operation   `yaml:"get"`
+	Put        *Operation   `yaml:"put"`
+	Post       *Operation   `yaml:"post"`
+	Delete     *Operation   `yaml:"delete"`
+	Patch      *Operation   `yaml:"patch"`
+}
+
+func (p *PathItem) operations() map[string]*Operation {
+	operations := map[string]*Operation{
+		http.MethodGet:    p.Get,
+		http.MethodPut:    p.Put,
+		http.MethodPost:   p.Post,
+		http.MethodDelete: p.Delete,
+		http.MethodPatch:  p.Patch,
+	}
+
+	for method, operation := range operations {
+		if operation == nil {
+			delete(operations, method)
+		}
+	}
+
+	return operations
+}
+
+type Operation struct {
+	OperationID string               `yaml:"operationId"`
+	Parameters  []*Parameter         `yaml:"parameters"`
+	RequestBody *RequestBody         `yaml:"requestBody"`
+	Responses   map[string]*Response `yaml:"responses"`
+
+	doc *Document
+}
+
+type Parameter struct {
+	Name     string  `yaml:"name"`
+	In       string  `yaml:"in"`
+	Required bool    `yaml:"required"`
+	Schema   *Schema `yaml:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                  `yaml:"required"`
+	Content  map[string]*MediaType `yaml:"content"`
+}
+
+type Response struct {
+	Description string                `yaml:"description"`
+	Content     map[string]*MediaType `yaml:"content"`
+}
+
+type MediaType struct {
+	Schema *Schema `yaml:"schema"`
+}
+
+// Route is an operation with its method and path template relative to the base path
+type Route struct {
+	Method    string
+	Path      string
+	Operation *Operation
+
+	segments []string
+	literals int
+}
+
+// Load parses the document and checks that every $ref points to a schema
+func Load(data []byte) (*Document, error) {
+	var doc Document
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse spec: %w", err)
+	}
+
+	if len(doc.Servers) > 0 {
+		u, err := url.Parse(doc.Servers[0].URL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid server url: %w", err)
+		}
+		doc.basePath = strings.TrimSuffix(u.Path, "/")
+	}
+
+	for path, item := range doc.Paths {
+		for method, operation := range item.operations() {
+			operation.doc = &doc
+			operation.Parameters = mergeParameters(item.Parameters, operation.Parameters)
+
+			if err := doc.checkOperation(operation); err != nil {
+				return nil, fmt.Errorf("%s %s: %w", method, path, err)
+			}
+
+			route := &Route{
+				Method:    method,
+				Path:      path,
+				Operation: operation,
+				segments:  splitPath(path),
+			}
+			for _, segment := range route.segments {
+				if !isParam(segment) {
+					route.literals++
+				}
+			}
+			doc.routes = append(doc.routes, route)
+		}
+	}
+
+	// Literal segments win over parameters, /users/import over /users/{id}
+	sort.Slice(doc.routes, func(i, j int) bool {
+		if doc.routes[i].literals != doc.routes[j].literals {
+			return doc.routes[i].literals > doc.routes[j].literals
+		}
+		return doc.routes[i].Path < doc.routes[j].Path
+	})
+
+	return &doc, nil
+}
+
+// BasePath is the path of the first server, operation paths are relative to it
+func (d *Document) BasePath() string {
+	return d.basePath
+}
+
+// Routes returns every operation of the document
+func (d *Document) Routes() []*Route {
+	return d.routes
+}
+
+// FindRoute returns the route serving the request path with the values of
+// its path parameters, or nil if no operation matches
+func (d *Document) FindRoute(method, path string) (*Route, map[string]string) {
+	relative, ok := strings.CutPrefix(path, d.basePath)
+	if !ok {
+		return nil, nil
+	}
+	segments := splitPath(relative)
+
+	for _, route := range d.routes {
+		if route.Method != method {
+			continue
+		}
+
+		if params, ok := route.match(segments); ok {
+			return route, params
+		}
+	}
+
+	return nil, nil
+}
+
+// Operation returns the operation of a path template relative to the base path
+func (d *Document) Operation(method, path string) *Operation {
+	for _, route := range d.routes {
+		if route.Method == method && route.Path == path {
+			return route.Operation
+		}
+	}
+
+	return nil
+}
+
+func (r *Route) match(segments []string) (map[string]string, bool) {
+	if len(segments) != len(r.segments) {
+		return nil, false
+	}
+
+	params := make(map[string]string)
+	for i, segment := range r.segments {
+		if isParam(segment) {
+			value, err := url.PathUnescape(segments[i])
+			if err != nil {
+				return nil, false
+			}
+			params[strings.Trim(segment, "{}")] = value
+			continue
+		}
+
+		if segment != segments[i] {
+			return nil, false
+		}
+	}
+
+	return params, true
+}
+
+func (d *Document) checkOperation(operation *Operation) error {
+	seen := make(map[*Schema]bool)
+
+	for _, param := range operation.Parameters {
+		if err := d.checkRefs(param.Schema, seen); err != nil {
+			return err
+		}
+	}
+
+	if operation.RequestBody != nil {
+		for _, media := range operation.RequestBody.Content {
+			if err := d.checkRefs(media.Schema, seen); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, response := range operation.Responses {
+		for _, media := range response.Content {
+			if err := d.checkRefs(media.Schema, seen); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (d *Document) checkRefs(schema *Schema, seen map[*Schema]bool) error {
+	if schema == nil || seen[schema] {
+		return nil
+	}
+	seen[schema] = true
+
+	if schema.Ref != "" {
+		target, err := d.lookup(schema.Ref)
+		if err != nil {
+			return err
+		}
+		return d.checkRefs(target, seen)
+	}
+
+	children := []*Schema{schema.Items}
+	children = append(children, schema.AllOf...)
+	children = append(children, schema.OneOf...)
+	children = append(children, schema.AnyOf...)
+	for _, property := range schema.Properties {
+		children = append(children, property)
+	}
+	if schema.AdditionalProperties != nil {
+		children = append(children, schema.AdditionalProperties.Schema)
+	}
+
+	for _, child := range children {
+		if err := d.checkRefs(child, seen); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (d *Document) lookup(ref string) (*Schema, error) {
+	name, ok := strings.CutPrefix(ref, "#/components/schemas/")
+	if !ok {
+		return nil, fmt.Errorf("unsupported $ref %q", ref)
+	}
+
+	schema, ok := d.Components.Schemas[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown schema %q", ref)
+	}
+
+	return schema, nil
+}
+
+// resolve follows $ref, references are checked by Load
+func (d *Document) resolve(schema *Schema) *Schema {
+	for schema != nil && schema.Ref != "" {
+		schema, _ = d.lookup(schema.Ref)
+	}
+
+	return schema
+}
+
+// mergeParameters adds path parameters the operation doesn't override
+func mergeParameters(path, operation []*Parameter) []*Parameter {
+	result := append([]*Parameter(nil), operation...)
+
+	for _, param := range path {
+		overridden := false
+		for _, own := range operation {
+			if own.Name == param.Name && own.In == param.In {
+				overridden = true
+				break
+			}
+		}
+
+		if !overridden {
+			result = append(result, param)
+		}
+	}
+
+	return result
+}
+
+func splitPath(path string) []string {
+	return strings.Split(strings.Trim(path, "/"), "/")
+}
+
+func isParam(segment string) bool {
+	return strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}")
+}
@@ -0,0 +1,388 @@
+package openapi
+
+import (
+	"bsu-quiz/quiz/api"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// testSpec covers the parts of the specification the validator supports
+const testSpec = `
+openapi: 3.0.3
+servers:
+  - url: http://localhost:8080/v1
+paths:
+  /users/{id}:
+    parameters:
+      - in: path
+        name: id
+        required: true
+        schema:
+          type: integer
+    get:
+      operationId: getUser
+      parameters:
+        - in: query
+          name: fields
+          schema:
+            type: array
+            items:
+              type: string
+              enum: [login, role]
+        - in: header
+          name: X-Request-Id
+          schema:
+            type: string
+            format: uuid
+      responses:
+        '200':
+          description: User
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/User'
+        '204':
+          description: No content
+        4XX:
+          description: Problem
+          content:
+            application/problem+json:
+              schema:
+                $ref: '#/components/schemas/Problem'
+    patch:
+      operationId: updateUser
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/UserUpdate'
+      responses:
+        default:
+          description: Anything
+  /users/import:
+    post:
+      operationId: importUsers
+      requestBody:
+        required: true
+        content:
+          multipart/form-data:
+            schema:
+              type: object
+              required: [file]
+              properties:
+                file:
+                  type: string
+                  format: binary
+                dry_run:
+                  type: boolean
+      responses:
+        '200':
+          description: Report
+  /users/{id}/role:
+    put:
+      operationId: setRole
+      parameters:
+        - in: path
+          name: id
+          required: true
+          schema:
+            type: string
+      requestBody:
+        content:
+          application/x-www-form-urlencoded:
+            schema:
+              type: object
+              required: [role]
+              properties:
+                role:
+                  type: string
+                  enum: [student, teacher]
+      responses:
+        '204':
+          description: Done
+components:
+  schemas:
+    User:
+      type: object
+      required: [id, login]
+      additionalProperties: false
+      properties:
+        id:
+          type: integer
+          minimum: 1
+        login:
+          type: string
+          minLength: 3
+          maxLength: 8
+          pattern: '^[a-z]+$'
+        group:
+          type: string
+          nullable: true
+        tags:
+          type: array
+          maxItems: 2
+          items:
+            type: string
+        created_at:
+          type: string
+          format: date-time
+        extra:
+          type: object
+          additionalProperties:
+            type: integer
+        contact:
+          oneOf:
+            - $ref: '#/components/schemas/Email'
+            - $ref: '#/components/schemas/Phone'
+    UserUpdate:
+      allOf:
+        - type: object
+          properties:
+            role:
+              type: string
+              enum: [student, teacher]
+        - type: object
+          properties:
+            score:
+              type: number
+              maximum: 100
+    Email:
+      type: object
+      required: [email]
+      properties:
+        email:
+          type: string
+    Phone:
+      type: object
+      required: [phone]
+      properties:
+        phone:
+          type: string
+    Problem:
+      type: object
+      required: [title]
+      properties:
+        title:
+          type: string
+`
+
+func loadTestSpec(t *testing.T) *Document {
+	t.Helper()
+
+	doc, err := Load([]byte(testSpec))
+	if err != nil {
+		t.Fatalf("failed to load the test spec: %v", err)
+	}
+
+	return doc
+}
+
+func TestLoad(t *testing.T) {
+	doc := loadTestSpec(t)
+
+	if doc.BasePath() != "/v1" {
+		t.Errorf("got base path %q, want /v1", doc.BasePath())
+	}
+	if len(doc.Routes()) != 4 {
+		t.Errorf("got %d routes, want 4", len(doc.Routes()))
+	}
+
+	// Path parameters are shared by the operations of the path
+	get := doc.Operation(http.MethodGet, "/users/{id}")
+	if get == nil || get.OperationID != "getUser" || len(get.Parameters) != 3 {
+		t.Fatalf("got operation %+v, want getUser with 3 parameters", get)
+	}
+
+	// Operations override path parameters of the same name
+	put := doc.Operation(http.MethodPut, "/users/{id}/role")
+	if put == nil || len(put.Parameters) != 1 || put.Parameters[0].Schema.Type != "string" {
+		t.Errorf("got parameters %+v, want the string id of the operation", put.Parameters)
+	}
+
+	if doc.Operation(http.MethodDelete, "/users/{id}") != nil {
+		t.Errorf("got an operation for an undescribed method")
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		spec string
+		want string
+	}{
+		{
+			name: "not YAML",
+			spec: "paths: [",
+			want: "failed to parse spec",
+		},
+		{
+			name: "unknown schema",
+			spec: `
+paths:
+  /users:
+    get:
+      responses:
+        '200':
+          description: Users
+          content:
+            application/json:
+              schema:
+                type: array
+                items:
+                  $ref: '#/components/schemas/Missing'
+`,
+			want: `GET /users: unknown schema "#/components/schemas/Missing"`,
+		},
+		{
+			name: "external ref",
+			spec: `
+paths:
+  /users:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              $ref: 'users.yml#/User'
+      responses:
+        '201':
+          description: Created
+`,
+			want: `unsupported $ref "users.yml#/User"`,
+		},
+		{
+			name: "broken ref in a component",
+			spec: `
+paths:
+  /users:
+    get:
+      parameters:
+        - in: query
+          name: role
+          schema:
+            $ref: '#/components/schemas/Role'
+      responses:
+        '200':
+          description: Users
+components:
+  schemas:
+    Role:
+      oneOf:
+        - $ref: '#/components/schemas/Missing'
+`,
+			want: `unknown schema "#/components/schemas/Missing"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Load([]byte(tt.spec))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadRecursiveSchema(t *testing.T) {
+	spec := `
+paths:
+  /tree:
+    get:
+      responses:
+        '200':
+          description: Tree
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Node'
+components:
+  schemas:
+    Node:
+      type: object
+      properties:
+        children:
+          type: array
+          items:
+            $ref: '#/components/schemas/Node'
+`
+
+	if _, err := Load([]byte(spec)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestFindRoute(t *testing.T) {
+	doc := loadTestSpec(t)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		want   string
+		params map[string]string
+	}{
+		{name: "path parameter", method: http.MethodGet, path: "/v1/users/42", want: "getUser", params: map[string]string{"id": "42"}},
+		{name: "escaped parameter", method: http.MethodPut, path: "/v1/users/a%20b/role", want: "setRole", params: map[string]string{"id": "a b"}},
+		{name: "trailing slash", method: http.MethodGet, path: "/v1/users/42/", want: "getUser", params: map[string]string{"id": "42"}},
+		{name: "literal wins over parameter", method: http.MethodPost, path: "/v1/users/import", want: "importUsers", params: map[string]string{}},
+		{name: "other method", method: http.MethodDelete, path: "/v1/users/42"},
+		{name: "outside the base path", method: http.MethodGet, path: "/users/42"},
+		{name: "longer path", method: http.MethodGet, path: "/v1/users/42/role/extra"},
+		{name: "bad escape", method: http.MethodGet, path: "/v1/users/%zz"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			route, params := doc.FindRoute(tt.method, tt.path)
+
+			if tt.want == "" {
+				if route != nil {
+					t.Fatalf("got route %s %s, want none", route.Method, route.Path)
+				}
+				return
+			}
+
+			if route == nil {
+				t.Fatalf("got no route, want %s", tt.want)
+			}
+			if route.Operation.OperationID != tt.want {
+				t.Errorf("got operation %s, want %s", route.Operation.OperationID, tt.want)
+			}
+			if len(params) != len(tt.params) {
+				t.Fatalf("got params %v, want %v", params, tt.params)
+			}
+			for name, value := range tt.params {
+				if params[name] != value {
+					t.Errorf("got %s = %q, want %q", name, params[name], value)
+				}
+			}
+		})
+	}
+}
+
+func TestAdminSpec(t *testing.T) {
+	doc, err := Load(api.AdminSpec)
+	if err != nil {
+		t.Fatalf("failed to load the admin spec: %v", err)
+	}
+
+	if doc.BasePath() != "/v1" {
+		t.Errorf("got base path %q, want /v1", doc.BasePath())
+	}
+
+	route, params := doc.FindRoute(http.MethodPost, "/v1/admin/users/import")
+	if route == nil || route.Path != "/admin/users/import" {
+		t.Fatalf("got route %+v, want the import", route)
+	}
+	if len(params) != 0 {
+		t.Errorf("got params %v for a literal path", params)
+	}
+
+	route, params = doc.FindRoute(http.MethodGet, "/v1/admin/users/7")
+	if route == nil || route.Path != "/admin/users/{id}" || params["id"] != "7" {
+		t.Fatalf("got route %+v with %v, want the user by id", route, params)
+	}
+}
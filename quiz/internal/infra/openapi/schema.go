@@ -0,0 +1,279 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"gopkg.in/yaml.v3"
+)
+
+// Schema is the subset of the OpenAPI schema object the validator checks
+type Schema struct {
+	Ref                  string             `yaml:"$ref"`
+	Type                 string             `yaml:"type"`
+	Format               string             `yaml:"format"`
+	Nullable             bool               `yaml:"nullable"`
+	Enum                 []any              `yaml:"enum"`
+	Properties           map[string]*Schema `yaml:"properties"`
+	Required             []string           `yaml:"required"`
+	AdditionalProperties *Additional        `yaml:"additionalProperties"`
+	Items                *Schema            `yaml:"items"`
+	AllOf                []*Schema          `yaml:"allOf"`
+	OneOf                []*Schema          `yaml:"oneOf"`
+	AnyOf                []*Schema          `yaml:"anyOf"`
+	MinLength            *int               `yaml:"minLength"`
+	MaxLength            *int               `yaml:"maxLength"`
+	Pattern              string             `yaml:"pattern"`
+	Minimum              *float64           `yaml:"minimum"`
+	Maximum              *float64           `yaml:"maximum"`
+	MinItems             *int               `yaml:"minItems"`
+	MaxItems             *int               `yaml:"maxItems"`
+}
+
+// Additional is the additionalProperties keyword, a boolean or a schema.
+// Additional properties are allowed unless it is false
+type Additional struct {
+	Denied bool
+	Schema *Schema
+}
+
+func (a *Additional) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		var allowed bool
+		if err := node.Decode(&allowed); err != nil {
+			return err
+		}
+		a.Denied = !allowed
+		return nil
+	}
+
+	return node.Decode(&a.Schema)
+}
+
+// validate checks a decoded JSON value, numbers must be json.Number.
+// Problems are added to fields keyed by the path of the value
+func (d *Document) validate(schema *Schema, value any, path string, fields map[string]string) {
+	schema = d.resolve(schema)
+	if schema == nil {
+		return
+	}
+
+	if value == nil {
+		if !schema.Nullable && schema.Type != "" {
+			addField(fields, path, "must not be null")
+		}
+		return
+	}
+
+	for _, sub := range schema.AllOf {
+		d.validate(sub, value, path, fields)
+	}
+
+	if len(schema.OneOf) > 0 && d.countMatches(schema.OneOf, value) != 1 {
+		addField(fields, path, "must match exactly one schema")
+	}
+
+	if len(schema.AnyOf) > 0 && d.countMatches(schema.AnyOf, value) == 0 {
+		addField(fields, path, "must match at least one schema")
+	}
+
+	kind := schema.Type
+	if kind == "" && schema.Properties != nil {
+		kind = "object"
+	}
+
+	switch kind {
+	case "object":
+		d.validateObject(schema, value, path, fields)
+	case "array":
+		d.validateArray(schema, value, path, fields)
+	case "string":
+		validateString(schema, value, path, fields)
+	case "integer", "number":
+		validateNumber(schema, value, path, fields)
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			addField(fields, path, "must be a boolean")
+		}
+	}
+
+	if len(schema.Enum) > 0 && !inEnum(schema.Enum, value) {
+		addField(fields, path, "must be one of "+formatEnum(schema.Enum))
+	}
+}
+
+func (d *Document) validateObject(schema *Schema, value any, path string, fields map[string]string) {
+	object, ok := value.(map[string]any)
+	if !ok {
+		addField(fields, path, "must be an object")
+		return
+	}
+
+	for _, name := range schema.Required {
+		if _, ok := object[name]; !ok {
+			addField(fields, joinPath(path, name), "is required")
+		}
+	}
+
+	for name, property := range object {
+		if propertySchema, ok := schema.Properties[name]; ok {
+			d.validate(propertySchema, property, joinPath(path, name), fields)
+			continue
+		}
+
+		if additional := schema.AdditionalProperties; additional != nil {
+			if additional.Denied {
+				addField(fields, joinPath(path, name), "is not allowed")
+			} else if additional.Schema != nil {
+				d.validate(additional.Schema, property, joinPath(path, name), fields)
+			}
+		}
+	}
+}
+
+func (d *Document) validateArray(schema *Schema, value any, path string, fields map[string]string) {
+	items, ok := value.([]any)
+	if !ok {
+		addField(fields, path, "must be an array")
+		return
+	}
+
+	if schema.MinItems != nil && len(items) < *schema.MinItems {
+		addField(fields, path, fmt.Sprintf("must have at least %d items", *schema.MinItems))
+	}
+	if schema.MaxItems != nil && len(items) > *schema.MaxItems {
+		addField(fields, path, fmt.Sprintf("must have at most %d items", *schema.MaxItems))
+	}
+
+	for i, item := range items {
+		d.validate(schema.Items, item, fmt.Sprintf("%s[%d]", path, i), fields)
+	}
+}
+
+func validateString(schema *Schema, value any, path string, fields map[string]string) {
+	str, ok := value.(string)
+	if !ok {
+		addField(fields, path, "must be a string")
+		return
+	}
+
+	length := utf8.RuneCountInString(str)
+	if schema.MinLength != nil && length < *schema.MinLength {
+		addField(fields, path, fmt.Sprintf("must be at least %d characters", *schema.MinLength))
+	}
+	if schema.MaxLength != nil && length > *schema.MaxLength {
+		addField(fields, path, fmt.Sprintf("must be at most %d characters", *schema.MaxLength))
+	}
+
+	if schema.Pattern != "" {
+		if matched, err := regexp.MatchString(schema.Pattern, str); err == nil && !matched {
+			addField(fields, path, "must match "+schema.Pattern)
+		}
+	}
+
+	switch schema.Format {
+	case "uuid":
+		if _, err := uuid.Parse(str); err != nil {
+			addField(fields, path, "must be a UUID")
+		}
+	case "date-time":
+		if _, err := time.Parse(time.RFC3339, str); err != nil {
+			addField(fields, path, "must be an RFC 3339 time")
+		}
+	case "date":
+		if _, err := time.Parse(time.DateOnly, str); err != nil {
+			addField(fields, path, "must be a date like 2025-06-22")
+		}
+	}
+}
+
+func validateNumber(schema *Schema, value any, path string, fields map[string]string) {
+	number, ok := value.(json.Number)
+	if !ok {
+		if schema.Type == "integer" {
+			addField(fields, path, "must be an integer")
+		} else {
+			addField(fields, path, "must be a number")
+		}
+		return
+	}
+
+	if schema.Type == "integer" {
+		if _, err := number.Int64(); err != nil {
+			addField(fields, path, "must be an integer")
+			return
+		}
+	}
+
+	f, err := number.Float64()
+	if err != nil {
+		addField(fields, path, "must be a number")
+		return
+	}
+
+	if schema.Minimum != nil && f < *schema.Minimum {
+		addField(fields, path, fmt.Sprintf("must be at least %v", *schema.Minimum))
+	}
+	if schema.Maximum != nil && f > *schema.Maximum {
+		addField(fields, path, fmt.Sprintf("must be at most %v", *schema.Maximum))
+	}
+}
+
+func (d *Document) countMatches(schemas []*Schema, value any) int {
+	matches := 0
+	for _, schema := range schemas {
+		problems := make(map[string]string)
+		d.validate(schema, value, "", problems)
+		if len(problems) == 0 {
+			matches++
+		}
+	}
+
+	return matches
+}
+
+// inEnum compares by the printed value, YAML and JSON decode numbers differently
+func inEnum(enum []any, value any) bool {
+	for _, allowed := range enum {
+		if fmt.Sprint(allowed) == fmt.Sprint(value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func formatEnum(enum []any) string {
+	values := make([]string, 0, len(enum))
+	for _, value := range enum {
+		values = append(values, fmt.Sprint(value))
+	}
+	sort.Strings(values)
+
+	return strings.Join(values, ", ")
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+
+	return path + "." + name
+}
+
+// addField keeps the first problem of a field, the root value is called body
+func addField(fields map[string]string, path, message string) {
+	if path == "" {
+		path = "body"
+	}
+
+	if _, ok := fields[path]; !ok {
+		fields[path] = message
+	}
+}
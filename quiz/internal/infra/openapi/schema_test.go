@@ -0,0 +1,177 @@
+package openapi
+
+import "testing"
+
+func TestValidateSchema(t *testing.T) {
+	doc := loadTestSpec(t)
+	user := &Schema{Ref: "#/components/schemas/User"}
+
+	tests := []struct {
+		name string
+		body string
+		// fields are the expected problems by the path of the value
+		fields map[string]string
+	}{
+		{
+			name: "valid",
+			body: `{"id": 1, "login": "ivan", "group": null, "tags": ["a"], "created_at": "2025-06-22T10:00:00Z", "extra": {"a": 1}, "contact": {"email": "a@b"}}`,
+		},
+		{
+			name:   "missing required",
+			body:   `{"login": "ivan"}`,
+			fields: map[string]string{"id": "is required"},
+		},
+		{
+			name:   "not an object",
+			body:   `[1]`,
+			fields: map[string]string{"body": "must be an object"},
+		},
+		{
+			name:   "null",
+			body:   `null`,
+			fields: map[string]string{"body": "must not be null"},
+		},
+		{
+			name:   "null of a non-nullable property",
+			body:   `{"id": 1, "login": null}`,
+			fields: map[string]string{"login": "must not be null"},
+		},
+		{
+			name:   "unknown property",
+			body:   `{"id": 1, "login": "ivan", "password": "x"}`,
+			fields: map[string]string{"password": "is not allowed"},
+		},
+		{
+			name:   "wrong types",
+			body:   `{"id": "1", "login": 5, "tags": "a"}`,
+			fields: map[string]string{"id": "must be an integer", "login": "must be a string", "tags": "must be an array"},
+		},
+		{
+			name:   "fraction for an integer",
+			body:   `{"id": 1.5, "login": "ivan"}`,
+			fields: map[string]string{"id": "must be an integer"},
+		},
+		{
+			name:   "below minimum",
+			body:   `{"id": 0, "login": "ivan"}`,
+			fields: map[string]string{"id": "must be at least 1"},
+		},
+		{
+			name:   "too short",
+			body:   `{"id": 1, "login": "iv"}`,
+			fields: map[string]string{"login": "must be at least 3 characters"},
+		},
+		{
+			name:   "too long",
+			body:   `{"id": 1, "login": "ivanivanov"}`,
+			fields: map[string]string{"login": "must be at most 8 characters"},
+		},
+		{
+			name:   "pattern",
+			body:   `{"id": 1, "login": "Ivan"}`,
+			fields: map[string]string{"login": "must match ^[a-z]+$"},
+		},
+		{
+			name:   "date-time",
+			body:   `{"id": 1, "login": "ivan", "created_at": "yesterday"}`,
+			fields: map[string]string{"created_at": "must be an RFC 3339 time"},
+		},
+		{
+			name:   "too many items",
+			body:   `{"id": 1, "login": "ivan", "tags": ["a", "b", "c"]}`,
+			fields: map[string]string{"tags": "must have at most 2 items"},
+		},
+		{
+			name:   "wrong item",
+			body:   `{"id": 1, "login": "ivan", "tags": ["a", 2]}`,
+			fields: map[string]string{"tags[1]": "must be a string"},
+		},
+		{
+			name:   "additional properties schema",
+			body:   `{"id": 1, "login": "ivan", "extra": {"a": "b"}}`,
+			fields: map[string]string{"extra.a": "must be an integer"},
+		},
+		{
+			name:   "no oneOf match",
+			body:   `{"id": 1, "login": "ivan", "contact": {}}`,
+			fields: map[string]string{"contact": "must match exactly one schema"},
+		},
+		{
+			name:   "several oneOf matches",
+			body:   `{"id": 1, "login": "ivan", "contact": {"email": "a@b", "phone": "1"}}`,
+			fields: map[string]string{"contact": "must match exactly one schema"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, err := decodeJSON([]byte(tt.body))
+			if err != nil {
+				t.Fatalf("bad test body: %v", err)
+			}
+
+			fields := make(map[string]string)
+			doc.validate(user, value, "", fields)
+
+			checkFields(t, fields, tt.fields)
+		})
+	}
+}
+
+func TestValidateAllOf(t *testing.T) {
+	doc := loadTestSpec(t)
+	update := &Schema{Ref: "#/components/schemas/UserUpdate"}
+
+	tests := []struct {
+		name   string
+		body   string
+		fields map[string]string
+	}{
+		{name: "valid", body: `{"role": "teacher", "score": 99.5}`},
+		{name: "first schema", body: `{"role": "admin"}`, fields: map[string]string{"role": "must be one of student, teacher"}},
+		{name: "second schema", body: `{"score": 101}`, fields: map[string]string{"score": "must be at most 100"}},
+		{name: "both", body: `{"role": 1, "score": "a"}`, fields: map[string]string{"role": "must be a string", "score": "must be a number"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, err := decodeJSON([]byte(tt.body))
+			if err != nil {
+				t.Fatalf("bad test body: %v", err)
+			}
+
+			fields := make(map[string]string)
+			doc.validate(update, value, "", fields)
+
+			checkFields(t, fields, tt.fields)
+		})
+	}
+}
+
+func TestInEnum(t *testing.T) {
+	// YAML decodes 1 to int, JSON to json.Number
+	value, _ := decodeJSON([]byte(`1`))
+
+	if !inEnum([]any{1, 2}, value) {
+		t.Errorf("json number 1 isn't found in [1 2]")
+	}
+	if inEnum([]any{2, 3}, value) {
+		t.Errorf("json number 1 is found in [2 3]")
+	}
+	if got := formatEnum([]any{"teacher", "admin", "student"}); got != "admin, student, teacher" {
+		t.Errorf("got %q, want the sorted values", got)
+	}
+}
+
+func checkFields(t *testing.T, got, want map[string]string) {
+	t.Helper()
+
+	if len(got) != len(want) {
+		t.Fatalf("got problems %v, want %v", got, want)
+	}
+	for field, message := range want {
+		if got[field] != message {
+			t.Errorf("got %s %q, want %q", field, got[field], message)
+		}
+	}
+}
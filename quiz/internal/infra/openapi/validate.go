@@ -0,0 +1,263 @@
+package openapi
+
+import (
+	"bsu-quiz/quiz/internal/domain/errors"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// maxMemory is how much of a multipart body is kept in memory, as in Gin
+const maxMemory = 32 << 20
+
+// ValidateRequest checks the parameters and the body of the request. The
+// body is read and replaced, so handlers can still bind it. A mismatch is
+// a validation error with the invalid fields
+func (o *Operation) ValidateRequest(r *http.Request, pathParams map[string]string) error {
+	fields := make(map[string]string)
+
+	query := r.URL.Query()
+	for _, param := range o.Parameters {
+		var raw string
+		var present bool
+
+		switch param.In {
+		case "path":
+			raw, present = pathParams[param.Name]
+		case "query":
+			present = query.Has(param.Name)
+			raw = query.Get(param.Name)
+		case "header":
+			raw = r.Header.Get(param.Name)
+			present = raw != ""
+		default:
+			continue
+		}
+
+		if !present {
+			if param.Required {
+				addField(fields, param.Name, "is required")
+			}
+			continue
+		}
+
+		schema := o.doc.resolve(param.Schema)
+		o.doc.validate(schema, parseParam(schema, raw), param.Name, fields)
+	}
+
+	if o.RequestBody != nil {
+		if err := o.validateRequestBody(r, fields); err != nil {
+			return err
+		}
+	}
+
+	if len(fields) > 0 {
+		return invalid("request doesn't match the API spec", fields)
+	}
+
+	return nil
+}
+
+func (o *Operation) validateRequestBody(r *http.Request, fields map[string]string) error {
+	mediaType := contentType(r.Header)
+
+	if r.Body == nil || r.Body == http.NoBody || r.ContentLength == 0 {
+		if o.RequestBody.Required {
+			addField(fields, "body", "is required")
+		}
+		return nil
+	}
+
+	media, ok := o.RequestBody.Content[mediaType]
+	if !ok {
+		addField(fields, "body", "unsupported content type "+mediaType)
+		return nil
+	}
+
+	switch {
+	case isJSON(mediaType):
+		data, err := io.ReadAll(r.Body)
+		if err != nil {
+			return fmt.Errorf("failed to read request body: %w", err)
+		}
+		r.Body = io.NopCloser(bytes.NewReader(data))
+
+		value, err := decodeJSON(data)
+		if err != nil {
+			addField(fields, "body", "must be valid JSON")
+			return nil
+		}
+		o.doc.validate(media.Schema, value, "", fields)
+
+	case mediaType == "multipart/form-data", mediaType == "application/x-www-form-urlencoded":
+		// Parsed forms are kept in the request, Gin reuses them
+		var err error
+		if mediaType == "multipart/form-data" {
+			err = r.ParseMultipartForm(maxMemory)
+		} else {
+			err = r.ParseForm()
+		}
+		if err != nil {
+			addField(fields, "body", "invalid form")
+			return nil
+		}
+		o.validateForm(r, media.Schema, fields)
+	}
+
+	return nil
+}
+
+// validateForm checks form values like query parameters and files by presence
+func (o *Operation) validateForm(r *http.Request, schema *Schema, fields map[string]string) {
+	schema = o.doc.resolve(schema)
+	if schema == nil {
+		return
+	}
+
+	for name, property := range schema.Properties {
+		property = o.doc.resolve(property)
+
+		if property.Format == "binary" {
+			if r.MultipartForm == nil || len(r.MultipartForm.File[name]) == 0 {
+				if contains(schema.Required, name) {
+					addField(fields, name, "is required")
+				}
+			}
+			continue
+		}
+
+		if _, ok := r.PostForm[name]; !ok {
+			if contains(schema.Required, name) {
+				addField(fields, name, "is required")
+			}
+			continue
+		}
+
+		o.doc.validate(property, parseParam(property, r.PostForm.Get(name)), name, fields)
+	}
+}
+
+// ValidateResponse checks that the status is documented and the body
+// matches the schema of its content type
+func (o *Operation) ValidateResponse(status int, header http.Header, body []byte) error {
+	response := o.response(status)
+	if response == nil {
+		return errors.Validation(fmt.Sprintf("response status %d isn't documented", status))
+	}
+
+	if len(response.Content) == 0 {
+		if len(body) > 0 {
+			return errors.Validation(fmt.Sprintf("response %d has a body but none is documented", status))
+		}
+		return nil
+	}
+
+	mediaType := contentType(header)
+	media, ok := response.Content[mediaType]
+	if !ok {
+		return errors.Validation(fmt.Sprintf("content type %q of response %d isn't documented", mediaType, status))
+	}
+
+	if !isJSON(mediaType) {
+		return nil
+	}
+
+	value, err := decodeJSON(body)
+	if err != nil {
+		return errors.Validation("response body isn't valid JSON")
+	}
+
+	fields := make(map[string]string)
+	o.doc.validate(media.Schema, value, "", fields)
+	if len(fields) > 0 {
+		return invalid("response doesn't match the API spec", fields)
+	}
+
+	return nil
+}
+
+// response finds the response of a status: exact, a range like 2XX or the default
+func (o *Operation) response(status int) *Response {
+	keys := []string{strconv.Itoa(status), fmt.Sprintf("%dXX", status/100), "default"}
+	for _, key := range keys {
+		if response, ok := o.Responses[key]; ok {
+			return response
+		}
+	}
+
+	return nil
+}
+
+// parseParam converts a string value to what JSON decoding would produce
+// for the schema type, values of the wrong type are left for validate to report
+func parseParam(schema *Schema, raw string) any {
+	if schema == nil {
+		return raw
+	}
+
+	switch schema.Type {
+	case "integer", "number":
+		return json.Number(raw)
+	case "boolean":
+		if b, err := strconv.ParseBool(raw); err == nil {
+			return b
+		}
+	case "array":
+		items := make([]any, 0)
+		for _, item := range strings.Split(raw, ",") {
+			items = append(items, parseParam(schema.Items, item))
+		}
+		return items
+	}
+
+	return raw
+}
+
+func decodeJSON(data []byte) (any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+
+	return value, nil
+}
+
+func contentType(header http.Header) string {
+	mediaType, _, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		return ""
+	}
+
+	return mediaType
+}
+
+func isJSON(mediaType string) bool {
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+func invalid(message string, fields map[string]string) error {
+	err := errors.Validation(message)
+	for field, problem := range fields {
+		err.WithField(field, problem)
+	}
+
+	return err
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,228 @@
+package openapi
+
+import (
+	"bsu-quiz/quiz/internal/domain/errors"
+	"bytes"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestValidateRequestParameters(t *testing.T) {
+	doc := loadTestSpec(t)
+
+	tests := []struct {
+		name   string
+		target string
+		header string
+		fields map[string]string
+	}{
+		{name: "valid", target: "/v1/users/42?fields=login,role", header: "0b0c8a6e-7f0e-4a8b-9a55-111111111111"},
+		{name: "without optional", target: "/v1/users/42"},
+		{name: "not an integer", target: "/v1/users/abc", fields: map[string]string{"id": "must be an integer"}},
+		{name: "array item", target: "/v1/users/42?fields=login,password", fields: map[string]string{"fields[1]": "must be one of login, role"}},
+		{name: "header", target: "/v1/users/42", header: "42", fields: map[string]string{"X-Request-Id": "must be a UUID"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			if tt.header != "" {
+				r.Header.Set("X-Request-Id", tt.header)
+			}
+
+			route, params := doc.FindRoute(r.Method, r.URL.Path)
+			if route == nil {
+				t.Fatalf("no route for %s", tt.target)
+			}
+
+			checkValidation(t, route.Operation.ValidateRequest(r, params), tt.fields)
+		})
+	}
+}
+
+func TestValidateRequestMissingPathParameter(t *testing.T) {
+	doc := loadTestSpec(t)
+	operation := doc.Operation(http.MethodGet, "/users/{id}")
+
+	r := httptest.NewRequest(http.MethodGet, "/v1/users/42", nil)
+	err := operation.ValidateRequest(r, map[string]string{})
+
+	checkValidation(t, err, map[string]string{"id": "is required"})
+}
+
+func TestValidateRequestJSONBody(t *testing.T) {
+	doc := loadTestSpec(t)
+	operation := doc.Operation(http.MethodPatch, "/users/{id}")
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		fields      map[string]string
+	}{
+		{name: "valid", contentType: "application/json", body: `{"role": "student"}`},
+		{name: "with charset", contentType: "application/json; charset=utf-8", body: `{"score": 10}`},
+		{name: "invalid", contentType: "application/json", body: `{"role": "admin"}`, fields: map[string]string{"role": "must be one of student, teacher"}},
+		{name: "not JSON", contentType: "application/json", body: `{"role":`, fields: map[string]string{"body": "must be valid JSON"}},
+		{name: "missing", contentType: "application/json", fields: map[string]string{"body": "is required"}},
+		{name: "other content type", contentType: "text/plain", body: "role=student", fields: map[string]string{"body": "unsupported content type text/plain"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPatch, "/v1/users/42", strings.NewReader(tt.body))
+			r.Header.Set("Content-Type", tt.contentType)
+
+			err := operation.ValidateRequest(r, map[string]string{"id": "42"})
+			checkValidation(t, err, tt.fields)
+
+			// Handlers bind the body after the validation
+			body, _ := io.ReadAll(r.Body)
+			if string(body) != tt.body {
+				t.Errorf("got body %q after validation, want %q", body, tt.body)
+			}
+		})
+	}
+}
+
+func TestValidateRequestForms(t *testing.T) {
+	doc := loadTestSpec(t)
+
+	t.Run("urlencoded", func(t *testing.T) {
+		operation := doc.Operation(http.MethodPut, "/users/{id}/role")
+		params := map[string]string{"id": "7"}
+
+		for _, tt := range []struct {
+			form   url.Values
+			fields map[string]string
+		}{
+			{form: url.Values{"role": {"teacher"}}},
+			{form: url.Values{"role": {"admin"}}, fields: map[string]string{"role": "must be one of student, teacher"}},
+			{form: url.Values{"other": {"x"}}, fields: map[string]string{"role": "is required"}},
+		} {
+			r := httptest.NewRequest(http.MethodPut, "/v1/users/7/role", strings.NewReader(tt.form.Encode()))
+			r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+			checkValidation(t, operation.ValidateRequest(r, params), tt.fields)
+		}
+
+		// The body is optional
+		r := httptest.NewRequest(http.MethodPut, "/v1/users/7/role", nil)
+		checkValidation(t, operation.ValidateRequest(r, params), nil)
+	})
+
+	t.Run("multipart", func(t *testing.T) {
+		operation := doc.Operation(http.MethodPost, "/users/import")
+
+		for _, tt := range []struct {
+			name   string
+			file   bool
+			dryRun string
+			fields map[string]string
+		}{
+			{name: "valid", file: true, dryRun: "true"},
+			{name: "without file", dryRun: "false", fields: map[string]string{"file": "is required"}},
+			{name: "not a boolean", file: true, dryRun: "maybe", fields: map[string]string{"dry_run": "must be a boolean"}},
+		} {
+			t.Run(tt.name, func(t *testing.T) {
+				var body bytes.Buffer
+				writer := multipart.NewWriter(&body)
+				if tt.file {
+					part, _ := writer.CreateFormFile("file", "users.csv")
+					part.Write([]byte("login\nivan\n"))
+				}
+				writer.WriteField("dry_run", tt.dryRun)
+				writer.Close()
+
+				r := httptest.NewRequest(http.MethodPost, "/v1/users/import", &body)
+				r.Header.Set("Content-Type", writer.FormDataContentType())
+
+				checkValidation(t, operation.ValidateRequest(r, nil), tt.fields)
+			})
+		}
+	})
+}
+
+func TestValidateResponse(t *testing.T) {
+	doc := loadTestSpec(t)
+	operation := doc.Operation(http.MethodGet, "/users/{id}")
+
+	tests := []struct {
+		name        string
+		status      int
+		contentType string
+		body        string
+		// wantErr is a part of the expected error, empty if the response is valid
+		wantErr string
+		fields  map[string]string
+	}{
+		{name: "valid", status: 200, contentType: "application/json", body: `{"id": 1, "login": "ivan"}`},
+		{name: "no content", status: 204},
+		{name: "status range", status: 404, contentType: "application/problem+json", body: `{"title": "Not found"}`},
+		{name: "undocumented status", status: 500, contentType: "application/json", body: `{}`, wantErr: "response status 500 isn't documented"},
+		{name: "unexpected body", status: 204, body: `{}`, wantErr: "response 204 has a body but none is documented"},
+		{name: "undocumented content type", status: 200, contentType: "text/html", body: "<p>", wantErr: `content type "text/html" of response 200 isn't documented`},
+		{name: "not JSON", status: 200, contentType: "application/json", body: "{", wantErr: "response body isn't valid JSON"},
+		{
+			name:        "body mismatch",
+			status:      200,
+			contentType: "application/json",
+			body:        `{"id": 1}`,
+			wantErr:     "response doesn't match the API spec",
+			fields:      map[string]string{"login": "is required"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			if tt.contentType != "" {
+				header.Set("Content-Type", tt.contentType)
+			}
+
+			err := operation.ValidateResponse(tt.status, header, []byte(tt.body))
+
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+
+			if !errors.Is(err, errors.ErrValidation) || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("got error %v, want %q", err, tt.wantErr)
+			}
+			if tt.fields != nil {
+				checkFields(t, errors.Fields(err), tt.fields)
+			}
+		})
+	}
+
+	// The default response covers any status
+	update := doc.Operation(http.MethodPatch, "/users/{id}")
+	if err := update.ValidateResponse(http.StatusTeapot, http.Header{}, nil); err != nil {
+		t.Errorf("unexpected error for the default response: %v", err)
+	}
+}
+
+// checkValidation expects no error without fields, a validation error with them otherwise
+func checkValidation(t *testing.T, err error, fields map[string]string) {
+	t.Helper()
+
+	if len(fields) == 0 {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return
+	}
+
+	if !errors.Is(err, errors.ErrValidation) {
+		t.Fatalf("got error %v, want a validation error", err)
+	}
+	checkFields(t, errors.Fields(err), fields)
+}
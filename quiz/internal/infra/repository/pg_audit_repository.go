@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"bsu-quiz/quiz/internal/domain/models"
+	"bsu-quiz/quiz/internal/ports"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type PgAuditRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPgAuditRepository(pool *pgxpool.Pool) ports.AuditRepositorier {
+	return &PgAuditRepository{pool: pool}
+}
+
+func (r *PgAuditRepository) Create(ctx context.Context, entry *models.AuditEntry) (int64, error) {
+	query := `
+		INSERT INTO audit_log (actor_id, action, target_type, target_id, diff, ip, user_agent)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at
+	`
+
+	// A nil RawMessage is stored as SQL NULL rather than JSON null
+	var diff any
+	if len(entry.Diff) > 0 {
+		diff = entry.Diff
+	}
+
+	err := conn(ctx, r.pool).QueryRow(
+		ctx,
+		query,
+		entry.ActorID,
+		entry.Action,
+		entry.TargetType,
+		entry.TargetID,
+		diff,
+		entry.IP,
+		entry.UserAgent,
+	).Scan(&entry.ID, &entry.CreatedAt)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return entry.ID, nil
+}
+
+func (r *PgAuditRepository) List(ctx context.Context, filter models.AuditFilter) ([]*models.AuditEntry, error) {
+	conditions, args := auditFilterConditions(filter)
+
+	args = append(args, filter.Offset, filter.Limit)
+	query := fmt.Sprintf(`
+		SELECT a.id, a.actor_id, COALESCE(u.login, ''), a.action, a.target_type, a.target_id,
+			a.diff, a.ip, a.user_agent, a.created_at
+		FROM audit_log a
+		LEFT JOIN users u ON u.id = a.actor_id
+		WHERE %s
+		ORDER BY a.created_at DESC, a.id DESC
+		OFFSET $%d LIMIT $%d
+	`, strings.Join(conditions, " AND "), len(args)-1, len(args))
+
+	rows, err := conn(ctx, r.pool).Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []*models.AuditEntry
+	for rows.Next() {
+		entry := &models.AuditEntry{}
+		var diff []byte
+		err := rows.Scan(
+			&entry.ID,
+			&entry.ActorID,
+			&entry.ActorLogin,
+			&entry.Action,
+			&entry.TargetType,
+			&entry.TargetID,
+			&diff,
+			&entry.IP,
+			&entry.UserAgent,
+			&entry.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		entry.Diff = diff
+		entries = append(entries, entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+func (r *PgAuditRepository) Count(ctx context.Context, filter models.AuditFilter) (int, error) {
+	conditions, args := auditFilterConditions(filter)
+
+	query := `
+		SELECT COUNT(*)
+		FROM audit_log a
+		WHERE ` + strings.Join(conditions, " AND ")
+
+	var count int
+	if err := conn(ctx, r.pool).QueryRow(ctx, query, args...).Scan(&count); err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// auditFilterConditions builds the WHERE conditions of the filter
+// over audit_log aliased as a
+func auditFilterConditions(filter models.AuditFilter) ([]string, []any) {
+	conditions := []string{"TRUE"}
+	var args []any
+
+	if filter.ActorID != nil {
+		args = append(args, *filter.ActorID)
+		conditions = append(conditions, fmt.Sprintf("a.actor_id = $%d", len(args)))
+	}
+
+	if filter.Action != "" {
+		args = append(args, filter.Action)
+		conditions = append(conditions, fmt.Sprintf("a.action = $%d", len(args)))
+	}
+
+	if filter.TargetType != "" {
+		args = append(args, filter.TargetType)
+		conditions = append(conditions, fmt.Sprintf("a.target_type = $%d", len(args)))
+	}
+
+	if filter.TargetID != "" {
+		args = append(args, filter.TargetID)
+		conditions = append(conditions, fmt.Sprintf("a.target_id = $%d", len(args)))
+	}
+
+	if filter.From != nil {
+		args = append(args, *filter.From)
+		conditions = append(conditions, fmt.Sprintf("a.created_at >= $%d", len(args)))
+	}
+
+	if filter.To != nil {
+		args = append(args, *filter.To)
+		conditions = append(conditions, fmt.Sprintf("a.created_at < $%d", len(args)))
+	}
+
+	return conditions, args
+}
@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"bsu-quiz/quiz/internal/domain/errors"
+	"bsu-quiz/quiz/internal/domain/models"
+	"bsu-quiz/quiz/internal/ports"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type PgCollaboratorRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPgCollaboratorRepository(pool *pgxpool.Pool) ports.CollaboratorRepositorier {
+	return &PgCollaboratorRepository{pool: pool}
+}
+
+func (r *PgCollaboratorRepository) Upsert(ctx context.Context, collaborator *models.Collaborator) error {
+	collaborator.CreatedAt = time.Now()
+
+	query := `
+		INSERT INTO quiz_collaborators (quiz_id, user_id, role, invited_by, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (quiz_id, user_id) DO UPDATE SET role = EXCLUDED.role
+	`
+
+	_, err := conn(ctx, r.pool).Exec(
+		ctx,
+		query,
+		collaborator.QuizID,
+		collaborator.UserID,
+		collaborator.Role,
+		collaborator.InvitedBy,
+		collaborator.CreatedAt,
+	)
+
+	return err
+}
+
+func (r *PgCollaboratorRepository) GetRole(ctx context.Context, quizID uuid.UUID, userID int64) (models.QuizRole, error) {
+	query := `SELECT role FROM quiz_collaborators WHERE quiz_id = $1 AND user_id = $2`
+
+	var role models.QuizRole
+	err := conn(ctx, r.pool).QueryRow(ctx, query, quizID, userID).Scan(&role)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.QuizRoleNone, nil
+		}
+		return models.QuizRoleNone, err
+	}
+
+	return role, nil
+}
+
+func (r *PgCollaboratorRepository) List(ctx context.Context, quizID uuid.UUID) ([]models.Collaborator, error) {
+	query := `
+		SELECT c.quiz_id, c.user_id, u.login, c.role, c.invited_by, c.created_at
+		FROM quiz_collaborators c
+		JOIN users u ON u.id = c.user_id
+		WHERE c.quiz_id = $1
+		ORDER BY c.created_at
+	`
+
+	rows, err := conn(ctx, r.pool).Query(ctx, query, quizID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var collaborators []models.Collaborator
+	for rows.Next() {
+		c := models.Collaborator{}
+		if err := rows.Scan(
+			&c.QuizID,
+			&c.UserID,
+			&c.Login,
+			&c.Role,
+			&c.InvitedBy,
+			&c.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		collaborators = append(collaborators, c)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return collaborators, nil
+}
+
+func (r *PgCollaboratorRepository) Remove(ctx context.Context, quizID uuid.UUID, userID int64) error {
+	query := `DELETE FROM quiz_collaborators WHERE quiz_id = $1 AND user_id = $2`
+
+	commandTag, err := conn(ctx, r.pool).Exec(ctx, query, quizID, userID)
+	if err != nil {
+		return err
+	}
+
+	if commandTag.RowsAffected() == 0 {
+		return errors.NotFound("collaborator")
+	}
+
+	return nil
+}
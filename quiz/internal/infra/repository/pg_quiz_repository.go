@@ -1,10 +1,10 @@
 package repository
 
 import (
+	"bsu-quiz/quiz/internal/domain/errors"
 	"bsu-quiz/quiz/internal/domain/models"
 	"bsu-quiz/quiz/internal/ports"
 	"context"
-	"errors"
 	"time"
 
 	"github.com/google/uuid"
@@ -30,19 +30,22 @@ func (r *PgQuizRepository) Create(ctx context.Context, quiz *models.Quiz) (uuid.
 	quiz.UpdatedAt = now
 	
 	query := `
-		INSERT INTO quizzes (id, user_id, title, is_public, created_by, created_at, updated_at) 
-		VALUES ($1, $2, $3, $4, $5, $6, $7) 
+		INSERT INTO quizzes (id, user_id, title, is_public, status, publish_at, published_at, created_by, created_at, updated_at) 
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) 
 		RETURNING id
 	`
 	
 	var id uuid.UUID
-	err := r.pool.QueryRow(
+	err := conn(ctx, r.pool).QueryRow(
 		ctx, 
 		query, 
 		quiz.ID, 
 		quiz.UserID, 
 		quiz.Title, 
 		quiz.IsPublic, 
+		quiz.Status,
+		quiz.PublishAt,
+		quiz.PublishedAt,
 		quiz.CreatedBy, 
 		quiz.CreatedAt, 
 		quiz.UpdatedAt,
@@ -56,18 +59,37 @@ func (r *PgQuizRepository) Create(ctx context.Context, quiz *models.Quiz) (uuid.
 }
 
 func (r *PgQuizRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Quiz, error) {
+	quiz, err := r.GetHeader(ctx, id)
+	if err != nil || quiz == nil {
+		return nil, err
+	}
+	
+	// Get questions
+	questions, err := r.GetQuestions(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	quiz.Questions = questions
+	
+	return quiz, nil
+}
+
+func (r *PgQuizRepository) GetHeader(ctx context.Context, id uuid.UUID) (*models.Quiz, error) {
 	query := `
-		SELECT id, user_id, title, is_public, created_by, created_at, updated_at 
+		SELECT id, user_id, title, is_public, status, publish_at, published_at, created_by, created_at, updated_at 
 		FROM quizzes 
-		WHERE id = $1
+		WHERE id = $1 AND deleted_at IS NULL
 	`
 	
 	quiz := &models.Quiz{}
-	err := r.pool.QueryRow(ctx, query, id).Scan(
+	err := conn(ctx, r.pool).QueryRow(ctx, query, id).Scan(
 		&quiz.ID, 
 		&quiz.UserID, 
 		&quiz.Title, 
 		&quiz.IsPublic, 
+		&quiz.Status,
+		&quiz.PublishAt,
+		&quiz.PublishedAt,
 		&quiz.CreatedBy, 
 		&quiz.CreatedAt, 
 		&quiz.UpdatedAt,
@@ -80,25 +102,18 @@ func (r *PgQuizRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Q
 		return nil, err
 	}
 	
-	// Get questions
-	questions, err := r.GetQuestions(ctx, id)
-	if err != nil {
-		return nil, err
-	}
-	quiz.Questions = questions
-	
 	return quiz, nil
 }
 
 func (r *PgQuizRepository) GetQuestions(ctx context.Context, quizID uuid.UUID) ([]models.Question, error) {
 	query := `
-		SELECT id, quiz_id, text, time_limit, points, position
+		SELECT id, quiz_id, text, time_limit, points, position, explanation
 		FROM questions
 		WHERE quiz_id = $1
 		ORDER BY position
 	`
 	
-	rows, err := r.pool.Query(ctx, query, quizID)
+	rows, err := conn(ctx, r.pool).Query(ctx, query, quizID)
 	if err != nil {
 		return nil, err
 	}
@@ -107,7 +122,7 @@ func (r *PgQuizRepository) GetQuestions(ctx context.Context, quizID uuid.UUID) (
 	var questions []models.Question
 	for rows.Next() {
 		q := models.Question{}
-		if err := rows.Scan(&q.ID, &q.QuizID, &q.Text, &q.TimeLimit, &q.Points, &q.Position); err != nil {
+		if err := rows.Scan(&q.ID, &q.QuizID, &q.Text, &q.TimeLimit, &q.Points, &q.Position, &q.Explanation); err != nil {
 			return nil, err
 		}
 		
@@ -130,13 +145,13 @@ func (r *PgQuizRepository) GetQuestions(ctx context.Context, quizID uuid.UUID) (
 
 func (r *PgQuizRepository) GetOptions(ctx context.Context, questionID uuid.UUID) ([]models.Option, error) {
 	query := `
-		SELECT id, question_id, text, is_correct, position
+		SELECT id, question_id, text, is_correct, position, feedback
 		FROM options
 		WHERE question_id = $1
 		ORDER BY position
 	`
 	
-	rows, err := r.pool.Query(ctx, query, questionID)
+	rows, err := conn(ctx, r.pool).Query(ctx, query, questionID)
 	if err != nil {
 		return nil, err
 	}
@@ -145,7 +160,7 @@ func (r *PgQuizRepository) GetOptions(ctx context.Context, questionID uuid.UUID)
 	var options []models.Option
 	for rows.Next() {
 		o := models.Option{}
-		if err := rows.Scan(&o.ID, &o.QuestionID, &o.Text, &o.IsCorrect, &o.Position); err != nil {
+		if err := rows.Scan(&o.ID, &o.QuestionID, &o.Text, &o.IsCorrect, &o.Position, &o.Feedback); err != nil {
 			return nil, err
 		}
 		options = append(options, o)
@@ -164,10 +179,10 @@ func (r *PgQuizRepository) Update(ctx context.Context, quiz *models.Quiz) error
 	query := `
 		UPDATE quizzes 
 		SET title = $1, is_public = $2, updated_at = $3
-		WHERE id = $4
+		WHERE id = $4 AND deleted_at IS NULL
 	`
 	
-	commandTag, err := r.pool.Exec(
+	commandTag, err := conn(ctx, r.pool).Exec(
 		ctx, 
 		query, 
 		quiz.Title, 
@@ -181,37 +196,55 @@ func (r *PgQuizRepository) Update(ctx context.Context, quiz *models.Quiz) error
 	}
 	
 	if commandTag.RowsAffected() == 0 {
-		return errors.New("quiz not found")
+		return errors.NotFound("quiz")
 	}
 	
 	return nil
 }
 
-func (r *PgQuizRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	query := `DELETE FROM quizzes WHERE id = $1`
+func (r *PgQuizRepository) Delete(ctx context.Context, id uuid.UUID, deletedBy int64) error {
+	query := `
+		UPDATE quizzes 
+		SET deleted_at = NOW(), deleted_by = $2
+		WHERE id = $1 AND deleted_at IS NULL
+	`
 	
-	commandTag, err := r.pool.Exec(ctx, query, id)
+	commandTag, err := conn(ctx, r.pool).Exec(ctx, query, id, deletedBy)
 	if err != nil {
 		return err
 	}
 	
 	if commandTag.RowsAffected() == 0 {
-		return errors.New("quiz not found")
+		return errors.NotFound("quiz")
 	}
 	
 	return nil
 }
 
-func (r *PgQuizRepository) List(ctx context.Context, userID int64, offset, limit int) ([]*models.Quiz, error) {
+var quizSortColumns = map[string]string{
+	"created_at": "created_at",
+	"updated_at": "updated_at",
+	"title":      "lower(title)",
+	"status":     "status",
+}
+
+func (r *PgQuizRepository) List(ctx context.Context, userID int64, opts models.ListOptions) ([]*models.Quiz, error) {
+	order, err := orderBy(opts.Sort, quizSortColumns, "id")
+	if err != nil {
+		return nil, err
+	}
+	
 	query := `
-		SELECT id, user_id, title, is_public, created_by, created_at, updated_at
+		SELECT id, user_id, title, is_public, status, publish_at, published_at, created_by, created_at, updated_at
 		FROM quizzes
-		WHERE user_id = $1
-		ORDER BY updated_at DESC
+		WHERE (user_id = $1
+			OR id IN (SELECT quiz_id FROM quiz_collaborators WHERE user_id = $1))
+			AND deleted_at IS NULL
+		` + order + `
 		LIMIT $2 OFFSET $3
 	`
 	
-	rows, err := r.pool.Query(ctx, query, userID, limit, offset)
+	rows, err := conn(ctx, r.pool).Query(ctx, query, userID, opts.Limit, opts.Offset)
 	if err != nil {
 		return nil, err
 	}
@@ -225,6 +258,9 @@ func (r *PgQuizRepository) List(ctx context.Context, userID int64, offset, limit
 			&quiz.UserID, 
 			&quiz.Title, 
 			&quiz.IsPublic, 
+			&quiz.Status,
+			&quiz.PublishAt,
+			&quiz.PublishedAt,
 			&quiz.CreatedBy, 
 			&quiz.CreatedAt, 
 			&quiz.UpdatedAt,
@@ -241,16 +277,33 @@ func (r *PgQuizRepository) List(ctx context.Context, userID int64, offset, limit
 	return quizzes, nil
 }
 
+func (r *PgQuizRepository) CountByUser(ctx context.Context, userID int64) (int, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM quizzes
+		WHERE (user_id = $1
+			OR id IN (SELECT quiz_id FROM quiz_collaborators WHERE user_id = $1))
+			AND deleted_at IS NULL
+	`
+	
+	var count int
+	if err := conn(ctx, r.pool).QueryRow(ctx, query, userID).Scan(&count); err != nil {
+		return 0, err
+	}
+	
+	return count, nil
+}
+
 func (r *PgQuizRepository) ListPublic(ctx context.Context, offset, limit int) ([]*models.Quiz, error) {
 	query := `
-		SELECT id, user_id, title, is_public, created_by, created_at, updated_at
+		SELECT id, user_id, title, is_public, status, publish_at, published_at, created_by, created_at, updated_at
 		FROM quizzes
-		WHERE is_public = true
+		WHERE is_public = true AND status = 'published' AND deleted_at IS NULL
 		ORDER BY updated_at DESC
 		LIMIT $1 OFFSET $2
 	`
 	
-	rows, err := r.pool.Query(ctx, query, limit, offset)
+	rows, err := conn(ctx, r.pool).Query(ctx, query, limit, offset)
 	if err != nil {
 		return nil, err
 	}
@@ -264,6 +317,9 @@ func (r *PgQuizRepository) ListPublic(ctx context.Context, offset, limit int) ([
 			&quiz.UserID, 
 			&quiz.Title, 
 			&quiz.IsPublic, 
+			&quiz.Status,
+			&quiz.PublishAt,
+			&quiz.PublishedAt,
 			&quiz.CreatedBy, 
 			&quiz.CreatedAt, 
 			&quiz.UpdatedAt,
@@ -280,6 +336,250 @@ func (r *PgQuizRepository) ListPublic(ctx context.Context, offset, limit int) ([
 	return quizzes, nil
 }
 
+func (r *PgQuizRepository) ListAll(ctx context.Context, opts models.ListOptions) ([]*models.Quiz, error) {
+	order, err := orderBy(opts.Sort, quizSortColumns, "id")
+	if err != nil {
+		return nil, err
+	}
+	
+	query := `
+		SELECT id, user_id, title, is_public, status, publish_at, published_at, created_by, created_at, updated_at
+		FROM quizzes
+		WHERE deleted_at IS NULL
+		` + order + `
+		LIMIT $1 OFFSET $2
+	`
+	
+	rows, err := conn(ctx, r.pool).Query(ctx, query, opts.Limit, opts.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	
+	var quizzes []*models.Quiz
+	for rows.Next() {
+		quiz := &models.Quiz{}
+		if err := rows.Scan(
+			&quiz.ID, 
+			&quiz.UserID, 
+			&quiz.Title, 
+			&quiz.IsPublic, 
+			&quiz.Status,
+			&quiz.PublishAt,
+			&quiz.PublishedAt,
+			&quiz.CreatedBy, 
+			&quiz.CreatedAt, 
+			&quiz.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		quizzes = append(quizzes, quiz)
+	}
+	
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	
+	return quizzes, nil
+}
+
+func (r *PgQuizRepository) Count(ctx context.Context) (int, error) {
+	query := `SELECT COUNT(*) FROM quizzes WHERE deleted_at IS NULL`
+	
+	var count int
+	if err := conn(ctx, r.pool).QueryRow(ctx, query).Scan(&count); err != nil {
+		return 0, err
+	}
+	
+	return count, nil
+}
+
+// Lifecycle methods
+func (r *PgQuizRepository) UpdateStatus(ctx context.Context, id uuid.UUID, status models.QuizStatus, publishAt *time.Time) error {
+	query := `
+		UPDATE quizzes 
+		SET status = $1,
+			publish_at = $2,
+			published_at = CASE WHEN $1 = 'published' THEN NOW() ELSE published_at END,
+			updated_at = NOW()
+		WHERE id = $3 AND deleted_at IS NULL
+	`
+	
+	commandTag, err := conn(ctx, r.pool).Exec(ctx, query, status, publishAt, id)
+	if err != nil {
+		return err
+	}
+	
+	if commandTag.RowsAffected() == 0 {
+		return errors.NotFound("quiz")
+	}
+	
+	return nil
+}
+
+// PublishScheduled skips quizzes whose questions were all deleted after
+// scheduling, they stay scheduled until questions are added
+func (r *PgQuizRepository) PublishScheduled(ctx context.Context, now time.Time) (int64, error) {
+	query := `
+		UPDATE quizzes 
+		SET status = 'published', published_at = publish_at, publish_at = NULL, updated_at = $1
+		WHERE status = 'scheduled' AND publish_at <= $1 AND deleted_at IS NULL
+			AND EXISTS (SELECT 1 FROM questions WHERE questions.quiz_id = quizzes.id)
+	`
+	
+	commandTag, err := conn(ctx, r.pool).Exec(ctx, query, now)
+	if err != nil {
+		return 0, err
+	}
+	
+	return commandTag.RowsAffected(), nil
+}
+
+// Trash methods
+func (r *PgQuizRepository) GetDeleted(ctx context.Context, id uuid.UUID) (*models.Quiz, error) {
+	query := `
+		SELECT id, user_id, title, is_public, status, publish_at, published_at, created_by, created_at, updated_at, deleted_at, deleted_by
+		FROM quizzes 
+		WHERE id = $1 AND deleted_at IS NOT NULL
+	`
+	
+	quiz := &models.Quiz{}
+	err := conn(ctx, r.pool).QueryRow(ctx, query, id).Scan(
+		&quiz.ID, 
+		&quiz.UserID, 
+		&quiz.Title, 
+		&quiz.IsPublic, 
+		&quiz.Status,
+		&quiz.PublishAt,
+		&quiz.PublishedAt,
+		&quiz.CreatedBy, 
+		&quiz.CreatedAt, 
+		&quiz.UpdatedAt,
+		&quiz.DeletedAt,
+		&quiz.DeletedBy,
+	)
+	
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	
+	return quiz, nil
+}
+
+func (r *PgQuizRepository) ListDeleted(ctx context.Context, userID int64, offset, limit int) ([]*models.Quiz, error) {
+	query := `
+		SELECT id, user_id, title, is_public, status, publish_at, published_at, created_by, created_at, updated_at, deleted_at, deleted_by
+		FROM quizzes
+		WHERE user_id = $1 AND deleted_at IS NOT NULL
+		ORDER BY deleted_at DESC
+		LIMIT $2 OFFSET $3
+	`
+	
+	rows, err := conn(ctx, r.pool).Query(ctx, query, userID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	
+	return scanDeletedQuizzes(rows)
+}
+
+func (r *PgQuizRepository) ListAllDeleted(ctx context.Context, offset, limit int) ([]*models.Quiz, error) {
+	query := `
+		SELECT id, user_id, title, is_public, status, publish_at, published_at, created_by, created_at, updated_at, deleted_at, deleted_by
+		FROM quizzes
+		WHERE deleted_at IS NOT NULL
+		ORDER BY deleted_at DESC
+		LIMIT $1 OFFSET $2
+	`
+	
+	rows, err := conn(ctx, r.pool).Query(ctx, query, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	
+	return scanDeletedQuizzes(rows)
+}
+
+func scanDeletedQuizzes(rows pgx.Rows) ([]*models.Quiz, error) {
+	defer rows.Close()
+	
+	var quizzes []*models.Quiz
+	for rows.Next() {
+		quiz := &models.Quiz{}
+		if err := rows.Scan(
+			&quiz.ID, 
+			&quiz.UserID, 
+			&quiz.Title, 
+			&quiz.IsPublic, 
+			&quiz.Status,
+			&quiz.PublishAt,
+			&quiz.PublishedAt,
+			&quiz.CreatedBy, 
+			&quiz.CreatedAt, 
+			&quiz.UpdatedAt,
+			&quiz.DeletedAt,
+			&quiz.DeletedBy,
+		); err != nil {
+			return nil, err
+		}
+		quizzes = append(quizzes, quiz)
+	}
+	
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	
+	return quizzes, nil
+}
+
+func (r *PgQuizRepository) Restore(ctx context.Context, id uuid.UUID) error {
+	query := `
+		UPDATE quizzes 
+		SET deleted_at = NULL, deleted_by = NULL
+		WHERE id = $1 AND deleted_at IS NOT NULL
+	`
+	
+	commandTag, err := conn(ctx, r.pool).Exec(ctx, query, id)
+	if err != nil {
+		return err
+	}
+	
+	if commandTag.RowsAffected() == 0 {
+		return errors.NotFound("deleted quiz")
+	}
+	
+	return nil
+}
+
+func (r *PgQuizRepository) HardDelete(ctx context.Context, id uuid.UUID) error {
+	query := `DELETE FROM quizzes WHERE id = $1`
+	
+	commandTag, err := conn(ctx, r.pool).Exec(ctx, query, id)
+	if err != nil {
+		return err
+	}
+	
+	if commandTag.RowsAffected() == 0 {
+		return errors.NotFound("quiz")
+	}
+	
+	return nil
+}
+
+func (r *PgQuizRepository) PurgeDeleted(ctx context.Context, before time.Time) (int64, error) {
+	query := `DELETE FROM quizzes WHERE deleted_at IS NOT NULL AND deleted_at < $1`
+	
+	commandTag, err := conn(ctx, r.pool).Exec(ctx, query, before)
+	if err != nil {
+		return 0, err
+	}
+	
+	return commandTag.RowsAffected(), nil
+}
+
 // Question methods
 func (r *PgQuizRepository) CreateQuestion(ctx context.Context, question *models.Question) (uuid.UUID, error) {
 	if question.ID == uuid.Nil {
@@ -287,13 +587,13 @@ func (r *PgQuizRepository) CreateQuestion(ctx context.Context, question *models.
 	}
 	
 	query := `
-		INSERT INTO questions (id, quiz_id, text, time_limit, points, position) 
-		VALUES ($1, $2, $3, $4, $5, $6) 
+		INSERT INTO questions (id, quiz_id, text, time_limit, points, position, explanation) 
+		VALUES ($1, $2, $3, $4, $5, $6, $7) 
 		RETURNING id
 	`
 	
 	var id uuid.UUID
-	err := r.pool.QueryRow(
+	err := conn(ctx, r.pool).QueryRow(
 		ctx, 
 		query, 
 		question.ID, 
@@ -302,6 +602,7 @@ func (r *PgQuizRepository) CreateQuestion(ctx context.Context, question *models.
 		question.TimeLimit, 
 		question.Points,
 		question.Position,
+		question.Explanation,
 	).Scan(&id)
 	
 	if err != nil {
@@ -314,17 +615,18 @@ func (r *PgQuizRepository) CreateQuestion(ctx context.Context, question *models.
 func (r *PgQuizRepository) UpdateQuestion(ctx context.Context, question *models.Question) error {
 	query := `
 		UPDATE questions 
-		SET text = $1, time_limit = $2, points = $3, position = $4
-		WHERE id = $5
+		SET text = $1, time_limit = $2, points = $3, position = $4, explanation = $5
+		WHERE id = $6
 	`
 	
-	commandTag, err := r.pool.Exec(
+	commandTag, err := conn(ctx, r.pool).Exec(
 		ctx, 
 		query, 
 		question.Text, 
 		question.TimeLimit, 
 		question.Points,
 		question.Position,
+		question.Explanation,
 		question.ID,
 	)
 	
@@ -333,7 +635,7 @@ func (r *PgQuizRepository) UpdateQuestion(ctx context.Context, question *models.
 	}
 	
 	if commandTag.RowsAffected() == 0 {
-		return errors.New("question not found")
+		return errors.NotFound("question")
 	}
 	
 	return nil
@@ -342,18 +644,33 @@ func (r *PgQuizRepository) UpdateQuestion(ctx context.Context, question *models.
 func (r *PgQuizRepository) DeleteQuestion(ctx context.Context, id uuid.UUID) error {
 	query := `DELETE FROM questions WHERE id = $1`
 	
-	commandTag, err := r.pool.Exec(ctx, query, id)
+	commandTag, err := conn(ctx, r.pool).Exec(ctx, query, id)
 	if err != nil {
 		return err
 	}
 	
 	if commandTag.RowsAffected() == 0 {
-		return errors.New("question not found")
+		return errors.NotFound("question")
 	}
 	
 	return nil
 }
 
+func (r *PgQuizRepository) GetQuestionQuizID(ctx context.Context, questionID uuid.UUID) (uuid.UUID, error) {
+	query := `SELECT quiz_id FROM questions WHERE id = $1`
+	
+	var quizID uuid.UUID
+	err := conn(ctx, r.pool).QueryRow(ctx, query, questionID).Scan(&quizID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return uuid.Nil, nil
+		}
+		return uuid.Nil, err
+	}
+	
+	return quizID, nil
+}
+
 // Option methods
 func (r *PgQuizRepository) CreateOption(ctx context.Context, option *models.Option) (uuid.UUID, error) {
 	if option.ID == uuid.Nil {
@@ -361,13 +678,13 @@ func (r *PgQuizRepository) CreateOption(ctx context.Context, option *models.Opti
 	}
 	
 	query := `
-		INSERT INTO options (id, question_id, text, is_correct, position) 
-		VALUES ($1, $2, $3, $4, $5) 
+		INSERT INTO options (id, question_id, text, is_correct, position, feedback) 
+		VALUES ($1, $2, $3, $4, $5, $6) 
 		RETURNING id
 	`
 	
 	var id uuid.UUID
-	err := r.pool.QueryRow(
+	err := conn(ctx, r.pool).QueryRow(
 		ctx, 
 		query, 
 		option.ID, 
@@ -375,6 +692,7 @@ func (r *PgQuizRepository) CreateOption(ctx context.Context, option *models.Opti
 		option.Text, 
 		option.IsCorrect,
 		option.Position,
+		option.Feedback,
 	).Scan(&id)
 	
 	if err != nil {
@@ -387,16 +705,17 @@ func (r *PgQuizRepository) CreateOption(ctx context.Context, option *models.Opti
 func (r *PgQuizRepository) UpdateOption(ctx context.Context, option *models.Option) error {
 	query := `
 		UPDATE options 
-		SET text = $1, is_correct = $2, position = $3
-		WHERE id = $4
+		SET text = $1, is_correct = $2, position = $3, feedback = $4
+		WHERE id = $5
 	`
 	
-	commandTag, err := r.pool.Exec(
+	commandTag, err := conn(ctx, r.pool).Exec(
 		ctx, 
 		query, 
 		option.Text, 
 		option.IsCorrect,
 		option.Position,
+		option.Feedback,
 		option.ID,
 	)
 	
@@ -405,7 +724,7 @@ func (r *PgQuizRepository) UpdateOption(ctx context.Context, option *models.Opti
 	}
 	
 	if commandTag.RowsAffected() == 0 {
-		return errors.New("option not found")
+		return errors.NotFound("option")
 	}
 	
 	return nil
@@ -414,14 +733,34 @@ func (r *PgQuizRepository) UpdateOption(ctx context.Context, option *models.Opti
 func (r *PgQuizRepository) DeleteOption(ctx context.Context, id uuid.UUID) error {
 	query := `DELETE FROM options WHERE id = $1`
 	
-	commandTag, err := r.pool.Exec(ctx, query, id)
+	commandTag, err := conn(ctx, r.pool).Exec(ctx, query, id)
 	if err != nil {
 		return err
 	}
 	
 	if commandTag.RowsAffected() == 0 {
-		return errors.New("option not found")
+		return errors.NotFound("option")
 	}
 	
 	return nil
+}
+
+func (r *PgQuizRepository) GetOptionQuizID(ctx context.Context, optionID uuid.UUID) (uuid.UUID, error) {
+	query := `
+		SELECT q.quiz_id
+		FROM options o
+		JOIN questions q ON q.id = o.question_id
+		WHERE o.id = $1
+	`
+	
+	var quizID uuid.UUID
+	err := conn(ctx, r.pool).QueryRow(ctx, query, optionID).Scan(&quizID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return uuid.Nil, nil
+		}
+		return uuid.Nil, err
+	}
+	
+	return quizID, nil
 }
\ No newline at end of file
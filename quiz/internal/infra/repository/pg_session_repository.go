@@ -1,10 +1,13 @@
 package repository
 
 import (
+	"bsu-quiz/quiz/internal/domain/errors"
 	"bsu-quiz/quiz/internal/domain/models"
 	"bsu-quiz/quiz/internal/ports"
 	"context"
-	"errors"
+	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -27,13 +30,13 @@ func (r *PgSessionRepository) Create(ctx context.Context, session *models.GameSe
 	}
 	
 	query := `
-		INSERT INTO game_sessions (id, quiz_id, host_id, join_code, status_flags, current_question_index) 
-		VALUES ($1, $2, $3, $4, $5, $6) 
+		INSERT INTO game_sessions (id, quiz_id, host_id, join_code, status_flags, current_question_index, exam_mode) 
+		VALUES ($1, $2, $3, $4, $5, $6, $7) 
 		RETURNING id
 	`
 	
 	var id uuid.UUID
-	err := r.pool.QueryRow(
+	err := conn(ctx, r.pool).QueryRow(
 		ctx, 
 		query, 
 		session.ID, 
@@ -42,6 +45,7 @@ func (r *PgSessionRepository) Create(ctx context.Context, session *models.GameSe
 		session.JoinCode, 
 		session.StatusFlags,
 		session.CurrentQuestionIndex,
+		session.ExamMode,
 	).Scan(&id)
 	
 	if err != nil {
@@ -53,21 +57,25 @@ func (r *PgSessionRepository) Create(ctx context.Context, session *models.GameSe
 
 func (r *PgSessionRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.GameSession, error) {
 	query := `
-		SELECT id, quiz_id, host_id, join_code, status_flags, current_question_index, started_at, ended_at
+		SELECT id, quiz_id, host_id, join_code, status_flags, current_question_index, exam_mode, started_at, ended_at,
+			question_started_at, paused_at
 		FROM game_sessions 
-		WHERE id = $1
+		WHERE id = $1 AND deleted_at IS NULL
 	`
 	
 	session := &models.GameSession{}
-	err := r.pool.QueryRow(ctx, query, id).Scan(
+	err := conn(ctx, r.pool).QueryRow(ctx, query, id).Scan(
 		&session.ID, 
 		&session.QuizID, 
 		&session.HostID, 
 		&session.JoinCode, 
 		&session.StatusFlags,
 		&session.CurrentQuestionIndex,
+		&session.ExamMode,
 		&session.StartedAt,
 		&session.EndedAt,
+		&session.QuestionStartedAt,
+		&session.PausedAt,
 	)
 	
 	if err != nil {
@@ -87,23 +95,64 @@ func (r *PgSessionRepository) GetByID(ctx context.Context, id uuid.UUID) (*model
 	return session, nil
 }
 
-func (r *PgSessionRepository) GetByJoinCode(ctx context.Context, joinCode string) (*models.GameSession, error) {
+// GetForUpdate locks the row so concurrent changes of the session wait
+// for each other instead of overwriting each other
+func (r *PgSessionRepository) GetForUpdate(ctx context.Context, id uuid.UUID) (*models.GameSession, error) {
 	query := `
-		SELECT id, quiz_id, host_id, join_code, status_flags, current_question_index, started_at, ended_at
+		SELECT id, quiz_id, host_id, join_code, status_flags, current_question_index, exam_mode, started_at, ended_at,
+			question_started_at, paused_at
 		FROM game_sessions 
-		WHERE join_code = $1
+		WHERE id = $1 AND deleted_at IS NULL
+		FOR UPDATE
 	`
 	
 	session := &models.GameSession{}
-	err := r.pool.QueryRow(ctx, query, joinCode).Scan(
+	err := conn(ctx, r.pool).QueryRow(ctx, query, id).Scan(
 		&session.ID, 
 		&session.QuizID, 
 		&session.HostID, 
 		&session.JoinCode, 
 		&session.StatusFlags,
 		&session.CurrentQuestionIndex,
+		&session.ExamMode,
 		&session.StartedAt,
 		&session.EndedAt,
+		&session.QuestionStartedAt,
+		&session.PausedAt,
+	)
+	
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	
+	return session, nil
+}
+
+func (r *PgSessionRepository) GetByJoinCode(ctx context.Context, joinCode string) (*models.GameSession, error) {
+	query := `
+		SELECT gs.id, gs.quiz_id, gs.host_id, gs.join_code, gs.status_flags, gs.current_question_index, gs.exam_mode,
+			gs.started_at, gs.ended_at, gs.question_started_at, gs.paused_at
+		FROM game_sessions gs
+		JOIN quizzes q ON q.id = gs.quiz_id
+		WHERE gs.join_code = $1 AND gs.deleted_at IS NULL AND q.deleted_at IS NULL
+	`
+	
+	session := &models.GameSession{}
+	err := conn(ctx, r.pool).QueryRow(ctx, query, joinCode).Scan(
+		&session.ID, 
+		&session.QuizID, 
+		&session.HostID, 
+		&session.JoinCode, 
+		&session.StatusFlags,
+		&session.CurrentQuestionIndex,
+		&session.ExamMode,
+		&session.StartedAt,
+		&session.EndedAt,
+		&session.QuestionStartedAt,
+		&session.PausedAt,
 	)
 	
 	if err != nil {
@@ -126,17 +175,20 @@ func (r *PgSessionRepository) GetByJoinCode(ctx context.Context, joinCode string
 func (r *PgSessionRepository) Update(ctx context.Context, session *models.GameSession) error {
 	query := `
 		UPDATE game_sessions 
-		SET status_flags = $1, current_question_index = $2, started_at = $3, ended_at = $4
-		WHERE id = $5
+		SET status_flags = $1, current_question_index = $2, started_at = $3, ended_at = $4,
+			question_started_at = $5, paused_at = $6
+		WHERE id = $7
 	`
 	
-	commandTag, err := r.pool.Exec(
+	commandTag, err := conn(ctx, r.pool).Exec(
 		ctx, 
 		query, 
 		session.StatusFlags,
 		session.CurrentQuestionIndex,
 		session.StartedAt,
 		session.EndedAt,
+		session.QuestionStartedAt,
+		session.PausedAt,
 		session.ID,
 	)
 	
@@ -145,7 +197,7 @@ func (r *PgSessionRepository) Update(ctx context.Context, session *models.GameSe
 	}
 	
 	if commandTag.RowsAffected() == 0 {
-		return errors.New("session not found")
+		return errors.NotFound("session")
 	}
 	
 	return nil
@@ -174,11 +226,11 @@ func (r *PgSessionRepository) UpdateStatus(ctx context.Context, id uuid.UUID, st
 	var err error
 	
 	if startedAt != nil {
-		commandTag, err = r.pool.Exec(ctx, query, statusFlags, id, startedAt)
+		commandTag, err = conn(ctx, r.pool).Exec(ctx, query, statusFlags, id, startedAt)
 	} else if endedAt != nil {
-		commandTag, err = r.pool.Exec(ctx, query, statusFlags, id, endedAt)
+		commandTag, err = conn(ctx, r.pool).Exec(ctx, query, statusFlags, id, endedAt)
 	} else {
-		commandTag, err = r.pool.Exec(ctx, query, statusFlags, id)
+		commandTag, err = conn(ctx, r.pool).Exec(ctx, query, statusFlags, id)
 	}
 	
 	if err != nil {
@@ -186,7 +238,7 @@ func (r *PgSessionRepository) UpdateStatus(ctx context.Context, id uuid.UUID, st
 	}
 	
 	if commandTag.RowsAffected() == 0 {
-		return errors.New("session not found")
+		return errors.NotFound("session")
 	}
 	
 	return nil
@@ -195,28 +247,32 @@ func (r *PgSessionRepository) UpdateStatus(ctx context.Context, id uuid.UUID, st
 func (r *PgSessionRepository) UpdateCurrentQuestion(ctx context.Context, id uuid.UUID, index int) error {
 	query := `UPDATE game_sessions SET current_question_index = $1 WHERE id = $2`
 	
-	commandTag, err := r.pool.Exec(ctx, query, index, id)
+	commandTag, err := conn(ctx, r.pool).Exec(ctx, query, index, id)
 	if err != nil {
 		return err
 	}
 	
 	if commandTag.RowsAffected() == 0 {
-		return errors.New("session not found")
+		return errors.NotFound("session")
 	}
 	
 	return nil
 }
 
-func (r *PgSessionRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	query := `DELETE FROM game_sessions WHERE id = $1`
+func (r *PgSessionRepository) Delete(ctx context.Context, id uuid.UUID, deletedBy int64) error {
+	query := `
+		UPDATE game_sessions 
+		SET deleted_at = NOW(), deleted_by = $2
+		WHERE id = $1 AND deleted_at IS NULL
+	`
 	
-	commandTag, err := r.pool.Exec(ctx, query, id)
+	commandTag, err := conn(ctx, r.pool).Exec(ctx, query, id, deletedBy)
 	if err != nil {
 		return err
 	}
 	
 	if commandTag.RowsAffected() == 0 {
-		return errors.New("session not found")
+		return errors.NotFound("session")
 	}
 	
 	return nil
@@ -224,14 +280,16 @@ func (r *PgSessionRepository) Delete(ctx context.Context, id uuid.UUID) error {
 
 func (r *PgSessionRepository) ListByHost(ctx context.Context, hostID int64, offset, limit int) ([]*models.GameSession, error) {
 	query := `
-		SELECT id, quiz_id, host_id, join_code, status_flags, current_question_index, started_at, ended_at
-		FROM game_sessions
-		WHERE host_id = $1
-		ORDER BY COALESCE(started_at, NOW()) DESC
+		SELECT gs.id, gs.quiz_id, gs.host_id, gs.join_code, gs.status_flags, gs.current_question_index, gs.exam_mode,
+			gs.started_at, gs.ended_at
+		FROM game_sessions gs
+		JOIN quizzes q ON q.id = gs.quiz_id
+		WHERE gs.host_id = $1 AND gs.deleted_at IS NULL AND q.deleted_at IS NULL
+		ORDER BY COALESCE(gs.started_at, NOW()) DESC
 		LIMIT $2 OFFSET $3
 	`
 	
-	rows, err := r.pool.Query(ctx, query, hostID, limit, offset)
+	rows, err := conn(ctx, r.pool).Query(ctx, query, hostID, limit, offset)
 	if err != nil {
 		return nil, err
 	}
@@ -247,6 +305,7 @@ func (r *PgSessionRepository) ListByHost(ctx context.Context, hostID int64, offs
 			&session.JoinCode, 
 			&session.StatusFlags,
 			&session.CurrentQuestionIndex,
+			&session.ExamMode,
 			&session.StartedAt,
 			&session.EndedAt,
 		); err != nil {
@@ -262,6 +321,281 @@ func (r *PgSessionRepository) ListByHost(ctx context.Context, hostID int64, offs
 	return sessions, nil
 }
 
+// Listing methods, sessions of all hosts for the admin panel
+var sessionSortColumns = map[string]string{
+	"started_at": "gs.started_at",
+	"ended_at":   "gs.ended_at",
+}
+
+func (r *PgSessionRepository) List(ctx context.Context, filter models.SessionFilter) ([]*models.SessionListItem, error) {
+	column, err := sortColumn(filter.Sort, sessionSortColumns)
+	if err != nil {
+		return nil, err
+	}
+	
+	conditions, args := sessionFilterConditions(filter)
+	
+	if filter.After != nil {
+		var condition string
+		condition, args = sessionCursorCondition(column, filter.Sort, filter.After, args)
+		conditions = append(conditions, condition)
+	}
+	
+	order := column + " ASC NULLS LAST, gs.id ASC"
+	if filter.Sort.Desc() {
+		order = column + " DESC NULLS FIRST, gs.id DESC"
+	}
+	
+	args = append(args, filter.Limit)
+	query := `
+		SELECT gs.id, gs.quiz_id, gs.host_id, gs.join_code, gs.status_flags, gs.current_question_index,
+			gs.exam_mode, gs.started_at, gs.ended_at, q.title,
+			(SELECT COUNT(*) FROM participants p WHERE p.session_id = gs.id) AS participant_count
+		FROM game_sessions gs
+		JOIN quizzes q ON q.id = gs.quiz_id
+		WHERE ` + strings.Join(conditions, " AND ") + `
+		ORDER BY ` + order + `
+		LIMIT $` + strconv.Itoa(len(args))
+	
+	rows, err := conn(ctx, r.pool).Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	
+	var items []*models.SessionListItem
+	for rows.Next() {
+		item := &models.SessionListItem{}
+		if err := rows.Scan(
+			&item.ID, 
+			&item.QuizID, 
+			&item.HostID, 
+			&item.JoinCode, 
+			&item.StatusFlags,
+			&item.CurrentQuestionIndex,
+			&item.ExamMode,
+			&item.StartedAt,
+			&item.EndedAt,
+			&item.QuizTitle,
+			&item.ParticipantCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	
+	return items, nil
+}
+
+func (r *PgSessionRepository) Count(ctx context.Context, filter models.SessionFilter) (int, error) {
+	conditions, args := sessionFilterConditions(filter)
+	
+	query := `
+		SELECT COUNT(*)
+		FROM game_sessions gs
+		JOIN quizzes q ON q.id = gs.quiz_id
+		WHERE ` + strings.Join(conditions, " AND ")
+	
+	var count int
+	if err := conn(ctx, r.pool).QueryRow(ctx, query, args...).Scan(&count); err != nil {
+		return 0, err
+	}
+	
+	return count, nil
+}
+
+// sessionFilterConditions builds the WHERE conditions of the filter over
+// game_sessions aliased as gs joined with their quizzes aliased as q, the
+// cursor isn't included. Sessions of quizzes in the trash are left out
+func sessionFilterConditions(filter models.SessionFilter) ([]string, []any) {
+	conditions := []string{"gs.deleted_at IS NULL", "q.deleted_at IS NULL"}
+	var args []any
+	
+	if filter.StatusFlags != 0 {
+		args = append(args, filter.StatusFlags)
+		conditions = append(conditions, fmt.Sprintf("gs.status_flags & $%d <> 0", len(args)))
+	}
+	
+	if filter.HostID != nil {
+		args = append(args, *filter.HostID)
+		conditions = append(conditions, fmt.Sprintf("gs.host_id = $%d", len(args)))
+	}
+	
+	if filter.QuizID != nil {
+		args = append(args, *filter.QuizID)
+		conditions = append(conditions, fmt.Sprintf("gs.quiz_id = $%d", len(args)))
+	}
+	
+	if filter.From != nil {
+		args = append(args, *filter.From)
+		conditions = append(conditions, fmt.Sprintf("gs.started_at >= $%d", len(args)))
+	}
+	
+	if filter.To != nil {
+		args = append(args, *filter.To)
+		conditions = append(conditions, fmt.Sprintf("gs.started_at < $%d", len(args)))
+	}
+	
+	return conditions, args
+}
+
+// sessionCursorCondition returns the condition selecting sessions after the
+// cursor with its arguments appended to args. Sessions without the sort time
+// are treated as the latest: they come first in descending order and last
+// in ascending
+func sessionCursorCondition(column string, sort models.Sort, after *models.SessionCursor, args []any) (string, []any) {
+	switch {
+	case sort.Desc() && after.Value == nil:
+		args = append(args, after.ID)
+		return fmt.Sprintf("(%[1]s IS NOT NULL OR gs.id < $%[2]d)", column, len(args)), args
+	case sort.Desc():
+		args = append(args, *after.Value, after.ID)
+		return fmt.Sprintf(
+			"(%[1]s IS NOT NULL AND (%[1]s, gs.id) < ($%[2]d, $%[3]d))", column, len(args)-1, len(args),
+		), args
+	case after.Value == nil:
+		args = append(args, after.ID)
+		return fmt.Sprintf("(%[1]s IS NULL AND gs.id > $%[2]d)", column, len(args)), args
+	default:
+		args = append(args, *after.Value, after.ID)
+		return fmt.Sprintf(
+			"(%[1]s IS NULL OR (%[1]s, gs.id) > ($%[2]d, $%[3]d))", column, len(args)-1, len(args),
+		), args
+	}
+}
+
+// Trash methods
+func (r *PgSessionRepository) GetDeleted(ctx context.Context, id uuid.UUID) (*models.GameSession, error) {
+	query := `
+		SELECT id, quiz_id, host_id, join_code, status_flags, current_question_index, exam_mode, started_at, ended_at, deleted_at, deleted_by
+		FROM game_sessions 
+		WHERE id = $1 AND deleted_at IS NOT NULL
+	`
+	
+	session := &models.GameSession{}
+	err := conn(ctx, r.pool).QueryRow(ctx, query, id).Scan(
+		&session.ID, 
+		&session.QuizID, 
+		&session.HostID, 
+		&session.JoinCode, 
+		&session.StatusFlags,
+		&session.CurrentQuestionIndex,
+		&session.ExamMode,
+		&session.StartedAt,
+		&session.EndedAt,
+		&session.DeletedAt,
+		&session.DeletedBy,
+	)
+	
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	
+	return session, nil
+}
+
+func (r *PgSessionRepository) ListDeleted(ctx context.Context, hostID int64, offset, limit int) ([]*models.GameSession, error) {
+	query := `
+		SELECT id, quiz_id, host_id, join_code, status_flags, current_question_index, exam_mode, started_at, ended_at, deleted_at, deleted_by
+		FROM game_sessions
+		WHERE host_id = $1 AND deleted_at IS NOT NULL
+		ORDER BY deleted_at DESC
+		LIMIT $2 OFFSET $3
+	`
+	
+	rows, err := conn(ctx, r.pool).Query(ctx, query, hostID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	
+	return scanDeletedSessions(rows)
+}
+
+func (r *PgSessionRepository) ListAllDeleted(ctx context.Context, offset, limit int) ([]*models.GameSession, error) {
+	query := `
+		SELECT id, quiz_id, host_id, join_code, status_flags, current_question_index, exam_mode, started_at, ended_at, deleted_at, deleted_by
+		FROM game_sessions
+		WHERE deleted_at IS NOT NULL
+		ORDER BY deleted_at DESC
+		LIMIT $1 OFFSET $2
+	`
+	
+	rows, err := conn(ctx, r.pool).Query(ctx, query, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	
+	return scanDeletedSessions(rows)
+}
+
+func scanDeletedSessions(rows pgx.Rows) ([]*models.GameSession, error) {
+	defer rows.Close()
+	
+	var sessions []*models.GameSession
+	for rows.Next() {
+		session := &models.GameSession{}
+		if err := rows.Scan(
+			&session.ID, 
+			&session.QuizID, 
+			&session.HostID, 
+			&session.JoinCode, 
+			&session.StatusFlags,
+			&session.CurrentQuestionIndex,
+			&session.ExamMode,
+			&session.StartedAt,
+			&session.EndedAt,
+			&session.DeletedAt,
+			&session.DeletedBy,
+		); err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, session)
+	}
+	
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	
+	return sessions, nil
+}
+
+func (r *PgSessionRepository) Restore(ctx context.Context, id uuid.UUID) error {
+	query := `
+		UPDATE game_sessions 
+		SET deleted_at = NULL, deleted_by = NULL
+		WHERE id = $1 AND deleted_at IS NOT NULL
+	`
+	
+	commandTag, err := conn(ctx, r.pool).Exec(ctx, query, id)
+	if err != nil {
+		return err
+	}
+	
+	if commandTag.RowsAffected() == 0 {
+		return errors.NotFound("deleted session")
+	}
+	
+	return nil
+}
+
+func (r *PgSessionRepository) PurgeDeleted(ctx context.Context, before time.Time) (int64, error) {
+	query := `DELETE FROM game_sessions WHERE deleted_at IS NOT NULL AND deleted_at < $1`
+	
+	commandTag, err := conn(ctx, r.pool).Exec(ctx, query, before)
+	if err != nil {
+		return 0, err
+	}
+	
+	return commandTag.RowsAffected(), nil
+}
+
 // Participant methods
 func (r *PgSessionRepository) AddParticipant(ctx context.Context, participant *models.Participant) (uuid.UUID, error) {
 	if participant.ID == uuid.Nil {
@@ -277,7 +611,7 @@ func (r *PgSessionRepository) AddParticipant(ctx context.Context, participant *m
 	`
 	
 	var id uuid.UUID
-	err := r.pool.QueryRow(
+	err := conn(ctx, r.pool).QueryRow(
 		ctx, 
 		query, 
 		participant.ID, 
@@ -299,11 +633,11 @@ func (r *PgSessionRepository) GetParticipants(ctx context.Context, sessionID uui
 	query := `
 		SELECT id, session_id, user_id, login, score, joined_at
 		FROM participants
-		WHERE session_id = $1
+		WHERE session_id = $1 AND kicked_at IS NULL
 		ORDER BY score DESC, login
 	`
 	
-	rows, err := r.pool.Query(ctx, query, sessionID)
+	rows, err := conn(ctx, r.pool).Query(ctx, query, sessionID)
 	if err != nil {
 		return nil, err
 	}
@@ -340,16 +674,43 @@ func (r *PgSessionRepository) GetParticipants(ctx context.Context, sessionID uui
 	return participants, nil
 }
 
+func (r *PgSessionRepository) GetParticipantByUser(ctx context.Context, sessionID uuid.UUID, userID int64) (*models.Participant, error) {
+	query := `
+		SELECT id, session_id, user_id, login, score, joined_at, kicked_at
+		FROM participants
+		WHERE session_id = $1 AND user_id = $2
+	`
+	
+	p := &models.Participant{}
+	err := conn(ctx, r.pool).QueryRow(ctx, query, sessionID, userID).Scan(
+		&p.ID, 
+		&p.SessionID, 
+		&p.UserID, 
+		&p.Login, 
+		&p.Score,
+		&p.JoinedAt,
+		&p.KickedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	
+	return p, nil
+}
+
 func (r *PgSessionRepository) UpdateParticipantScore(ctx context.Context, id uuid.UUID, score int) error {
 	query := `UPDATE participants SET score = $1 WHERE id = $2`
 	
-	commandTag, err := r.pool.Exec(ctx, query, score, id)
+	commandTag, err := conn(ctx, r.pool).Exec(ctx, query, score, id)
 	if err != nil {
 		return err
 	}
 	
 	if commandTag.RowsAffected() == 0 {
-		return errors.New("participant not found")
+		return errors.NotFound("participant")
 	}
 	
 	return nil
@@ -358,13 +719,28 @@ func (r *PgSessionRepository) UpdateParticipantScore(ctx context.Context, id uui
 func (r *PgSessionRepository) RemoveParticipant(ctx context.Context, id uuid.UUID) error {
 	query := `DELETE FROM participants WHERE id = $1`
 	
-	commandTag, err := r.pool.Exec(ctx, query, id)
+	commandTag, err := conn(ctx, r.pool).Exec(ctx, query, id)
+	if err != nil {
+		return err
+	}
+	
+	if commandTag.RowsAffected() == 0 {
+		return errors.NotFound("participant")
+	}
+	
+	return nil
+}
+
+func (r *PgSessionRepository) KickParticipant(ctx context.Context, id uuid.UUID) error {
+	query := `UPDATE participants SET kicked_at = NOW() WHERE id = $1 AND kicked_at IS NULL`
+	
+	commandTag, err := conn(ctx, r.pool).Exec(ctx, query, id)
 	if err != nil {
 		return err
 	}
 	
 	if commandTag.RowsAffected() == 0 {
-		return errors.New("participant not found")
+		return errors.NotFound("participant")
 	}
 	
 	return nil
@@ -385,7 +761,7 @@ func (r *PgSessionRepository) RecordAnswer(ctx context.Context, answer *models.A
 	`
 	
 	var id uuid.UUID
-	err := r.pool.QueryRow(
+	err := conn(ctx, r.pool).QueryRow(
 		ctx, 
 		query, 
 		answer.ID, 
@@ -413,7 +789,7 @@ func (r *PgSessionRepository) GetAnswers(ctx context.Context, participantID uuid
 		ORDER BY answered_at
 	`
 	
-	rows, err := r.pool.Query(ctx, query, participantID)
+	rows, err := conn(ctx, r.pool).Query(ctx, query, participantID)
 	if err != nil {
 		return nil, err
 	}
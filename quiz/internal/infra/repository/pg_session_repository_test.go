@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"bsu-quiz/quiz/internal/domain/models"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestSessionCursorCondition(t *testing.T) {
+	startedAt := time.Date(2025, 6, 22, 10, 0, 0, 0, time.UTC)
+	id := uuid.New()
+
+	tests := []struct {
+		name      string
+		direction models.SortDirection
+		value     *time.Time
+		want      string
+		wantArgs  []any
+	}{
+		{
+			name:      "descending",
+			direction: models.SortDesc,
+			value:     &startedAt,
+			want:      "(gs.started_at IS NOT NULL AND (gs.started_at, gs.id) < ($2, $3))",
+			wantArgs:  []any{startedAt, id},
+		},
+		{
+			name:      "descending after a session without the time",
+			direction: models.SortDesc,
+			want:      "(gs.started_at IS NOT NULL OR gs.id < $2)",
+			wantArgs:  []any{id},
+		},
+		{
+			name:      "ascending",
+			direction: models.SortAsc,
+			value:     &startedAt,
+			want:      "(gs.started_at IS NULL OR (gs.started_at, gs.id) > ($2, $3))",
+			wantArgs:  []any{startedAt, id},
+		},
+		{
+			name:      "ascending after a session without the time",
+			direction: models.SortAsc,
+			want:      "(gs.started_at IS NULL AND gs.id > $2)",
+			wantArgs:  []any{id},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sort := models.Sort{Field: "started_at", Direction: tt.direction}
+			after := &models.SessionCursor{Sort: sort, Value: tt.value, ID: id}
+
+			// Placeholders continue after the arguments of the filter
+			condition, args := sessionCursorCondition("gs.started_at", sort, after, []any{7})
+
+			if condition != tt.want {
+				t.Errorf("got %q, want %q", condition, tt.want)
+			}
+			if len(args) != len(tt.wantArgs)+1 || args[0] != 7 {
+				t.Fatalf("got args %v, want the filter argument and %v", args, tt.wantArgs)
+			}
+			for i, want := range tt.wantArgs {
+				if args[i+1] != want {
+					t.Errorf("got arg %d %v, want %v", i+2, args[i+1], want)
+				}
+			}
+		})
+	}
+}
+
+func TestSessionFilterConditions(t *testing.T) {
+	hostID := int64(3)
+	from := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	conditions, args := sessionFilterConditions(models.SessionFilter{
+		StatusFlags: models.GameStatusActive,
+		HostID:      &hostID,
+		From:        &from,
+	})
+
+	want := []string{
+		"gs.deleted_at IS NULL",
+		"q.deleted_at IS NULL",
+		"gs.status_flags & $1 <> 0",
+		"gs.host_id = $2",
+		"gs.started_at >= $3",
+	}
+	if len(conditions) != len(want) {
+		t.Fatalf("got conditions %v, want %v", conditions, want)
+	}
+	for i := range want {
+		if conditions[i] != want[i] {
+			t.Errorf("got condition %q, want %q", conditions[i], want[i])
+		}
+	}
+	if len(args) != 3 || args[0] != models.GameStatusActive || args[1] != hostID || args[2] != from {
+		t.Errorf("got args %v", args)
+	}
+}
@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"bsu-quiz/quiz/internal/domain/models"
+	"bsu-quiz/quiz/internal/ports"
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type PgStatsRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPgStatsRepository(pool *pgxpool.Pool) ports.StatsRepositorier {
+	return &PgStatsRepository{pool: pool}
+}
+
+func (r *PgStatsRepository) CountUsersByRole(ctx context.Context) (*models.UsersByRole, error) {
+	query := `
+		SELECT
+			COUNT(*) FILTER (WHERE role_flags & $1 <> 0),
+			COUNT(*) FILTER (WHERE role_flags & $2 <> 0),
+			COUNT(*) FILTER (WHERE role_flags & $3 <> 0),
+			COUNT(*) FILTER (WHERE role_flags & $4 <> 0)
+		FROM users
+	`
+
+	counts := &models.UsersByRole{}
+	err := conn(ctx, r.pool).QueryRow(
+		ctx,
+		query,
+		models.RoleUser,
+		models.RoleAdmin,
+		models.RoleTeacher,
+		models.RoleBlocked,
+	).Scan(
+		&counts.Users,
+		&counts.Admins,
+		&counts.Teachers,
+		&counts.Blocked,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return counts, nil
+}
+
+func (r *PgStatsRepository) SessionsPerDay(ctx context.Context, since time.Time) ([]models.DailyCount, error) {
+	query := `
+		SELECT d::date, COUNT(gs.id)
+		FROM generate_series($1::date, CURRENT_DATE, INTERVAL '1 day') AS d
+		LEFT JOIN game_sessions gs
+			ON gs.started_at >= d AND gs.started_at < d + INTERVAL '1 day'
+			AND gs.deleted_at IS NULL
+		GROUP BY d
+		ORDER BY d
+	`
+
+	return r.dailyCounts(ctx, query, since)
+}
+
+func (r *PgStatsRepository) AnswersPerDay(ctx context.Context, since time.Time) ([]models.DailyCount, error) {
+	query := `
+		SELECT d::date, COUNT(a.id)
+		FROM generate_series($1::date, CURRENT_DATE, INTERVAL '1 day') AS d
+		LEFT JOIN answers a
+			ON a.answered_at >= d AND a.answered_at < d + INTERVAL '1 day'
+		GROUP BY d
+		ORDER BY d
+	`
+
+	return r.dailyCounts(ctx, query, since)
+}
+
+func (r *PgStatsRepository) dailyCounts(ctx context.Context, query string, since time.Time) ([]models.DailyCount, error) {
+	rows, err := conn(ctx, r.pool).Query(ctx, query, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var counts []models.DailyCount
+	for rows.Next() {
+		var count models.DailyCount
+		if err := rows.Scan(&count.Date, &count.Count); err != nil {
+			return nil, err
+		}
+		counts = append(counts, count)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return counts, nil
+}
@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"bsu-quiz/quiz/internal/ports"
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type txKey struct{}
+
+// dbtx is the part of pgxpool.Pool and pgx.Tx used by repositories
+type dbtx interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// conn returns the transaction started by PgTransactor if ctx has one, otherwise the pool
+func conn(ctx context.Context, pool *pgxpool.Pool) dbtx {
+	if tx, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return tx
+	}
+
+	return pool
+}
+
+type PgTransactor struct {
+	pool *pgxpool.Pool
+}
+
+func NewPgTransactor(pool *pgxpool.Pool) ports.Transactor {
+	return &PgTransactor{pool: pool}
+}
+
+// WithinTx commits the transaction if fn succeeds and rolls it back otherwise.
+// Nested calls join the outer transaction
+func (t *PgTransactor) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := t.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
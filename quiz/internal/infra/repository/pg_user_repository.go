@@ -1,10 +1,10 @@
 package repository
 
 import (
+	"bsu-quiz/quiz/internal/domain/errors"
 	"bsu-quiz/quiz/internal/domain/models"
 	"bsu-quiz/quiz/internal/ports"
 	"context"
-	"errors"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -16,7 +16,7 @@ type UserRepository interface {
 	Update(ctx context.Context, user *models.User) error
 	UpdateRole(ctx context.Context, userID int64, roleFlags int) error
 	Delete(ctx context.Context, id int64) error
-	List(ctx context.Context, offset, limit int) ([]*models.User, error)
+	List(ctx context.Context, opts models.ListOptions) ([]*models.User, error)
 }
 
 type PgUserRepository struct {
@@ -27,11 +27,26 @@ func NewPgUserRepository(pool *pgxpool.Pool) ports.UserRepositorier {
 	return &PgUserRepository{pool: pool}
 }
 
+func (r *PgUserRepository) Create(ctx context.Context, user *models.User) (int64, error) {
+	query := `INSERT INTO users (login, role_flags, academic_group, password_hash) VALUES ($1, $2, $3, NULLIF($4, '')) RETURNING id`
+	
+	var id int64
+	err := conn(ctx, r.pool).QueryRow(ctx, query, user.Login, user.RoleFlags, user.Group, user.Password).Scan(&id)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return 0, errors.Conflict("user with this login already exists")
+		}
+		return 0, err
+	}
+	
+	return id, nil
+}
+
 func (r *PgUserRepository) GetByID(ctx context.Context, id int64) (*models.User, error) {
-	query := `SELECT id, login, role_flags FROM users WHERE id = $1`
+	query := `SELECT id, login, role_flags, academic_group FROM users WHERE id = $1`
 	
 	user := &models.User{}
-	err := r.pool.QueryRow(ctx, query, id).Scan(&user.ID, &user.Login, &user.RoleFlags)
+	err := conn(ctx, r.pool).QueryRow(ctx, query, id).Scan(&user.ID, &user.Login, &user.RoleFlags, &user.Group)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, nil
@@ -42,11 +57,41 @@ func (r *PgUserRepository) GetByID(ctx context.Context, id int64) (*models.User,
 	return user, nil
 }
 
+// GetByLogin returns the user with the password hash, it's what signing in looks users up by
 func (r *PgUserRepository) GetByLogin(ctx context.Context, login string) (*models.User, error) {
-	query := `SELECT id, login, password, role_flags FROM users WHERE login = $1`
+	query := `SELECT id, login, role_flags, academic_group, COALESCE(password_hash, '') FROM users WHERE login = $1`
 	
 	user := &models.User{}
-	err := r.pool.QueryRow(ctx, query, login).Scan(&user.ID, &user.Login, &user.Password, &user.RoleFlags)
+	err := conn(ctx, r.pool).QueryRow(ctx, query, login).Scan(&user.ID, &user.Login, &user.RoleFlags, &user.Group, &user.Password)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	
+	return user, nil
+}
+
+// GetByTelegramID returns the user the Telegram account is linked to, players
+// of the Web App are looked up by it
+func (r *PgUserRepository) GetByTelegramID(ctx context.Context, telegramUserID int64) (*models.User, error) {
+	query := `
+		SELECT id, login, role_flags, academic_group, telegram_user_id, telegram_chat_id, COALESCE(telegram_username, '')
+		FROM users
+		WHERE telegram_user_id = $1
+	`
+	
+	user := &models.User{}
+	err := conn(ctx, r.pool).QueryRow(ctx, query, telegramUserID).Scan(
+		&user.ID,
+		&user.Login,
+		&user.RoleFlags,
+		&user.Group,
+		&user.TelegramUserID,
+		&user.TelegramChatID,
+		&user.TelegramUsername,
+	)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, nil
@@ -58,15 +103,33 @@ func (r *PgUserRepository) GetByLogin(ctx context.Context, login string) (*model
 }
 
 func (r *PgUserRepository) Update(ctx context.Context, user *models.User) error {
-	query := `UPDATE users SET login = $1, role_flags = $2 WHERE id = $3`
+	query := `UPDATE users SET login = $1, role_flags = $2, academic_group = $3 WHERE id = $4`
+	
+	commandTag, err := conn(ctx, r.pool).Exec(ctx, query, user.Login, user.RoleFlags, user.Group, user.ID)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return errors.Conflict("user with this login already exists")
+		}
+		return err
+	}
+	
+	if commandTag.RowsAffected() == 0 {
+		return errors.NotFound("user")
+	}
 	
-	commandTag, err := r.pool.Exec(ctx, query, user.Login, user.RoleFlags, user.ID)
+	return nil
+}
+
+func (r *PgUserRepository) UpdatePassword(ctx context.Context, userID int64, hash string) error {
+	query := `UPDATE users SET password_hash = $1 WHERE id = $2`
+	
+	commandTag, err := conn(ctx, r.pool).Exec(ctx, query, hash, userID)
 	if err != nil {
 		return err
 	}
 	
 	if commandTag.RowsAffected() == 0 {
-		return errors.New("user not found")
+		return errors.NotFound("user")
 	}
 	
 	return nil
@@ -75,37 +138,72 @@ func (r *PgUserRepository) Update(ctx context.Context, user *models.User) error
 func (r *PgUserRepository) UpdateRole(ctx context.Context, userID int64, roleFlags int) error {
 	query := `UPDATE users SET role_flags = $1 WHERE id = $2`
 	
-	commandTag, err := r.pool.Exec(ctx, query, roleFlags, userID)
+	commandTag, err := conn(ctx, r.pool).Exec(ctx, query, roleFlags, userID)
 	if err != nil {
 		return err
 	}
 	
 	if commandTag.RowsAffected() == 0 {
-		return errors.New("user not found")
+		return errors.NotFound("user")
 	}
 	
 	return nil
 }
 
+// UpdateRoles sets and clears role flags of several users at once
+func (r *PgUserRepository) UpdateRoles(ctx context.Context, ids []int64, set, clear int) (int64, error) {
+	query := `UPDATE users SET role_flags = (role_flags | $1) & ~$2 WHERE id = ANY($3)`
+	
+	commandTag, err := conn(ctx, r.pool).Exec(ctx, query, set, clear, ids)
+	if err != nil {
+		return 0, err
+	}
+	
+	return commandTag.RowsAffected(), nil
+}
+
 func (r *PgUserRepository) Delete(ctx context.Context, id int64) error {
 	query := `DELETE FROM users WHERE id = $1`
 	
-	commandTag, err := r.pool.Exec(ctx, query, id)
+	commandTag, err := conn(ctx, r.pool).Exec(ctx, query, id)
 	if err != nil {
 		return err
 	}
 	
 	if commandTag.RowsAffected() == 0 {
-		return errors.New("user not found")
+		return errors.NotFound("user")
 	}
 	
 	return nil
 }
 
-func (r *PgUserRepository) List(ctx context.Context, offset, limit int) ([]*models.User, error) {
-	query := `SELECT id, login, role_flags FROM users ORDER BY id LIMIT $1 OFFSET $2`
+// CountOwned counts the quizzes and sessions Delete would cascade to
+func (r *PgUserRepository) CountOwned(ctx context.Context, id int64) (quizzes, sessions int, err error) {
+	query := `
+		SELECT
+			(SELECT COUNT(*) FROM quizzes WHERE user_id = $1),
+			(SELECT COUNT(*) FROM game_sessions WHERE host_id = $1)
+	`
 	
-	rows, err := r.pool.Query(ctx, query, limit, offset)
+	err = conn(ctx, r.pool).QueryRow(ctx, query, id).Scan(&quizzes, &sessions)
+	return quizzes, sessions, err
+}
+
+var userSortColumns = map[string]string{
+	"id":    "id",
+	"login": "login",
+	"group": "academic_group",
+}
+
+func (r *PgUserRepository) List(ctx context.Context, opts models.ListOptions) ([]*models.User, error) {
+	order, err := orderBy(opts.Sort, userSortColumns, "id")
+	if err != nil {
+		return nil, err
+	}
+	
+	query := `SELECT id, login, role_flags, academic_group FROM users ` + order + ` LIMIT $1 OFFSET $2`
+	
+	rows, err := conn(ctx, r.pool).Query(ctx, query, opts.Limit, opts.Offset)
 	if err != nil {
 		return nil, err
 	}
@@ -114,7 +212,7 @@ func (r *PgUserRepository) List(ctx context.Context, offset, limit int) ([]*mode
 	var users []*models.User
 	for rows.Next() {
 		user := &models.User{}
-		if err := rows.Scan(&user.ID, &user.Login, &user.RoleFlags); err != nil {
+		if err := rows.Scan(&user.ID, &user.Login, &user.RoleFlags, &user.Group); err != nil {
 			return nil, err
 		}
 		users = append(users, user)
@@ -125,4 +223,75 @@ func (r *PgUserRepository) List(ctx context.Context, offset, limit int) ([]*mode
 	}
 	
 	return users, nil
-}
\ No newline at end of file
+}
+
+func (r *PgUserRepository) ListRecent(ctx context.Context, limit int) ([]*models.User, error) {
+	query := `SELECT id, login, role_flags, academic_group FROM users ORDER BY id DESC LIMIT $1`
+	
+	rows, err := conn(ctx, r.pool).Query(ctx, query, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	
+	var users []*models.User
+	for rows.Next() {
+		user := &models.User{}
+		if err := rows.Scan(&user.ID, &user.Login, &user.RoleFlags, &user.Group); err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+	
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	
+	return users, nil
+}
+
+func (r *PgUserRepository) GetByIDs(ctx context.Context, ids []int64) ([]*models.User, error) {
+	query := `SELECT id, login, role_flags, academic_group FROM users WHERE id = ANY($1) ORDER BY id`
+	
+	return r.queryUsers(ctx, query, ids)
+}
+
+func (r *PgUserRepository) GetByLogins(ctx context.Context, logins []string) ([]*models.User, error) {
+	query := `SELECT id, login, role_flags, academic_group FROM users WHERE login = ANY($1) ORDER BY id`
+	
+	return r.queryUsers(ctx, query, logins)
+}
+
+func (r *PgUserRepository) queryUsers(ctx context.Context, query string, args ...any) ([]*models.User, error) {
+	rows, err := conn(ctx, r.pool).Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	
+	var users []*models.User
+	for rows.Next() {
+		user := &models.User{}
+		if err := rows.Scan(&user.ID, &user.Login, &user.RoleFlags, &user.Group); err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+	
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	
+	return users, nil
+}
+
+func (r *PgUserRepository) Count(ctx context.Context) (int, error) {
+	query := `SELECT COUNT(*) FROM users`
+	
+	var count int
+	if err := conn(ctx, r.pool).QueryRow(ctx, query).Scan(&count); err != nil {
+		return 0, err
+	}
+	
+	return count, nil
+}
@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"bsu-quiz/quiz/internal/domain/errors"
+	"bsu-quiz/quiz/internal/domain/models"
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type RedisImpersonationRepository struct {
+	client *redis.Client
+	prefix string
+}
+
+func NewRedisImpersonationRepository(client *redis.Client, prefix string) *RedisImpersonationRepository {
+	return &RedisImpersonationRepository{
+		client: client,
+		prefix: prefix,
+	}
+}
+
+func (r *RedisImpersonationRepository) Save(ctx context.Context, impersonation *models.Impersonation) error {
+	data, err := json.Marshal(impersonation)
+	if err != nil {
+		return err
+	}
+
+	return r.client.Set(ctx, r.key(impersonation.AdminID), data, time.Until(impersonation.ExpiresAt)).Err()
+}
+
+func (r *RedisImpersonationRepository) Get(ctx context.Context, adminID int64) (*models.Impersonation, error) {
+	data, err := r.client.Get(ctx, r.key(adminID)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var impersonation models.Impersonation
+	if err := json.Unmarshal(data, &impersonation); err != nil {
+		return nil, err
+	}
+
+	return &impersonation, nil
+}
+
+func (r *RedisImpersonationRepository) Delete(ctx context.Context, adminID int64) error {
+	return r.client.Del(ctx, r.key(adminID)).Err()
+}
+
+func (r *RedisImpersonationRepository) key(adminID int64) string {
+	return r.prefix + "impersonation:" + strconv.FormatInt(adminID, 10)
+}
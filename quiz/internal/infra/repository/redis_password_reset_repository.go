@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"bsu-quiz/quiz/internal/domain/errors"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type RedisPasswordResetRepository struct {
+	client *redis.Client
+	prefix string
+}
+
+func NewRedisPasswordResetRepository(client *redis.Client, prefix string) *RedisPasswordResetRepository {
+	return &RedisPasswordResetRepository{
+		client: client,
+		prefix: prefix,
+	}
+}
+
+func (r *RedisPasswordResetRepository) Save(ctx context.Context, token string, userID int64, ttl time.Duration) error {
+	return r.client.Set(ctx, r.key(token), userID, ttl).Err()
+}
+
+func (r *RedisPasswordResetRepository) Take(ctx context.Context, token string) (int64, bool, error) {
+	userID, err := r.client.GetDel(ctx, r.key(token)).Int64()
+	if errors.Is(err, redis.Nil) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+
+	return userID, true, nil
+}
+
+// key hashes the token like web session keys do
+func (r *RedisPasswordResetRepository) key(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return r.prefix + "password_reset:" + hex.EncodeToString(sum[:])
+}
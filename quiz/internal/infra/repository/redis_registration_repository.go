@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"bsu-quiz/quiz/internal/domain/errors"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type RedisRegistrationRepository struct {
+	client *redis.Client
+	prefix string
+}
+
+func NewRedisRegistrationRepository(client *redis.Client, prefix string) *RedisRegistrationRepository {
+	return &RedisRegistrationRepository{
+		client: client,
+		prefix: prefix,
+	}
+}
+
+func (r *RedisRegistrationRepository) Save(ctx context.Context, token, login string, ttl time.Duration) error {
+	return r.client.Set(ctx, r.key(token), login, ttl).Err()
+}
+
+func (r *RedisRegistrationRepository) Take(ctx context.Context, token string) (string, bool, error) {
+	login, err := r.client.GetDel(ctx, r.key(token)).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+
+	return login, true, nil
+}
+
+// key hashes the token like password reset keys do
+func (r *RedisRegistrationRepository) key(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return r.prefix + "registration:" + hex.EncodeToString(sum[:])
+}
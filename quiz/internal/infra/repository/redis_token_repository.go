@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"bsu-quiz/quiz/internal/domain/errors"
+	"bsu-quiz/quiz/internal/domain/models"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type RedisTokenRepository struct {
+	client *redis.Client
+	prefix string
+}
+
+func NewRedisTokenRepository(client *redis.Client, prefix string) *RedisTokenRepository {
+	return &RedisTokenRepository{
+		client: client,
+		prefix: prefix,
+	}
+}
+
+// SaveRefreshToken stores the token and indexes it by user like web sessions
+func (r *RedisTokenRepository) SaveRefreshToken(ctx context.Context, token *models.RefreshToken) error {
+	data, err := json.Marshal(token)
+	if err != nil {
+		return err
+	}
+
+	ttl := time.Until(token.ExpiresAt)
+	key := r.refreshKey(token.Token)
+	userKey := r.userKey(token.UserID)
+
+	_, err = r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, key, data, ttl)
+		pipe.SAdd(ctx, userKey, key)
+		pipe.Expire(ctx, userKey, ttl)
+		return nil
+	})
+
+	return err
+}
+
+func (r *RedisTokenRepository) TakeRefreshToken(ctx context.Context, token string) (*models.RefreshToken, error) {
+	key := r.refreshKey(token)
+
+	data, err := r.client.GetDel(ctx, key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var refresh models.RefreshToken
+	if err := json.Unmarshal(data, &refresh); err != nil {
+		return nil, err
+	}
+	refresh.Token = token
+
+	if err := r.client.SRem(ctx, r.userKey(refresh.UserID), key).Err(); err != nil {
+		return nil, err
+	}
+
+	return &refresh, nil
+}
+
+func (r *RedisTokenRepository) DeleteRefreshTokens(ctx context.Context, userID int64) error {
+	return deleteIndexed(ctx, r.client, r.userKey(userID))
+}
+
+func (r *RedisTokenRepository) RevokeAccessToken(ctx context.Context, id string, expiresAt time.Time) error {
+	ttl := time.Until(expiresAt)
+	if ttl <= 0 {
+		// Expired tokens are refused anyway
+		return nil
+	}
+
+	return r.client.Set(ctx, r.revokedKey(id), 1, ttl).Err()
+}
+
+func (r *RedisTokenRepository) IsAccessTokenRevoked(ctx context.Context, id string) (bool, error) {
+	n, err := r.client.Exists(ctx, r.revokedKey(id)).Result()
+	if err != nil {
+		return false, err
+	}
+
+	return n > 0, nil
+}
+
+// refreshKey hashes the token like web session keys do
+func (r *RedisTokenRepository) refreshKey(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return r.prefix + "refresh_token:" + hex.EncodeToString(sum[:])
+}
+
+func (r *RedisTokenRepository) userKey(userID int64) string {
+	return r.prefix + "refresh_token_user:" + strconv.FormatInt(userID, 10)
+}
+
+func (r *RedisTokenRepository) revokedKey(id string) string {
+	return r.prefix + "revoked_token:" + id
+}
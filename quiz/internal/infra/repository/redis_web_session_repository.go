@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"bsu-quiz/quiz/internal/domain/errors"
+	"bsu-quiz/quiz/internal/domain/models"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type RedisWebSessionRepository struct {
+	client *redis.Client
+	prefix string
+}
+
+func NewRedisWebSessionRepository(client *redis.Client, prefix string) *RedisWebSessionRepository {
+	return &RedisWebSessionRepository{
+		client: client,
+		prefix: prefix,
+	}
+}
+
+// Save stores the session and indexes it by user, the index lives as
+// long as the latest session of the user
+func (r *RedisWebSessionRepository) Save(ctx context.Context, session *models.WebSession) error {
+	data, err := json.Marshal(session)
+	if err != nil {
+		return err
+	}
+
+	ttl := time.Until(session.ExpiresAt)
+	key := r.key(session.Token)
+	userKey := r.userKey(session.UserID)
+
+	_, err = r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, key, data, ttl)
+		pipe.SAdd(ctx, userKey, key)
+		pipe.Expire(ctx, userKey, ttl)
+		return nil
+	})
+
+	return err
+}
+
+func (r *RedisWebSessionRepository) Get(ctx context.Context, token string) (*models.WebSession, error) {
+	data, err := r.client.Get(ctx, r.key(token)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var session models.WebSession
+	if err := json.Unmarshal(data, &session); err != nil {
+		return nil, err
+	}
+	session.Token = token
+
+	return &session, nil
+}
+
+func (r *RedisWebSessionRepository) Delete(ctx context.Context, token string) error {
+	key := r.key(token)
+
+	data, err := r.client.GetDel(ctx, key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var session models.WebSession
+	if err := json.Unmarshal(data, &session); err != nil {
+		return err
+	}
+
+	return r.client.SRem(ctx, r.userKey(session.UserID), key).Err()
+}
+
+func (r *RedisWebSessionRepository) DeleteByUser(ctx context.Context, userID int64) error {
+	return deleteIndexed(ctx, r.client, r.userKey(userID))
+}
+
+// key hashes the token, a dump of Redis doesn't give away live sessions
+func (r *RedisWebSessionRepository) key(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return r.prefix + "web_session:" + hex.EncodeToString(sum[:])
+}
+
+func (r *RedisWebSessionRepository) userKey(userID int64) string {
+	return r.prefix + "web_session_user:" + strconv.FormatInt(userID, 10)
+}
+
+// deleteIndexed deletes the keys listed in the set at index and the set itself
+func deleteIndexed(ctx context.Context, client *redis.Client, index string) error {
+	keys, err := client.SMembers(ctx, index).Result()
+	if err != nil {
+		return err
+	}
+
+	return client.Del(ctx, append(keys, index)...).Err()
+}
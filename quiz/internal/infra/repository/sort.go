@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"bsu-quiz/quiz/internal/domain/models"
+	"fmt"
+)
+
+// sortColumn returns the column of the sort field, only whitelisted
+// columns ever make it into a query
+func sortColumn(sort models.Sort, columns map[string]string) (string, error) {
+	column, ok := columns[sort.Field]
+	if !ok {
+		return "", fmt.Errorf("unknown sort field %q", sort.Field)
+	}
+
+	return column, nil
+}
+
+// sortDirection returns the SQL keyword of the sort direction
+func sortDirection(sort models.Sort) string {
+	if sort.Desc() {
+		return "DESC"
+	}
+
+	return "ASC"
+}
+
+// orderBy returns the ORDER BY clause of the sort, ties are broken by
+// the id column in the same direction so pages don't overlap
+func orderBy(sort models.Sort, columns map[string]string, id string) (string, error) {
+	column, err := sortColumn(sort, columns)
+	if err != nil {
+		return "", err
+	}
+
+	direction := sortDirection(sort)
+
+	return fmt.Sprintf("ORDER BY %s %s, %s %s", column, direction, id, direction), nil
+}
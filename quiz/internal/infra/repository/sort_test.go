@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"bsu-quiz/quiz/internal/domain/models"
+	"testing"
+)
+
+func TestOrderBy(t *testing.T) {
+	columns := map[string]string{"login": "u.login"}
+
+	got, err := orderBy(models.Sort{Field: "login", Direction: models.SortDesc}, columns, "u.id")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "ORDER BY u.login DESC, u.id DESC"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	got, err = orderBy(models.Sort{Field: "login"}, columns, "u.id")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "ORDER BY u.login ASC, u.id ASC"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	// Fields come from clients, only whitelisted ones reach the query
+	if _, err := orderBy(models.Sort{Field: "login; DROP TABLE users"}, columns, "u.id"); err == nil {
+		t.Errorf("unknown sort field is accepted")
+	}
+}
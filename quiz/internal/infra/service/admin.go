@@ -1,97 +1,455 @@
 package service
 
 import (
+	"bsu-quiz/pkg/authz"
+	"bsu-quiz/quiz/internal/domain/errors"
 	"bsu-quiz/quiz/internal/domain/models"
+	"bsu-quiz/quiz/internal/domain/rules"
 	"bsu-quiz/quiz/internal/ports"
 
 	"context"
-	"errors"
-	"time"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
 
 	"github.com/google/uuid"
 )
 
 type AdminProvider interface {
 	// User management
-	GetAllUsers(ctx context.Context, offset, limit int) ([]*models.User, error)
+	GetAllUsers(ctx context.Context, opts models.ListOptions) (*models.Page[*models.User], error)
+	GetUser(ctx context.Context, id int64) (*models.User, error)
+	CreateUser(ctx context.Context, user *models.User) (int64, error)
+	UpdateUser(ctx context.Context, user *models.User) error
 	UpdateUserRole(ctx context.Context, userID int64, roleFlags int) error
+	DeleteUser(ctx context.Context, id int64) error
+	
+	// Bulk user management
+	ImportUsers(ctx context.Context, r io.Reader) (*models.UserImportReport, error)
+	BulkUpdateUsers(ctx context.Context, ids []int64, action models.BulkUserAction, role int) (int, error)
 	
 	// Quiz management
-	GetAllQuizzes(ctx context.Context, offset, limit int) ([]*models.Quiz, error)
-	DeleteQuiz(ctx context.Context, id uuid.UUID) error
+	GetAllQuizzes(ctx context.Context, opts models.ListOptions) (*models.Page[*models.Quiz], error)
+	GetQuiz(ctx context.Context, id uuid.UUID) (*models.Quiz, error)
+	DeleteQuiz(ctx context.Context, id uuid.UUID, adminID int64) error
 	
 	// Session management
-	GetAllSessions(ctx context.Context, offset, limit int) ([]*models.GameSession, error)
-	EndSession(ctx context.Context, id uuid.UUID) error
+	GetAllSessions(ctx context.Context, filter models.SessionFilter) (*models.Page[*models.SessionListItem], error)
+	GetSession(ctx context.Context, id uuid.UUID) (*models.GameSession, error)
 }
 
+// AdminServiceImpl checks the permissions of the actor of the request,
+// see models.ActorFromContext
 type AdminServiceImpl struct {
 	userRepo    ports.UserRepositorier
 	quizRepo    ports.QuizRepositorier
 	sessionRepo ports.SessionRepositorier
+	policy      *rules.Policy
+	tx          ports.Transactor
+	audit       AuditProvider
 }
 
 func NewAdminService(
 	userRepo ports.UserRepositorier,
 	quizRepo ports.QuizRepositorier,
 	sessionRepo ports.SessionRepositorier,
+	policy *rules.Policy,
+	tx ports.Transactor,
+	audit AuditProvider,
 ) *AdminServiceImpl {
 	return &AdminServiceImpl{
 		userRepo:    userRepo,
 		quizRepo:    quizRepo,
 		sessionRepo: sessionRepo,
+		policy:      policy,
+		tx:          tx,
+		audit:       audit,
+	}
+}
+
+// authorize checks the actor of the request has the permission,
+// requests without an actor have none
+func (s *AdminServiceImpl) authorize(ctx context.Context, permission authz.Permission) error {
+	actor, ok := models.ActorFromContext(ctx)
+	if !ok {
+		return &rules.PermissionError{Permission: permission}
+	}
+	
+	return s.policy.Require(ctx, actor.UserID, permission)
+}
+
+// defaultPageSize is the page size of listings when none is requested
+const defaultPageSize = 10
+
+// listDefaults fills in the page size and sort the caller left out
+func listDefaults(opts models.ListOptions, sort models.Sort) models.ListOptions {
+	if opts.Limit <= 0 {
+		opts.Limit = defaultPageSize
+	}
+	
+	if opts.Sort.Field == "" {
+		opts.Sort = sort
 	}
+	
+	return opts
 }
 
 // User management
-func (s *AdminServiceImpl) GetAllUsers(ctx context.Context, offset, limit int) ([]*models.User, error) {
-	return s.userRepo.List(ctx, offset, limit)
+func (s *AdminServiceImpl) GetAllUsers(ctx context.Context, opts models.ListOptions) (*models.Page[*models.User], error) {
+	if err := s.authorize(ctx, authz.UserManage); err != nil {
+		return nil, err
+	}
+	
+	opts = listDefaults(opts, models.DefaultUserSort)
+	
+	users, err := s.userRepo.List(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	
+	total, err := s.userRepo.Count(ctx)
+	if err != nil {
+		return nil, err
+	}
+	
+	return models.NewOffsetPage(users, total, opts), nil
+}
+
+func (s *AdminServiceImpl) GetUser(ctx context.Context, id int64) (*models.User, error) {
+	if err := s.authorize(ctx, authz.UserManage); err != nil {
+		return nil, err
+	}
+	
+	return s.userRepo.GetByID(ctx, id)
+}
+
+// CreateUser creates a user with the given login and roles.
+// Every user is at least a regular user. Users get no password, they
+// choose one through the emailed reset link or sign in through Telegram
+func (s *AdminServiceImpl) CreateUser(ctx context.Context, user *models.User) (int64, error) {
+	if err := s.authorize(ctx, authz.UserManage); err != nil {
+		return 0, err
+	}
+	
+	if err := validateLogin(user.Login); err != nil {
+		return 0, err
+	}
+	
+	if err := validateGroup(user.Group); err != nil {
+		return 0, err
+	}
+	
+	if user.RoleFlags&^models.RoleAll != 0 {
+		return 0, errors.Invalid("roleFlags", "unknown role")
+	}
+	
+	if user.Password != "" {
+		return 0, errors.Invalid("password", "password can't be set, users choose it through the emailed link")
+	}
+	
+	existing, err := s.userRepo.GetByLogin(ctx, user.Login)
+	if err != nil {
+		return 0, err
+	}
+	
+	if existing != nil {
+		return 0, errors.Conflict("user with this login already exists")
+	}
+	
+	user.RoleFlags |= models.RoleUser
+	
+	var id int64
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		id, err = s.userRepo.Create(ctx, user)
+		if err != nil {
+			return err
+		}
+		user.ID = id
+		
+		return s.audit.Record(ctx, models.AuditUserCreate, models.AuditTargetUser, userTargetID(id), nil, user)
+	})
+	if err != nil {
+		return 0, err
+	}
+	
+	return id, nil
+}
+
+func (s *AdminServiceImpl) UpdateUser(ctx context.Context, user *models.User) error {
+	if err := s.authorize(ctx, authz.UserManage); err != nil {
+		return err
+	}
+	
+	if err := validateLogin(user.Login); err != nil {
+		return err
+	}
+	
+	if err := validateGroup(user.Group); err != nil {
+		return err
+	}
+	
+	if err := checkRoleChange(ctx, user.ID, user.RoleFlags); err != nil {
+		return err
+	}
+	
+	existing, err := s.userRepo.GetByLogin(ctx, user.Login)
+	if err != nil {
+		return err
+	}
+	
+	if existing != nil && existing.ID != user.ID {
+		return errors.Conflict("user with this login already exists")
+	}
+	
+	before, err := s.userRepo.GetByID(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+	
+	if before == nil {
+		return errors.NotFound("user")
+	}
+	
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.userRepo.Update(ctx, user); err != nil {
+			return err
+		}
+		
+		return s.audit.Record(ctx, models.AuditUserUpdate, models.AuditTargetUser, userTargetID(user.ID), before, user)
+	})
+}
+
+// DeleteUser deletes a user who owns nothing. Deleting a user removes
+// their quizzes and sessions with them, so users who own some, even in
+// the trash, are blocked instead
+func (s *AdminServiceImpl) DeleteUser(ctx context.Context, id int64) error {
+	if err := s.authorize(ctx, authz.UserManage); err != nil {
+		return err
+	}
+	
+	if actor, ok := models.ActorFromContext(ctx); ok && actor.UserID == id {
+		return errors.Forbidden("you can't delete yourself")
+	}
+	
+	user, err := s.userRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	
+	if user == nil {
+		return errors.NotFound("user")
+	}
+	
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		quizzes, sessions, err := s.userRepo.CountOwned(ctx, id)
+		if err != nil {
+			return err
+		}
+		
+		if quizzes > 0 || sessions > 0 {
+			return errors.Conflict(fmt.Sprintf(
+				"user owns %d quizzes and %d sessions, including ones in the trash, block the user instead",
+				quizzes, sessions,
+			))
+		}
+		
+		if err := s.userRepo.Delete(ctx, id); err != nil {
+			return err
+		}
+		
+		return s.audit.Record(ctx, models.AuditUserDelete, models.AuditTargetUser, userTargetID(id), user, nil)
+	})
 }
 
 func (s *AdminServiceImpl) UpdateUserRole(ctx context.Context, userID int64, roleFlags int) error {
+	if err := s.authorize(ctx, authz.UserManage); err != nil {
+		return err
+	}
+	
+	if err := checkRoleChange(ctx, userID, roleFlags); err != nil {
+		return err
+	}
+	
 	user, err := s.userRepo.GetByID(ctx, userID)
 	if err != nil {
 		return err
 	}
 	
 	if user == nil {
-		return errors.New("user not found")
+		return errors.NotFound("user")
 	}
 	
-	return s.userRepo.UpdateRole(ctx, userID, roleFlags)
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.userRepo.UpdateRole(ctx, userID, roleFlags); err != nil {
+			return err
+		}
+		
+		after := *user
+		after.RoleFlags = roleFlags
+		
+		return s.audit.Record(ctx, models.AuditUserRoleUpdate, models.AuditTargetUser, userTargetID(userID), user, &after)
+	})
 }
 
-// Quiz management
-func (s *AdminServiceImpl) GetAllQuizzes(ctx context.Context, offset, limit int) ([]*models.Quiz, error) {
-	return s.quizRepo.ListPublic(ctx, offset, limit)
+// checkRoleChange rejects unknown role flags and, like BulkUpdateUsers,
+// admins locking themselves out
+func checkRoleChange(ctx context.Context, userID int64, roleFlags int) error {
+	if roleFlags&^models.RoleAll != 0 {
+		return errors.Invalid("roleFlags", "unknown role")
+	}
+	
+	if actor, ok := models.ActorFromContext(ctx); ok && actor.UserID == userID {
+		if roleFlags&models.RoleBlocked != 0 || roleFlags&models.RoleAdmin == 0 {
+			return errors.Forbidden("you can't block yourself or remove your own admin role")
+		}
+	}
+	
+	return nil
 }
 
-func (s *AdminServiceImpl) DeleteQuiz(ctx context.Context, id uuid.UUID) error {
-	return s.quizRepo.Delete(ctx, id)
+// Quiz management
+func (s *AdminServiceImpl) GetAllQuizzes(ctx context.Context, opts models.ListOptions) (*models.Page[*models.Quiz], error) {
+	if err := s.authorize(ctx, authz.QuizModerate); err != nil {
+		return nil, err
+	}
+	
+	opts = listDefaults(opts, models.DefaultQuizSort)
+	
+	quizzes, err := s.quizRepo.ListAll(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	
+	total, err := s.quizRepo.Count(ctx)
+	if err != nil {
+		return nil, err
+	}
+	
+	return models.NewOffsetPage(quizzes, total, opts), nil
 }
 
-// Session management
-func (s *AdminServiceImpl) GetAllSessions(ctx context.Context, offset, limit int) ([]*models.GameSession, error) {
-	// In a real implementation, you would likely need a method to list all sessions,
-	// not just those belonging to a specific host.
-	// For demonstration purposes, we'll return an error for now.
-	return nil, errors.New("not implemented: need a method to list all sessions")
+func (s *AdminServiceImpl) GetQuiz(ctx context.Context, id uuid.UUID) (*models.Quiz, error) {
+	if err := s.authorize(ctx, authz.QuizModerate); err != nil {
+		return nil, err
+	}
+	
+	return s.quizRepo.GetByID(ctx, id)
 }
 
-func (s *AdminServiceImpl) EndSession(ctx context.Context, id uuid.UUID) error {
-	session, err := s.sessionRepo.GetByID(ctx, id)
+func (s *AdminServiceImpl) DeleteQuiz(ctx context.Context, id uuid.UUID, adminID int64) error {
+	if err := s.policy.Require(ctx, adminID, authz.QuizModerate); err != nil {
+		return err
+	}
+	
+	quiz, err := s.quizRepo.GetByID(ctx, id)
 	if err != nil {
 		return err
 	}
 	
-	if session == nil {
-		return errors.New("session not found")
+	if quiz == nil {
+		return errors.NotFound("quiz")
 	}
 	
-	// Update status to finished
-	now := time.Now()
-	session.StatusFlags = models.GameStatusFinished
-	session.EndedAt = &now
+	ctx = withActor(ctx, adminID)
 	
-	return s.sessionRepo.Update(ctx, session)
-}
\ No newline at end of file
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.quizRepo.Delete(ctx, id, adminID); err != nil {
+			return err
+		}
+		
+		return s.audit.Record(ctx, models.AuditQuizDelete, models.AuditTargetQuiz, id.String(), auditQuiz(quiz), nil)
+	})
+}
+
+// Session management
+// GetAllSessions returns a page of sessions matching the filter, the cursor
+// of the next page is empty on the last page
+func (s *AdminServiceImpl) GetAllSessions(ctx context.Context, filter models.SessionFilter) (*models.Page[*models.SessionListItem], error) {
+	if err := s.authorize(ctx, authz.SessionModerate); err != nil {
+		return nil, err
+	}
+	
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return nil, errors.Invalid("to", "date range start must be before its end")
+	}
+	
+	if filter.Sort.Field == "" {
+		filter.Sort = models.DefaultSessionSort
+	}
+	
+	// A cursor only makes sense in the order it was created in
+	if filter.After != nil && filter.After.Sort != filter.Sort {
+		return nil, models.ErrInvalidCursor
+	}
+	
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = defaultPageSize
+	}
+	
+	total, err := s.sessionRepo.Count(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	
+	// Fetch one extra row to know if there is a next page
+	filter.Limit = limit + 1
+	items, err := s.sessionRepo.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	
+	page := &models.Page[*models.SessionListItem]{
+		Items:    items,
+		Total:    total,
+		PageSize: limit,
+	}
+	
+	if len(items) > limit {
+		page.Items = items[:limit]
+		page.NextCursor = models.NewSessionCursor(&items[limit-1].GameSession, filter.Sort).Encode()
+	}
+	
+	return page, nil
+}
+
+func (s *AdminServiceImpl) GetSession(ctx context.Context, id uuid.UUID) (*models.GameSession, error) {
+	if err := s.authorize(ctx, authz.SessionModerate); err != nil {
+		return nil, err
+	}
+	
+	return s.sessionRepo.GetByID(ctx, id)
+}
+
+func userTargetID(id int64) string {
+	return strconv.FormatInt(id, 10)
+}
+
+// auditQuiz strips questions from the quiz, the audit log keeps
+// only the quiz's own fields
+func auditQuiz(quiz *models.Quiz) *models.Quiz {
+	stripped := *quiz
+	stripped.Questions = nil
+	return &stripped
+}
+
+// loginPattern allows the characters of Telegram usernames and university logins
+var loginPattern = regexp.MustCompile(`^[A-Za-z0-9._-]+$`)
+
+// validateLogin checks the login fits the users table
+func validateLogin(login string) error {
+	if login == "" {
+		return errors.Invalid("login", "login is required")
+	}
+	
+	if len(login) > 30 {
+		return errors.Invalid("login", "login must be at most 30 characters")
+	}
+	
+	if !loginPattern.MatchString(login) {
+		return errors.Invalid("login", "login may only contain letters, digits, dots, dashes and underscores")
+	}
+	
+	return nil
+}
@@ -0,0 +1,109 @@
+package service
+
+import (
+	"bsu-quiz/quiz/internal/domain/errors"
+	"bsu-quiz/quiz/internal/domain/models"
+	"bsu-quiz/quiz/internal/domain/rules"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const sessionsAdmin int64 = 1
+
+// newSessionsAdminService returns the service over a listing of n sessions
+// started a minute apart, newest first
+func newSessionsAdminService(n int) (*AdminServiceImpl, []*models.SessionListItem) {
+	start := time.Date(2025, 6, 22, 10, 0, 0, 0, time.UTC)
+
+	var listed []*models.SessionListItem
+	for i := range n {
+		started := start.Add(-time.Duration(i) * time.Minute)
+		listed = append(listed, &models.SessionListItem{
+			GameSession: models.GameSession{ID: uuid.New(), StartedAt: &started},
+		})
+	}
+
+	users := &fakeUserRepo{users: map[int64]*models.User{
+		sessionsAdmin: {ID: sessionsAdmin, RoleFlags: models.RoleUser | models.RoleAdmin},
+	}}
+	sessions := &fakeSessionRepo{listed: listed}
+	policy := rules.NewPolicy(nil, users, nil, sessions)
+
+	return NewAdminService(users, nil, sessions, policy, nil, nil), listed
+}
+
+func TestGetAllSessionsPages(t *testing.T) {
+	service, listed := newSessionsAdminService(5)
+	ctx := models.WithActor(context.Background(), models.Actor{UserID: sessionsAdmin})
+
+	filter := models.SessionFilter{Limit: 2}
+	var seen []uuid.UUID
+	for pages := 0; ; pages++ {
+		if pages > len(listed) {
+			t.Fatalf("pagination doesn't end")
+		}
+
+		page, err := service.GetAllSessions(ctx, filter)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if page.Total != len(listed) || page.PageSize != 2 {
+			t.Errorf("got total %d and page size %d, want %d and 2", page.Total, page.PageSize, len(listed))
+		}
+		for _, item := range page.Items {
+			seen = append(seen, item.ID)
+		}
+
+		if page.NextCursor == "" {
+			break
+		}
+
+		// Clients send the cursor back as it is
+		filter.After, err = models.DecodeSessionCursor(page.NextCursor)
+		if err != nil {
+			t.Fatalf("failed to decode cursor: %v", err)
+		}
+		if filter.After.Sort != models.DefaultSessionSort {
+			t.Errorf("got cursor sort %+v, want the default sort", filter.After.Sort)
+		}
+	}
+
+	if len(seen) != len(listed) {
+		t.Fatalf("got %d sessions over all pages, want %d", len(seen), len(listed))
+	}
+	for i, item := range listed {
+		if seen[i] != item.ID {
+			t.Errorf("session %d is out of order", i)
+		}
+	}
+}
+
+func TestGetAllSessionsCursorOfOtherSort(t *testing.T) {
+	service, listed := newSessionsAdminService(3)
+	ctx := models.WithActor(context.Background(), models.Actor{UserID: sessionsAdmin})
+
+	after := models.NewSessionCursor(&listed[0].GameSession, models.DefaultSessionSort)
+	filter := models.SessionFilter{
+		Sort:  models.Sort{Field: "ended_at", Direction: models.SortAsc},
+		After: after,
+	}
+
+	_, err := service.GetAllSessions(ctx, filter)
+	if !errors.Is(err, errors.ErrValidation) {
+		t.Fatalf("got error %v, want an invalid cursor", err)
+	}
+}
+
+func TestGetAllSessionsDateRange(t *testing.T) {
+	service, _ := newSessionsAdminService(1)
+	ctx := models.WithActor(context.Background(), models.Actor{UserID: sessionsAdmin})
+
+	day := time.Date(2025, 6, 22, 0, 0, 0, 0, time.UTC)
+	_, err := service.GetAllSessions(ctx, models.SessionFilter{From: &day, To: &day})
+	if _, ok := errors.Fields(err)["to"]; !ok {
+		t.Fatalf("got error %v, want an invalid range", err)
+	}
+}
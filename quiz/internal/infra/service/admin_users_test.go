@@ -0,0 +1,120 @@
+package service
+
+import (
+	"bsu-quiz/quiz/internal/domain/errors"
+	"bsu-quiz/quiz/internal/domain/models"
+	"bsu-quiz/quiz/internal/domain/rules"
+	"context"
+	"testing"
+)
+
+const (
+	usersAdmin int64 = iota + 1
+	usersStudent
+)
+
+func newUsersAdminService() (*AdminServiceImpl, *fakeUserRepo, *fakeAudit) {
+	users := &fakeUserRepo{users: map[int64]*models.User{
+		usersAdmin:   {ID: usersAdmin, Login: "admin", RoleFlags: models.RoleUser | models.RoleAdmin},
+		usersStudent: {ID: usersStudent, Login: "student", RoleFlags: models.RoleUser},
+	}}
+	audit := &fakeAudit{}
+	policy := rules.NewPolicy(nil, users, nil, nil)
+
+	return NewAdminService(users, nil, nil, policy, fakeTransactor{}, audit), users, audit
+}
+
+func TestUpdateUserRole(t *testing.T) {
+	tests := []struct {
+		name      string
+		userID    int64
+		roleFlags int
+		// want is the kind of the expected error, nil if the roles change
+		want error
+	}{
+		{name: "promote a student", userID: usersStudent, roleFlags: models.RoleUser | models.RoleTeacher},
+		{name: "block a student", userID: usersStudent, roleFlags: models.RoleUser | models.RoleBlocked},
+		{name: "unknown flag", userID: usersStudent, roleFlags: models.RoleUser | 1<<4, want: errors.ErrValidation},
+		{name: "negative flags", userID: usersStudent, roleFlags: -1, want: errors.ErrValidation},
+		{name: "unknown user", userID: 42, roleFlags: models.RoleUser, want: errors.ErrNotFound},
+		{name: "keep own admin role", userID: usersAdmin, roleFlags: models.RoleUser | models.RoleAdmin | models.RoleTeacher},
+		{name: "remove own admin role", userID: usersAdmin, roleFlags: models.RoleUser, want: errors.ErrForbidden},
+		{name: "block yourself", userID: usersAdmin, roleFlags: models.RoleAdmin | models.RoleBlocked, want: errors.ErrForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, users, audit := newUsersAdminService()
+			ctx := models.WithActor(context.Background(), models.Actor{UserID: usersAdmin})
+
+			err := service.UpdateUserRole(ctx, tt.userID, tt.roleFlags)
+
+			if tt.want != nil {
+				if !errors.Is(err, tt.want) {
+					t.Fatalf("got error %v, want %v", err, tt.want)
+				}
+				if len(audit.actions) != 0 {
+					t.Errorf("a rejected change was recorded: %v", audit.actions)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := users.users[tt.userID].RoleFlags; got != tt.roleFlags {
+				t.Errorf("got roles %b, want %b", got, tt.roleFlags)
+			}
+			if len(audit.actions) != 1 || audit.actions[0] != models.AuditUserRoleUpdate {
+				t.Errorf("got audit actions %v, want the role update", audit.actions)
+			}
+		})
+	}
+}
+
+// UpdateUser writes the roles too, it can't be used to get around UpdateUserRole
+func TestUpdateUserRoles(t *testing.T) {
+	service, users, _ := newUsersAdminService()
+	ctx := models.WithActor(context.Background(), models.Actor{UserID: usersAdmin})
+
+	self := *users.users[usersAdmin]
+	self.RoleFlags = models.RoleUser
+	if err := service.UpdateUser(ctx, &self); !errors.Is(err, errors.ErrForbidden) {
+		t.Errorf("got error %v for removing own admin role, want forbidden", err)
+	}
+
+	student := *users.users[usersStudent]
+	student.RoleFlags = 1 << 8
+	if err := service.UpdateUser(ctx, &student); !errors.Is(err, errors.ErrValidation) {
+		t.Errorf("got error %v for an unknown flag, want a validation error", err)
+	}
+
+	student.RoleFlags = models.RoleUser | models.RoleTeacher
+	if err := service.UpdateUser(ctx, &student); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if users.users[usersStudent].RoleFlags != student.RoleFlags {
+		t.Errorf("the roles of the student weren't updated")
+	}
+}
+
+func TestCreateUserUnknownRole(t *testing.T) {
+	service, _, _ := newUsersAdminService()
+	ctx := models.WithActor(context.Background(), models.Actor{UserID: usersAdmin})
+
+	_, err := service.CreateUser(ctx, &models.User{Login: "ivanov", RoleFlags: 1 << 5})
+	if !errors.Is(err, errors.ErrValidation) {
+		t.Fatalf("got error %v, want a validation error", err)
+	}
+}
+
+func TestUpdateUserRoleByTeacher(t *testing.T) {
+	service, users, _ := newUsersAdminService()
+	users.users[usersStudent].RoleFlags |= models.RoleTeacher
+	ctx := models.WithActor(context.Background(), models.Actor{UserID: usersStudent})
+
+	err := service.UpdateUserRole(ctx, usersStudent, models.RoleUser|models.RoleTeacher|models.RoleAdmin)
+	if !errors.Is(err, errors.ErrForbidden) {
+		t.Fatalf("got error %v, want forbidden", err)
+	}
+}
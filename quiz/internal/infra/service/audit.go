@@ -0,0 +1,74 @@
+package service
+
+import (
+	"bsu-quiz/quiz/internal/domain/models"
+	"bsu-quiz/quiz/internal/ports"
+	"context"
+)
+
+type AuditProvider interface {
+	// Record stores an entry for the actor in ctx with the fields that
+	// changed between before and after. It should be called inside the
+	// transaction of the change so both are stored or neither is
+	Record(ctx context.Context, action, targetType, targetID string, before, after any) error
+	List(ctx context.Context, filter models.AuditFilter) ([]*models.AuditEntry, error)
+	Count(ctx context.Context, filter models.AuditFilter) (int, error)
+}
+
+type AuditServiceImpl struct {
+	auditRepo ports.AuditRepositorier
+}
+
+func NewAuditService(auditRepo ports.AuditRepositorier) *AuditServiceImpl {
+	return &AuditServiceImpl{
+		auditRepo: auditRepo,
+	}
+}
+
+func (s *AuditServiceImpl) Record(ctx context.Context, action, targetType, targetID string, before, after any) error {
+	diff, err := models.NewAuditDiff(before, after)
+	if err != nil {
+		return err
+	}
+
+	entry := &models.AuditEntry{
+		Action:     action,
+		TargetType: targetType,
+		TargetID:   targetID,
+		Diff:       diff,
+	}
+
+	// Changes made without an actor, e.g. by workers, are stored as system ones
+	if actor, ok := models.ActorFromContext(ctx); ok {
+		entry.ActorID = &actor.UserID
+		entry.IP = actor.IP
+		entry.UserAgent = actor.UserAgent
+	}
+
+	_, err = s.auditRepo.Create(ctx, entry)
+	return err
+}
+
+func (s *AuditServiceImpl) List(ctx context.Context, filter models.AuditFilter) ([]*models.AuditEntry, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = 10
+	}
+
+	return s.auditRepo.List(ctx, filter)
+}
+
+func (s *AuditServiceImpl) Count(ctx context.Context, filter models.AuditFilter) (int, error) {
+	return s.auditRepo.Count(ctx, filter)
+}
+
+// withActor makes sure ctx carries an actor, services that receive the
+// acting user explicitly use it when the request didn't set one
+func withActor(ctx context.Context, userID int64) context.Context {
+	actor, ok := models.ActorFromContext(ctx)
+	if ok && actor.UserID == userID {
+		return ctx
+	}
+	actor.UserID = userID
+
+	return models.WithActor(ctx, actor)
+}
@@ -1,28 +1,169 @@
 package service
 
 import (
+	"bsu-quiz/quiz/internal/domain/errors"
 	"bsu-quiz/quiz/internal/domain/models"
-	"bsu-quiz/quiz/internal/infra/repository"
+	"bsu-quiz/quiz/internal/ports"
 	"context"
-	"errors"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
 
 	"golang.org/x/crypto/bcrypt"
 )
 
 type AuthProvider interface {
-	Register(ctx context.Context, login, password string) (int64, error)
+	// Register emails a link with a one-time token to the BSU mailbox of
+	// the login, the account is created once the password is chosen with
+	// it. Existing logins get a password reset link instead, so logins
+	// can't be probed
+	Register(ctx context.Context, login string) error
+	// CompleteRegistration creates the account the token was sent for with
+	// the password and returns its id
+	CompleteRegistration(ctx context.Context, token, password string) (int64, error)
 	Login(ctx context.Context, login, password string) (*models.User, error)
 	GetUserByID(ctx context.Context, id int64) (*models.User, error)
+
+	// Sessions of the admin panel, the token goes into the session cookie
+	StartSession(ctx context.Context, userID int64) (*models.WebSession, error)
+	// Authenticate returns the user signed in with the session token,
+	// it fails with errors.Unauthorized when the session is over
+	Authenticate(ctx context.Context, token string) (*models.User, error)
+	EndSession(ctx context.Context, token string) error
+
+	// AuthenticateTelegram returns the user the verified Telegram account
+	// is linked to, unlinked and blocked accounts get errors.Forbidden
+	AuthenticateTelegram(ctx context.Context, tgUser *models.TelegramUser) (*models.User, error)
+
+	// RequestPasswordReset emails the user a link with a one-time token,
+	// unknown logins are ignored so they can't be probed
+	RequestPasswordReset(ctx context.Context, login string) error
+	// ResetPassword sets the password of the user the token was sent to
+	// and signs the user out everywhere
+	ResetPassword(ctx context.Context, token, password string) error
 }
 
 type AuthServiceImpl struct {
-	userRepo repository.UserRepository
+	userRepo         ports.UserRepositorier
+	webSessionRepo   ports.WebSessionRepositorier
+	tokenRepo        ports.TokenRepositorier
+	resetRepo        ports.PasswordResetRepositorier
+	registrationRepo ports.RegistrationRepositorier
+	mailer           ports.Mailer
+	sessionTTL       time.Duration
+	resetTTL         time.Duration
+	registrationTTL  time.Duration
+	// resetURL and registrationURL are the pages of the panel the links
+	// of the emails point to
+	resetURL        string
+	registrationURL string
 }
 
-func NewAuthService(userRepo repository.UserRepository) *AuthServiceImpl {
+func NewAuthService(
+	userRepo ports.UserRepositorier,
+	webSessionRepo ports.WebSessionRepositorier,
+	tokenRepo ports.TokenRepositorier,
+	resetRepo ports.PasswordResetRepositorier,
+	registrationRepo ports.RegistrationRepositorier,
+	mailer ports.Mailer,
+	sessionTTL time.Duration,
+	resetTTL time.Duration,
+	registrationTTL time.Duration,
+	resetURL string,
+	registrationURL string,
+) *AuthServiceImpl {
 	return &AuthServiceImpl{
-		userRepo: userRepo,
+		userRepo:         userRepo,
+		webSessionRepo:   webSessionRepo,
+		tokenRepo:        tokenRepo,
+		resetRepo:        resetRepo,
+		registrationRepo: registrationRepo,
+		mailer:           mailer,
+		sessionTTL:       sessionTTL,
+		resetTTL:         resetTTL,
+		registrationTTL:  registrationTTL,
+		resetURL:         resetURL,
+		registrationURL:  registrationURL,
+	}
+}
+
+// Register proves the one signing up owns the BSU mailbox of the login,
+// the password is only set from the link emailed there
+func (s *AuthServiceImpl) Register(ctx context.Context, login string) error {
+	login = strings.TrimSpace(login)
+	if err := validateLogin(login); err != nil {
+		return err
+	}
+
+	user, err := s.userRepo.GetByLogin(ctx, login)
+	if err != nil {
+		return err
+	}
+
+	// Students imported by admins exist without a password, the reset
+	// link lets them choose one all the same
+	if user != nil {
+		return s.sendPasswordReset(ctx, user)
+	}
+
+	token, err := newToken()
+	if err != nil {
+		return err
+	}
+
+	if err := s.registrationRepo.Save(ctx, token, login, s.registrationTTL); err != nil {
+		return err
+	}
+
+	return s.mailer.Send(ctx, login, "BSU Quiz sign up", "registration", map[string]any{
+		"Login":     login,
+		"URL":       s.registrationURL + "?token=" + url.QueryEscape(token),
+		"ExpiresIn": formatTTL(s.registrationTTL),
+	})
+}
+
+func (s *AuthServiceImpl) CompleteRegistration(ctx context.Context, token, password string) (int64, error) {
+	login, ok, err := s.registrationRepo.Take(ctx, token)
+	if err != nil {
+		return 0, err
+	}
+
+	if !ok {
+		return 0, errors.Invalid("token", "the sign up link is invalid or has expired, sign up again")
+	}
+
+	if err := models.ValidatePassword(password, login); err != nil {
+		// The token is used up by now, keep it for another try
+		return 0, errors.Join(err, s.registrationRepo.Save(ctx, token, login, s.registrationTTL))
+	}
+
+	hash, err := hashPassword(password)
+	if err != nil {
+		return 0, err
+	}
+
+	// An admin may have created the login since the link was sent
+	user, err := s.userRepo.GetByLogin(ctx, login)
+	if err != nil {
+		return 0, err
+	}
+
+	if user == nil {
+		return s.userRepo.Create(ctx, &models.User{
+			Login:     login,
+			Password:  hash,
+			RoleFlags: models.RoleUser,
+		})
 	}
+
+	if user.IsBlocked() {
+		return 0, errors.Forbidden("this account has been blocked")
+	}
+
+	return user.ID, s.userRepo.UpdatePassword(ctx, user.ID, hash)
 }
 
 func (s *AuthServiceImpl) Login(ctx context.Context, login, password string) (*models.User, error) {
@@ -30,28 +171,202 @@ func (s *AuthServiceImpl) Login(ctx context.Context, login, password string) (*m
 	if err != nil {
 		return nil, err
 	}
-	
+
+	// Unknown logins and wrong passwords look the same, logins can't be probed
 	if user == nil {
-		return nil, errors.New("user not found")
+		return nil, errors.Unauthorized("invalid login or password")
 	}
-	
-	// Check if user is blocked
-	if user.IsBlocked() {
-		return nil, errors.New("this account has been blocked")
+
+	// Verify password, users without one can only sign in through Telegram
+	if user.Password == "" {
+		return nil, errors.Unauthorized("invalid login or password")
 	}
-	
-	// Verify password
+
 	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password))
 	if err != nil {
-		return nil, errors.New("invalid password")
+		return nil, errors.Unauthorized("invalid login or password")
+	}
+
+	// Only the owner of the password learns the account is blocked
+	if user.IsBlocked() {
+		return nil, errors.Forbidden("this account has been blocked")
 	}
-	
+
 	// Don't return the password hash
 	user.Password = ""
-	
+
 	return user, nil
 }
 
 func (s *AuthServiceImpl) GetUserByID(ctx context.Context, id int64) (*models.User, error) {
 	return s.userRepo.GetByID(ctx, id)
-}
\ No newline at end of file
+}
+
+func (s *AuthServiceImpl) StartSession(ctx context.Context, userID int64) (*models.WebSession, error) {
+	token, err := newToken()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	session := &models.WebSession{
+		Token:     token,
+		UserID:    userID,
+		CreatedAt: now,
+		ExpiresAt: now.Add(s.sessionTTL),
+	}
+
+	if err := s.webSessionRepo.Save(ctx, session); err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
+
+func (s *AuthServiceImpl) Authenticate(ctx context.Context, token string) (*models.User, error) {
+	session, err := s.webSessionRepo.Get(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	if session == nil {
+		return nil, errors.Unauthorized("your session has expired, sign in again")
+	}
+
+	// Roles are read on every request, so role changes and blocks apply at once
+	user, err := s.userRepo.GetByID(ctx, session.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user == nil || user.IsBlocked() {
+		return nil, errors.Join(errors.Unauthorized("your account is no longer available"), s.webSessionRepo.Delete(ctx, token))
+	}
+
+	return user, nil
+}
+
+func (s *AuthServiceImpl) EndSession(ctx context.Context, token string) error {
+	return s.webSessionRepo.Delete(ctx, token)
+}
+
+func (s *AuthServiceImpl) AuthenticateTelegram(ctx context.Context, tgUser *models.TelegramUser) (*models.User, error) {
+	user, err := s.userRepo.GetByTelegramID(ctx, tgUser.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user == nil {
+		return nil, errors.Forbidden("this Telegram account isn't linked to a BSU login, register with the bot first")
+	}
+
+	if user.IsBlocked() {
+		return nil, errors.Forbidden("this account has been blocked")
+	}
+
+	return user, nil
+}
+
+func (s *AuthServiceImpl) RequestPasswordReset(ctx context.Context, login string) error {
+	user, err := s.userRepo.GetByLogin(ctx, strings.TrimSpace(login))
+	if err != nil {
+		return err
+	}
+
+	if user == nil {
+		return nil
+	}
+
+	return s.sendPasswordReset(ctx, user)
+}
+
+// sendPasswordReset emails the user a reset link, blocked users get none
+func (s *AuthServiceImpl) sendPasswordReset(ctx context.Context, user *models.User) error {
+	if user.IsBlocked() {
+		return nil
+	}
+
+	token, err := newToken()
+	if err != nil {
+		return err
+	}
+
+	if err := s.resetRepo.Save(ctx, token, user.ID, s.resetTTL); err != nil {
+		return err
+	}
+
+	return s.mailer.Send(ctx, user.Login, "BSU Quiz password reset", "password_reset", map[string]any{
+		"Login":     user.Login,
+		"URL":       s.resetURL + "?token=" + url.QueryEscape(token),
+		"ExpiresIn": formatTTL(s.resetTTL),
+	})
+}
+
+// formatTTL tells how long a link works in the emails
+func formatTTL(ttl time.Duration) string {
+	if ttl >= time.Hour && ttl%time.Hour == 0 {
+		return fmt.Sprintf("%.0f hours", ttl.Hours())
+	}
+
+	return fmt.Sprintf("%.0f minutes", ttl.Minutes())
+}
+
+func (s *AuthServiceImpl) ResetPassword(ctx context.Context, token, password string) error {
+	userID, ok, err := s.resetRepo.Take(ctx, token)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return errors.Invalid("token", "the reset link is invalid or has expired, ask for a new one")
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if user == nil || user.IsBlocked() {
+		return errors.Invalid("token", "the reset link is invalid or has expired, ask for a new one")
+	}
+
+	if err := models.ValidatePassword(password, user.Login); err != nil {
+		// The token is used up by now, keep it for another try
+		return errors.Join(err, s.resetRepo.Save(ctx, token, userID, s.resetTTL))
+	}
+
+	hash, err := hashPassword(password)
+	if err != nil {
+		return err
+	}
+
+	if err := s.userRepo.UpdatePassword(ctx, userID, hash); err != nil {
+		return err
+	}
+
+	// Whoever knew the old password is signed out of the panel and the API
+	if err := s.webSessionRepo.DeleteByUser(ctx, userID); err != nil {
+		return err
+	}
+
+	return s.tokenRepo.DeleteRefreshTokens(ctx, userID)
+}
+
+func hashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+
+	return string(hash), nil
+}
+
+// newToken returns a random token of 256 bits for cookies and links
+func newToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
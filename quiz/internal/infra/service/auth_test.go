@@ -0,0 +1,148 @@
+package service
+
+import (
+	"bsu-quiz/quiz/internal/domain/errors"
+	"bsu-quiz/quiz/internal/domain/models"
+	"context"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Users of the auth tests, all with authPassword except the Telegram student
+const (
+	authStudent int64 = iota + 1
+	authBlocked
+	authTelegramStudent
+)
+
+const authPassword = "secret123"
+
+type authFixture struct {
+	service  *AuthServiceImpl
+	users    *fakeUserRepo
+	sessions *fakeWebSessionRepo
+	tokens   *fakeTokenRepo
+	resets   *fakeResetRepo
+}
+
+func newAuthFixture(t *testing.T) *authFixture {
+	t.Helper()
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(authPassword), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("failed to hash the password: %v", err)
+	}
+
+	f := &authFixture{
+		users: &fakeUserRepo{users: map[int64]*models.User{
+			authStudent:         {ID: authStudent, Login: "student", Password: string(hash), RoleFlags: models.RoleUser},
+			authBlocked:         {ID: authBlocked, Login: "blocked", Password: string(hash), RoleFlags: models.RoleUser | models.RoleBlocked},
+			authTelegramStudent: {ID: authTelegramStudent, Login: "telegram", RoleFlags: models.RoleUser},
+		}},
+		sessions: newFakeWebSessionRepo(),
+		tokens:   newFakeTokenRepo(),
+		resets:   &fakeResetRepo{tokens: make(map[string]int64)},
+	}
+	f.service = NewAuthService(f.users, f.sessions, f.tokens, f.resets, nil, nil, time.Hour, time.Hour, time.Hour, "", "")
+
+	return f
+}
+
+func TestLogin(t *testing.T) {
+	tests := []struct {
+		name     string
+		login    string
+		password string
+		// want is the kind of the expected error, nil if the login succeeds
+		want error
+	}{
+		{name: "valid", login: "student", password: authPassword},
+		{name: "wrong password", login: "student", password: "wrong123", want: errors.ErrUnauthorized},
+		{name: "unknown login", login: "nobody", password: authPassword, want: errors.ErrUnauthorized},
+		{name: "without a password", login: "telegram", password: "", want: errors.ErrUnauthorized},
+		// A blocked account isn't revealed to those who don't know its password
+		{name: "blocked with a wrong password", login: "blocked", password: "wrong123", want: errors.ErrUnauthorized},
+		{name: "blocked", login: "blocked", password: authPassword, want: errors.ErrForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newAuthFixture(t)
+
+			user, err := f.service.Login(context.Background(), tt.login, tt.password)
+
+			if tt.want != nil {
+				if !errors.Is(err, tt.want) {
+					t.Fatalf("got error %v, want %v", err, tt.want)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if user.ID != authStudent || user.Password != "" {
+				t.Errorf("got user %+v, want the student without the hash", user)
+			}
+		})
+	}
+}
+
+func TestResetPasswordSignsOutEverywhere(t *testing.T) {
+	f := newAuthFixture(t)
+	ctx := context.Background()
+
+	for _, userID := range []int64{authStudent, authStudent, authBlocked} {
+		if _, err := f.service.StartSession(ctx, userID); err != nil {
+			t.Fatalf("failed to start a session: %v", err)
+		}
+	}
+	f.tokens.SaveRefreshToken(ctx, &models.RefreshToken{Token: "student", UserID: authStudent})
+	f.tokens.SaveRefreshToken(ctx, &models.RefreshToken{Token: "other", UserID: authBlocked})
+	f.resets.Save(ctx, "reset", authStudent, time.Hour)
+
+	if err := f.service.ResetPassword(ctx, "reset", "newsecret123"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, session := range f.sessions.sessions {
+		if session.UserID == authStudent {
+			t.Errorf("a panel session of the student survived the reset")
+		}
+	}
+	if len(f.sessions.sessions) != 1 {
+		t.Errorf("got %d sessions, want the session of the other user", len(f.sessions.sessions))
+	}
+	if _, ok := f.tokens.refresh["student"]; ok {
+		t.Errorf("the refresh token of the student survived the reset")
+	}
+	if _, ok := f.tokens.refresh["other"]; !ok {
+		t.Errorf("the refresh token of another user was deleted")
+	}
+
+	if _, err := f.service.Login(ctx, "student", "newsecret123"); err != nil {
+		t.Errorf("can't sign in with the new password: %v", err)
+	}
+}
+
+func TestResetPasswordInvalidPassword(t *testing.T) {
+	f := newAuthFixture(t)
+	ctx := context.Background()
+
+	session, _ := f.service.StartSession(ctx, authStudent)
+	f.resets.Save(ctx, "reset", authStudent, time.Hour)
+
+	if err := f.service.ResetPassword(ctx, "reset", "short"); !errors.Is(err, errors.ErrValidation) {
+		t.Fatalf("got error %v, want a validation error", err)
+	}
+
+	// Nothing changed, the link works for another try
+	if _, ok := f.sessions.sessions[session.Token]; !ok {
+		t.Errorf("the session ended although the password wasn't changed")
+	}
+	if _, ok := f.resets.tokens["reset"]; !ok {
+		t.Errorf("the reset token is used up by an invalid password")
+	}
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"bsu-quiz/quiz/internal/domain/errors"
+	"bsu-quiz/quiz/internal/domain/models"
+	"bsu-quiz/quiz/internal/domain/rules"
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// Users of the collaborator tests
+const (
+	collabOwner int64 = iota + 1
+	collabEditor
+	collabViewer
+	collabStudent
+)
+
+type collabFixture struct {
+	service *QuizServiceImpl
+	quizID  uuid.UUID
+	collabs *fakeCollaboratorRepo
+	audit   *fakeAudit
+}
+
+func newCollabFixture() *collabFixture {
+	quiz := &models.Quiz{ID: uuid.New(), UserID: collabOwner}
+
+	users := &fakeUserRepo{users: map[int64]*models.User{
+		collabOwner:   {ID: collabOwner, Login: "owner", RoleFlags: models.RoleUser | models.RoleTeacher},
+		collabEditor:  {ID: collabEditor, Login: "editor", RoleFlags: models.RoleUser | models.RoleTeacher},
+		collabViewer:  {ID: collabViewer, Login: "viewer", RoleFlags: models.RoleUser | models.RoleTeacher},
+		collabStudent: {ID: collabStudent, Login: "student", RoleFlags: models.RoleUser},
+	}}
+	quizzes := &fakeQuizRepo{quizzes: map[uuid.UUID]*models.Quiz{quiz.ID: quiz}}
+	collabs := &fakeCollaboratorRepo{roles: map[collaboratorKey]models.QuizRole{
+		{quiz.ID, collabEditor}: models.QuizRoleEditor,
+		{quiz.ID, collabViewer}: models.QuizRoleViewer,
+	}}
+	audit := &fakeAudit{}
+	policy := rules.NewPolicy(quizzes, users, collabs, nil)
+
+	return &collabFixture{
+		service: NewQuizService(quizzes, users, collabs, policy, fakeTransactor{}, audit),
+		quizID:  quiz.ID,
+		collabs: collabs,
+		audit:   audit,
+	}
+}
+
+func TestInviteCollaborator(t *testing.T) {
+	tests := []struct {
+		name    string
+		actorID int64
+		login   string
+		role    models.QuizRole
+		// want is the kind of the expected error, nil if the user is invited
+		want error
+	}{
+		{name: "owner invites", actorID: collabOwner, login: "student", role: models.QuizRoleViewer},
+		{name: "owner promotes a viewer", actorID: collabOwner, login: "viewer", role: models.QuizRoleEditor},
+		{name: "editor invites", actorID: collabEditor, login: "student", role: models.QuizRoleViewer, want: errors.ErrForbidden},
+		{name: "owner changes own role", actorID: collabOwner, login: "owner", role: models.QuizRoleViewer, want: errors.ErrForbidden},
+		{name: "unknown login", actorID: collabOwner, login: "nobody", role: models.QuizRoleViewer, want: errors.ErrNotFound},
+		{name: "invalid role", actorID: collabOwner, login: "student", role: "admin", want: errors.ErrValidation},
+		{name: "no role", actorID: collabOwner, login: "student", role: models.QuizRoleNone, want: errors.ErrValidation},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newCollabFixture()
+
+			err := f.service.InviteCollaborator(context.Background(), f.quizID, tt.actorID, tt.login, tt.role)
+
+			if tt.want != nil {
+				if !errors.Is(err, tt.want) {
+					t.Fatalf("got error %v, want %v", err, tt.want)
+				}
+				if len(f.audit.actions) != 0 {
+					t.Errorf("a rejected invite was recorded: %v", f.audit.actions)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var invited int64
+			for _, id := range []int64{collabViewer, collabStudent} {
+				if f.collabs.roles[collaboratorKey{f.quizID, id}] == tt.role {
+					invited = id
+				}
+			}
+			if invited == 0 {
+				t.Errorf("%s didn't get the %s role", tt.login, tt.role)
+			}
+			if len(f.audit.actions) != 1 || f.audit.actions[0] != models.AuditCollaboratorInvite {
+				t.Errorf("got audit actions %v, want the invite", f.audit.actions)
+			}
+		})
+	}
+}
+
+func TestRemoveCollaborator(t *testing.T) {
+	tests := []struct {
+		name    string
+		actorID int64
+		userID  int64
+		// want is the kind of the expected error, nil if the user is removed
+		want error
+	}{
+		{name: "owner removes an editor", actorID: collabOwner, userID: collabEditor},
+		{name: "viewer leaves", actorID: collabViewer, userID: collabViewer},
+		{name: "editor removes a viewer", actorID: collabEditor, userID: collabViewer, want: errors.ErrForbidden},
+		{name: "outsider leaves", actorID: collabStudent, userID: collabStudent, want: errors.ErrForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newCollabFixture()
+			key := collaboratorKey{f.quizID, tt.userID}
+			before := f.collabs.roles[key]
+
+			err := f.service.RemoveCollaborator(context.Background(), f.quizID, tt.actorID, tt.userID)
+
+			if tt.want != nil {
+				if !errors.Is(err, tt.want) {
+					t.Fatalf("got error %v, want %v", err, tt.want)
+				}
+				if f.collabs.roles[key] != before {
+					t.Errorf("the role changed on a rejected removal")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if _, ok := f.collabs.roles[key]; ok {
+				t.Errorf("the collaborator wasn't removed")
+			}
+		})
+	}
+}
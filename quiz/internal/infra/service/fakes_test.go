@@ -0,0 +1,284 @@
+package service
+
+import (
+	"bsu-quiz/quiz/internal/domain/models"
+	"bsu-quiz/quiz/internal/ports"
+	"context"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// The fakes keep their data in maps and implement the methods the tests
+// reach, the embedded interfaces panic on any other call
+
+type fakeUserRepo struct {
+	ports.UserRepositorier
+	users map[int64]*models.User
+}
+
+func (r *fakeUserRepo) GetByID(_ context.Context, id int64) (*models.User, error) {
+	user, ok := r.users[id]
+	if !ok {
+		return nil, nil
+	}
+
+	copied := *user
+	return &copied, nil
+}
+
+func (r *fakeUserRepo) GetByLogin(_ context.Context, login string) (*models.User, error) {
+	for _, user := range r.users {
+		if user.Login == login {
+			copied := *user
+			return &copied, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (r *fakeUserRepo) UpdatePassword(_ context.Context, userID int64, hash string) error {
+	r.users[userID].Password = hash
+	return nil
+}
+
+func (r *fakeUserRepo) Update(_ context.Context, user *models.User) error {
+	copied := *user
+	r.users[user.ID] = &copied
+	return nil
+}
+
+func (r *fakeUserRepo) UpdateRole(_ context.Context, userID int64, roleFlags int) error {
+	r.users[userID].RoleFlags = roleFlags
+	return nil
+}
+
+func (r *fakeUserRepo) GetByLogins(_ context.Context, logins []string) ([]*models.User, error) {
+	var users []*models.User
+	for _, user := range r.users {
+		if slices.Contains(logins, user.Login) {
+			copied := *user
+			users = append(users, &copied)
+		}
+	}
+
+	return users, nil
+}
+
+func (r *fakeUserRepo) Create(_ context.Context, user *models.User) (int64, error) {
+	id := int64(len(r.users) + 1)
+	for r.users[id] != nil {
+		id++
+	}
+
+	copied := *user
+	copied.ID = id
+	r.users[id] = &copied
+
+	return id, nil
+}
+
+func (r *fakeUserRepo) Count(_ context.Context) (int, error) {
+	return len(r.users), nil
+}
+
+func (r *fakeUserRepo) ListRecent(_ context.Context, _ int) ([]*models.User, error) {
+	return nil, nil
+}
+
+type fakeQuizRepo struct {
+	ports.QuizRepositorier
+	quizzes map[uuid.UUID]*models.Quiz
+}
+
+func (r *fakeQuizRepo) GetByID(_ context.Context, id uuid.UUID) (*models.Quiz, error) {
+	quiz, ok := r.quizzes[id]
+	if !ok {
+		return nil, nil
+	}
+
+	copied := *quiz
+	return &copied, nil
+}
+
+func (r *fakeQuizRepo) Count(_ context.Context) (int, error) {
+	return len(r.quizzes), nil
+}
+
+func (r *fakeQuizRepo) ListAll(_ context.Context, _ models.ListOptions) ([]*models.Quiz, error) {
+	return nil, nil
+}
+
+type fakeSessionRepo struct {
+	ports.SessionRepositorier
+	sessions map[uuid.UUID]*models.GameSession
+	// listed is the admin listing in its order, List pages through it
+	listed []*models.SessionListItem
+}
+
+func (r *fakeSessionRepo) GetByID(_ context.Context, id uuid.UUID) (*models.GameSession, error) {
+	session, ok := r.sessions[id]
+	if !ok {
+		return nil, nil
+	}
+
+	copied := *session
+	return &copied, nil
+}
+
+func (r *fakeSessionRepo) List(_ context.Context, filter models.SessionFilter) ([]*models.SessionListItem, error) {
+	items := r.listed
+	if filter.After != nil {
+		for i, item := range items {
+			if item.ID == filter.After.ID {
+				items = items[i+1:]
+				break
+			}
+		}
+	}
+
+	return items[:min(filter.Limit, len(items))], nil
+}
+
+func (r *fakeSessionRepo) Count(_ context.Context, _ models.SessionFilter) (int, error) {
+	return len(r.listed), nil
+}
+
+type collaboratorKey struct {
+	quizID uuid.UUID
+	userID int64
+}
+
+type fakeCollaboratorRepo struct {
+	ports.CollaboratorRepositorier
+	roles map[collaboratorKey]models.QuizRole
+}
+
+func (r *fakeCollaboratorRepo) GetRole(_ context.Context, quizID uuid.UUID, userID int64) (models.QuizRole, error) {
+	return r.roles[collaboratorKey{quizID, userID}], nil
+}
+
+func (r *fakeCollaboratorRepo) Upsert(_ context.Context, collaborator *models.Collaborator) error {
+	r.roles[collaboratorKey{collaborator.QuizID, collaborator.UserID}] = collaborator.Role
+	return nil
+}
+
+func (r *fakeCollaboratorRepo) Remove(_ context.Context, quizID uuid.UUID, userID int64) error {
+	delete(r.roles, collaboratorKey{quizID, userID})
+	return nil
+}
+
+type fakeTxKey struct{}
+
+// fakeTransactor runs the function with a context inFakeTx recognises
+type fakeTransactor struct{}
+
+func (fakeTransactor) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(context.WithValue(ctx, fakeTxKey{}, true))
+}
+
+func inFakeTx(ctx context.Context) bool {
+	return ctx.Value(fakeTxKey{}) != nil
+}
+
+// fakeAudit keeps the recorded actions
+type fakeAudit struct {
+	AuditProvider
+	actions []string
+}
+
+func (a *fakeAudit) Record(_ context.Context, action, _, _ string, _, _ any) error {
+	a.actions = append(a.actions, action)
+	return nil
+}
+
+type fakeWebSessionRepo struct {
+	sessions map[string]*models.WebSession
+}
+
+func newFakeWebSessionRepo() *fakeWebSessionRepo {
+	return &fakeWebSessionRepo{sessions: make(map[string]*models.WebSession)}
+}
+
+func (r *fakeWebSessionRepo) Save(_ context.Context, session *models.WebSession) error {
+	copied := *session
+	r.sessions[session.Token] = &copied
+	return nil
+}
+
+func (r *fakeWebSessionRepo) Get(_ context.Context, token string) (*models.WebSession, error) {
+	return r.sessions[token], nil
+}
+
+func (r *fakeWebSessionRepo) Delete(_ context.Context, token string) error {
+	delete(r.sessions, token)
+	return nil
+}
+
+func (r *fakeWebSessionRepo) DeleteByUser(_ context.Context, userID int64) error {
+	for token, session := range r.sessions {
+		if session.UserID == userID {
+			delete(r.sessions, token)
+		}
+	}
+	return nil
+}
+
+type fakeTokenRepo struct {
+	refresh map[string]*models.RefreshToken
+	revoked map[string]bool
+}
+
+func newFakeTokenRepo() *fakeTokenRepo {
+	return &fakeTokenRepo{
+		refresh: make(map[string]*models.RefreshToken),
+		revoked: make(map[string]bool),
+	}
+}
+
+func (r *fakeTokenRepo) SaveRefreshToken(_ context.Context, token *models.RefreshToken) error {
+	copied := *token
+	r.refresh[token.Token] = &copied
+	return nil
+}
+
+func (r *fakeTokenRepo) TakeRefreshToken(_ context.Context, token string) (*models.RefreshToken, error) {
+	refresh := r.refresh[token]
+	delete(r.refresh, token)
+	return refresh, nil
+}
+
+func (r *fakeTokenRepo) DeleteRefreshTokens(_ context.Context, userID int64) error {
+	for token, refresh := range r.refresh {
+		if refresh.UserID == userID {
+			delete(r.refresh, token)
+		}
+	}
+	return nil
+}
+
+func (r *fakeTokenRepo) RevokeAccessToken(_ context.Context, id string, _ time.Time) error {
+	r.revoked[id] = true
+	return nil
+}
+
+func (r *fakeTokenRepo) IsAccessTokenRevoked(_ context.Context, id string) (bool, error) {
+	return r.revoked[id], nil
+}
+
+type fakeResetRepo struct {
+	tokens map[string]int64
+}
+
+func (r *fakeResetRepo) Save(_ context.Context, token string, userID int64, _ time.Duration) error {
+	r.tokens[token] = userID
+	return nil
+}
+
+func (r *fakeResetRepo) Take(_ context.Context, token string) (int64, bool, error) {
+	userID, ok := r.tokens[token]
+	delete(r.tokens, token)
+	return userID, ok, nil
+}
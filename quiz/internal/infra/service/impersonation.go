@@ -0,0 +1,114 @@
+package service
+
+import (
+	"bsu-quiz/pkg/authz"
+	"bsu-quiz/quiz/internal/domain/errors"
+	"bsu-quiz/quiz/internal/domain/models"
+	"bsu-quiz/quiz/internal/ports"
+	"context"
+	"time"
+)
+
+type ImpersonationProvider interface {
+	// Start lets the admin view the panel as the user until Stop or the TTL is over
+	Start(ctx context.Context, adminID, userID int64) (*models.Impersonation, error)
+	Stop(ctx context.Context, adminID int64) error
+	// Active returns the impersonation of the admin, nil if there's none
+	Active(ctx context.Context, adminID int64) (*models.Impersonation, error)
+}
+
+type ImpersonationServiceImpl struct {
+	impersonationRepo ports.ImpersonationRepositorier
+	userRepo          ports.UserRepositorier
+	audit             AuditProvider
+	ttl               time.Duration
+}
+
+func NewImpersonationService(
+	impersonationRepo ports.ImpersonationRepositorier,
+	userRepo ports.UserRepositorier,
+	audit AuditProvider,
+	ttl time.Duration,
+) *ImpersonationServiceImpl {
+	return &ImpersonationServiceImpl{
+		impersonationRepo: impersonationRepo,
+		userRepo:          userRepo,
+		audit:             audit,
+		ttl:               ttl,
+	}
+}
+
+func (s *ImpersonationServiceImpl) Start(ctx context.Context, adminID, userID int64) (*models.Impersonation, error) {
+	admin, err := s.userRepo.GetByID(ctx, adminID)
+	if err != nil {
+		return nil, err
+	}
+
+	if admin == nil || !admin.Can(authz.UserImpersonate) {
+		return nil, errors.Forbidden("only admins can view the panel as another user")
+	}
+
+	if adminID == userID {
+		return nil, errors.Invalid("id", "you can't impersonate yourself")
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user == nil {
+		return nil, errors.NotFound("user")
+	}
+
+	// Viewing as another admin wouldn't show anything new and would hide who acts
+	if user.IsAdmin() {
+		return nil, errors.Forbidden("admins can't be impersonated")
+	}
+
+	now := time.Now()
+	impersonation := &models.Impersonation{
+		AdminID:    admin.ID,
+		AdminLogin: admin.Login,
+		UserID:     user.ID,
+		UserLogin:  user.Login,
+		UserRoles:  user.RoleFlags,
+		StartedAt:  now,
+		ExpiresAt:  now.Add(s.ttl),
+	}
+
+	if err := s.impersonationRepo.Save(ctx, impersonation); err != nil {
+		return nil, err
+	}
+
+	ctx = withActor(ctx, adminID)
+	err = s.audit.Record(ctx, models.AuditImpersonationStart, models.AuditTargetUser, userTargetID(userID), nil, impersonation)
+	if err != nil {
+		// Don't leave an impersonation the audit log doesn't know about
+		return nil, errors.Join(err, s.impersonationRepo.Delete(ctx, adminID))
+	}
+
+	return impersonation, nil
+}
+
+func (s *ImpersonationServiceImpl) Stop(ctx context.Context, adminID int64) error {
+	impersonation, err := s.impersonationRepo.Get(ctx, adminID)
+	if err != nil {
+		return err
+	}
+
+	if impersonation == nil {
+		return nil
+	}
+
+	if err := s.impersonationRepo.Delete(ctx, adminID); err != nil {
+		return err
+	}
+
+	ctx = withActor(ctx, adminID)
+	return s.audit.Record(ctx, models.AuditImpersonationStop, models.AuditTargetUser, userTargetID(impersonation.UserID), impersonation, nil)
+}
+
+func (s *ImpersonationServiceImpl) Active(ctx context.Context, adminID int64) (*models.Impersonation, error) {
+	return s.impersonationRepo.Get(ctx, adminID)
+}
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"bsu-quiz/quiz/internal/domain/errors"
+	"bsu-quiz/quiz/internal/domain/models"
+	"context"
+	"testing"
+	"time"
+)
+
+// Users of the impersonation tests
+const (
+	impersonationAdmin int64 = iota + 1
+	impersonationOtherAdmin
+	impersonationTeacher
+)
+
+type fakeImpersonationRepo struct {
+	impersonations map[int64]*models.Impersonation
+}
+
+func (r *fakeImpersonationRepo) Save(_ context.Context, impersonation *models.Impersonation) error {
+	r.impersonations[impersonation.AdminID] = impersonation
+	return nil
+}
+
+func (r *fakeImpersonationRepo) Get(_ context.Context, adminID int64) (*models.Impersonation, error) {
+	return r.impersonations[adminID], nil
+}
+
+func (r *fakeImpersonationRepo) Delete(_ context.Context, adminID int64) error {
+	delete(r.impersonations, adminID)
+	return nil
+}
+
+func newImpersonationFixture() (*ImpersonationServiceImpl, *fakeImpersonationRepo, *fakeAudit) {
+	users := &fakeUserRepo{users: map[int64]*models.User{
+		impersonationAdmin:      {ID: impersonationAdmin, Login: "admin", RoleFlags: models.RoleUser | models.RoleAdmin},
+		impersonationOtherAdmin: {ID: impersonationOtherAdmin, Login: "root", RoleFlags: models.RoleUser | models.RoleAdmin},
+		impersonationTeacher:    {ID: impersonationTeacher, Login: "teacher", RoleFlags: models.RoleUser | models.RoleTeacher},
+	}}
+	repo := &fakeImpersonationRepo{impersonations: make(map[int64]*models.Impersonation)}
+	audit := &fakeAudit{}
+
+	return NewImpersonationService(repo, users, audit, time.Hour), repo, audit
+}
+
+func TestStartImpersonation(t *testing.T) {
+	tests := []struct {
+		name    string
+		adminID int64
+		userID  int64
+		// want is the kind of the expected error, nil if it starts
+		want error
+	}{
+		{name: "admin views as a teacher", adminID: impersonationAdmin, userID: impersonationTeacher},
+		{name: "teacher views as an admin", adminID: impersonationTeacher, userID: impersonationAdmin, want: errors.ErrForbidden},
+		{name: "admin views as an admin", adminID: impersonationAdmin, userID: impersonationOtherAdmin, want: errors.ErrForbidden},
+		{name: "admin views as themselves", adminID: impersonationAdmin, userID: impersonationAdmin, want: errors.ErrValidation},
+		{name: "unknown user", adminID: impersonationAdmin, userID: 42, want: errors.ErrNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, repo, audit := newImpersonationFixture()
+
+			impersonation, err := service.Start(context.Background(), tt.adminID, tt.userID)
+
+			if tt.want != nil {
+				if !errors.Is(err, tt.want) {
+					t.Fatalf("got error %v, want %v", err, tt.want)
+				}
+				if len(repo.impersonations) != 0 || len(audit.actions) != 0 {
+					t.Errorf("a refused impersonation was stored or recorded")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if impersonation.UserID != tt.userID || impersonation.UserLogin != "teacher" ||
+				impersonation.UserRoles != models.RoleUser|models.RoleTeacher {
+				t.Errorf("got impersonation %+v, want the teacher", impersonation)
+			}
+			if got := impersonation.ExpiresAt.Sub(impersonation.StartedAt); got != time.Hour {
+				t.Errorf("got TTL %v, want 1h", got)
+			}
+			if active, _ := service.Active(context.Background(), tt.adminID); active == nil {
+				t.Errorf("the impersonation isn't active")
+			}
+			if len(audit.actions) != 1 || audit.actions[0] != models.AuditImpersonationStart {
+				t.Errorf("got audit actions %v, want the start", audit.actions)
+			}
+		})
+	}
+}
+
+func TestStopImpersonation(t *testing.T) {
+	service, repo, audit := newImpersonationFixture()
+	ctx := context.Background()
+
+	// Stopping without an impersonation does nothing
+	if err := service.Stop(ctx, impersonationAdmin); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(audit.actions) != 0 {
+		t.Errorf("got audit actions %v without an impersonation", audit.actions)
+	}
+
+	if _, err := service.Start(ctx, impersonationAdmin, impersonationTeacher); err != nil {
+		t.Fatalf("failed to start: %v", err)
+	}
+	if err := service.Stop(ctx, impersonationAdmin); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(repo.impersonations) != 0 {
+		t.Errorf("the impersonation is still active")
+	}
+	if len(audit.actions) != 2 || audit.actions[1] != models.AuditImpersonationStop {
+		t.Errorf("got audit actions %v, want the start and the stop", audit.actions)
+	}
+}
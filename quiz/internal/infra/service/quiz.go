@@ -1,10 +1,13 @@
 package service
 
 import (
+	"bsu-quiz/pkg/authz"
+	"bsu-quiz/quiz/internal/domain/errors"
 	"bsu-quiz/quiz/internal/domain/models"
+	"bsu-quiz/quiz/internal/domain/rules"
+	"bsu-quiz/quiz/internal/infra/markup"
 	"bsu-quiz/quiz/internal/ports"
 	"context"
-	"errors"
 	"time"
 
 	"github.com/google/uuid"
@@ -15,36 +18,69 @@ type QuizProvider interface {
 	GetQuiz(ctx context.Context, id uuid.UUID) (*models.Quiz, error)
 	UpdateQuiz(ctx context.Context, quiz *models.Quiz) error
 	DeleteQuiz(ctx context.Context, id uuid.UUID, userID int64) error
-	ListQuizzes(ctx context.Context, userID int64, offset, limit int) ([]*models.Quiz, error)
+	// ListQuizzes returns a page of quizzes owned by the user or shared with them
+	ListQuizzes(ctx context.Context, userID int64, opts models.ListOptions) (*models.Page[*models.Quiz], error)
 	ListPublicQuizzes(ctx context.Context, offset, limit int) ([]*models.Quiz, error)
+	GetQuizRole(ctx context.Context, quizID uuid.UUID, userID int64) (models.QuizRole, error)
+
+	// Publishing lifecycle
+	ChangeQuizStatus(ctx context.Context, id uuid.UUID, userID int64, status models.QuizStatus, publishAt *time.Time) error
+	PublishScheduledQuizzes(ctx context.Context) (int64, error)
+
+	// Collaborators
+	InviteCollaborator(ctx context.Context, quizID uuid.UUID, actorID int64, login string, role models.QuizRole) error
+	ListCollaborators(ctx context.Context, quizID uuid.UUID, actorID int64) ([]models.Collaborator, error)
+	RemoveCollaborator(ctx context.Context, quizID uuid.UUID, actorID, userID int64) error
+
+	// Import and export
+	ExportQuiz(ctx context.Context, id uuid.UUID, userID int64) (*models.Quiz, error)
+	ImportQuiz(ctx context.Context, quiz *models.Quiz) (uuid.UUID, error)
 
 	// Question management
-	AddQuestion(ctx context.Context, question *models.Question) (uuid.UUID, error)
-	UpdateQuestion(ctx context.Context, question *models.Question) error
-	DeleteQuestion(ctx context.Context, id uuid.UUID) error
+	AddQuestion(ctx context.Context, userID int64, question *models.Question) (uuid.UUID, error)
+	UpdateQuestion(ctx context.Context, userID int64, question *models.Question) error
+	DeleteQuestion(ctx context.Context, userID int64, id uuid.UUID) error
 
 	// Option management
-	AddOption(ctx context.Context, option *models.Option) (uuid.UUID, error)
-	UpdateOption(ctx context.Context, option *models.Option) error
-	DeleteOption(ctx context.Context, id uuid.UUID) error
+	AddOption(ctx context.Context, userID int64, option *models.Option) (uuid.UUID, error)
+	UpdateOption(ctx context.Context, userID int64, option *models.Option) error
+	DeleteOption(ctx context.Context, userID int64, id uuid.UUID) error
 }
 
 type QuizServiceImpl struct {
-	quizRepo ports.QuizRepositorier
-	userRepo ports.UserRepositorier
+	quizRepo   ports.QuizRepositorier
+	userRepo   ports.UserRepositorier
+	collabRepo ports.CollaboratorRepositorier
+	policy     *rules.Policy
+	tx         ports.Transactor
+	audit      AuditProvider
 }
 
 func NewQuizService(
 	quizRepo ports.QuizRepositorier,
 	userRepo ports.UserRepositorier,
+	collabRepo ports.CollaboratorRepositorier,
+	policy *rules.Policy,
+	tx ports.Transactor,
+	audit AuditProvider,
 ) *QuizServiceImpl {
 	return &QuizServiceImpl{
-		quizRepo: quizRepo,
-		userRepo: userRepo,
+		quizRepo:   quizRepo,
+		userRepo:   userRepo,
+		collabRepo: collabRepo,
+		policy:     policy,
+		tx:         tx,
+		audit:      audit,
 	}
 }
 
+// CreateQuiz creates a draft quiz owned by quiz.UserID, only teachers and
+// admins may create quizzes
 func (s *QuizServiceImpl) CreateQuiz(ctx context.Context, quiz *models.Quiz) (uuid.UUID, error) {
+	if err := s.policy.Require(ctx, quiz.UserID, authz.QuizCreate); err != nil {
+		return uuid.Nil, err
+	}
+
 	// Generate new UUID if not provided
 	if quiz.ID == uuid.Nil {
 		quiz.ID = uuid.New()
@@ -62,11 +98,16 @@ func (s *QuizServiceImpl) CreateQuiz(ctx context.Context, quiz *models.Quiz) (uu
 	}
 
 	if user == nil {
-		return uuid.Nil, errors.New("user not found")
+		return uuid.Nil, errors.NotFound("user")
 	}
 
 	quiz.CreatedBy = user.Login
 
+	// New quizzes start as drafts and are published explicitly
+	quiz.Status = models.QuizStatusDraft
+	quiz.PublishAt = nil
+	quiz.PublishedAt = nil
+
 	return s.quizRepo.Create(ctx, quiz)
 }
 
@@ -74,92 +115,377 @@ func (s *QuizServiceImpl) GetQuiz(ctx context.Context, id uuid.UUID) (*models.Qu
 	return s.quizRepo.GetByID(ctx, id)
 }
 
+// UpdateQuiz updates the quiz on behalf of quiz.UserID
 func (s *QuizServiceImpl) UpdateQuiz(ctx context.Context, quiz *models.Quiz) error {
-	// Check if quiz exists
-	existingQuiz, err := s.quizRepo.GetByID(ctx, quiz.ID)
+	// Ensure user is an owner, an editor or an admin
+	if _, err := s.policy.AuthorizeQuiz(ctx, quiz.UserID, quiz.ID, rules.ActionEdit); err != nil {
+		return err
+	}
+
+	// Update timestamp
+	quiz.UpdatedAt = time.Now()
+
+	return s.quizRepo.Update(ctx, quiz)
+}
+
+func (s *QuizServiceImpl) DeleteQuiz(ctx context.Context, id uuid.UUID, userID int64) error {
+	// Check if user is an owner or has admin permissions
+	quiz, err := s.policy.AuthorizeQuiz(ctx, userID, id, rules.ActionManage)
 	if err != nil {
 		return err
 	}
 
-	if existingQuiz == nil {
-		return errors.New("quiz not found")
+	ctx = withActor(ctx, userID)
+
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.quizRepo.Delete(ctx, id, userID); err != nil {
+			return err
+		}
+
+		return s.audit.Record(ctx, models.AuditQuizDelete, models.AuditTargetQuiz, id.String(), auditQuiz(quiz), nil)
+	})
+}
+
+func (s *QuizServiceImpl) ListQuizzes(ctx context.Context, userID int64, opts models.ListOptions) (*models.Page[*models.Quiz], error) {
+	opts = listDefaults(opts, models.DefaultQuizSort)
+
+	quizzes, err := s.quizRepo.List(ctx, userID, opts)
+	if err != nil {
+		return nil, err
 	}
 
-	// Ensure user is the owner
-	if existingQuiz.UserID != quiz.UserID {
-		return errors.New("you don't have permission to update this quiz")
+	total, err := s.quizRepo.CountByUser(ctx, userID)
+	if err != nil {
+		return nil, err
 	}
 
-	// Update timestamp
-	quiz.UpdatedAt = time.Now()
+	return models.NewOffsetPage(quizzes, total, opts), nil
+}
 
-	return s.quizRepo.Update(ctx, quiz)
+func (s *QuizServiceImpl) ListPublicQuizzes(ctx context.Context, offset, limit int) ([]*models.Quiz, error) {
+	return s.quizRepo.ListPublic(ctx, offset, limit)
 }
 
-func (s *QuizServiceImpl) DeleteQuiz(ctx context.Context, id uuid.UUID, userID int64) error {
-	// Check if quiz exists
-	existingQuiz, err := s.quizRepo.GetByID(ctx, id)
+// GetQuizRole returns the role of the user in the quiz
+func (s *QuizServiceImpl) GetQuizRole(ctx context.Context, quizID uuid.UUID, userID int64) (models.QuizRole, error) {
+	quiz, err := s.quizRepo.GetByID(ctx, quizID)
+	if err != nil {
+		return models.QuizRoleNone, err
+	}
+
+	if quiz == nil {
+		return models.QuizRoleNone, errors.NotFound("quiz")
+	}
+
+	return s.policy.QuizRole(ctx, quiz, userID)
+}
+
+// Collaborators
+
+// InviteCollaborator adds the user with the given login to the quiz
+// or changes their role. Only owners may manage collaborators
+func (s *QuizServiceImpl) InviteCollaborator(ctx context.Context, quizID uuid.UUID, actorID int64, login string, role models.QuizRole) error {
+	if !role.IsValid() {
+		return errors.Invalid("role", "invalid collaborator role")
+	}
+
+	if _, err := s.policy.AuthorizeQuiz(ctx, actorID, quizID, rules.ActionManage); err != nil {
+		return err
+	}
+
+	user, err := s.userRepo.GetByLogin(ctx, login)
 	if err != nil {
 		return err
 	}
 
-	if existingQuiz == nil {
-		return errors.New("quiz not found")
+	if user == nil {
+		return errors.NotFound("user")
+	}
+
+	if user.ID == actorID {
+		return errors.Forbidden("you can't change your own role")
 	}
 
-	// Check if user is the owner or has admin permissions
-	user, err := s.userRepo.GetByID(ctx, userID)
+	previous, err := s.collabRepo.GetRole(ctx, quizID, user.ID)
 	if err != nil {
 		return err
 	}
 
-	if existingQuiz.UserID != userID && !user.IsAdmin() {
-		return errors.New("you don't have permission to delete this quiz")
+	ctx = withActor(ctx, actorID)
+
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		err := s.collabRepo.Upsert(ctx, &models.Collaborator{
+			QuizID:    quizID,
+			UserID:    user.ID,
+			Role:      role,
+			InvitedBy: &actorID,
+		})
+		if err != nil {
+			return err
+		}
+
+		return s.audit.Record(ctx, models.AuditCollaboratorInvite, models.AuditTargetQuiz, quizID.String(),
+			collaboratorAudit(user.ID, previous), collaboratorAudit(user.ID, role))
+	})
+}
+
+func (s *QuizServiceImpl) ListCollaborators(ctx context.Context, quizID uuid.UUID, actorID int64) ([]models.Collaborator, error) {
+	if _, err := s.policy.AuthorizeQuiz(ctx, actorID, quizID, rules.ActionView); err != nil {
+		return nil, err
 	}
 
-	return s.quizRepo.Delete(ctx, id)
+	return s.collabRepo.List(ctx, quizID)
 }
 
-func (s *QuizServiceImpl) ListQuizzes(ctx context.Context, userID int64, offset, limit int) ([]*models.Quiz, error) {
-	return s.quizRepo.List(ctx, userID, offset, limit)
+// RemoveCollaborator removes the user from the quiz. Owners may remove anyone,
+// other collaborators may only leave the quiz themselves
+func (s *QuizServiceImpl) RemoveCollaborator(ctx context.Context, quizID uuid.UUID, actorID, userID int64) error {
+	action := rules.ActionManage
+	if actorID == userID {
+		action = rules.ActionView
+	}
+
+	if _, err := s.policy.AuthorizeQuiz(ctx, actorID, quizID, action); err != nil {
+		return err
+	}
+
+	previous, err := s.collabRepo.GetRole(ctx, quizID, userID)
+	if err != nil {
+		return err
+	}
+
+	ctx = withActor(ctx, actorID)
+
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.collabRepo.Remove(ctx, quizID, userID); err != nil {
+			return err
+		}
+
+		return s.audit.Record(ctx, models.AuditCollaboratorRemove, models.AuditTargetQuiz, quizID.String(),
+			collaboratorAudit(userID, previous), collaboratorAudit(userID, models.QuizRoleNone))
+	})
 }
 
-func (s *QuizServiceImpl) ListPublicQuizzes(ctx context.Context, offset, limit int) ([]*models.Quiz, error) {
-	return s.quizRepo.ListPublic(ctx, offset, limit)
+// collaboratorAudit is the audited state of a collaborator in a quiz
+func collaboratorAudit(userID int64, role models.QuizRole) map[string]any {
+	return map[string]any{
+		"user_id": userID,
+		"role":    role,
+	}
 }
 
 // Question management
-func (s *QuizServiceImpl) AddQuestion(ctx context.Context, question *models.Question) (uuid.UUID, error) {
+func (s *QuizServiceImpl) AddQuestion(ctx context.Context, userID int64, question *models.Question) (uuid.UUID, error) {
 	// Check if quiz exists and user has permission
-	quiz, err := s.quizRepo.GetByID(ctx, question.QuizID)
-	if err != nil {
+	if _, err := s.policy.AuthorizeQuiz(ctx, userID, question.QuizID, rules.ActionEdit); err != nil {
 		return uuid.Nil, err
 	}
 
-	if quiz == nil {
-		return uuid.Nil, errors.New("quiz not found")
+	if err := validateQuestion(question); err != nil {
+		return uuid.Nil, err
 	}
 
 	return s.quizRepo.CreateQuestion(ctx, question)
 }
 
-func (s *QuizServiceImpl) UpdateQuestion(ctx context.Context, question *models.Question) error {
+func (s *QuizServiceImpl) UpdateQuestion(ctx context.Context, userID int64, question *models.Question) error {
+	if err := s.policy.AuthorizeQuestion(ctx, userID, question.ID, rules.ActionEdit); err != nil {
+		return err
+	}
+
+	if err := validateQuestion(question); err != nil {
+		return err
+	}
+
 	return s.quizRepo.UpdateQuestion(ctx, question)
 }
 
-func (s *QuizServiceImpl) DeleteQuestion(ctx context.Context, id uuid.UUID) error {
+func (s *QuizServiceImpl) DeleteQuestion(ctx context.Context, userID int64, id uuid.UUID) error {
+	if err := s.policy.AuthorizeQuestion(ctx, userID, id, rules.ActionEdit); err != nil {
+		return err
+	}
+
 	return s.quizRepo.DeleteQuestion(ctx, id)
 }
 
 // Option management
-func (s *QuizServiceImpl) AddOption(ctx context.Context, option *models.Option) (uuid.UUID, error) {
+func (s *QuizServiceImpl) AddOption(ctx context.Context, userID int64, option *models.Option) (uuid.UUID, error) {
+	if err := s.policy.AuthorizeQuestion(ctx, userID, option.QuestionID, rules.ActionEdit); err != nil {
+		return uuid.Nil, err
+	}
+
+	if err := validateOption(option); err != nil {
+		return uuid.Nil, err
+	}
+
 	return s.quizRepo.CreateOption(ctx, option)
 }
 
-func (s *QuizServiceImpl) UpdateOption(ctx context.Context, option *models.Option) error {
+func (s *QuizServiceImpl) UpdateOption(ctx context.Context, userID int64, option *models.Option) error {
+	if err := s.policy.AuthorizeOption(ctx, userID, option.ID, rules.ActionEdit); err != nil {
+		return err
+	}
+
+	if err := validateOption(option); err != nil {
+		return err
+	}
+
 	return s.quizRepo.UpdateOption(ctx, option)
 }
 
-func (s *QuizServiceImpl) DeleteOption(ctx context.Context, id uuid.UUID) error {
+func (s *QuizServiceImpl) DeleteOption(ctx context.Context, userID int64, id uuid.UUID) error {
+	if err := s.policy.AuthorizeOption(ctx, userID, id, rules.ActionEdit); err != nil {
+		return err
+	}
+
 	return s.quizRepo.DeleteOption(ctx, id)
 }
+
+// Publishing lifecycle
+
+// ChangeQuizStatus moves the quiz to another lifecycle state.
+// publishAt is required for scheduled quizzes and must be in the future
+func (s *QuizServiceImpl) ChangeQuizStatus(ctx context.Context, id uuid.UUID, userID int64, status models.QuizStatus, publishAt *time.Time) error {
+	if !status.IsValid() {
+		return errors.Invalid("status", "invalid quiz status")
+	}
+
+	// Only owners and admins may publish or archive a quiz
+	quiz, err := s.policy.AuthorizeQuiz(ctx, userID, id, rules.ActionManage)
+	if err != nil {
+		return err
+	}
+
+	if quiz.Status == status && status != models.QuizStatusScheduled {
+		return nil
+	}
+
+	if quiz.Status != status && !quiz.Status.CanTransitionTo(status) {
+		return errors.Conflict("quiz can't be moved from " + string(quiz.Status) + " to " + string(status))
+	}
+
+	if status == models.QuizStatusScheduled {
+		if publishAt == nil || !publishAt.After(time.Now()) {
+			return errors.Invalid("publish_at", "publish time must be in the future")
+		}
+	} else {
+		publishAt = nil
+	}
+
+	if (status == models.QuizStatusPublished || status == models.QuizStatusScheduled) && len(quiz.Questions) == 0 {
+		return errors.Conflict("quiz without questions can't be published")
+	}
+
+	before := auditQuiz(quiz)
+	after := auditQuiz(quiz)
+	after.Status = status
+	after.PublishAt = publishAt
+
+	ctx = withActor(ctx, userID)
+
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.quizRepo.UpdateStatus(ctx, id, status, publishAt); err != nil {
+			return err
+		}
+
+		return s.audit.Record(ctx, models.AuditQuizStatusChange, models.AuditTargetQuiz, id.String(), before, after)
+	})
+}
+
+// PublishScheduledQuizzes publishes quizzes whose publish time has come,
+// it's called periodically by the publisher worker
+func (s *QuizServiceImpl) PublishScheduledQuizzes(ctx context.Context) (int64, error) {
+	return s.quizRepo.PublishScheduled(ctx, time.Now())
+}
+
+// Import and export
+
+// ExportQuiz returns the quiz with all questions and options,
+// including explanations and option feedback
+func (s *QuizServiceImpl) ExportQuiz(ctx context.Context, id uuid.UUID, userID int64) (*models.Quiz, error) {
+	return s.policy.AuthorizeQuiz(ctx, userID, id, rules.ActionView)
+}
+
+// ImportQuiz creates a new quiz owned by quiz.UserID from an exported one.
+// All IDs are regenerated, so the same export can be imported many times
+func (s *QuizServiceImpl) ImportQuiz(ctx context.Context, quiz *models.Quiz) (uuid.UUID, error) {
+	questions := quiz.Questions
+	quiz.ID = uuid.Nil
+	quiz.Questions = nil
+
+	for i := range questions {
+		if err := validateQuestion(&questions[i]); err != nil {
+			return uuid.Nil, err
+		}
+
+		for j := range questions[i].Options {
+			if err := validateOption(&questions[i].Options[j]); err != nil {
+				return uuid.Nil, err
+			}
+		}
+	}
+
+	// The quiz is created with all its questions or not at all
+	var quizID uuid.UUID
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		quizID, err = s.CreateQuiz(ctx, quiz)
+		if err != nil {
+			return err
+		}
+
+		for i := range questions {
+			question := questions[i]
+			question.ID = uuid.Nil
+			question.QuizID = quizID
+
+			questionID, err := s.quizRepo.CreateQuestion(ctx, &question)
+			if err != nil {
+				return err
+			}
+
+			for j := range question.Options {
+				option := question.Options[j]
+				option.ID = uuid.Nil
+				option.QuestionID = questionID
+
+				if _, err := s.quizRepo.CreateOption(ctx, &option); err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	return quizID, nil
+}
+
+// validateQuestion checks the markup of the question text and explanation
+func validateQuestion(question *models.Question) error {
+	if err := markup.Validate(question.Text); err != nil {
+		return errors.Invalid("text", err.Error())
+	}
+
+	if err := markup.Validate(question.Explanation); err != nil {
+		return errors.Invalid("explanation", err.Error())
+	}
+
+	return nil
+}
+
+// validateOption checks the markup of the option text and feedback
+func validateOption(option *models.Option) error {
+	if err := markup.Validate(option.Text); err != nil {
+		return errors.Invalid("text", err.Error())
+	}
+
+	if err := markup.Validate(option.Feedback); err != nil {
+		return errors.Invalid("feedback", err.Error())
+	}
+
+	return nil
+}
@@ -0,0 +1,121 @@
+package service
+
+import (
+	"bsu-quiz/quiz/internal/domain/errors"
+	"bsu-quiz/quiz/internal/domain/models"
+	"bsu-quiz/quiz/internal/domain/rules"
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// importQuizRepo records the writes of an import, the option with the
+// text failOption can't be stored
+type importQuizRepo struct {
+	*fakeQuizRepo
+	failOption string
+	// outsideTx counts writes made without a transaction
+	outsideTx int
+	questions int
+	options   int
+}
+
+func (r *importQuizRepo) write(ctx context.Context) {
+	if !inFakeTx(ctx) {
+		r.outsideTx++
+	}
+}
+
+func (r *importQuizRepo) Create(ctx context.Context, quiz *models.Quiz) (uuid.UUID, error) {
+	r.write(ctx)
+	return quiz.ID, nil
+}
+
+func (r *importQuizRepo) CreateQuestion(ctx context.Context, _ *models.Question) (uuid.UUID, error) {
+	r.write(ctx)
+	r.questions++
+	return uuid.New(), nil
+}
+
+func (r *importQuizRepo) CreateOption(ctx context.Context, option *models.Option) (uuid.UUID, error) {
+	r.write(ctx)
+	if option.Text == r.failOption {
+		return uuid.Nil, errors.New("connection reset")
+	}
+	r.options++
+	return uuid.New(), nil
+}
+
+func newImportFixture(failOption string) (*QuizServiceImpl, *importQuizRepo) {
+	const teacher int64 = 1
+
+	users := &fakeUserRepo{users: map[int64]*models.User{
+		teacher: {ID: teacher, Login: "teacher", RoleFlags: models.RoleUser | models.RoleTeacher},
+	}}
+	quizzes := &importQuizRepo{fakeQuizRepo: &fakeQuizRepo{}, failOption: failOption}
+	policy := rules.NewPolicy(quizzes, users, nil, nil)
+
+	return NewQuizService(quizzes, users, nil, policy, fakeTransactor{}, nil), quizzes
+}
+
+func importedQuiz() *models.Quiz {
+	quiz := &models.Quiz{ID: uuid.New(), UserID: 1, Title: "Imported"}
+	for range 2 {
+		quiz.Questions = append(quiz.Questions, models.Question{
+			ID:      uuid.New(),
+			Text:    "question",
+			Options: []models.Option{{ID: uuid.New(), Text: "yes", IsCorrect: true}, {ID: uuid.New(), Text: "no"}},
+		})
+	}
+	quiz.Questions[1].Options[1].Text = "last"
+
+	return quiz
+}
+
+func TestImportQuiz(t *testing.T) {
+	service, quizzes := newImportFixture("")
+	quiz := importedQuiz()
+	exportedID := quiz.ID
+
+	id, err := service.ImportQuiz(context.Background(), quiz)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if id == uuid.Nil || id == exportedID {
+		t.Errorf("got quiz id %v, want a new one", id)
+	}
+	if quizzes.questions != 2 || quizzes.options != 4 {
+		t.Errorf("got %d questions and %d options, want 2 and 4", quizzes.questions, quizzes.options)
+	}
+	if quizzes.outsideTx != 0 {
+		t.Errorf("%d writes were made outside the transaction", quizzes.outsideTx)
+	}
+}
+
+func TestImportQuizFailure(t *testing.T) {
+	// The last option fails, the transaction rolls back everything before it.
+	// HardDelete isn't faked, the import must not clean up by hand
+	service, quizzes := newImportFixture("last")
+
+	if _, err := service.ImportQuiz(context.Background(), importedQuiz()); err == nil {
+		t.Fatalf("got no error for a failed option")
+	}
+	if quizzes.outsideTx != 0 {
+		t.Errorf("%d writes were made outside the transaction", quizzes.outsideTx)
+	}
+}
+
+func TestImportQuizInvalidMarkup(t *testing.T) {
+	service, quizzes := newImportFixture("")
+	quiz := importedQuiz()
+	quiz.Questions[0].Options[0].Text = "$\\frac{1$"
+
+	if _, err := service.ImportQuiz(context.Background(), quiz); !errors.Is(err, errors.ErrValidation) {
+		t.Fatalf("got error %v, want a validation error", err)
+	}
+	if quizzes.questions != 0 {
+		t.Errorf("questions were stored for an invalid import")
+	}
+}
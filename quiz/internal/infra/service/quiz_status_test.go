@@ -0,0 +1,88 @@
+package service
+
+import (
+	"bsu-quiz/quiz/internal/domain/errors"
+	"bsu-quiz/quiz/internal/domain/models"
+	"bsu-quiz/quiz/internal/domain/rules"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// statusQuizRepo stores status changes in the quizzes
+type statusQuizRepo struct {
+	*fakeQuizRepo
+}
+
+func (r *statusQuizRepo) UpdateStatus(_ context.Context, id uuid.UUID, status models.QuizStatus, publishAt *time.Time) error {
+	r.quizzes[id].Status = status
+	r.quizzes[id].PublishAt = publishAt
+	return nil
+}
+
+func TestChangeQuizStatus(t *testing.T) {
+	const teacher int64 = 1
+	future := time.Now().Add(time.Hour)
+	past := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name      string
+		from      models.QuizStatus
+		questions int
+		to        models.QuizStatus
+		publishAt *time.Time
+		// want is the kind of the expected error, nil if the status changes
+		want error
+	}{
+		{name: "schedule", from: models.QuizStatusDraft, questions: 1, to: models.QuizStatusScheduled, publishAt: &future},
+		{name: "publish", from: models.QuizStatusDraft, questions: 1, to: models.QuizStatusPublished},
+		{name: "archive an empty quiz", from: models.QuizStatusDraft, to: models.QuizStatusArchived},
+		{name: "schedule an empty quiz", from: models.QuizStatusDraft, to: models.QuizStatusScheduled, publishAt: &future, want: errors.ErrConflict},
+		{name: "publish an empty quiz", from: models.QuizStatusDraft, to: models.QuizStatusPublished, want: errors.ErrConflict},
+		{name: "reschedule an emptied quiz", from: models.QuizStatusScheduled, to: models.QuizStatusScheduled, publishAt: &future, want: errors.ErrConflict},
+		{name: "schedule in the past", from: models.QuizStatusDraft, questions: 1, to: models.QuizStatusScheduled, publishAt: &past, want: errors.ErrValidation},
+		{name: "schedule without time", from: models.QuizStatusDraft, questions: 1, to: models.QuizStatusScheduled, want: errors.ErrValidation},
+		{name: "schedule a published quiz", from: models.QuizStatusPublished, questions: 1, to: models.QuizStatusScheduled, publishAt: &future, want: errors.ErrConflict},
+		{name: "unknown status", from: models.QuizStatusDraft, questions: 1, to: "hidden", want: errors.ErrValidation},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			quiz := &models.Quiz{ID: uuid.New(), UserID: teacher, Status: tt.from}
+			for range tt.questions {
+				quiz.Questions = append(quiz.Questions, models.Question{ID: uuid.New()})
+			}
+
+			users := &fakeUserRepo{users: map[int64]*models.User{
+				teacher: {ID: teacher, RoleFlags: models.RoleUser | models.RoleTeacher},
+			}}
+			quizzes := &statusQuizRepo{&fakeQuizRepo{quizzes: map[uuid.UUID]*models.Quiz{quiz.ID: quiz}}}
+			policy := rules.NewPolicy(quizzes, users, nil, nil)
+			service := NewQuizService(quizzes, users, nil, policy, fakeTransactor{}, &fakeAudit{})
+
+			err := service.ChangeQuizStatus(context.Background(), quiz.ID, teacher, tt.to, tt.publishAt)
+
+			if tt.want != nil {
+				if !errors.Is(err, tt.want) {
+					t.Fatalf("got error %v, want %v", err, tt.want)
+				}
+				if quiz.Status != tt.from {
+					t.Errorf("got status %s after a rejected change, want %s", quiz.Status, tt.from)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if quiz.Status != tt.to {
+				t.Errorf("got status %s, want %s", quiz.Status, tt.to)
+			}
+			if (quiz.PublishAt != nil) != (tt.to == models.QuizStatusScheduled) {
+				t.Errorf("got publish time %v for a %s quiz", quiz.PublishAt, tt.to)
+			}
+		})
+	}
+}
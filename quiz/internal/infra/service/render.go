@@ -0,0 +1,129 @@
+package service
+
+import (
+	"bsu-quiz/quiz/internal/domain/errors"
+	"bsu-quiz/quiz/internal/infra/markup"
+	"container/list"
+	"context"
+	"fmt"
+	"sync"
+)
+
+type RenderProvider interface {
+	Preview(ctx context.Context, text string) (*markup.Rendered, error)
+	Render(text string) *markup.Rendered
+	FormulaImage(ctx context.Context, tex string, display bool) ([]byte, error)
+}
+
+type RenderServiceImpl struct {
+	renderer *markup.Renderer
+	images   *formulaCache
+}
+
+// NewRenderService returns the service keeping up to cacheSize formula
+// images, drawing is slow and the image endpoint is public
+func NewRenderService(renderer *markup.Renderer, cacheSize int) *RenderServiceImpl {
+	return &RenderServiceImpl{
+		renderer: renderer,
+		images:   newFormulaCache(cacheSize),
+	}
+}
+
+// Preview renders question or option text the way participants will see it
+func (s *RenderServiceImpl) Preview(ctx context.Context, text string) (*markup.Rendered, error) {
+	return s.renderer.Render(text), nil
+}
+
+// Render renders question or option text delivered to participants
+func (s *RenderServiceImpl) Render(text string) *markup.Rendered {
+	return s.renderer.Render(text)
+}
+
+// FormulaImage draws a formula as a PNG image for clients without MathML
+// support, such as the Telegram bot
+func (s *RenderServiceImpl) FormulaImage(ctx context.Context, tex string, display bool) ([]byte, error) {
+	if tex == "" {
+		return nil, errors.Invalid("tex", "formula is required")
+	}
+
+	key := formulaKey{tex: tex, display: display}
+	if img, ok := s.images.get(key); ok {
+		return img, nil
+	}
+
+	img, err := markup.FormulaImage(tex, display)
+	switch {
+	case errors.Is(err, markup.ErrUnbalancedBraces),
+		errors.Is(err, markup.ErrUnknownCommand),
+		errors.Is(err, markup.ErrMissingArgument),
+		errors.Is(err, markup.ErrFormulaTooLong),
+		errors.Is(err, markup.ErrFormulaTooLarge):
+		return nil, errors.Invalid("tex", err.Error())
+	case err != nil:
+		return nil, fmt.Errorf("failed to draw formula: %w", err)
+	}
+	s.images.add(key, img)
+
+	return img, nil
+}
+
+type formulaKey struct {
+	tex     string
+	display bool
+}
+
+type formulaEntry struct {
+	key formulaKey
+	img []byte
+}
+
+// formulaCache keeps the recently drawn formula images, the least
+// recently used one is dropped when the cache is full
+type formulaCache struct {
+	mu      sync.Mutex
+	size    int
+	order   *list.List
+	entries map[formulaKey]*list.Element
+}
+
+func newFormulaCache(size int) *formulaCache {
+	return &formulaCache{
+		size:    size,
+		order:   list.New(),
+		entries: make(map[formulaKey]*list.Element),
+	}
+}
+
+func (c *formulaCache) get(key formulaKey) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(elem)
+
+	return elem.Value.(*formulaEntry).img, true
+}
+
+func (c *formulaCache) add(key formulaKey, img []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.size <= 0 {
+		return
+	}
+
+	if elem, ok := c.entries[key]; ok {
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	if c.order.Len() >= c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*formulaEntry).key)
+	}
+	c.entries[key] = c.order.PushFront(&formulaEntry{key: key, img: img})
+}
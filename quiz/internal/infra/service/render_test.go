@@ -0,0 +1,80 @@
+package service
+
+import (
+	"bsu-quiz/quiz/internal/domain/errors"
+	"bsu-quiz/quiz/internal/infra/markup"
+	"bytes"
+	"context"
+	"testing"
+)
+
+func TestFormulaImageCache(t *testing.T) {
+	service := NewRenderService(markup.NewRenderer(""), 2)
+	ctx := context.Background()
+
+	first, err := service.FormulaImage(ctx, `x^2`, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cached, ok := service.images.get(formulaKey{tex: `x^2`})
+	if !ok || !bytes.Equal(cached, first) {
+		t.Fatalf("the drawn image isn't cached")
+	}
+
+	// Display mode draws another image
+	if _, ok := service.images.get(formulaKey{tex: `x^2`, display: true}); ok {
+		t.Errorf("the inline image is cached for display mode")
+	}
+
+	again, err := service.FormulaImage(ctx, `x^2`, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Equal(again, first) {
+		t.Errorf("got another image for the cached formula")
+	}
+}
+
+func TestFormulaImageCacheErrors(t *testing.T) {
+	service := NewRenderService(markup.NewRenderer(""), 2)
+
+	if _, err := service.FormulaImage(context.Background(), `\frac{1`, false); !errors.Is(err, errors.ErrValidation) {
+		t.Fatalf("got error %v, want a validation error", err)
+	}
+	if service.images.order.Len() != 0 {
+		t.Errorf("an invalid formula is cached")
+	}
+}
+
+func TestFormulaCacheEviction(t *testing.T) {
+	cache := newFormulaCache(2)
+	a, b, c := formulaKey{tex: "a"}, formulaKey{tex: "b"}, formulaKey{tex: "c"}
+
+	cache.add(a, []byte("a"))
+	cache.add(b, []byte("b"))
+	// a is used, so b is the least recently used
+	cache.get(a)
+	cache.add(c, []byte("c"))
+
+	if _, ok := cache.get(b); ok {
+		t.Errorf("the least recently used image is kept")
+	}
+	for _, key := range []formulaKey{a, c} {
+		if _, ok := cache.get(key); !ok {
+			t.Errorf("image of %q is dropped", key.tex)
+		}
+	}
+	if cache.order.Len() != 2 || len(cache.entries) != 2 {
+		t.Errorf("got %d images, want 2", cache.order.Len())
+	}
+}
+
+func TestFormulaCacheDisabled(t *testing.T) {
+	cache := newFormulaCache(0)
+	cache.add(formulaKey{tex: "a"}, []byte("a"))
+
+	if _, ok := cache.get(formulaKey{tex: "a"}); ok {
+		t.Errorf("an image is cached with the cache turned off")
+	}
+}
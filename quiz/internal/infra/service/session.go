@@ -1,32 +1,53 @@
 package service
 
 import (
+	"bsu-quiz/pkg/authz"
+	"bsu-quiz/quiz/internal/domain/errors"
 	"bsu-quiz/quiz/internal/domain/models"
+	"bsu-quiz/quiz/internal/domain/rules"
 	"bsu-quiz/quiz/internal/ports"
 	"context"
 	"crypto/rand"
-	"errors"
 	"math/big"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 )
 
 type SessionProvider interface {
-	CreateSession(ctx context.Context, quizID uuid.UUID, hostID int64) (*models.GameSession, error)
+	CreateSession(ctx context.Context, quizID uuid.UUID, hostID int64, examMode bool) (*models.GameSession, error)
 	GetSession(ctx context.Context, id uuid.UUID) (*models.GameSession, error)
 	GetSessionByJoinCode(ctx context.Context, joinCode string) (*models.GameSession, error)
+	GetSessionResults(ctx context.Context, id uuid.UUID, userID int64) (*models.GameSession, error)
+	DeleteSession(ctx context.Context, id uuid.UUID, userID int64) error
+
+	// Feedback, shown to those who may view the session and to its
+	// participants. Players only get their own review
+	GetQuestionFeedback(ctx context.Context, sessionID, questionID uuid.UUID, userID int64) (*models.QuestionReview, error)
+	GetSessionReview(ctx context.Context, sessionID, participantID uuid.UUID, userID int64) ([]models.QuestionReview, error)
+	// GetPlayerFeedback returns the feedback revealed so far to the user
+	// playing the session with their answers
+	GetPlayerFeedback(ctx context.Context, sessionID uuid.UUID, userID int64) (*models.PlayerFeedback, error)
 	// ListSessions(ctx context.Context, hostID int64, offset, limit int) ([]*models.GameSession, error)
 
-	// Session control
-	// StartSession(ctx context.Context, id uuid.UUID, hostID int64) error
-	// PauseSession(ctx context.Context, id uuid.UUID, hostID int64) error
-	// ResumeSession(ctx context.Context, id uuid.UUID, hostID int64) error
-	// EndSession(ctx context.Context, id uuid.UUID, hostID int64) error
-	// AdvanceQuestion(ctx context.Context, id uuid.UUID, hostID int64) error
+	// Session control, allowed to the host, those who may host the quiz
+	// and admins. Every change is recorded in the audit log
+	StartSession(ctx context.Context, id uuid.UUID, userID int64) error
+	PauseSession(ctx context.Context, id uuid.UUID, userID int64) error
+	ResumeSession(ctx context.Context, id uuid.UUID, userID int64) error
+	EndSession(ctx context.Context, id uuid.UUID, userID int64) error
+	// SkipQuestion opens the next question, skipping the last one ends the session
+	SkipQuestion(ctx context.Context, id uuid.UUID, userID int64) error
+	// MonitorSession returns a snapshot of the session for the live monitor
+	MonitorSession(ctx context.Context, id uuid.UUID, userID int64) (*models.SessionMonitor, error)
 
 	// Participant management
+	// JoinSession adds the user to the session with the join code, joining
+	// again returns the participant they already are
+	JoinSession(ctx context.Context, joinCode string, userID int64) (*models.Participant, error)
 	// AddParticipant(ctx context.Context, sessionID uuid.UUID, userID *int64, nickname string) (*models.Participant, error)
-	// RemoveParticipant(ctx context.Context, participantID uuid.UUID, hostID int64) error
+	KickParticipant(ctx context.Context, sessionID, participantID uuid.UUID, userID int64) error
 	// RecordAnswer(ctx context.Context, answer *models.Answer) error
 }
 
@@ -34,17 +55,26 @@ type SessionServiceImpl struct {
 	sessionRepo ports.SessionRepositorier
 	quizRepo    ports.QuizRepositorier
 	userRepo    ports.UserRepositorier
+	policy      *rules.Policy
+	tx          ports.Transactor
+	audit       AuditProvider
 }
 
 func NewSessionService(
 	sessionRepo ports.SessionRepositorier,
 	quizRepo ports.QuizRepositorier,
 	userRepo ports.UserRepositorier,
+	policy *rules.Policy,
+	tx ports.Transactor,
+	audit AuditProvider,
 ) *SessionServiceImpl {
 	return &SessionServiceImpl{
 		sessionRepo: sessionRepo,
 		quizRepo:    quizRepo,
 		userRepo:    userRepo,
+		policy:      policy,
+		tx:          tx,
+		audit:       audit,
 	}
 }
 
@@ -67,15 +97,20 @@ func generateJoinCode() (string, error) {
 	return string(code), nil
 }
 
-func (s *SessionServiceImpl) CreateSession(ctx context.Context, quizID uuid.UUID, hostID int64) (*models.GameSession, error) {
-	// Check if quiz exists
-	quiz, err := s.quizRepo.GetByID(ctx, quizID)
+func (s *SessionServiceImpl) CreateSession(ctx context.Context, quizID uuid.UUID, hostID int64, examMode bool) (*models.GameSession, error) {
+	if err := s.policy.Require(ctx, hostID, authz.SessionHost); err != nil {
+		return nil, err
+	}
+
+	// Only owners, editors and admins may host sessions of the quiz
+	quiz, err := s.policy.AuthorizeQuiz(ctx, hostID, quizID, rules.ActionHost)
 	if err != nil {
 		return nil, err
 	}
 
-	if quiz == nil {
-		return nil, errors.New("quiz not found")
+	// Archived quizzes keep their old sessions but can't be hosted again
+	if !quiz.CanStartSession() {
+		return nil, errors.Conflict("quiz is archived, new sessions can't be started")
 	}
 
 	// Generate join code
@@ -92,6 +127,7 @@ func (s *SessionServiceImpl) CreateSession(ctx context.Context, quizID uuid.UUID
 		JoinCode:             joinCode,
 		StatusFlags:          models.GameStatusWaiting,
 		CurrentQuestionIndex: 0,
+		ExamMode:             examMode,
 	}
 
 	id, err := s.sessionRepo.Create(ctx, session)
@@ -112,7 +148,7 @@ func (s *SessionServiceImpl) GetSession(ctx context.Context, id uuid.UUID) (*mod
 	}
 
 	if session == nil {
-		return nil, errors.New("session not found")
+		return nil, errors.NotFound("session")
 	}
 
 	// Get quiz details
@@ -133,7 +169,7 @@ func (s *SessionServiceImpl) GetSessionByJoinCode(ctx context.Context, joinCode
 	}
 
 	if session == nil {
-		return nil, errors.New("session not found")
+		return nil, errors.NotFound("session")
 	}
 
 	// Get quiz details
@@ -146,3 +182,314 @@ func (s *SessionServiceImpl) GetSessionByJoinCode(ctx context.Context, joinCode
 
 	return session, nil
 }
+
+// GetSessionResults returns the session with participants and their answers.
+// Results are visible to the host, collaborators of the quiz and admins
+func (s *SessionServiceImpl) GetSessionResults(ctx context.Context, id uuid.UUID, userID int64) (*models.GameSession, error) {
+	return s.policy.AuthorizeSession(ctx, userID, id, rules.ActionView)
+}
+
+// DeleteSession moves the session to the trash
+func (s *SessionServiceImpl) DeleteSession(ctx context.Context, id uuid.UUID, userID int64) error {
+	if _, err := s.policy.AuthorizeSession(ctx, userID, id, rules.ActionManage); err != nil {
+		return err
+	}
+
+	return s.sessionRepo.Delete(ctx, id, userID)
+}
+
+// StartSession opens the first question of a waiting session
+func (s *SessionServiceImpl) StartSession(ctx context.Context, id uuid.UUID, userID int64) error {
+	return s.controlSession(ctx, id, userID, models.AuditSessionStart, func(session *models.GameSession, now time.Time) error {
+		return session.Start(now)
+	})
+}
+
+// PauseSession stops the question timer
+func (s *SessionServiceImpl) PauseSession(ctx context.Context, id uuid.UUID, userID int64) error {
+	return s.controlSession(ctx, id, userID, models.AuditSessionPause, func(session *models.GameSession, now time.Time) error {
+		return session.Pause(now)
+	})
+}
+
+// ResumeSession restarts the question timer where it stopped
+func (s *SessionServiceImpl) ResumeSession(ctx context.Context, id uuid.UUID, userID int64) error {
+	return s.controlSession(ctx, id, userID, models.AuditSessionResume, func(session *models.GameSession, now time.Time) error {
+		return session.Resume(now)
+	})
+}
+
+// SkipQuestion closes the open question before its time is up
+func (s *SessionServiceImpl) SkipQuestion(ctx context.Context, id uuid.UUID, userID int64) error {
+	return s.controlSession(ctx, id, userID, models.AuditSessionSkip, func(session *models.GameSession, now time.Time) error {
+		return session.SkipQuestion(now, len(session.Quiz.Questions))
+	})
+}
+
+// EndSession finishes the session for everyone
+func (s *SessionServiceImpl) EndSession(ctx context.Context, id uuid.UUID, userID int64) error {
+	return s.controlSession(ctx, id, userID, models.AuditSessionEnd, func(session *models.GameSession, now time.Time) error {
+		return session.End(now)
+	})
+}
+
+// controlSession applies the change to the session and records it in the
+// audit log in one transaction. The change is applied to the session row
+// locked within the transaction, so concurrent controls don't overwrite
+// each other
+func (s *SessionServiceImpl) controlSession(
+	ctx context.Context,
+	id uuid.UUID,
+	userID int64,
+	action string,
+	change func(session *models.GameSession, now time.Time) error,
+) error {
+	authorized, err := s.policy.AuthorizeSession(ctx, userID, id, rules.ActionHost)
+	if err != nil {
+		return err
+	}
+
+	ctx = withActor(ctx, userID)
+
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		session, err := s.lockSession(ctx, id)
+		if err != nil {
+			return err
+		}
+		session.Quiz = authorized.Quiz
+
+		before := auditSession(session)
+		if err := change(session, time.Now()); err != nil {
+			return err
+		}
+
+		if err := s.sessionRepo.Update(ctx, session); err != nil {
+			return err
+		}
+
+		return s.audit.Record(ctx, action, models.AuditTargetSession, id.String(), before, auditSession(session))
+	})
+}
+
+// lockSession reloads the session and locks it until the transaction of ctx ends
+func (s *SessionServiceImpl) lockSession(ctx context.Context, id uuid.UUID) (*models.GameSession, error) {
+	session, err := s.sessionRepo.GetForUpdate(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if session == nil {
+		return nil, errors.NotFound("session")
+	}
+
+	return session, nil
+}
+
+func (s *SessionServiceImpl) JoinSession(ctx context.Context, joinCode string, userID int64) (*models.Participant, error) {
+	if err := s.policy.Require(ctx, userID, authz.SessionJoin); err != nil {
+		return nil, err
+	}
+
+	session, err := s.sessionRepo.GetByJoinCode(ctx, strings.ToUpper(strings.TrimSpace(joinCode)))
+	if err != nil {
+		return nil, err
+	}
+
+	if session == nil {
+		return nil, errors.NotFound("session")
+	}
+
+	if session.IsFinished() {
+		return nil, errors.Conflict("session is already finished")
+	}
+
+	participant, err := s.sessionRepo.GetParticipantByUser(ctx, session.ID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if participant != nil && participant.KickedAt != nil {
+		return nil, errors.Forbidden("you have been removed from this session")
+	}
+
+	if participant != nil {
+		return participant, nil
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user == nil {
+		return nil, errors.NotFound("user")
+	}
+
+	participant = &models.Participant{
+		SessionID: session.ID,
+		UserID:    &user.ID,
+		Login:     user.Login,
+	}
+
+	if _, err := s.sessionRepo.AddParticipant(ctx, participant); err != nil {
+		return nil, err
+	}
+
+	return participant, nil
+}
+
+// KickParticipant removes the participant from a session that isn't finished
+func (s *SessionServiceImpl) KickParticipant(ctx context.Context, sessionID, participantID uuid.UUID, userID int64) error {
+	session, err := s.policy.AuthorizeSession(ctx, userID, sessionID, rules.ActionHost)
+	if err != nil {
+		return err
+	}
+
+	participant := session.Participant(participantID)
+	if participant == nil {
+		return errors.NotFound("participant")
+	}
+
+	// The audit log keeps who was kicked, not their answers
+	kicked := *participant
+	kicked.Answers = nil
+
+	ctx = withActor(ctx, userID)
+
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		// The session may have ended since it was authorized
+		locked, err := s.lockSession(ctx, sessionID)
+		if err != nil {
+			return err
+		}
+
+		if locked.IsFinished() {
+			return errors.Conflict("session is already finished")
+		}
+
+		if err := s.sessionRepo.KickParticipant(ctx, participantID); err != nil {
+			return err
+		}
+
+		return s.audit.Record(ctx, models.AuditSessionKick, models.AuditTargetSession, sessionID.String(), &kicked, nil)
+	})
+}
+
+func (s *SessionServiceImpl) MonitorSession(ctx context.Context, id uuid.UUID, userID int64) (*models.SessionMonitor, error) {
+	session, err := s.policy.AuthorizeSession(ctx, userID, id, rules.ActionHost)
+	if err != nil {
+		return nil, err
+	}
+
+	return models.NewSessionMonitor(session, time.Now()), nil
+}
+
+// auditSession strips participants and the quiz from the session,
+// the audit log keeps only the session's own fields
+func auditSession(session *models.GameSession) *models.GameSession {
+	stripped := *session
+	stripped.Participants = nil
+	stripped.Quiz = nil
+
+	return &stripped
+}
+
+// authorizeFeedback returns the session with its quiz if the user may see
+// its feedback, and the participant of the user when it's a player. Those
+// who may view the session get a nil participant
+func (s *SessionServiceImpl) authorizeFeedback(ctx context.Context, sessionID uuid.UUID, userID int64) (*models.GameSession, *models.Participant, error) {
+	session, err := s.policy.AuthorizeSession(ctx, userID, sessionID, rules.ActionView)
+	if err == nil {
+		return session, nil, nil
+	}
+
+	if !errors.Is(err, rules.ErrForbidden) {
+		return nil, nil, err
+	}
+
+	return s.playerSession(ctx, sessionID, userID)
+}
+
+// playerSession returns the session with its quiz and the participant of
+// the user, users who don't take part in the session get errors.Forbidden
+func (s *SessionServiceImpl) playerSession(ctx context.Context, sessionID uuid.UUID, userID int64) (*models.GameSession, *models.Participant, error) {
+	session, err := s.GetSession(ctx, sessionID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if session.Quiz == nil {
+		return nil, nil, errors.NotFound("quiz")
+	}
+
+	participant := session.ParticipantByUser(userID)
+	if participant == nil {
+		return nil, nil, errors.Forbidden("you don't take part in this session")
+	}
+
+	return session, participant, nil
+}
+
+// GetQuestionFeedback returns the explanation and option feedback of a question
+// once it's closed, or once the session is finished in exam mode
+func (s *SessionServiceImpl) GetQuestionFeedback(ctx context.Context, sessionID, questionID uuid.UUID, userID int64) (*models.QuestionReview, error) {
+	session, participant, err := s.authorizeFeedback(ctx, sessionID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if session.Quiz == nil {
+		return nil, errors.NotFound("quiz")
+	}
+
+	for i, review := range models.NewSessionReview(session.Quiz, participant) {
+		if review.QuestionID != questionID {
+			continue
+		}
+
+		if !session.CanRevealFeedback(i) {
+			return nil, errors.Conflict("feedback is not available yet")
+		}
+
+		return &review, nil
+	}
+
+	return nil, errors.NotFound("question")
+}
+
+// GetSessionReview returns the post-session review for a participant:
+// every question with its explanation, option feedback and the participant's answer
+func (s *SessionServiceImpl) GetSessionReview(ctx context.Context, sessionID, participantID uuid.UUID, userID int64) ([]models.QuestionReview, error) {
+	session, player, err := s.authorizeFeedback(ctx, sessionID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if player != nil && player.ID != participantID {
+		return nil, errors.Forbidden("you can only review your own answers")
+	}
+
+	if !session.IsFinished() {
+		return nil, errors.Conflict("session is not finished yet")
+	}
+
+	if session.Quiz == nil {
+		return nil, errors.NotFound("quiz")
+	}
+
+	participant := session.Participant(participantID)
+	if participant == nil {
+		return nil, errors.NotFound("participant")
+	}
+
+	return models.NewSessionReview(session.Quiz, participant), nil
+}
+
+func (s *SessionServiceImpl) GetPlayerFeedback(ctx context.Context, sessionID uuid.UUID, userID int64) (*models.PlayerFeedback, error) {
+	session, participant, err := s.playerSession(ctx, sessionID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return models.NewPlayerFeedback(session, participant), nil
+}
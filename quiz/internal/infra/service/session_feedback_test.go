@@ -0,0 +1,200 @@
+package service
+
+import (
+	"bsu-quiz/quiz/internal/domain/errors"
+	"bsu-quiz/quiz/internal/domain/models"
+	"bsu-quiz/quiz/internal/domain/rules"
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// Users of the feedback tests
+const (
+	feedbackHost int64 = iota + 1
+	feedbackAdmin
+	feedbackViewer
+	feedbackPlayer
+	feedbackOtherPlayer
+	feedbackOutsider
+)
+
+// feedbackFixture is a session with two players of a quiz with two
+// questions, the first one is closed
+type feedbackFixture struct {
+	service *SessionServiceImpl
+	session *models.GameSession
+	quiz    *models.Quiz
+	// player and otherPlayer are the participants of the players
+	player      uuid.UUID
+	otherPlayer uuid.UUID
+}
+
+func newFeedbackFixture(status int) *feedbackFixture {
+	quiz := &models.Quiz{ID: uuid.New(), UserID: feedbackHost}
+	for range 2 {
+		quiz.Questions = append(quiz.Questions, models.Question{
+			ID:          uuid.New(),
+			Explanation: "because",
+			Options:     []models.Option{{ID: uuid.New(), IsCorrect: true}},
+		})
+	}
+
+	playerID, otherPlayerID := feedbackPlayer, feedbackOtherPlayer
+	session := &models.GameSession{
+		ID:                   uuid.New(),
+		QuizID:               quiz.ID,
+		HostID:               feedbackHost,
+		StatusFlags:          status,
+		CurrentQuestionIndex: 1,
+		Participants: []models.Participant{
+			{
+				ID:      uuid.New(),
+				UserID:  &playerID,
+				Answers: []models.Answer{{QuestionID: quiz.Questions[0].ID, IsCorrect: true, PointsAwarded: 100}},
+			},
+			{ID: uuid.New(), UserID: &otherPlayerID},
+		},
+	}
+
+	users := &fakeUserRepo{users: map[int64]*models.User{
+		feedbackHost:        {ID: feedbackHost, RoleFlags: models.RoleUser | models.RoleTeacher},
+		feedbackAdmin:       {ID: feedbackAdmin, RoleFlags: models.RoleUser | models.RoleAdmin},
+		feedbackViewer:      {ID: feedbackViewer, RoleFlags: models.RoleUser | models.RoleTeacher},
+		feedbackPlayer:      {ID: feedbackPlayer, RoleFlags: models.RoleUser},
+		feedbackOtherPlayer: {ID: feedbackOtherPlayer, RoleFlags: models.RoleUser},
+		feedbackOutsider:    {ID: feedbackOutsider, RoleFlags: models.RoleUser},
+	}}
+	quizzes := &fakeQuizRepo{quizzes: map[uuid.UUID]*models.Quiz{quiz.ID: quiz}}
+	sessions := &fakeSessionRepo{sessions: map[uuid.UUID]*models.GameSession{session.ID: session}}
+	collaborators := &fakeCollaboratorRepo{roles: map[collaboratorKey]models.QuizRole{
+		{quiz.ID, feedbackViewer}: models.QuizRoleViewer,
+	}}
+
+	policy := rules.NewPolicy(quizzes, users, collaborators, sessions)
+
+	return &feedbackFixture{
+		service:     NewSessionService(sessions, quizzes, users, policy, nil, nil),
+		session:     session,
+		quiz:        quiz,
+		player:      session.Participants[0].ID,
+		otherPlayer: session.Participants[1].ID,
+	}
+}
+
+func TestGetSessionReviewAuthorization(t *testing.T) {
+	tests := []struct {
+		name   string
+		userID int64
+		// forbidden is true if the user may not see the review of the player
+		forbidden bool
+	}{
+		{name: "host", userID: feedbackHost},
+		{name: "admin", userID: feedbackAdmin},
+		{name: "viewer of the quiz", userID: feedbackViewer},
+		{name: "the player", userID: feedbackPlayer},
+		{name: "another player", userID: feedbackOtherPlayer, forbidden: true},
+		{name: "outsider", userID: feedbackOutsider, forbidden: true},
+		{name: "unknown user", userID: 100, forbidden: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFeedbackFixture(models.GameStatusFinished)
+
+			reviews, err := f.service.GetSessionReview(context.Background(), f.session.ID, f.player, tt.userID)
+
+			if tt.forbidden {
+				if !errors.Is(err, errors.ErrForbidden) {
+					t.Fatalf("got error %v, want forbidden", err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(reviews) != 2 || !reviews[0].IsCorrect || reviews[0].PointsAwarded != 100 {
+				t.Errorf("review doesn't carry the player's answers: %+v", reviews)
+			}
+		})
+	}
+}
+
+func TestGetSessionReviewBeforeTheEnd(t *testing.T) {
+	f := newFeedbackFixture(models.GameStatusActive)
+
+	_, err := f.service.GetSessionReview(context.Background(), f.session.ID, f.player, feedbackPlayer)
+	if !errors.Is(err, errors.ErrConflict) {
+		t.Fatalf("got error %v, want a conflict", err)
+	}
+}
+
+func TestGetQuestionFeedbackAuthorization(t *testing.T) {
+	tests := []struct {
+		name      string
+		userID    int64
+		forbidden bool
+		// answered is true if the feedback carries the user's own answer
+		answered bool
+	}{
+		{name: "host", userID: feedbackHost},
+		{name: "viewer of the quiz", userID: feedbackViewer},
+		{name: "player", userID: feedbackPlayer, answered: true},
+		{name: "player who didn't answer", userID: feedbackOtherPlayer},
+		{name: "outsider", userID: feedbackOutsider, forbidden: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFeedbackFixture(models.GameStatusActive)
+
+			feedback, err := f.service.GetQuestionFeedback(context.Background(), f.session.ID, f.quiz.Questions[0].ID, tt.userID)
+
+			if tt.forbidden {
+				if !errors.Is(err, errors.ErrForbidden) {
+					t.Fatalf("got error %v, want forbidden", err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if feedback.Explanation != "because" {
+				t.Errorf("got explanation %q", feedback.Explanation)
+			}
+			if feedback.IsCorrect != tt.answered {
+				t.Errorf("got is_correct %v, want %v", feedback.IsCorrect, tt.answered)
+			}
+		})
+	}
+}
+
+func TestGetQuestionFeedbackOfOpenQuestion(t *testing.T) {
+	f := newFeedbackFixture(models.GameStatusActive)
+
+	_, err := f.service.GetQuestionFeedback(context.Background(), f.session.ID, f.quiz.Questions[1].ID, feedbackPlayer)
+	if !errors.Is(err, errors.ErrConflict) {
+		t.Fatalf("got error %v, want a conflict", err)
+	}
+}
+
+func TestGetPlayerFeedback(t *testing.T) {
+	f := newFeedbackFixture(models.GameStatusActive)
+
+	feedback, err := f.service.GetPlayerFeedback(context.Background(), f.session.ID, feedbackPlayer)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if feedback.Finished || len(feedback.Questions) != 1 || !feedback.Questions[0].IsCorrect {
+		t.Errorf("got %+v, want the closed question with the player's answer", feedback)
+	}
+
+	// Hosts watch the session, they don't play it
+	_, err = f.service.GetPlayerFeedback(context.Background(), f.session.ID, feedbackHost)
+	if !errors.Is(err, errors.ErrForbidden) {
+		t.Fatalf("got error %v for the host, want forbidden", err)
+	}
+}
@@ -0,0 +1,183 @@
+package service
+
+import (
+	"bsu-quiz/quiz/internal/domain/models"
+	"bsu-quiz/quiz/internal/infra/health"
+	"bsu-quiz/quiz/internal/ports"
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	recentItemsLimit   = 5
+	healthCheckTimeout = 2 * time.Second
+)
+
+type StatsProvider interface {
+	GetDashboardStats(ctx context.Context) (*models.DashboardStats, error)
+}
+
+// StatsServiceImpl computes the admin dashboard statistics. The result is
+// cached for a short time, so refreshing the dashboard doesn't query
+// Postgres every time
+type StatsServiceImpl struct {
+	userRepo    ports.UserRepositorier
+	quizRepo    ports.QuizRepositorier
+	sessionRepo ports.SessionRepositorier
+	statsRepo   ports.StatsRepositorier
+	checkers    []health.Checker
+	days        int
+	cacheTTL    time.Duration
+
+	mu        sync.Mutex
+	cached    *models.DashboardStats
+	expiresAt time.Time
+}
+
+func NewStatsService(
+	userRepo ports.UserRepositorier,
+	quizRepo ports.QuizRepositorier,
+	sessionRepo ports.SessionRepositorier,
+	statsRepo ports.StatsRepositorier,
+	days int,
+	cacheTTL time.Duration,
+	checkers ...health.Checker,
+) *StatsServiceImpl {
+	return &StatsServiceImpl{
+		userRepo:    userRepo,
+		quizRepo:    quizRepo,
+		sessionRepo: sessionRepo,
+		statsRepo:   statsRepo,
+		checkers:    checkers,
+		days:        days,
+		cacheTTL:    cacheTTL,
+	}
+}
+
+// GetDashboardStats returns the cached statistics or computes them again.
+// Only healthy results are cached, the dashboard shows the numbers again
+// as soon as a failed dependency is back
+func (s *StatsServiceImpl) GetDashboardStats(ctx context.Context) (*models.DashboardStats, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now().UTC()
+
+	cached := s.cached
+	if cached == nil || now.After(s.expiresAt) {
+		stats, err := s.compute(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		cached = stats
+		if stats.SystemStatus == models.SystemStatusHealthy {
+			s.cached = stats
+			s.expiresAt = now.Add(s.cacheTTL)
+		}
+	}
+
+	// Callers get a copy, so the cached value is never modified
+	stats := *cached
+	stats.CurrentServerTime = now
+
+	return &stats, nil
+}
+
+func (s *StatsServiceImpl) compute(ctx context.Context) (*models.DashboardStats, error) {
+	stats := &models.DashboardStats{
+		GeneratedAt: time.Now().UTC(),
+	}
+
+	stats.SystemStatus, stats.Components = s.checkHealth(ctx)
+
+	// Without the database there is nothing else to show, the numbers
+	// don't depend on the other components
+	if !componentHealthy(stats.Components, health.PostgresName) {
+		return stats, nil
+	}
+
+	var err error
+	if stats.TotalUsers, err = s.userRepo.Count(ctx); err != nil {
+		return nil, err
+	}
+
+	if stats.TotalQuizzes, err = s.quizRepo.Count(ctx); err != nil {
+		return nil, err
+	}
+
+	if stats.TotalSessions, err = s.sessionRepo.Count(ctx, models.SessionFilter{}); err != nil {
+		return nil, err
+	}
+
+	active := models.SessionFilter{StatusFlags: models.GameStatusActive | models.GameStatusPaused}
+	if stats.ActiveSessions, err = s.sessionRepo.Count(ctx, active); err != nil {
+		return nil, err
+	}
+
+	if stats.RecentUsers, err = s.userRepo.ListRecent(ctx, recentItemsLimit); err != nil {
+		return nil, err
+	}
+
+	if stats.RecentQuizzes, err = s.quizRepo.ListAll(ctx, models.ListOptions{Limit: recentItemsLimit, Sort: models.DefaultQuizSort}); err != nil {
+		return nil, err
+	}
+
+	if stats.UsersByRole, err = s.statsRepo.CountUsersByRole(ctx); err != nil {
+		return nil, err
+	}
+
+	since := time.Now().AddDate(0, 0, -(s.days - 1))
+
+	if stats.SessionsPerDay, err = s.statsRepo.SessionsPerDay(ctx, since); err != nil {
+		return nil, err
+	}
+
+	if stats.AnswersPerDay, err = s.statsRepo.AnswersPerDay(ctx, since); err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+// checkHealth runs the health checks. The system is in error if a critical
+// dependency is down and in warning if any other one is
+func (s *StatsServiceImpl) checkHealth(ctx context.Context) (string, []models.ComponentStatus) {
+	status := models.SystemStatusHealthy
+	results := health.Run(ctx, healthCheckTimeout, s.checkers...)
+	components := make([]models.ComponentStatus, 0, len(results))
+
+	for _, result := range results {
+		components = append(components, models.ComponentStatus{
+			Name:      result.Name,
+			Healthy:   result.Healthy,
+			Error:     result.Error,
+			LatencyMS: result.Latency.Milliseconds(),
+		})
+
+		if result.Healthy {
+			continue
+		}
+
+		if result.Critical {
+			status = models.SystemStatusError
+		} else if status == models.SystemStatusHealthy {
+			status = models.SystemStatusWarning
+		}
+	}
+
+	return status, components
+}
+
+// componentHealthy checks the component by name, components that aren't
+// checked count as healthy
+func componentHealthy(components []models.ComponentStatus, name string) bool {
+	for _, component := range components {
+		if component.Name == name {
+			return component.Healthy
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"bsu-quiz/quiz/internal/domain/errors"
+	"bsu-quiz/quiz/internal/domain/models"
+	"bsu-quiz/quiz/internal/infra/health"
+	"bsu-quiz/quiz/internal/ports"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type fakeChecker struct {
+	name string
+	down bool
+}
+
+func (c *fakeChecker) Name() string {
+	return c.name
+}
+
+func (c *fakeChecker) Critical() bool {
+	return true
+}
+
+func (c *fakeChecker) Check(_ context.Context) error {
+	if c.down {
+		return errors.New("connection refused")
+	}
+	return nil
+}
+
+// fakeStatsRepo counts the computations of the statistics
+type fakeStatsRepo struct {
+	ports.StatsRepositorier
+	computed int
+}
+
+func (r *fakeStatsRepo) CountUsersByRole(_ context.Context) (*models.UsersByRole, error) {
+	r.computed++
+	return &models.UsersByRole{}, nil
+}
+
+func (r *fakeStatsRepo) SessionsPerDay(_ context.Context, _ time.Time) ([]models.DailyCount, error) {
+	return nil, nil
+}
+
+func (r *fakeStatsRepo) AnswersPerDay(_ context.Context, _ time.Time) ([]models.DailyCount, error) {
+	return nil, nil
+}
+
+type statsFixture struct {
+	service  *StatsServiceImpl
+	stats    *fakeStatsRepo
+	postgres *fakeChecker
+	redis    *fakeChecker
+}
+
+func newStatsFixture() *statsFixture {
+	users := &fakeUserRepo{users: map[int64]*models.User{1: {ID: 1}, 2: {ID: 2}}}
+	quizzes := &fakeQuizRepo{quizzes: map[uuid.UUID]*models.Quiz{uuid.New(): {}}}
+	sessions := &fakeSessionRepo{}
+
+	f := &statsFixture{
+		stats:    &fakeStatsRepo{},
+		postgres: &fakeChecker{name: health.PostgresName},
+		redis:    &fakeChecker{name: "redis"},
+	}
+	f.service = NewStatsService(users, quizzes, sessions, f.stats, 7, time.Minute, f.postgres, f.redis)
+
+	return f
+}
+
+func TestGetDashboardStatsDegraded(t *testing.T) {
+	tests := []struct {
+		name         string
+		postgresDown bool
+		redisDown    bool
+		wantStatus   string
+		// wantCounts is true if the numbers from Postgres are shown
+		wantCounts bool
+	}{
+		{name: "healthy", wantStatus: models.SystemStatusHealthy, wantCounts: true},
+		{name: "redis down", redisDown: true, wantStatus: models.SystemStatusError, wantCounts: true},
+		{name: "postgres down", postgresDown: true, wantStatus: models.SystemStatusError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newStatsFixture()
+			f.postgres.down = tt.postgresDown
+			f.redis.down = tt.redisDown
+
+			stats, err := f.service.GetDashboardStats(context.Background())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if stats.SystemStatus != tt.wantStatus {
+				t.Errorf("got status %s, want %s", stats.SystemStatus, tt.wantStatus)
+			}
+			if len(stats.Components) != 2 {
+				t.Errorf("got %d components, want 2", len(stats.Components))
+			}
+
+			// Counts come from Postgres, a Redis outage doesn't hide them
+			if tt.wantCounts && (stats.TotalUsers != 2 || stats.TotalQuizzes != 1 || stats.UsersByRole == nil) {
+				t.Errorf("got stats %+v, want the counts", stats)
+			}
+			if !tt.wantCounts && f.stats.computed != 0 {
+				t.Errorf("the database is queried while it's down")
+			}
+		})
+	}
+}
+
+func TestGetDashboardStatsCache(t *testing.T) {
+	f := newStatsFixture()
+	ctx := context.Background()
+
+	f.redis.down = true
+	for range 2 {
+		if _, err := f.service.GetDashboardStats(ctx); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if f.stats.computed != 2 {
+		t.Errorf("degraded stats were computed %d times for 2 requests, want them not cached", f.stats.computed)
+	}
+
+	// As soon as Redis is back the result is healthy and cached
+	f.redis.down = false
+	for range 2 {
+		stats, err := f.service.GetDashboardStats(ctx)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if stats.SystemStatus != models.SystemStatusHealthy {
+			t.Errorf("got status %s after the recovery", stats.SystemStatus)
+		}
+	}
+	if f.stats.computed != 3 {
+		t.Errorf("healthy stats were computed %d times, want once", f.stats.computed-2)
+	}
+}
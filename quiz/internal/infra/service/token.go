@@ -0,0 +1,200 @@
+package service
+
+import (
+	"bsu-quiz/quiz/internal/domain/errors"
+	"bsu-quiz/quiz/internal/domain/models"
+	"bsu-quiz/quiz/internal/ports"
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+type TokenProvider interface {
+	// Login checks the password like the panel does and issues a token pair
+	Login(ctx context.Context, login, password string) (*models.TokenPair, error)
+	// Refresh rotates the refresh token: it's used up and a new pair is issued
+	Refresh(ctx context.Context, refreshToken string) (*models.TokenPair, error)
+	// Logout revokes the refresh token and the access token of the claims
+	Logout(ctx context.Context, refreshToken string, claims *models.AccessClaims) error
+	// Verify checks the signature, expiry and revocation of an access token.
+	// Login and roles are those the user has now, not those of the token
+	Verify(ctx context.Context, accessToken string) (*models.AccessClaims, error)
+}
+
+type TokenServiceImpl struct {
+	auth       AuthProvider
+	userRepo   ports.UserRepositorier
+	tokenRepo  ports.TokenRepositorier
+	secret     []byte
+	issuer     string
+	accessTTL  time.Duration
+	refreshTTL time.Duration
+}
+
+func NewTokenService(
+	auth AuthProvider,
+	userRepo ports.UserRepositorier,
+	tokenRepo ports.TokenRepositorier,
+	secret string,
+	issuer string,
+	accessTTL time.Duration,
+	refreshTTL time.Duration,
+) *TokenServiceImpl {
+	return &TokenServiceImpl{
+		auth:       auth,
+		userRepo:   userRepo,
+		tokenRepo:  tokenRepo,
+		secret:     []byte(secret),
+		issuer:     issuer,
+		accessTTL:  accessTTL,
+		refreshTTL: refreshTTL,
+	}
+}
+
+// accessClaims are the claims of access tokens, roles are the names of
+// the role flags of the user
+type accessClaims struct {
+	jwt.RegisteredClaims
+	Login string   `json:"login"`
+	Roles []string `json:"roles"`
+}
+
+func (s *TokenServiceImpl) Login(ctx context.Context, login, password string) (*models.TokenPair, error) {
+	user, err := s.auth.Login(ctx, login, password)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.issue(ctx, user)
+}
+
+func (s *TokenServiceImpl) Refresh(ctx context.Context, refreshToken string) (*models.TokenPair, error) {
+	refresh, err := s.tokenRepo.TakeRefreshToken(ctx, refreshToken)
+	if err != nil {
+		return nil, err
+	}
+
+	if refresh == nil {
+		return nil, errors.Unauthorized("refresh token is invalid or expired")
+	}
+
+	// Roles go into the new access token, so they're read again
+	user, err := s.userRepo.GetByID(ctx, refresh.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user == nil || user.IsBlocked() {
+		return nil, errors.Unauthorized("your account is no longer available")
+	}
+
+	return s.issue(ctx, user)
+}
+
+func (s *TokenServiceImpl) Logout(ctx context.Context, refreshToken string, claims *models.AccessClaims) error {
+	if refreshToken != "" {
+		if _, err := s.tokenRepo.TakeRefreshToken(ctx, refreshToken); err != nil {
+			return err
+		}
+	}
+
+	if claims != nil {
+		return s.tokenRepo.RevokeAccessToken(ctx, claims.TokenID, claims.ExpiresAt)
+	}
+
+	return nil
+}
+
+func (s *TokenServiceImpl) Verify(ctx context.Context, accessToken string) (*models.AccessClaims, error) {
+	var claims accessClaims
+	_, err := jwt.ParseWithClaims(accessToken, &claims, func(*jwt.Token) (any, error) {
+		return s.secret, nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithIssuer(s.issuer),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return nil, errors.Unauthorized("access token is invalid or expired")
+	}
+
+	userID, err := strconv.ParseInt(claims.Subject, 10, 64)
+	if err != nil || claims.ID == "" {
+		return nil, errors.Unauthorized("access token is invalid or expired")
+	}
+
+	revoked, err := s.tokenRepo.IsAccessTokenRevoked(ctx, claims.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if revoked {
+		return nil, errors.Unauthorized("access token has been revoked")
+	}
+
+	// Roles are read on every request like Authenticate does for the panel,
+	// so role changes and blocks apply before the token expires
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user == nil || user.IsBlocked() {
+		return nil, errors.Unauthorized("your account is no longer available")
+	}
+
+	return &models.AccessClaims{
+		TokenID:   claims.ID,
+		UserID:    userID,
+		Login:     user.Login,
+		RoleFlags: user.RoleFlags,
+		ExpiresAt: claims.ExpiresAt.Time,
+	}, nil
+}
+
+// issue signs an access token for the user and stores a new refresh token
+func (s *TokenServiceImpl) issue(ctx context.Context, user *models.User) (*models.TokenPair, error) {
+	now := time.Now()
+	pair := &models.TokenPair{
+		AccessExpiresAt:  now.Add(s.accessTTL),
+		RefreshExpiresAt: now.Add(s.refreshTTL),
+	}
+
+	access := jwt.NewWithClaims(jwt.SigningMethodHS256, accessClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			Issuer:    s.issuer,
+			Subject:   strconv.FormatInt(user.ID, 10),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(pair.AccessExpiresAt),
+		},
+		Login: user.Login,
+		Roles: models.RoleNames(user.RoleFlags),
+	})
+
+	var err error
+	pair.AccessToken, err = access.SignedString(s.secret)
+	if err != nil {
+		return nil, err
+	}
+
+	pair.RefreshToken, err = newToken()
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.tokenRepo.SaveRefreshToken(ctx, &models.RefreshToken{
+		Token:     pair.RefreshToken,
+		UserID:    user.ID,
+		CreatedAt: now,
+		ExpiresAt: pair.RefreshExpiresAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return pair, nil
+}
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"bsu-quiz/quiz/internal/domain/errors"
+	"bsu-quiz/quiz/internal/domain/models"
+	"context"
+	"testing"
+	"time"
+)
+
+func newTokenFixture(t *testing.T) (*TokenServiceImpl, *authFixture) {
+	t.Helper()
+
+	f := newAuthFixture(t)
+	return NewTokenService(f.service, f.users, f.tokens, "secret", "bsu-quiz", time.Minute, time.Hour), f
+}
+
+func TestVerify(t *testing.T) {
+	tests := []struct {
+		name string
+		// change is made to the student after the token was issued
+		change func(user *models.User)
+		// want is the kind of the expected error, nil if the token works
+		want      error
+		wantRoles int
+	}{
+		{name: "unchanged", wantRoles: models.RoleUser},
+		{
+			name:      "promoted",
+			change:    func(user *models.User) { user.RoleFlags |= models.RoleTeacher },
+			wantRoles: models.RoleUser | models.RoleTeacher,
+		},
+		{
+			name:   "blocked",
+			change: func(user *models.User) { user.RoleFlags |= models.RoleBlocked },
+			want:   errors.ErrUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokens, f := newTokenFixture(t)
+			ctx := context.Background()
+
+			pair, err := tokens.Login(ctx, "student", authPassword)
+			if err != nil {
+				t.Fatalf("failed to sign in: %v", err)
+			}
+			if tt.change != nil {
+				tt.change(f.users.users[authStudent])
+			}
+
+			claims, err := tokens.Verify(ctx, pair.AccessToken)
+
+			if tt.want != nil {
+				if !errors.Is(err, tt.want) {
+					t.Fatalf("got error %v, want %v", err, tt.want)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if claims.UserID != authStudent || claims.RoleFlags != tt.wantRoles {
+				t.Errorf("got user %d with roles %b, want %d with %b", claims.UserID, claims.RoleFlags, authStudent, tt.wantRoles)
+			}
+		})
+	}
+}
+
+func TestVerifyDeletedUser(t *testing.T) {
+	tokens, f := newTokenFixture(t)
+	ctx := context.Background()
+
+	pair, _ := tokens.Login(ctx, "student", authPassword)
+	delete(f.users.users, authStudent)
+
+	if _, err := tokens.Verify(ctx, pair.AccessToken); !errors.Is(err, errors.ErrUnauthorized) {
+		t.Fatalf("got error %v, want unauthorized", err)
+	}
+}
+
+func TestVerifyRevoked(t *testing.T) {
+	tokens, _ := newTokenFixture(t)
+	ctx := context.Background()
+
+	pair, _ := tokens.Login(ctx, "student", authPassword)
+	claims, err := tokens.Verify(ctx, pair.AccessToken)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := tokens.Logout(ctx, pair.RefreshToken, claims); err != nil {
+		t.Fatalf("failed to sign out: %v", err)
+	}
+
+	if _, err := tokens.Verify(ctx, pair.AccessToken); !errors.Is(err, errors.ErrUnauthorized) {
+		t.Errorf("got error %v for a revoked access token, want unauthorized", err)
+	}
+	if _, err := tokens.Refresh(ctx, pair.RefreshToken); !errors.Is(err, errors.ErrUnauthorized) {
+		t.Errorf("got error %v for a revoked refresh token, want unauthorized", err)
+	}
+}
+
+func TestVerifyForeignToken(t *testing.T) {
+	tokens, f := newTokenFixture(t)
+	ctx := context.Background()
+
+	// Signed with another secret
+	other := NewTokenService(f.service, f.users, f.tokens, "other", "bsu-quiz", time.Minute, time.Hour)
+	pair, _ := other.Login(ctx, "student", authPassword)
+
+	for _, token := range []string{pair.AccessToken, "", "not.a.token"} {
+		if _, err := tokens.Verify(ctx, token); !errors.Is(err, errors.ErrUnauthorized) {
+			t.Errorf("got error %v for %q, want unauthorized", err, token)
+		}
+	}
+}
+
+func TestRefresh(t *testing.T) {
+	tokens, f := newTokenFixture(t)
+	ctx := context.Background()
+
+	pair, _ := tokens.Login(ctx, "student", authPassword)
+
+	next, err := tokens.Refresh(ctx, pair.RefreshToken)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if next.RefreshToken == pair.RefreshToken {
+		t.Errorf("the refresh token wasn't rotated")
+	}
+
+	// The refresh token works once
+	if _, err := tokens.Refresh(ctx, pair.RefreshToken); !errors.Is(err, errors.ErrUnauthorized) {
+		t.Errorf("got error %v for a used refresh token, want unauthorized", err)
+	}
+
+	f.users.users[authStudent].RoleFlags |= models.RoleBlocked
+	if _, err := tokens.Refresh(ctx, next.RefreshToken); !errors.Is(err, errors.ErrUnauthorized) {
+		t.Errorf("got error %v for a blocked user, want unauthorized", err)
+	}
+}
@@ -0,0 +1,140 @@
+package service
+
+import (
+	"bsu-quiz/pkg/authz"
+	"bsu-quiz/quiz/internal/domain/errors"
+	"bsu-quiz/quiz/internal/domain/models"
+	"bsu-quiz/quiz/internal/domain/rules"
+	"bsu-quiz/quiz/internal/ports"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type TrashProvider interface {
+	ListDeletedQuizzes(ctx context.Context, userID int64, offset, limit int) ([]*models.Quiz, error)
+	ListDeletedSessions(ctx context.Context, userID int64, offset, limit int) ([]*models.GameSession, error)
+	RestoreQuiz(ctx context.Context, id uuid.UUID, userID int64) error
+	RestoreSession(ctx context.Context, id uuid.UUID, userID int64) error
+
+	// Purge removes items deleted longer than the retention period ago
+	Purge(ctx context.Context) (quizzes, sessions int64, err error)
+	Retention() time.Duration
+}
+
+type TrashServiceImpl struct {
+	quizRepo    ports.QuizRepositorier
+	sessionRepo ports.SessionRepositorier
+	userRepo    ports.UserRepositorier
+	policy      *rules.Policy
+	retention   time.Duration
+}
+
+func NewTrashService(
+	quizRepo ports.QuizRepositorier,
+	sessionRepo ports.SessionRepositorier,
+	userRepo ports.UserRepositorier,
+	policy *rules.Policy,
+	retention time.Duration,
+) *TrashServiceImpl {
+	return &TrashServiceImpl{
+		quizRepo:    quizRepo,
+		sessionRepo: sessionRepo,
+		userRepo:    userRepo,
+		policy:      policy,
+		retention:   retention,
+	}
+}
+
+func (s *TrashServiceImpl) Retention() time.Duration {
+	return s.retention
+}
+
+// ListDeletedQuizzes returns quizzes the user deleted, admins see the whole trash
+func (s *TrashServiceImpl) ListDeletedQuizzes(ctx context.Context, userID int64, offset, limit int) ([]*models.Quiz, error) {
+	isAdmin, err := s.isAdmin(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if isAdmin {
+		return s.quizRepo.ListAllDeleted(ctx, offset, limit)
+	}
+
+	return s.quizRepo.ListDeleted(ctx, userID, offset, limit)
+}
+
+// ListDeletedSessions returns sessions the user hosted, admins see the whole trash
+func (s *TrashServiceImpl) ListDeletedSessions(ctx context.Context, userID int64, offset, limit int) ([]*models.GameSession, error) {
+	isAdmin, err := s.isAdmin(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if isAdmin {
+		return s.sessionRepo.ListAllDeleted(ctx, offset, limit)
+	}
+
+	return s.sessionRepo.ListDeleted(ctx, userID, offset, limit)
+}
+
+func (s *TrashServiceImpl) RestoreQuiz(ctx context.Context, id uuid.UUID, userID int64) error {
+	quiz, err := s.policy.AuthorizeDeletedQuiz(ctx, userID, id, rules.ActionManage)
+	if err != nil {
+		return err
+	}
+
+	if s.expired(quiz.DeletedAt) {
+		return errors.Conflict("quiz can't be restored, the retention period is over")
+	}
+
+	return s.quizRepo.Restore(ctx, id)
+}
+
+func (s *TrashServiceImpl) RestoreSession(ctx context.Context, id uuid.UUID, userID int64) error {
+	session, err := s.policy.AuthorizeDeletedSession(ctx, userID, id, rules.ActionManage)
+	if err != nil {
+		return err
+	}
+
+	if s.expired(session.DeletedAt) {
+		return errors.Conflict("session can't be restored, the retention period is over")
+	}
+
+	return s.sessionRepo.Restore(ctx, id)
+}
+
+func (s *TrashServiceImpl) Purge(ctx context.Context) (int64, int64, error) {
+	before := time.Now().Add(-s.retention)
+
+	// Sessions of purged quizzes are removed by the cascade
+	quizzes, err := s.quizRepo.PurgeDeleted(ctx, before)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	sessions, err := s.sessionRepo.PurgeDeleted(ctx, before)
+	if err != nil {
+		return quizzes, 0, err
+	}
+
+	return quizzes, sessions, nil
+}
+
+func (s *TrashServiceImpl) expired(deletedAt *time.Time) bool {
+	return deletedAt != nil && time.Since(*deletedAt) > s.retention
+}
+
+func (s *TrashServiceImpl) isAdmin(ctx context.Context, userID int64) (bool, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+
+	if user == nil {
+		return false, errors.NotFound("user")
+	}
+
+	return user.Can(authz.QuizModerate), nil
+}
@@ -0,0 +1,247 @@
+package service
+
+import (
+	"bsu-quiz/quiz/internal/domain/errors"
+	"bsu-quiz/quiz/internal/domain/models"
+	"bsu-quiz/quiz/internal/domain/rules"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Users of the trash tests
+const (
+	trashOwner int64 = iota + 1
+	trashAdmin
+	trashStranger
+)
+
+const trashRetention = 30 * 24 * time.Hour
+
+// trashQuizRepo keeps the deleted quizzes, restoring moves them back
+type trashQuizRepo struct {
+	*fakeQuizRepo
+	deleted map[uuid.UUID]*models.Quiz
+	// listed tells which listing was used, "user" or "all"
+	listed string
+	purged time.Time
+}
+
+func (r *trashQuizRepo) GetDeleted(_ context.Context, id uuid.UUID) (*models.Quiz, error) {
+	quiz, ok := r.deleted[id]
+	if !ok {
+		return nil, nil
+	}
+
+	copied := *quiz
+	return &copied, nil
+}
+
+func (r *trashQuizRepo) Restore(_ context.Context, id uuid.UUID) error {
+	r.quizzes[id] = r.deleted[id]
+	delete(r.deleted, id)
+	return nil
+}
+
+func (r *trashQuizRepo) ListDeleted(_ context.Context, _ int64, _, _ int) ([]*models.Quiz, error) {
+	r.listed = "user"
+	return nil, nil
+}
+
+func (r *trashQuizRepo) ListAllDeleted(_ context.Context, _, _ int) ([]*models.Quiz, error) {
+	r.listed = "all"
+	return nil, nil
+}
+
+func (r *trashQuizRepo) PurgeDeleted(_ context.Context, before time.Time) (int64, error) {
+	r.purged = before
+	return 1, nil
+}
+
+// trashSessionRepo keeps the deleted sessions, restoring marks them
+type trashSessionRepo struct {
+	*fakeSessionRepo
+	deleted  map[uuid.UUID]*models.GameSession
+	restored []uuid.UUID
+	purged   time.Time
+}
+
+func (r *trashSessionRepo) GetDeleted(_ context.Context, id uuid.UUID) (*models.GameSession, error) {
+	session, ok := r.deleted[id]
+	if !ok {
+		return nil, nil
+	}
+
+	copied := *session
+	return &copied, nil
+}
+
+func (r *trashSessionRepo) Restore(_ context.Context, id uuid.UUID) error {
+	r.restored = append(r.restored, id)
+	return nil
+}
+
+func (r *trashSessionRepo) PurgeDeleted(_ context.Context, before time.Time) (int64, error) {
+	r.purged = before
+	return 2, nil
+}
+
+type trashFixture struct {
+	service  *TrashServiceImpl
+	quizzes  *trashQuizRepo
+	sessions *trashSessionRepo
+}
+
+func newTrashFixture() *trashFixture {
+	users := &fakeUserRepo{users: map[int64]*models.User{
+		trashOwner:    {ID: trashOwner, RoleFlags: models.RoleUser | models.RoleTeacher},
+		trashAdmin:    {ID: trashAdmin, RoleFlags: models.RoleUser | models.RoleAdmin},
+		trashStranger: {ID: trashStranger, RoleFlags: models.RoleUser | models.RoleTeacher},
+	}}
+	quizzes := &trashQuizRepo{
+		fakeQuizRepo: &fakeQuizRepo{quizzes: make(map[uuid.UUID]*models.Quiz)},
+		deleted:      make(map[uuid.UUID]*models.Quiz),
+	}
+	sessions := &trashSessionRepo{
+		fakeSessionRepo: &fakeSessionRepo{},
+		deleted:         make(map[uuid.UUID]*models.GameSession),
+	}
+	policy := rules.NewPolicy(quizzes, users, &fakeCollaboratorRepo{}, sessions)
+
+	return &trashFixture{
+		service:  NewTrashService(quizzes, sessions, users, policy, trashRetention),
+		quizzes:  quizzes,
+		sessions: sessions,
+	}
+}
+
+// deleteQuiz puts a quiz of the owner deleted age ago into the trash
+func (f *trashFixture) deleteQuiz(age time.Duration) uuid.UUID {
+	deletedAt := time.Now().Add(-age)
+	quiz := &models.Quiz{ID: uuid.New(), UserID: trashOwner, DeletedAt: &deletedAt}
+	f.quizzes.deleted[quiz.ID] = quiz
+
+	return quiz.ID
+}
+
+func TestRestoreQuiz(t *testing.T) {
+	tests := []struct {
+		name   string
+		userID int64
+		age    time.Duration
+		// want is the kind of the expected error, nil if the quiz is restored
+		want error
+	}{
+		{name: "owner", userID: trashOwner, age: time.Hour},
+		{name: "admin", userID: trashAdmin, age: time.Hour},
+		{name: "stranger", userID: trashStranger, age: time.Hour, want: errors.ErrForbidden},
+		{name: "retention is over", userID: trashOwner, age: trashRetention + time.Hour, want: errors.ErrConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newTrashFixture()
+			id := f.deleteQuiz(tt.age)
+
+			err := f.service.RestoreQuiz(context.Background(), id, tt.userID)
+
+			if tt.want != nil {
+				if !errors.Is(err, tt.want) {
+					t.Fatalf("got error %v, want %v", err, tt.want)
+				}
+				if _, ok := f.quizzes.deleted[id]; !ok {
+					t.Errorf("the quiz left the trash")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if _, ok := f.quizzes.quizzes[id]; !ok {
+				t.Errorf("the quiz wasn't restored")
+			}
+		})
+	}
+}
+
+func TestRestoreQuizNotInTrash(t *testing.T) {
+	f := newTrashFixture()
+
+	if err := f.service.RestoreQuiz(context.Background(), uuid.New(), trashAdmin); !errors.Is(err, errors.ErrNotFound) {
+		t.Fatalf("got error %v, want not found", err)
+	}
+}
+
+func TestRestoreSession(t *testing.T) {
+	f := newTrashFixture()
+	ctx := context.Background()
+
+	// The quiz of the session is in the trash too
+	quizID := f.deleteQuiz(time.Hour)
+	deletedAt := time.Now().Add(-time.Hour)
+	session := &models.GameSession{ID: uuid.New(), QuizID: quizID, HostID: trashOwner, DeletedAt: &deletedAt}
+	f.sessions.deleted[session.ID] = session
+
+	if err := f.service.RestoreSession(ctx, session.ID, trashOwner); !errors.Is(err, errors.ErrConflict) {
+		t.Fatalf("got error %v for a session of a deleted quiz, want a conflict", err)
+	}
+
+	if err := f.service.RestoreQuiz(ctx, quizID, trashOwner); err != nil {
+		t.Fatalf("failed to restore the quiz: %v", err)
+	}
+	if err := f.service.RestoreSession(ctx, session.ID, trashOwner); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(f.sessions.restored) != 1 || f.sessions.restored[0] != session.ID {
+		t.Errorf("got restored sessions %v, want %v", f.sessions.restored, session.ID)
+	}
+}
+
+func TestListDeletedQuizzes(t *testing.T) {
+	tests := []struct {
+		name   string
+		userID int64
+		want   string
+	}{
+		{name: "teacher sees own quizzes", userID: trashOwner, want: "user"},
+		{name: "admin sees the whole trash", userID: trashAdmin, want: "all"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newTrashFixture()
+
+			if _, err := f.service.ListDeletedQuizzes(context.Background(), tt.userID, 0, 10); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if f.quizzes.listed != tt.want {
+				t.Errorf("got the %q listing, want %q", f.quizzes.listed, tt.want)
+			}
+		})
+	}
+}
+
+func TestPurge(t *testing.T) {
+	f := newTrashFixture()
+	start := time.Now()
+
+	quizzes, sessions, err := f.service.Purge(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if quizzes != 1 || sessions != 2 {
+		t.Errorf("got %d quizzes and %d sessions purged, want 1 and 2", quizzes, sessions)
+	}
+
+	// Items deleted longer than the retention period ago are purged
+	cutoff := start.Add(-trashRetention)
+	for name, purged := range map[string]time.Time{"quizzes": f.quizzes.purged, "sessions": f.sessions.purged} {
+		if purged.Before(cutoff) || purged.After(cutoff.Add(time.Minute)) {
+			t.Errorf("%s are purged before %v, want %v", name, purged, cutoff)
+		}
+	}
+}
@@ -0,0 +1,281 @@
+package service
+
+import (
+	"bsu-quiz/pkg/authz"
+	"bsu-quiz/quiz/internal/domain/errors"
+	"bsu-quiz/quiz/internal/domain/models"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ImportUsers creates users from a CSV with a login column and optional
+// group and role columns. A header row naming the columns may reorder them,
+// without it the columns are login, group, role. Logins that already exist
+// or repeat in the file are reported as duplicates, bad rows as invalid,
+// the rest is created in one transaction
+func (s *AdminServiceImpl) ImportUsers(ctx context.Context, r io.Reader) (*models.UserImportReport, error) {
+	if err := s.authorize(ctx, authz.UserManage); err != nil {
+		return nil, err
+	}
+
+	rows, err := parseUserImport(r)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.UserImportReport{
+		Created:    []models.UserImportRow{},
+		Duplicates: []models.UserImportRow{},
+		Invalid:    []models.UserImportRow{},
+	}
+
+	var valid []models.UserImportRow
+	seen := make(map[string]bool)
+	for _, row := range rows {
+		if row.Reason != "" {
+			report.Invalid = append(report.Invalid, row)
+			continue
+		}
+
+		if seen[row.Login] {
+			row.Reason = "login repeats in the file"
+			report.Duplicates = append(report.Duplicates, row)
+			continue
+		}
+		seen[row.Login] = true
+		valid = append(valid, row)
+	}
+
+	logins := make([]string, 0, len(valid))
+	for _, row := range valid {
+		logins = append(logins, row.Login)
+	}
+
+	existing, err := s.userRepo.GetByLogins(ctx, logins)
+	if err != nil {
+		return nil, err
+	}
+
+	exists := make(map[string]bool, len(existing))
+	for _, user := range existing {
+		exists[user.Login] = true
+	}
+
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		for _, row := range valid {
+			if exists[row.Login] {
+				row.Reason = "user already exists"
+				report.Duplicates = append(report.Duplicates, row)
+				continue
+			}
+
+			user := &models.User{
+				Login:     row.Login,
+				RoleFlags: row.RoleFlags,
+				Group:     row.Group,
+			}
+
+			id, err := s.userRepo.Create(ctx, user)
+			if err != nil {
+				return fmt.Errorf("line %d: %w", row.Line, err)
+			}
+			user.ID = id
+			row.ID = id
+
+			err = s.audit.Record(ctx, models.AuditUserImport, models.AuditTargetUser, userTargetID(id), nil, user)
+			if err != nil {
+				return err
+			}
+
+			report.Created = append(report.Created, row)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// BulkUpdateUsers applies the action to all given users in one transaction,
+// role is the role flag assigned or removed by assign_role and remove_role.
+// It returns the number of updated users
+func (s *AdminServiceImpl) BulkUpdateUsers(ctx context.Context, ids []int64, action models.BulkUserAction, role int) (int, error) {
+	if err := s.authorize(ctx, authz.UserManage); err != nil {
+		return 0, err
+	}
+
+	if len(ids) == 0 {
+		return 0, errors.Invalid("user_ids", "no users selected")
+	}
+
+	if action == models.BulkAssignRole || action == models.BulkRemoveRole {
+		if role != models.RoleUser && role != models.RoleTeacher && role != models.RoleAdmin {
+			return 0, errors.Invalid("role", "invalid role")
+		}
+	}
+
+	set, clear, ok := action.RoleChange(role)
+	if !ok {
+		return 0, errors.Invalid("action", "invalid bulk action")
+	}
+
+	// Admins can't lock themselves out
+	if actor, ok := models.ActorFromContext(ctx); ok && uniqueIDs(ids)[actor.UserID] {
+		if action == models.BulkBlock || (action == models.BulkRemoveRole && role == models.RoleAdmin) {
+			return 0, errors.Forbidden("you can't block yourself or remove your own admin role")
+		}
+	}
+
+	users, err := s.userRepo.GetByIDs(ctx, ids)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(users) != len(uniqueIDs(ids)) {
+		return 0, errors.NotFound("user")
+	}
+
+	var updated int64
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		updated, err = s.userRepo.UpdateRoles(ctx, ids, set, clear)
+		if err != nil {
+			return err
+		}
+
+		for _, user := range users {
+			after := *user
+			after.RoleFlags = (user.RoleFlags | set) &^ clear
+			if after.RoleFlags == user.RoleFlags {
+				continue
+			}
+
+			err := s.audit.Record(ctx, models.AuditUserBulkUpdate, models.AuditTargetUser, userTargetID(user.ID), user, &after)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return int(updated), nil
+}
+
+// parseUserImport reads the import rows, rows that can't be imported
+// have the reason set
+func parseUserImport(r io.Reader) ([]models.UserImportRow, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	columns := map[string]int{"login": 0, "group": 1, "role": 2}
+
+	var rows []models.UserImportRow
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, errors.Invalid("file", "invalid CSV: "+err.Error())
+		}
+
+		if line == 1 && isUserImportHeader(record) {
+			columns = make(map[string]int)
+			for i, name := range record {
+				columns[strings.ToLower(strings.TrimSpace(name))] = i
+			}
+			continue
+		}
+
+		if len(record) == 1 && strings.TrimSpace(record[0]) == "" {
+			continue
+		}
+
+		rows = append(rows, parseUserImportRow(line, record, columns))
+	}
+
+	if len(rows) == 0 {
+		return nil, errors.Invalid("file", "the file has no users")
+	}
+
+	return rows, nil
+}
+
+// isUserImportHeader checks if the record names the columns, a header
+// has a login column in any position
+func isUserImportHeader(record []string) bool {
+	for _, name := range record {
+		if strings.EqualFold(strings.TrimSpace(name), "login") {
+			return true
+		}
+	}
+
+	return false
+}
+
+func parseUserImportRow(line int, record []string, columns map[string]int) models.UserImportRow {
+	field := func(name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	row := models.UserImportRow{
+		Line:      line,
+		Login:     field("login"),
+		Group:     field("group"),
+		RoleFlags: models.RoleUser,
+	}
+
+	if err := validateLogin(row.Login); err != nil {
+		row.Reason = err.Error()
+		return row
+	}
+
+	if err := validateGroup(row.Group); err != nil {
+		row.Reason = err.Error()
+		return row
+	}
+
+	if name := field("role"); name != "" {
+		role, ok := models.ParseRole(name)
+		if !ok || role == models.RoleBlocked {
+			row.Reason = "unknown role " + strconv.Quote(name)
+			return row
+		}
+		row.RoleFlags |= role
+	}
+
+	return row
+}
+
+// validateGroup checks the academic group fits the users table
+func validateGroup(group string) error {
+	if len(group) > 32 {
+		return errors.Invalid("group", "group must be at most 32 characters")
+	}
+
+	return nil
+}
+
+func uniqueIDs(ids []int64) map[int64]bool {
+	unique := make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		unique[id] = true
+	}
+
+	return unique
+}
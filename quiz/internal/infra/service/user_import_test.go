@@ -0,0 +1,164 @@
+package service
+
+import (
+	"bsu-quiz/quiz/internal/domain/errors"
+	"bsu-quiz/quiz/internal/domain/models"
+	"bsu-quiz/quiz/internal/domain/rules"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestParseUserImport(t *testing.T) {
+	tests := []struct {
+		name string
+		csv  string
+		want []models.UserImportRow
+	}{
+		{
+			name: "without header",
+			csv:  "ivanov,ПМ-21,teacher\npetrov\n",
+			want: []models.UserImportRow{
+				{Line: 1, Login: "ivanov", Group: "ПМ-21", RoleFlags: models.RoleUser | models.RoleTeacher},
+				{Line: 2, Login: "petrov", RoleFlags: models.RoleUser},
+			},
+		},
+		{
+			name: "header reorders columns",
+			csv:  "Role, Group, Login\nadmin, ПМ-21, ivanov\n",
+			want: []models.UserImportRow{
+				{Line: 2, Login: "ivanov", Group: "ПМ-21", RoleFlags: models.RoleUser | models.RoleAdmin},
+			},
+		},
+		{
+			name: "header without optional columns",
+			csv:  "login\nivanov\n",
+			want: []models.UserImportRow{
+				{Line: 2, Login: "ivanov", RoleFlags: models.RoleUser},
+			},
+		},
+		{
+			name: "blank lines and spaces",
+			csv:  "  ivanov , ПМ-21 \n\n petrov\n",
+			want: []models.UserImportRow{
+				{Line: 1, Login: "ivanov", Group: "ПМ-21", RoleFlags: models.RoleUser},
+				{Line: 2, Login: "petrov", RoleFlags: models.RoleUser},
+			},
+		},
+		{
+			name: "quoted fields",
+			csv:  `"ivanov","ПМ-21, поток 2"` + "\n",
+			want: []models.UserImportRow{
+				{Line: 1, Login: "ivanov", Group: "ПМ-21, поток 2", RoleFlags: models.RoleUser},
+			},
+		},
+		{
+			name: "invalid rows",
+			csv: "bad login\n" +
+				",ПМ-21\n" +
+				"ivanov," + strings.Repeat("g", 33) + "\n" +
+				"petrov,,blocked\n" +
+				"sidorov,,dean\n",
+			want: []models.UserImportRow{
+				{Line: 1, Login: "bad login", RoleFlags: models.RoleUser, Reason: "login may only contain letters, digits, dots, dashes and underscores"},
+				{Line: 2, Group: "ПМ-21", RoleFlags: models.RoleUser, Reason: "login is required"},
+				{Line: 3, Login: "ivanov", Group: strings.Repeat("g", 33), RoleFlags: models.RoleUser, Reason: "group must be at most 32 characters"},
+				{Line: 4, Login: "petrov", RoleFlags: models.RoleUser, Reason: `unknown role "blocked"`},
+				{Line: 5, Login: "sidorov", RoleFlags: models.RoleUser, Reason: `unknown role "dean"`},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := parseUserImport(strings.NewReader(tt.csv))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(rows) != len(tt.want) {
+				t.Fatalf("got %d rows, want %d: %+v", len(rows), len(tt.want), rows)
+			}
+			for i := range tt.want {
+				if rows[i] != tt.want[i] {
+					t.Errorf("row %d: got %+v, want %+v", i, rows[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestParseUserImportErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		csv  string
+	}{
+		{name: "empty", csv: ""},
+		{name: "only header", csv: "login,group\n"},
+		{name: "only blank lines", csv: "\n\n"},
+		{name: "broken quotes", csv: "\"ivanov\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseUserImport(strings.NewReader(tt.csv))
+			if _, ok := errors.Fields(err)["file"]; !ok {
+				t.Fatalf("got error %v, want the file to be invalid", err)
+			}
+		})
+	}
+}
+
+func TestImportUsers(t *testing.T) {
+	const admin int64 = 1
+
+	users := &fakeUserRepo{users: map[int64]*models.User{
+		admin: {ID: admin, Login: "admin", RoleFlags: models.RoleUser | models.RoleAdmin},
+		2:     {ID: 2, Login: "petrov", RoleFlags: models.RoleUser},
+	}}
+	audit := &fakeAudit{}
+	policy := rules.NewPolicy(nil, users, nil, nil)
+	service := NewAdminService(users, nil, nil, policy, fakeTransactor{}, audit)
+
+	ctx := models.WithActor(context.Background(), models.Actor{UserID: admin})
+	csv := "login,group\nivanov,ПМ-21\npetrov,ПМ-21\nivanov,ПМ-22\nbad login,\nsidorov,\n"
+
+	report, err := service.ImportUsers(ctx, strings.NewReader(csv))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	logins := func(rows []models.UserImportRow) []string {
+		var result []string
+		for _, row := range rows {
+			result = append(result, row.Login)
+		}
+		return result
+	}
+
+	if got := strings.Join(logins(report.Created), ","); got != "ivanov,sidorov" {
+		t.Errorf("got created %s, want ivanov,sidorov", got)
+	}
+	if got := strings.Join(logins(report.Duplicates), ","); got != "ivanov,petrov" {
+		t.Errorf("got duplicates %s, want the repeated ivanov and the existing petrov", got)
+	}
+	if len(report.Invalid) != 1 || report.Invalid[0].Line != 5 {
+		t.Errorf("got invalid %+v, want line 5", report.Invalid)
+	}
+
+	for _, row := range report.Created {
+		user := users.users[row.ID]
+		if row.ID == 0 || user == nil || user.Login != row.Login || user.Group != row.Group {
+			t.Errorf("created row %+v isn't stored", row)
+		}
+	}
+	if len(audit.actions) != 2 || audit.actions[0] != models.AuditUserImport {
+		t.Errorf("got audit actions %v, want an import per created user", audit.actions)
+	}
+
+	// Only user managers may import
+	ctx = models.WithActor(context.Background(), models.Actor{UserID: 2})
+	if _, err := service.ImportUsers(ctx, strings.NewReader(csv)); !errors.Is(err, errors.ErrForbidden) {
+		t.Errorf("got error %v for a student, want forbidden", err)
+	}
+}
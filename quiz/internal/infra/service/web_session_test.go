@@ -0,0 +1,61 @@
+package service
+
+import (
+	"bsu-quiz/quiz/internal/domain/errors"
+	"bsu-quiz/quiz/internal/domain/models"
+	"context"
+	"testing"
+	"time"
+)
+
+func TestAuthenticate(t *testing.T) {
+	f := newAuthFixture(t)
+	ctx := context.Background()
+
+	session, err := f.service.StartSession(ctx, authStudent)
+	if err != nil {
+		t.Fatalf("failed to start a session: %v", err)
+	}
+	if session.Token == "" || session.ExpiresAt.Sub(session.CreatedAt) != time.Hour {
+		t.Errorf("got session %+v, want a token living the session TTL", session)
+	}
+
+	user, err := f.service.Authenticate(ctx, session.Token)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if user.ID != authStudent {
+		t.Errorf("got user %d, want %d", user.ID, authStudent)
+	}
+
+	// Roles are read on every request
+	f.users.users[authStudent].RoleFlags |= models.RoleTeacher
+	user, _ = f.service.Authenticate(ctx, session.Token)
+	if !user.HasRole(models.RoleTeacher) {
+		t.Errorf("a new role doesn't apply to the signed in user")
+	}
+
+	if err := f.service.EndSession(ctx, session.Token); err != nil {
+		t.Fatalf("failed to end the session: %v", err)
+	}
+	if _, err := f.service.Authenticate(ctx, session.Token); !errors.Is(err, errors.ErrUnauthorized) {
+		t.Errorf("got error %v for an ended session, want unauthorized", err)
+	}
+}
+
+func TestAuthenticateBlocked(t *testing.T) {
+	f := newAuthFixture(t)
+	ctx := context.Background()
+
+	session, _ := f.service.StartSession(ctx, authStudent)
+	f.users.users[authStudent].RoleFlags |= models.RoleBlocked
+
+	if _, err := f.service.Authenticate(ctx, session.Token); !errors.Is(err, errors.ErrUnauthorized) {
+		t.Fatalf("got error %v for a blocked user, want unauthorized", err)
+	}
+
+	// The session ends, unblocking doesn't sign the user back in
+	if _, ok := f.sessions.sessions[session.Token]; ok {
+		t.Errorf("the session of the blocked user is kept")
+	}
+}
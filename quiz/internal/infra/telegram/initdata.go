@@ -0,0 +1,96 @@
+// Package telegram checks data Telegram signs for the bot, so a Telegram
+// account can be trusted without a password
+package telegram
+
+import (
+	"bsu-quiz/quiz/internal/domain/errors"
+	"bsu-quiz/quiz/internal/domain/models"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxClockSkew is how far in the future auth_date may be, Telegram's clock
+// may run a little ahead of ours
+const maxClockSkew = time.Minute
+
+// ValidateInitData checks the init data the Web App got from Telegram: the
+// hash must be signed with the bot token and auth_date must be at most
+// maxAge old and not in the future. It returns the user the Web App was opened by
+func ValidateInitData(initData, botToken string, maxAge time.Duration, now time.Time) (*models.TelegramUser, error) {
+	values, err := url.ParseQuery(initData)
+	if err != nil {
+		return nil, errors.Unauthorized("init data is malformed")
+	}
+
+	// The secret key of Web App data is derived from the token with the "WebAppData" key
+	secret := hmacSHA256([]byte("WebAppData"), []byte(botToken))
+	if err := checkHash(values, secret, now, maxAge); err != nil {
+		return nil, err
+	}
+
+	var user models.TelegramUser
+	if err := json.Unmarshal([]byte(values.Get("user")), &user); err != nil || user.ID == 0 {
+		return nil, errors.Unauthorized("init data has no user")
+	}
+
+	return &user, nil
+}
+
+// checkHash checks the hash of the values and the age of their auth_date,
+// dates further in the future than maxClockSkew are refused.
+// The data check string is the other fields sorted by name as key=value
+// lines, its HMAC-SHA-256 with the secret is the hash in hex
+func checkHash(values url.Values, secret []byte, now time.Time, maxAge time.Duration) error {
+	hash := values.Get("hash")
+	if hash == "" {
+		return errors.Unauthorized("telegram data isn't signed")
+	}
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		if key != "hash" {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	lines := make([]string, 0, len(keys))
+	for _, key := range keys {
+		lines = append(lines, key+"="+values.Get(key))
+	}
+
+	want := hmacSHA256(secret, []byte(strings.Join(lines, "\n")))
+	got, err := hex.DecodeString(hash)
+	if err != nil || !hmac.Equal(got, want) {
+		return errors.Unauthorized("telegram data signature is invalid")
+	}
+
+	authDate, err := strconv.ParseInt(values.Get("auth_date"), 10, 64)
+	if err != nil {
+		return errors.Unauthorized("telegram data has no auth date")
+	}
+
+	age := now.Sub(time.Unix(authDate, 0))
+	if age > maxAge {
+		return errors.Unauthorized("telegram data has expired, reopen the app")
+	}
+
+	if age < -maxClockSkew {
+		return errors.Unauthorized("telegram data is dated in the future")
+	}
+
+	return nil
+}
+
+func hmacSHA256(key, data []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(data)
+	return mac.Sum(nil)
+}
@@ -0,0 +1,165 @@
+package telegram
+
+import (
+	"bsu-quiz/quiz/internal/domain/errors"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testBotToken = "123456:TEST-token"
+
+// testAuthDate is auth_date of the signed test data, 2025-06-01 10:00:00 UTC
+var testAuthDate = time.Unix(1748772000, 0)
+
+// testInitData is init data signed with testBotToken outside of this package
+const testInitData = "auth_date=1748772000&query_id=AAH" +
+	"&user=%7B%22id%22%3A42%2C%22first_name%22%3A%22Ivan%22%2C%22username%22%3A%22ivan%22%7D" +
+	"&hash=88bc62da7370e1daa4137806acfa2a04c37685502baf5d1ec497ae6c6c0aab74"
+
+// sign sets the hash of the values as Telegram computes it with the secret
+func sign(values url.Values, secret []byte) url.Values {
+	values.Del("hash")
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	lines := make([]string, 0, len(keys))
+	for _, key := range keys {
+		lines = append(lines, key+"="+values.Get(key))
+	}
+
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(strings.Join(lines, "\n")))
+	values.Set("hash", hex.EncodeToString(mac.Sum(nil)))
+
+	return values
+}
+
+// signInitData returns the test init data dated authDate signed with the token
+func signInitData(token string, authDate time.Time) string {
+	values, _ := url.ParseQuery(testInitData)
+	values.Set("auth_date", strconv.FormatInt(authDate.Unix(), 10))
+
+	secret := hmac.New(sha256.New, []byte("WebAppData"))
+	secret.Write([]byte(token))
+
+	return sign(values, secret.Sum(nil)).Encode()
+}
+
+func TestValidateInitData(t *testing.T) {
+	const maxAge = time.Hour
+
+	tests := []struct {
+		name     string
+		initData string
+		now      time.Time
+		// valid is false if the init data must be refused
+		valid bool
+	}{
+		{
+			name:     "known good",
+			initData: testInitData,
+			now:      testAuthDate.Add(time.Minute),
+			valid:    true,
+		},
+		{
+			name:     "tampered user",
+			initData: strings.Replace(testInitData, "%22id%22%3A42", "%22id%22%3A43", 1),
+			now:      testAuthDate.Add(time.Minute),
+		},
+		{
+			name:     "tampered auth date",
+			initData: strings.Replace(testInitData, "auth_date=1748772000", "auth_date=1748775600", 1),
+			now:      testAuthDate.Add(90 * time.Minute),
+		},
+		{
+			name:     "extra field",
+			initData: testInitData + "&start_param=admin",
+			now:      testAuthDate.Add(time.Minute),
+		},
+		{
+			name:     "wrong token",
+			initData: signInitData("654321:OTHER-token", testAuthDate),
+			now:      testAuthDate.Add(time.Minute),
+		},
+		{
+			name:     "unsigned",
+			initData: strings.Split(testInitData, "&hash=")[0],
+			now:      testAuthDate.Add(time.Minute),
+		},
+		{
+			name:     "hash not in hex",
+			initData: strings.Split(testInitData, "&hash=")[0] + "&hash=zz",
+			now:      testAuthDate.Add(time.Minute),
+		},
+		{
+			name:     "at max age",
+			initData: testInitData,
+			now:      testAuthDate.Add(maxAge),
+			valid:    true,
+		},
+		{
+			name:     "stale",
+			initData: testInitData,
+			now:      testAuthDate.Add(maxAge + time.Second),
+		},
+		{
+			name:     "slightly in the future",
+			initData: signInitData(testBotToken, testAuthDate.Add(30*time.Second)),
+			now:      testAuthDate,
+			valid:    true,
+		},
+		{
+			name:     "in the future",
+			initData: signInitData(testBotToken, testAuthDate.Add(maxClockSkew+time.Second)),
+			now:      testAuthDate,
+		},
+		{
+			name:     "far in the future",
+			initData: signInitData(testBotToken, testAuthDate.Add(24*time.Hour)),
+			now:      testAuthDate,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user, err := ValidateInitData(tt.initData, testBotToken, maxAge, tt.now)
+
+			if !tt.valid {
+				if !errors.Is(err, errors.ErrUnauthorized) {
+					t.Fatalf("got error %v, want unauthorized", err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if user.ID != 42 || user.FirstName != "Ivan" || user.Username != "ivan" {
+				t.Errorf("got user %+v, want ivan with id 42", user)
+			}
+		})
+	}
+}
+
+func TestValidateInitDataWithoutUser(t *testing.T) {
+	values := url.Values{"auth_date": {strconv.FormatInt(testAuthDate.Unix(), 10)}}
+	secret := hmac.New(sha256.New, []byte("WebAppData"))
+	secret.Write([]byte(testBotToken))
+	initData := sign(values, secret.Sum(nil)).Encode()
+
+	_, err := ValidateInitData(initData, testBotToken, time.Hour, testAuthDate)
+	if !errors.Is(err, errors.ErrUnauthorized) {
+		t.Fatalf("got error %v, want unauthorized", err)
+	}
+}
@@ -0,0 +1,45 @@
+package telegram
+
+import (
+	"bsu-quiz/quiz/internal/domain/errors"
+	"bsu-quiz/quiz/internal/domain/models"
+	"crypto/sha256"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// loginWidgetFields are the fields the Login Widget signs, other query
+// parameters of the callback such as next are left out of the check
+var loginWidgetFields = []string{"id", "first_name", "last_name", "username", "photo_url", "auth_date", "hash"}
+
+// ValidateLoginWidget checks the data the Telegram Login Widget sent to the
+// callback: the hash must be signed with the bot token and auth_date must be
+// at most maxAge old and not in the future. It returns the user who signed in
+func ValidateLoginWidget(query url.Values, botToken string, maxAge time.Duration, now time.Time) (*models.TelegramUser, error) {
+	values := url.Values{}
+	for _, field := range loginWidgetFields {
+		if value, ok := query[field]; ok {
+			values[field] = value
+		}
+	}
+
+	// The secret key of the widget is the SHA-256 of the token
+	secret := sha256.Sum256([]byte(botToken))
+	if err := checkHash(values, secret[:], now, maxAge); err != nil {
+		return nil, err
+	}
+
+	id, err := strconv.ParseInt(values.Get("id"), 10, 64)
+	if err != nil || id == 0 {
+		return nil, errors.Unauthorized("telegram data has no user")
+	}
+
+	return &models.TelegramUser{
+		ID:        id,
+		FirstName: values.Get("first_name"),
+		LastName:  values.Get("last_name"),
+		Username:  values.Get("username"),
+		PhotoURL:  values.Get("photo_url"),
+	}, nil
+}
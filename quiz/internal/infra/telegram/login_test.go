@@ -0,0 +1,136 @@
+package telegram
+
+import (
+	"bsu-quiz/quiz/internal/domain/errors"
+	"crypto/sha256"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// testLoginWidget returns the callback query of the Login Widget signed with
+// testBotToken outside of this package
+func testLoginWidget() url.Values {
+	return url.Values{
+		"id":         {"42"},
+		"first_name": {"Ivan"},
+		"username":   {"ivan"},
+		"auth_date":  {"1748772000"},
+		"hash":       {"20bd8ed5ce1731662902bad0f29c8e3505c0bb824fd09eb402823a6479eb0fd2"},
+	}
+}
+
+// signLoginWidget returns the widget query dated authDate signed with the token
+func signLoginWidget(token string, authDate time.Time) url.Values {
+	values := testLoginWidget()
+	values.Set("auth_date", strconv.FormatInt(authDate.Unix(), 10))
+
+	secret := sha256.Sum256([]byte(token))
+	return sign(values, secret[:])
+}
+
+func TestValidateLoginWidget(t *testing.T) {
+	const maxAge = 10 * time.Minute
+
+	tests := []struct {
+		name  string
+		query func() url.Values
+		now   time.Time
+		// valid is false if the query must be refused
+		valid bool
+	}{
+		{
+			name:  "known good",
+			query: testLoginWidget,
+			now:   testAuthDate.Add(time.Minute),
+			valid: true,
+		},
+		{
+			name: "callback parameters aren't signed",
+			query: func() url.Values {
+				values := testLoginWidget()
+				values.Set("next", "/admin/dashboard")
+				return values
+			},
+			now:   testAuthDate.Add(time.Minute),
+			valid: true,
+		},
+		{
+			name: "tampered id",
+			query: func() url.Values {
+				values := testLoginWidget()
+				values.Set("id", "43")
+				return values
+			},
+			now: testAuthDate.Add(time.Minute),
+		},
+		{
+			name: "added signed field",
+			query: func() url.Values {
+				values := testLoginWidget()
+				values.Set("last_name", "Petrov")
+				return values
+			},
+			now: testAuthDate.Add(time.Minute),
+		},
+		{
+			name:  "wrong token",
+			query: func() url.Values { return signLoginWidget("654321:OTHER-token", testAuthDate) },
+			now:   testAuthDate.Add(time.Minute),
+		},
+		{
+			name: "init data secret",
+			query: func() url.Values {
+				values := testLoginWidget()
+				return sign(values, hmacSHA256([]byte("WebAppData"), []byte(testBotToken)))
+			},
+			now: testAuthDate.Add(time.Minute),
+		},
+		{
+			name: "unsigned",
+			query: func() url.Values {
+				values := testLoginWidget()
+				values.Del("hash")
+				return values
+			},
+			now: testAuthDate.Add(time.Minute),
+		},
+		{
+			name:  "stale",
+			query: testLoginWidget,
+			now:   testAuthDate.Add(maxAge + time.Second),
+		},
+		{
+			name:  "slightly in the future",
+			query: func() url.Values { return signLoginWidget(testBotToken, testAuthDate.Add(30*time.Second)) },
+			now:   testAuthDate,
+			valid: true,
+		},
+		{
+			name:  "in the future",
+			query: func() url.Values { return signLoginWidget(testBotToken, testAuthDate.Add(maxClockSkew+time.Second)) },
+			now:   testAuthDate,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user, err := ValidateLoginWidget(tt.query(), testBotToken, maxAge, tt.now)
+
+			if !tt.valid {
+				if !errors.Is(err, errors.ErrUnauthorized) {
+					t.Fatalf("got error %v, want unauthorized", err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if user.ID != 42 || user.FirstName != "Ivan" || user.Username != "ivan" {
+				t.Errorf("got user %+v, want ivan with id 42", user)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package worker
+
+import (
+	"bsu-quiz/quiz/internal/infra/service"
+	"context"
+	"log/slog"
+	"time"
+)
+
+// QuizPublisher periodically publishes scheduled quizzes whose publish time has come
+type QuizPublisher struct {
+	quizService service.QuizProvider
+	interval    time.Duration
+	log         *slog.Logger
+}
+
+func NewQuizPublisher(quizService service.QuizProvider, interval time.Duration, log *slog.Logger) *QuizPublisher {
+	return &QuizPublisher{
+		quizService: quizService,
+		interval:    interval,
+		log:         log,
+	}
+}
+
+// Run publishes scheduled quizzes every interval until ctx is cancelled
+func (w *QuizPublisher) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		w.publish(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *QuizPublisher) publish(ctx context.Context) {
+	published, err := w.quizService.PublishScheduledQuizzes(ctx)
+	if err != nil {
+		w.log.Error("failed to publish scheduled quizzes", slog.String("error", err.Error()))
+		return
+	}
+
+	if published > 0 {
+		w.log.Info("scheduled quizzes published", slog.Int64("quizzes", published))
+	}
+}
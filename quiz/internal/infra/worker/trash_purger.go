@@ -0,0 +1,55 @@
+package worker
+
+import (
+	"bsu-quiz/quiz/internal/infra/service"
+	"context"
+	"log/slog"
+	"time"
+)
+
+// TrashPurger periodically removes quizzes and sessions whose
+// retention period in the trash is over
+type TrashPurger struct {
+	trashService service.TrashProvider
+	interval     time.Duration
+	log          *slog.Logger
+}
+
+func NewTrashPurger(trashService service.TrashProvider, interval time.Duration, log *slog.Logger) *TrashPurger {
+	return &TrashPurger{
+		trashService: trashService,
+		interval:     interval,
+		log:          log,
+	}
+}
+
+// Run purges the trash every interval until ctx is cancelled
+func (w *TrashPurger) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		w.purge(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *TrashPurger) purge(ctx context.Context) {
+	quizzes, sessions, err := w.trashService.Purge(ctx)
+	if err != nil {
+		w.log.Error("failed to purge trash", slog.String("error", err.Error()))
+		return
+	}
+
+	if quizzes > 0 || sessions > 0 {
+		w.log.Info("trash purged",
+			slog.Int64("quizzes", quizzes),
+			slog.Int64("sessions", sessions),
+		)
+	}
+}
@@ -0,0 +1,53 @@
+package dto
+
+import (
+	"bsu-quiz/quiz/internal/domain/models"
+	"encoding/json"
+	"time"
+)
+
+// AuditEntry is an audit log entry as returned by the API
+type AuditEntry struct {
+	ID         int64           `json:"id"`
+	ActorID    *int64          `json:"actorId"`
+	ActorLogin string          `json:"actorLogin,omitempty"`
+	Action     string          `json:"action"`
+	TargetType string          `json:"targetType"`
+	TargetID   string          `json:"targetId"`
+	Diff       json.RawMessage `json:"diff,omitempty"`
+	IP         string          `json:"ip"`
+	UserAgent  string          `json:"userAgent"`
+	CreatedAt  time.Time       `json:"createdAt"`
+}
+
+// AuditList is a page of audit log entries
+type AuditList struct {
+	Entries    []AuditEntry `json:"entries"`
+	TotalCount int          `json:"totalCount"`
+	Page       int          `json:"page"`
+	TotalPages int          `json:"totalPages"`
+}
+
+func NewAuditEntry(entry *models.AuditEntry) AuditEntry {
+	return AuditEntry{
+		ID:         entry.ID,
+		ActorID:    entry.ActorID,
+		ActorLogin: entry.ActorLogin,
+		Action:     entry.Action,
+		TargetType: entry.TargetType,
+		TargetID:   entry.TargetID,
+		Diff:       entry.Diff,
+		IP:         entry.IP,
+		UserAgent:  entry.UserAgent,
+		CreatedAt:  entry.CreatedAt,
+	}
+}
+
+func NewAuditEntries(entries []*models.AuditEntry) []AuditEntry {
+	result := make([]AuditEntry, 0, len(entries))
+	for _, entry := range entries {
+		result = append(result, NewAuditEntry(entry))
+	}
+
+	return result
+}
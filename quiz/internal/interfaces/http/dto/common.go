@@ -0,0 +1,40 @@
+package dto
+
+import "bsu-quiz/quiz/internal/domain/models"
+
+// Status is the body of responses that only report success
+type Status struct {
+	Status string `json:"status"`
+}
+
+// TotalPages returns the number of pages of the given size needed for total items
+func TotalPages(total, limit int) int {
+	if limit <= 0 {
+		return 0
+	}
+
+	return (total + limit - 1) / limit
+}
+
+// Page is a page of a listing, the next page is requested with nextCursor
+type Page[T any] struct {
+	Items      []T    `json:"items"`
+	Total      int    `json:"total"`
+	PageSize   int    `json:"pageSize"`
+	NextCursor string `json:"nextCursor,omitempty"`
+}
+
+// NewPage converts the items of the page with convert
+func NewPage[M, T any](page *models.Page[M], convert func(M) T) Page[T] {
+	items := make([]T, 0, len(page.Items))
+	for _, item := range page.Items {
+		items = append(items, convert(item))
+	}
+
+	return Page[T]{
+		Items:      items,
+		Total:      page.Total,
+		PageSize:   page.PageSize,
+		NextCursor: page.NextCursor,
+	}
+}
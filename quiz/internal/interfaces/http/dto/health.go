@@ -0,0 +1,32 @@
+package dto
+
+import "bsu-quiz/quiz/internal/infra/health"
+
+// Health is the body of the liveness and readiness probes
+type Health struct {
+	Status string        `json:"status"`
+	Checks []HealthCheck `json:"checks,omitempty"`
+}
+
+type HealthCheck struct {
+	Name      string `json:"name"`
+	Healthy   bool   `json:"healthy"`
+	Critical  bool   `json:"critical"`
+	Error     string `json:"error,omitempty"`
+	LatencyMS int64  `json:"latencyMs"`
+}
+
+func NewHealthChecks(results []health.Result) []HealthCheck {
+	checks := make([]HealthCheck, 0, len(results))
+	for _, result := range results {
+		checks = append(checks, HealthCheck{
+			Name:      result.Name,
+			Healthy:   result.Healthy,
+			Critical:  result.Critical,
+			Error:     result.Error,
+			LatencyMS: result.Latency.Milliseconds(),
+		})
+	}
+
+	return checks
+}
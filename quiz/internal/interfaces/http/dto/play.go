@@ -0,0 +1,99 @@
+package dto
+
+import (
+	"bsu-quiz/quiz/internal/domain/models"
+	"bsu-quiz/quiz/internal/infra/markup"
+
+	"github.com/google/uuid"
+)
+
+// JoinSession is the request body of a player joining a session
+type JoinSession struct {
+	JoinCode string `json:"joinCode" binding:"required"`
+}
+
+// Player is the BSU student signed in to the Web App with their Telegram account
+type Player struct {
+	UserID           int64  `json:"userId"`
+	Login            string `json:"login"`
+	Group            string `json:"group,omitempty"`
+	TelegramUserID   int64  `json:"telegramUserId"`
+	TelegramUsername string `json:"telegramUsername,omitempty"`
+}
+
+func NewPlayer(user *models.User, tgUser *models.TelegramUser) Player {
+	return Player{
+		UserID:           user.ID,
+		Login:            user.Login,
+		Group:            user.Group,
+		TelegramUserID:   tgUser.ID,
+		TelegramUsername: tgUser.Username,
+	}
+}
+
+// PlayerFeedback is models.PlayerFeedback with the texts rendered for the
+// Web App and for the bot
+type PlayerFeedback struct {
+	Finished  bool             `json:"finished"`
+	Questions []QuestionReview `json:"questions"`
+}
+
+// QuestionReview is a reviewed question with rendered texts, see markup.Rendered
+type QuestionReview struct {
+	QuestionID       uuid.UUID        `json:"question_id"`
+	Text             *markup.Rendered `json:"text"`
+	Explanation      *markup.Rendered `json:"explanation,omitempty"`
+	Options          []ReviewOption   `json:"options"`
+	SelectedOptionID *uuid.UUID       `json:"selected_option_id,omitempty"`
+	IsCorrect        bool             `json:"is_correct"`
+	PointsAwarded    int              `json:"points_awarded"`
+}
+
+type ReviewOption struct {
+	ID        uuid.UUID        `json:"id"`
+	Text      *markup.Rendered `json:"text"`
+	IsCorrect bool             `json:"is_correct"`
+	Feedback  *markup.Rendered `json:"feedback,omitempty"`
+}
+
+func NewPlayerFeedback(feedback *models.PlayerFeedback, render func(string) *markup.Rendered) PlayerFeedback {
+	questions := make([]QuestionReview, 0, len(feedback.Questions))
+	for _, review := range feedback.Questions {
+		questions = append(questions, NewQuestionReview(review, render))
+	}
+
+	return PlayerFeedback{
+		Finished:  feedback.Finished,
+		Questions: questions,
+	}
+}
+
+func NewQuestionReview(review models.QuestionReview, render func(string) *markup.Rendered) QuestionReview {
+	options := make([]ReviewOption, 0, len(review.Options))
+	for _, option := range review.Options {
+		options = append(options, ReviewOption{
+			ID:        option.ID,
+			Text:      render(option.Text),
+			IsCorrect: option.IsCorrect,
+			Feedback:  renderOptional(option.Feedback, render),
+		})
+	}
+
+	return QuestionReview{
+		QuestionID:       review.QuestionID,
+		Text:             render(review.Text),
+		Explanation:      renderOptional(review.Explanation, render),
+		Options:          options,
+		SelectedOptionID: review.SelectedOptionID,
+		IsCorrect:        review.IsCorrect,
+		PointsAwarded:    review.PointsAwarded,
+	}
+}
+
+func renderOptional(text string, render func(string) *markup.Rendered) *markup.Rendered {
+	if text == "" {
+		return nil
+	}
+
+	return render(text)
+}
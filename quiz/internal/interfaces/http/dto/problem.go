@@ -0,0 +1,12 @@
+package dto
+
+// Problem is an RFC 7807 problem details body of API errors
+type Problem struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+	// Errors maps invalid fields to what is wrong with them
+	Errors map[string]string `json:"errors,omitempty"`
+}
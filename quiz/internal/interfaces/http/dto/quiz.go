@@ -0,0 +1,98 @@
+package dto
+
+import (
+	"bsu-quiz/quiz/internal/domain/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Quiz is a quiz as returned by the API
+type Quiz struct {
+	ID          uuid.UUID  `json:"id"`
+	UserID      int64      `json:"userId"`
+	Title       string     `json:"title"`
+	IsPublic    bool       `json:"isPublic"`
+	Status      string     `json:"status"`
+	PublishAt   *time.Time `json:"publishAt,omitempty"`
+	PublishedAt *time.Time `json:"publishedAt,omitempty"`
+	CreatedBy   string     `json:"createdBy"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	UpdatedAt   time.Time  `json:"updatedAt"`
+	Questions   []Question `json:"questions,omitempty"`
+}
+
+type Question struct {
+	ID          uuid.UUID `json:"id"`
+	QuizID      uuid.UUID `json:"quizId"`
+	Text        string    `json:"text"`
+	TimeLimit   int       `json:"timeLimit"`
+	Points      int       `json:"points"`
+	Position    int       `json:"position"`
+	Explanation string    `json:"explanation,omitempty"`
+	Options     []Option  `json:"options,omitempty"`
+}
+
+type Option struct {
+	ID         uuid.UUID `json:"id"`
+	QuestionID uuid.UUID `json:"questionId"`
+	Text       string    `json:"text"`
+	IsCorrect  bool      `json:"isCorrect"`
+	Position   int       `json:"position"`
+	Feedback   string    `json:"feedback,omitempty"`
+}
+
+func NewQuiz(quiz *models.Quiz) Quiz {
+	result := Quiz{
+		ID:          quiz.ID,
+		UserID:      quiz.UserID,
+		Title:       quiz.Title,
+		IsPublic:    quiz.IsPublic,
+		Status:      string(quiz.Status),
+		PublishAt:   quiz.PublishAt,
+		PublishedAt: quiz.PublishedAt,
+		CreatedBy:   quiz.CreatedBy,
+		CreatedAt:   quiz.CreatedAt,
+		UpdatedAt:   quiz.UpdatedAt,
+	}
+
+	for i := range quiz.Questions {
+		result.Questions = append(result.Questions, NewQuestion(&quiz.Questions[i]))
+	}
+
+	return result
+}
+
+func NewQuizzes(quizzes []*models.Quiz) []Quiz {
+	result := make([]Quiz, 0, len(quizzes))
+	for _, quiz := range quizzes {
+		result = append(result, NewQuiz(quiz))
+	}
+
+	return result
+}
+
+func NewQuestion(question *models.Question) Question {
+	result := Question{
+		ID:          question.ID,
+		QuizID:      question.QuizID,
+		Text:        question.Text,
+		TimeLimit:   question.TimeLimit,
+		Points:      question.Points,
+		Position:    question.Position,
+		Explanation: question.Explanation,
+	}
+
+	for _, option := range question.Options {
+		result.Options = append(result.Options, Option{
+			ID:         option.ID,
+			QuestionID: option.QuestionID,
+			Text:       option.Text,
+			IsCorrect:  option.IsCorrect,
+			Position:   option.Position,
+			Feedback:   option.Feedback,
+		})
+	}
+
+	return result
+}
@@ -0,0 +1,198 @@
+package dto
+
+import (
+	"bsu-quiz/quiz/internal/domain/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// GameSession is a game session as returned by the API
+type GameSession struct {
+	ID                   uuid.UUID     `json:"id"`
+	QuizID               uuid.UUID     `json:"quizId"`
+	HostID               int64         `json:"hostId"`
+	JoinCode             string        `json:"joinCode"`
+	StatusFlags          int           `json:"statusFlags"`
+	CurrentQuestionIndex int           `json:"currentQuestionIndex"`
+	ExamMode             bool          `json:"examMode"`
+	StartedAt            *time.Time    `json:"startedAt,omitempty"`
+	EndedAt              *time.Time    `json:"endedAt,omitempty"`
+	Participants         []Participant `json:"participants,omitempty"`
+}
+
+// SessionListItem is a row of the session listing
+type SessionListItem struct {
+	GameSession
+	QuizTitle        string `json:"quizTitle"`
+	ParticipantCount int    `json:"participantCount"`
+}
+
+type Participant struct {
+	ID        uuid.UUID `json:"id"`
+	SessionID uuid.UUID `json:"sessionId"`
+	UserID    *int64    `json:"userId"`
+	Login     string    `json:"login"`
+	Score     int       `json:"score"`
+	JoinedAt  time.Time `json:"joinedAt"`
+	Answers   []Answer  `json:"answers,omitempty"`
+}
+
+type Answer struct {
+	ID             uuid.UUID  `json:"id"`
+	ParticipantID  uuid.UUID  `json:"participantId"`
+	QuestionID     uuid.UUID  `json:"questionId"`
+	OptionID       *uuid.UUID `json:"optionId"`
+	IsCorrect      bool       `json:"isCorrect"`
+	ResponseTimeMS *int       `json:"responseTimeMs"`
+	PointsAwarded  int        `json:"pointsAwarded"`
+	AnsweredAt     time.Time  `json:"answeredAt"`
+}
+
+func NewGameSession(session *models.GameSession) GameSession {
+	result := GameSession{
+		ID:                   session.ID,
+		QuizID:               session.QuizID,
+		HostID:               session.HostID,
+		JoinCode:             session.JoinCode,
+		StatusFlags:          session.StatusFlags,
+		CurrentQuestionIndex: session.CurrentQuestionIndex,
+		ExamMode:             session.ExamMode,
+		StartedAt:            session.StartedAt,
+		EndedAt:              session.EndedAt,
+	}
+
+	for _, participant := range session.Participants {
+		result.Participants = append(result.Participants, NewParticipant(&participant))
+	}
+
+	return result
+}
+
+func NewGameSessions(sessions []*models.GameSession) []GameSession {
+	result := make([]GameSession, 0, len(sessions))
+	for _, session := range sessions {
+		result = append(result, NewGameSession(session))
+	}
+
+	return result
+}
+
+func NewSessionListItem(item *models.SessionListItem) SessionListItem {
+	return SessionListItem{
+		GameSession:      NewGameSession(&item.GameSession),
+		QuizTitle:        item.QuizTitle,
+		ParticipantCount: item.ParticipantCount,
+	}
+}
+
+func NewParticipant(participant *models.Participant) Participant {
+	result := Participant{
+		ID:        participant.ID,
+		SessionID: participant.SessionID,
+		UserID:    participant.UserID,
+		Login:     participant.Login,
+		Score:     participant.Score,
+		JoinedAt:  participant.JoinedAt,
+	}
+
+	for _, answer := range participant.Answers {
+		result.Answers = append(result.Answers, Answer{
+			ID:             answer.ID,
+			ParticipantID:  answer.ParticipantID,
+			QuestionID:     answer.QuestionID,
+			OptionID:       answer.OptionID,
+			IsCorrect:      answer.IsCorrect,
+			ResponseTimeMS: answer.ResponseTimeMS,
+			PointsAwarded:  answer.PointsAwarded,
+			AnsweredAt:     answer.AnsweredAt,
+		})
+	}
+
+	return result
+}
+
+// SessionMonitor is a snapshot of the live session monitor
+type SessionMonitor struct {
+	ID            uuid.UUID            `json:"id"`
+	JoinCode      string               `json:"joinCode"`
+	QuizTitle     string               `json:"quizTitle"`
+	StatusFlags   int                  `json:"statusFlags"`
+	Status        string               `json:"status"`
+	QuestionIndex int                  `json:"questionIndex"`
+	QuestionCount int                  `json:"questionCount"`
+	Question      *MonitorQuestion     `json:"question,omitempty"`
+	Answered      int                  `json:"answered"`
+	TimeLeftMS    *int64               `json:"timeLeftMs,omitempty"`
+	Participants  []MonitorParticipant `json:"participants"`
+}
+
+// MonitorQuestion is the open question of a monitored session
+type MonitorQuestion struct {
+	ID        uuid.UUID `json:"id"`
+	Text      string    `json:"text"`
+	TimeLimit int       `json:"timeLimit"`
+}
+
+// MonitorParticipant is a participant of a monitored session, Answered
+// tells if they answered the open question
+type MonitorParticipant struct {
+	ID       uuid.UUID `json:"id"`
+	Login    string    `json:"login"`
+	Score    int       `json:"score"`
+	JoinedAt time.Time `json:"joinedAt"`
+	Answered bool      `json:"answered"`
+}
+
+func NewSessionMonitor(monitor *models.SessionMonitor) SessionMonitor {
+	session := monitor.Session
+	result := SessionMonitor{
+		ID:            session.ID,
+		JoinCode:      session.JoinCode,
+		StatusFlags:   session.StatusFlags,
+		Status:        models.StatusName(session.StatusFlags),
+		QuestionIndex: session.CurrentQuestionIndex,
+		QuestionCount: monitor.QuestionCount,
+		Answered:      monitor.Answered,
+		Participants:  make([]MonitorParticipant, 0, len(session.Participants)),
+	}
+
+	if session.Quiz != nil {
+		result.QuizTitle = session.Quiz.Title
+	}
+
+	if question := monitor.Question; question != nil {
+		result.Question = &MonitorQuestion{
+			ID:        question.ID,
+			Text:      question.Text,
+			TimeLimit: question.TimeLimit,
+		}
+	}
+
+	if monitor.TimeLeft != nil {
+		left := monitor.TimeLeft.Milliseconds()
+		result.TimeLeftMS = &left
+	}
+
+	for _, participant := range session.Participants {
+		item := MonitorParticipant{
+			ID:       participant.ID,
+			Login:    participant.Login,
+			Score:    participant.Score,
+			JoinedAt: participant.JoinedAt,
+		}
+
+		if monitor.Question != nil {
+			for _, answer := range participant.Answers {
+				if answer.QuestionID == monitor.Question.ID {
+					item.Answered = true
+					break
+				}
+			}
+		}
+
+		result.Participants = append(result.Participants, item)
+	}
+
+	return result
+}
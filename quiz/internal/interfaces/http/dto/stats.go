@@ -0,0 +1,80 @@
+package dto
+
+import (
+	"bsu-quiz/quiz/internal/domain/models"
+	"time"
+)
+
+// DashboardStats is the summary shown on the admin dashboard
+type DashboardStats struct {
+	TotalUsers        int               `json:"totalUsers"`
+	TotalQuizzes      int               `json:"totalQuizzes"`
+	TotalSessions     int               `json:"totalSessions"`
+	ActiveSessions    int               `json:"activeSessions"`
+	RecentUsers       []User            `json:"recentUsers"`
+	RecentQuizzes     []Quiz            `json:"recentQuizzes"`
+	UsersByRole       UsersByRole       `json:"usersByRole"`
+	SessionsPerDay    []DailyCount      `json:"sessionsPerDay"`
+	AnswersPerDay     []DailyCount      `json:"answersPerDay"`
+	SystemStatus      string            `json:"systemStatus"`
+	Components        []ComponentStatus `json:"components"`
+	CurrentServerTime time.Time         `json:"currentServerTime"`
+	GeneratedAt       time.Time         `json:"generatedAt"`
+}
+
+type UsersByRole struct {
+	Users    int `json:"users"`
+	Admins   int `json:"admins"`
+	Teachers int `json:"teachers"`
+	Blocked  int `json:"blocked"`
+}
+
+type DailyCount struct {
+	Date  string `json:"date"`
+	Count int    `json:"count"`
+}
+
+type ComponentStatus struct {
+	Name      string `json:"name"`
+	Healthy   bool   `json:"healthy"`
+	Error     string `json:"error,omitempty"`
+	LatencyMS int64  `json:"latencyMs"`
+}
+
+func NewDashboardStats(stats *models.DashboardStats) DashboardStats {
+	result := DashboardStats{
+		TotalUsers:        stats.TotalUsers,
+		TotalQuizzes:      stats.TotalQuizzes,
+		TotalSessions:     stats.TotalSessions,
+		ActiveSessions:    stats.ActiveSessions,
+		RecentUsers:       NewUsers(stats.RecentUsers),
+		RecentQuizzes:     NewQuizzes(stats.RecentQuizzes),
+		SessionsPerDay:    newDailyCounts(stats.SessionsPerDay),
+		AnswersPerDay:     newDailyCounts(stats.AnswersPerDay),
+		SystemStatus:      stats.SystemStatus,
+		CurrentServerTime: stats.CurrentServerTime,
+		GeneratedAt:       stats.GeneratedAt,
+	}
+
+	if stats.UsersByRole != nil {
+		result.UsersByRole = UsersByRole(*stats.UsersByRole)
+	}
+
+	for _, component := range stats.Components {
+		result.Components = append(result.Components, ComponentStatus(component))
+	}
+
+	return result
+}
+
+func newDailyCounts(counts []models.DailyCount) []DailyCount {
+	result := make([]DailyCount, 0, len(counts))
+	for _, count := range counts {
+		result = append(result, DailyCount{
+			Date:  count.Date.Format("2006-01-02"),
+			Count: count.Count,
+		})
+	}
+
+	return result
+}
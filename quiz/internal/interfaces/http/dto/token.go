@@ -0,0 +1,37 @@
+package dto
+
+import (
+	"bsu-quiz/quiz/internal/domain/models"
+	"time"
+)
+
+// TokenLogin is the request body of signing in to the API
+type TokenLogin struct {
+	Login    string `json:"login" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+// TokenRefresh is the request body of refreshing and revoking tokens
+type TokenRefresh struct {
+	RefreshToken string `json:"refreshToken" binding:"required"`
+}
+
+// TokenPair is the token pair of a signed in API client, expiresIn is the
+// lifetime of the access token in seconds
+type TokenPair struct {
+	AccessToken      string    `json:"accessToken"`
+	TokenType        string    `json:"tokenType"`
+	ExpiresIn        int       `json:"expiresIn"`
+	RefreshToken     string    `json:"refreshToken"`
+	RefreshExpiresAt time.Time `json:"refreshExpiresAt"`
+}
+
+func NewTokenPair(pair *models.TokenPair) TokenPair {
+	return TokenPair{
+		AccessToken:      pair.AccessToken,
+		TokenType:        "Bearer",
+		ExpiresIn:        int(time.Until(pair.AccessExpiresAt).Round(time.Second).Seconds()),
+		RefreshToken:     pair.RefreshToken,
+		RefreshExpiresAt: pair.RefreshExpiresAt,
+	}
+}
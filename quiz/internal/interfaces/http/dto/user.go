@@ -0,0 +1,103 @@
+package dto
+
+import "bsu-quiz/quiz/internal/domain/models"
+
+// User is a user as returned by the API
+type User struct {
+	ID        int64  `json:"id"`
+	Login     string `json:"login"`
+	RoleFlags int    `json:"roleFlags"`
+	Group     string `json:"group,omitempty"`
+}
+
+// UserCreate is the request body of user creation. Password is only
+// there to reject requests that set one
+type UserCreate struct {
+	Login     string  `json:"login" binding:"required"`
+	Password  *string `json:"password"`
+	RoleFlags int     `json:"roleFlags"`
+	Group     string  `json:"group"`
+}
+
+// UserUpdate is the request body of a user update, omitted fields are left unchanged
+type UserUpdate struct {
+	Login     *string `json:"login"`
+	RoleFlags *int    `json:"roleFlags"`
+	Group     *string `json:"group"`
+}
+
+// UserRoleUpdate is the request body of a role update
+type UserRoleUpdate struct {
+	RoleFlags *int `json:"roleFlags" binding:"required"`
+}
+
+func NewUser(user *models.User) User {
+	return User{
+		ID:        user.ID,
+		Login:     user.Login,
+		RoleFlags: user.RoleFlags,
+		Group:     user.Group,
+	}
+}
+
+func NewUsers(users []*models.User) []User {
+	result := make([]User, 0, len(users))
+	for _, user := range users {
+		result = append(result, NewUser(user))
+	}
+
+	return result
+}
+
+// UserImportRow is a row of the student import
+type UserImportRow struct {
+	Line      int    `json:"line"`
+	ID        int64  `json:"id,omitempty"`
+	Login     string `json:"login"`
+	Group     string `json:"group,omitempty"`
+	RoleFlags int    `json:"roleFlags"`
+	Reason    string `json:"reason,omitempty"`
+}
+
+// UserImportReport is the result of the student import
+type UserImportReport struct {
+	Created    []UserImportRow `json:"created"`
+	Duplicates []UserImportRow `json:"duplicates"`
+	Invalid    []UserImportRow `json:"invalid"`
+}
+
+// UserBulkUpdate is the request body of a bulk user action
+type UserBulkUpdate struct {
+	UserIDs []int64 `json:"userIds" binding:"required"`
+	Action  string  `json:"action" binding:"required"`
+	Role    string  `json:"role"`
+}
+
+// UserBulkResult is the response of a bulk user action
+type UserBulkResult struct {
+	Updated int `json:"updated"`
+}
+
+func NewUserImportReport(report *models.UserImportReport) UserImportReport {
+	return UserImportReport{
+		Created:    newUserImportRows(report.Created),
+		Duplicates: newUserImportRows(report.Duplicates),
+		Invalid:    newUserImportRows(report.Invalid),
+	}
+}
+
+func newUserImportRows(rows []models.UserImportRow) []UserImportRow {
+	result := make([]UserImportRow, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, UserImportRow{
+			Line:      row.Line,
+			ID:        row.ID,
+			Login:     row.Login,
+			Group:     row.Group,
+			RoleFlags: row.RoleFlags,
+			Reason:    row.Reason,
+		})
+	}
+
+	return result
+}
@@ -1,8 +1,12 @@
 package handlers
 
 import (
+	"bsu-quiz/quiz/internal/domain/errors"
 	"bsu-quiz/quiz/internal/domain/models"
 	"bsu-quiz/quiz/internal/infra/service"
+	"bsu-quiz/quiz/internal/interfaces/http/dto"
+	"context"
+	"fmt"
 	"net/http"
 	"strconv"
 
@@ -14,61 +18,72 @@ type AdminHandler struct {
 	adminService   service.AdminProvider
 	quizService    service.QuizProvider
 	sessionService service.SessionProvider
+	statsService   service.StatsProvider
+	auditService   service.AuditProvider
 }
 
 func NewAdminHandler(
 	adminService service.AdminProvider,
 	quizService service.QuizProvider,
 	sessionService service.SessionProvider,
+	statsService service.StatsProvider,
+	auditService service.AuditProvider,
 ) *AdminHandler {
 	return &AdminHandler{
 		adminService:   adminService,
 		quizService:    quizService,
 		sessionService: sessionService,
+		statsService:   statsService,
+		auditService:   auditService,
 	}
 }
 
 func (h *AdminHandler) Dashboard(c *gin.Context) {
+	// Get dashboard statistics
+	stats, err := h.statsService.GetDashboardStats(c)
+	if err != nil {
+		c.Error(fmt.Errorf("failed to fetch dashboard stats: %w", err))
+		return
+	}
+
 	username, _ := c.Get("username")
 
-	c.HTML(http.StatusOK, "admin/dashboard.html", gin.H{
+	render(c, "admin/dashboard.html", gin.H{
 		"Title":       "Admin Dashboard",
 		"Username":    username,
 		"CurrentNav":  "admin",
-		"CurrentTime": "2025-06-22 12:51:00", // Using provided timestamp
+		"Stats":       stats,
+		"CurrentTime": stats.CurrentServerTime.Format("2006-01-02 15:04:05"),
 	})
 }
 
 // Users handles displaying and managing users
 func (h *AdminHandler) Users(c *gin.Context) {
-	// Get page parameters
-	pageStr := c.DefaultQuery("page", "1")
-	page, _ := strconv.Atoi(pageStr)
-	if page < 1 {
-		page = 1
+	// Get cursor and sort parameters
+	opts, err := parseListOptions(c, 10, models.UserSortFields, models.DefaultUserSort)
+	if err != nil {
+		c.Error(fmt.Errorf("invalid query: %w", err))
+		return
 	}
-	limit := 10
-	offset := (page - 1) * limit
 
 	// Get users
-	users, err := h.adminService.GetAllUsers(c, offset, limit)
+	users, err := h.adminService.GetAllUsers(c, opts)
 	if err != nil {
-		c.HTML(http.StatusInternalServerError, "error.html", gin.H{
-			"ErrorMessage": "Failed to fetch users: " + err.Error(),
-		})
+		c.Error(fmt.Errorf("failed to fetch users: %w", err))
 		return
 	}
 
 	username, _ := c.Get("username")
 
-	c.HTML(http.StatusOK, "admin/users.html", gin.H{
-		"Title":       "Manage Users",
-		"Username":    username,
-		"Users":       users,
-		"CurrentNav":  "admin",
-		"SubNav":      "users",
-		"Page":        page,
-		"CurrentTime": "2025-06-22 12:51:00", // Using provided timestamp
+	render(c, "admin/users.html", gin.H{
+		"Title":      "Manage Users",
+		"Username":   username,
+		"Users":      users.Items,
+		"CurrentNav": "admin",
+		"SubNav":     "users",
+		"List":       users,
+		"Query":      c.Request.URL.Query(),
+		"Sort":       opts.Sort,
 	})
 }
 
@@ -77,7 +92,7 @@ func (h *AdminHandler) UpdateUserRole(c *gin.Context) {
 	// Parse user ID from request
 	userID, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		c.Error(errors.Invalid("id", "invalid user ID"))
 		return
 	}
 
@@ -101,7 +116,88 @@ func (h *AdminHandler) UpdateUserRole(c *gin.Context) {
 	// Update user role
 	err = h.adminService.UpdateUserRole(c, userID, roleFlags)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update user role: " + err.Error()})
+		c.Error(fmt.Errorf("failed to update user role: %w", err))
+		return
+	}
+
+	// Redirect back to users page
+	c.Redirect(http.StatusSeeOther, "/admin/users")
+}
+
+// ImportUsersForm displays the student import form
+func (h *AdminHandler) ImportUsersForm(c *gin.Context) {
+	username, _ := c.Get("username")
+
+	render(c, "admin/users_import.html", gin.H{
+		"Title":      "Import Students",
+		"Username":   username,
+		"CurrentNav": "admin",
+		"SubNav":     "users",
+	})
+}
+
+// ImportUsers creates users from an uploaded CSV and shows the import report
+func (h *AdminHandler) ImportUsers(c *gin.Context) {
+	username, _ := c.Get("username")
+
+	// Open uploaded file
+	file, err := c.FormFile("file")
+	if err != nil {
+		c.Error(errors.Invalid("file", "CSV file is required"))
+		return
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		c.Error(errors.Invalid("file", "failed to read file: "+err.Error()))
+		return
+	}
+	defer src.Close()
+
+	// Import users
+	report, err := h.adminService.ImportUsers(c, src)
+	if err != nil {
+		c.Error(fmt.Errorf("failed to import users: %w", err))
+		return
+	}
+
+	render(c, "admin/users_import.html", gin.H{
+		"Title":      "Import Students",
+		"Username":   username,
+		"Report":     report,
+		"CurrentNav": "admin",
+		"SubNav":     "users",
+	})
+}
+
+// BulkUpdateUsers applies a bulk action to the users selected on the users page
+func (h *AdminHandler) BulkUpdateUsers(c *gin.Context) {
+	// Parse selected users
+	var ids []int64
+	for _, value := range c.PostFormArray("user_ids") {
+		id, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			c.Error(errors.Invalid("user_ids", "invalid user ID"))
+			return
+		}
+		ids = append(ids, id)
+	}
+
+	// Parse role for role actions
+	role := 0
+	if name := c.PostForm("role"); name != "" {
+		var ok bool
+		role, ok = models.ParseRole(name)
+		if !ok {
+			c.Error(errors.Invalid("role", "invalid role"))
+			return
+		}
+	}
+
+	// Apply action
+	_, err := h.adminService.BulkUpdateUsers(c, ids, models.BulkUserAction(c.PostForm("action")), role)
+	if err != nil {
+		c.Error(fmt.Errorf("failed to update users: %w", err))
 		return
 	}
 
@@ -111,34 +207,31 @@ func (h *AdminHandler) UpdateUserRole(c *gin.Context) {
 
 // Quizzes handles displaying and managing all quizzes
 func (h *AdminHandler) Quizzes(c *gin.Context) {
-	// Get page parameters
-	pageStr := c.DefaultQuery("page", "1")
-	page, _ := strconv.Atoi(pageStr)
-	if page < 1 {
-		page = 1
+	// Get cursor and sort parameters
+	opts, err := parseListOptions(c, 10, models.QuizSortFields, models.DefaultQuizSort)
+	if err != nil {
+		c.Error(fmt.Errorf("invalid query: %w", err))
+		return
 	}
-	limit := 10
-	offset := (page - 1) * limit
 
 	// Get quizzes
-	quizzes, err := h.adminService.GetAllQuizzes(c, offset, limit)
+	quizzes, err := h.adminService.GetAllQuizzes(c, opts)
 	if err != nil {
-		c.HTML(http.StatusInternalServerError, "error.html", gin.H{
-			"ErrorMessage": "Failed to fetch quizzes: " + err.Error(),
-		})
+		c.Error(fmt.Errorf("failed to fetch quizzes: %w", err))
 		return
 	}
 
 	username, _ := c.Get("username")
 
-	c.HTML(http.StatusOK, "admin/quizzes.html", gin.H{
-		"Title":       "Manage Quizzes",
-		"Username":    username,
-		"Quizzes":     quizzes,
-		"CurrentNav":  "admin",
-		"SubNav":      "quizzes",
-		"Page":        page,
-		"CurrentTime": "2025-06-22 12:51:00", // Using provided timestamp
+	render(c, "admin/quizzes.html", gin.H{
+		"Title":      "Manage Quizzes",
+		"Username":   username,
+		"Quizzes":    quizzes.Items,
+		"CurrentNav": "admin",
+		"SubNav":     "quizzes",
+		"List":       quizzes,
+		"Query":      c.Request.URL.Query(),
+		"Sort":       opts.Sort,
 	})
 }
 
@@ -147,14 +240,21 @@ func (h *AdminHandler) DeleteQuiz(c *gin.Context) {
 	// Parse quiz ID from request
 	quizID, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid quiz ID"})
+		c.Error(errors.Invalid("id", "invalid quiz ID"))
+		return
+	}
+
+	// Get user ID from context
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.Error(errors.Unauthorized("unauthorized"))
 		return
 	}
 
 	// Delete quiz
-	err = h.adminService.DeleteQuiz(c, quizID)
+	err = h.adminService.DeleteQuiz(c, quizID, userID.(int64))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete quiz: " + err.Error()})
+		c.Error(fmt.Errorf("failed to delete quiz: %w", err))
 		return
 	}
 
@@ -164,53 +264,160 @@ func (h *AdminHandler) DeleteQuiz(c *gin.Context) {
 
 // Sessions handles displaying and managing all game sessions
 func (h *AdminHandler) Sessions(c *gin.Context) {
-	// Get page parameters
-	pageStr := c.DefaultQuery("page", "1")
-	page, _ := strconv.Atoi(pageStr)
-	if page < 1 {
-		page = 1
+	// Get filter, sort and cursor parameters
+	filter, err := parseSessionFilter(c, 10)
+	if err != nil {
+		c.Error(fmt.Errorf("invalid filter: %w", err))
+		return
 	}
-	limit := 10
-	offset := (page - 1) * limit
 
 	// Get sessions
-	sessions, err := h.adminService.GetAllSessions(c, offset, limit)
+	sessions, err := h.adminService.GetAllSessions(c, filter)
 	if err != nil {
-		c.HTML(http.StatusInternalServerError, "error.html", gin.H{
-			"ErrorMessage": "Failed to fetch sessions: " + err.Error(),
-		})
+		c.Error(fmt.Errorf("failed to fetch sessions: %w", err))
 		return
 	}
 
 	username, _ := c.Get("username")
 
-	c.HTML(http.StatusOK, "admin/sessions.html", gin.H{
-		"Title":       "Manage Game Sessions",
-		"Username":    username,
-		"Sessions":    sessions,
-		"CurrentNav":  "admin",
-		"SubNav":      "sessions",
-		"Page":        page,
-		"CurrentTime": "2025-06-22 12:51:00", // Using provided timestamp
+	render(c, "admin/sessions.html", gin.H{
+		"Title":      "Manage Game Sessions",
+		"Username":   username,
+		"Sessions":   sessions.Items,
+		"CurrentNav": "admin",
+		"SubNav":     "sessions",
+		"Filter":     c.Request.URL.Query(),
+		"List":       sessions,
+		"Query":      c.Request.URL.Query(),
+		"Sort":       filter.Sort,
+	})
+}
+
+// SessionMonitor displays the live monitor of a game session, the page
+// follows the session through SessionMonitorHandler.Events
+func (h *AdminHandler) SessionMonitor(c *gin.Context) {
+	// Parse session ID from request
+	sessionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.Error(errors.Invalid("id", "invalid session ID"))
+		return
+	}
+
+	// Get user ID from context
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.Error(errors.Unauthorized("unauthorized"))
+		return
+	}
+
+	monitor, err := h.sessionService.MonitorSession(c, sessionID, userID.(int64))
+	if err != nil {
+		c.Error(fmt.Errorf("failed to fetch session: %w", err))
+		return
+	}
+
+	username, _ := c.Get("username")
+
+	render(c, "admin/session_monitor.html", gin.H{
+		"Title":      "Session " + monitor.Session.JoinCode,
+		"Username":   username,
+		"Monitor":    dto.NewSessionMonitor(monitor),
+		"CurrentNav": "admin",
+		"SubNav":     "sessions",
 	})
 }
 
 // EndSession forcefully ends a game session
 func (h *AdminHandler) EndSession(c *gin.Context) {
+	h.controlSession(c, "end session", h.sessionService.EndSession)
+}
+
+// PauseSession stops the question timer of a game session
+func (h *AdminHandler) PauseSession(c *gin.Context) {
+	h.controlSession(c, "pause session", h.sessionService.PauseSession)
+}
+
+// ResumeSession restarts the question timer of a paused game session
+func (h *AdminHandler) ResumeSession(c *gin.Context) {
+	h.controlSession(c, "resume session", h.sessionService.ResumeSession)
+}
+
+// SkipQuestion moves a game session on to the next question
+func (h *AdminHandler) SkipQuestion(c *gin.Context) {
+	h.controlSession(c, "skip question", h.sessionService.SkipQuestion)
+}
+
+// KickParticipant removes a participant from a game session
+func (h *AdminHandler) KickParticipant(c *gin.Context) {
+	participantID, err := uuid.Parse(c.Param("participantId"))
+	if err != nil {
+		c.Error(errors.Invalid("participantId", "invalid participant ID"))
+		return
+	}
+
+	h.controlSession(c, "kick participant", func(ctx context.Context, sessionID uuid.UUID, userID int64) error {
+		return h.sessionService.KickParticipant(ctx, sessionID, participantID, userID)
+	})
+}
+
+// controlSession runs a control of the session service, the same the host
+// uses, on the session of the request as the current admin
+func (h *AdminHandler) controlSession(c *gin.Context, what string, control func(ctx context.Context, id uuid.UUID, userID int64) error) {
 	// Parse session ID from request
 	sessionID, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid session ID"})
+		c.Error(errors.Invalid("id", "invalid session ID"))
 		return
 	}
 
-	// End session
-	err = h.adminService.EndSession(c, sessionID)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to end session: " + err.Error()})
+	// Get user ID from context
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.Error(errors.Unauthorized("unauthorized"))
+		return
+	}
+
+	if err := control(c, sessionID, userID.(int64)); err != nil {
+		c.Error(fmt.Errorf("failed to %s: %w", what, err))
 		return
 	}
 
 	// Return success response
 	c.JSON(http.StatusOK, gin.H{"status": "success"})
 }
+
+// AuditLog displays the audit log filtered by actor, action, target and date
+func (h *AdminHandler) AuditLog(c *gin.Context) {
+	// Get page parameters
+	pageStr := c.DefaultQuery("page", "1")
+	page, _ := strconv.Atoi(pageStr)
+	if page < 1 {
+		page = 1
+	}
+	limit := 20
+	offset := (page - 1) * limit
+
+	filter, err := parseAuditFilter(c, offset, limit)
+	if err != nil {
+		c.Error(fmt.Errorf("invalid filter: %w", err))
+		return
+	}
+
+	entries, err := h.auditService.List(c, filter)
+	if err != nil {
+		c.Error(fmt.Errorf("failed to fetch audit log: %w", err))
+		return
+	}
+
+	username, _ := c.Get("username")
+
+	render(c, "admin/audit.html", gin.H{
+		"Title":      "Audit Log",
+		"Username":   username,
+		"Entries":    entries,
+		"CurrentNav": "admin",
+		"SubNav":     "audit",
+		"Filter":     c.Request.URL.Query(),
+		"Page":       page,
+	})
+}
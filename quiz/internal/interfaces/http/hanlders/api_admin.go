@@ -0,0 +1,473 @@
+package handlers
+
+import (
+	"bsu-quiz/quiz/internal/domain/errors"
+	"bsu-quiz/quiz/internal/domain/models"
+	"bsu-quiz/quiz/internal/infra/service"
+	"bsu-quiz/quiz/internal/interfaces/http/dto"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	apiDefaultLimit = 10
+	apiMaxLimit     = 100
+)
+
+// AdminAPIHandler implements the JSON admin API described in api/openapi/admin.yml
+type AdminAPIHandler struct {
+	adminService   service.AdminProvider
+	sessionService service.SessionProvider
+	statsService   service.StatsProvider
+	auditService   service.AuditProvider
+}
+
+func NewAdminAPIHandler(
+	adminService service.AdminProvider,
+	sessionService service.SessionProvider,
+	statsService service.StatsProvider,
+	auditService service.AuditProvider,
+) *AdminAPIHandler {
+	return &AdminAPIHandler{
+		adminService:   adminService,
+		sessionService: sessionService,
+		statsService:   statsService,
+		auditService:   auditService,
+	}
+}
+
+// GetAllUsers returns a sorted page of users, the next page is requested
+// with nextCursor
+func (h *AdminAPIHandler) GetAllUsers(c *gin.Context) {
+	limit, ok := apiLimit(c)
+	if !ok {
+		return
+	}
+
+	opts, err := parseListOptions(c, limit, models.UserSortFields, models.DefaultUserSort)
+	if err != nil {
+		c.Error(fmt.Errorf("invalid query: %w", err))
+		return
+	}
+
+	users, err := h.adminService.GetAllUsers(c, opts)
+	if err != nil {
+		c.Error(fmt.Errorf("failed to fetch users: %w", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewPage(users, dto.NewUser))
+}
+
+// CreateUser creates a new user
+func (h *AdminAPIHandler) CreateUser(c *gin.Context) {
+	var req dto.UserCreate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(errors.Validation("invalid request body: " + err.Error()))
+		return
+	}
+
+	if req.Password != nil {
+		c.Error(errors.Invalid("password", "password can't be set, users choose it through the emailed link"))
+		return
+	}
+
+	user := &models.User{
+		Login:     req.Login,
+		RoleFlags: req.RoleFlags,
+		Group:     req.Group,
+	}
+
+	id, err := h.adminService.CreateUser(c, user)
+	if err != nil {
+		c.Error(fmt.Errorf("failed to create user: %w", err))
+		return
+	}
+	user.ID = id
+
+	c.JSON(http.StatusCreated, dto.NewUser(user))
+}
+
+// ImportUsers creates users from the uploaded CSV file
+func (h *AdminAPIHandler) ImportUsers(c *gin.Context) {
+	file, err := c.FormFile("file")
+	if err != nil {
+		c.Error(errors.Invalid("file", "CSV file is required"))
+		return
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		c.Error(errors.Invalid("file", "failed to read file: "+err.Error()))
+		return
+	}
+	defer src.Close()
+
+	report, err := h.adminService.ImportUsers(c, src)
+	if err != nil {
+		c.Error(fmt.Errorf("failed to import users: %w", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewUserImportReport(report))
+}
+
+// BulkUpdateUsers assigns or removes a role, blocks or unblocks several users
+func (h *AdminAPIHandler) BulkUpdateUsers(c *gin.Context) {
+	var req dto.UserBulkUpdate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(errors.Validation("invalid request body: " + err.Error()))
+		return
+	}
+
+	role := 0
+	if req.Role != "" {
+		var ok bool
+		role, ok = models.ParseRole(req.Role)
+		if !ok {
+			c.Error(errors.Invalid("role", "invalid role"))
+			return
+		}
+	}
+
+	updated, err := h.adminService.BulkUpdateUsers(c, req.UserIDs, models.BulkUserAction(req.Action), role)
+	if err != nil {
+		c.Error(fmt.Errorf("failed to update users: %w", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.UserBulkResult{Updated: updated})
+}
+
+// GetUserByID returns a user
+func (h *AdminAPIHandler) GetUserByID(c *gin.Context) {
+	user, ok := h.findUser(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewUser(user))
+}
+
+// UpdateUser updates the login and roles of a user
+func (h *AdminAPIHandler) UpdateUser(c *gin.Context) {
+	user, ok := h.findUser(c)
+	if !ok {
+		return
+	}
+
+	var req dto.UserUpdate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(errors.Validation("invalid request body: " + err.Error()))
+		return
+	}
+
+	if req.Login != nil {
+		user.Login = *req.Login
+	}
+	if req.RoleFlags != nil {
+		user.RoleFlags = *req.RoleFlags
+	}
+	if req.Group != nil {
+		user.Group = *req.Group
+	}
+
+	if err := h.adminService.UpdateUser(c, user); err != nil {
+		c.Error(fmt.Errorf("failed to update user: %w", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewUser(user))
+}
+
+// UpdateUserRole replaces the role flags of a user
+func (h *AdminAPIHandler) UpdateUserRole(c *gin.Context) {
+	user, ok := h.findUser(c)
+	if !ok {
+		return
+	}
+
+	var req dto.UserRoleUpdate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(errors.Validation("invalid request body: " + err.Error()))
+		return
+	}
+
+	if err := h.adminService.UpdateUserRole(c, user.ID, *req.RoleFlags); err != nil {
+		c.Error(fmt.Errorf("failed to update user role: %w", err))
+		return
+	}
+	user.RoleFlags = *req.RoleFlags
+
+	c.JSON(http.StatusOK, dto.NewUser(user))
+}
+
+// DeleteUser deletes a user
+func (h *AdminAPIHandler) DeleteUser(c *gin.Context) {
+	user, ok := h.findUser(c)
+	if !ok {
+		return
+	}
+
+	if err := h.adminService.DeleteUser(c, user.ID); err != nil {
+		c.Error(fmt.Errorf("failed to delete user: %w", err))
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetAllQuizzes returns a sorted page of quizzes of all users, the next page
+// is requested with nextCursor
+func (h *AdminAPIHandler) GetAllQuizzes(c *gin.Context) {
+	limit, ok := apiLimit(c)
+	if !ok {
+		return
+	}
+
+	opts, err := parseListOptions(c, limit, models.QuizSortFields, models.DefaultQuizSort)
+	if err != nil {
+		c.Error(fmt.Errorf("invalid query: %w", err))
+		return
+	}
+
+	quizzes, err := h.adminService.GetAllQuizzes(c, opts)
+	if err != nil {
+		c.Error(fmt.Errorf("failed to fetch quizzes: %w", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewPage(quizzes, dto.NewQuiz))
+}
+
+// GetQuizByID returns a quiz with its questions and options
+func (h *AdminAPIHandler) GetQuizByID(c *gin.Context) {
+	quiz, ok := h.findQuiz(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewQuiz(quiz))
+}
+
+// DeleteQuiz moves a quiz to the trash
+func (h *AdminAPIHandler) DeleteQuiz(c *gin.Context) {
+	quiz, ok := h.findQuiz(c)
+	if !ok {
+		return
+	}
+
+	// Get user ID from context
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.Error(errors.Unauthorized("unauthorized"))
+		return
+	}
+
+	if err := h.adminService.DeleteQuiz(c, quiz.ID, userID.(int64)); err != nil {
+		c.Error(fmt.Errorf("failed to delete quiz: %w", err))
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetAllSessions returns a page of sessions filtered by status, host, quiz
+// and start date, the next page is requested with nextCursor
+func (h *AdminAPIHandler) GetAllSessions(c *gin.Context) {
+	limit, ok := apiLimit(c)
+	if !ok {
+		return
+	}
+
+	filter, err := parseSessionFilter(c, limit)
+	if err != nil {
+		c.Error(fmt.Errorf("invalid filter: %w", err))
+		return
+	}
+
+	sessions, err := h.adminService.GetAllSessions(c, filter)
+	if err != nil {
+		c.Error(fmt.Errorf("failed to fetch sessions: %w", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewPage(sessions, dto.NewSessionListItem))
+}
+
+// GetSessionByID returns a session with its participants
+func (h *AdminAPIHandler) GetSessionByID(c *gin.Context) {
+	session, ok := h.findSession(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewGameSession(session))
+}
+
+// EndSession forcefully ends a session
+func (h *AdminAPIHandler) EndSession(c *gin.Context) {
+	session, ok := h.findSession(c)
+	if !ok {
+		return
+	}
+
+	// Get user ID from context
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.Error(errors.Unauthorized("unauthorized"))
+		return
+	}
+
+	if err := h.sessionService.EndSession(c, session.ID, userID.(int64)); err != nil {
+		c.Error(fmt.Errorf("failed to end session: %w", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Status{Status: "success"})
+}
+
+// GetDashboardStats returns the admin dashboard summary
+func (h *AdminAPIHandler) GetDashboardStats(c *gin.Context) {
+	stats, err := h.statsService.GetDashboardStats(c)
+	if err != nil {
+		c.Error(fmt.Errorf("failed to fetch dashboard stats: %w", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewDashboardStats(stats))
+}
+
+// GetAuditLog returns a page of the audit log, newest entries first
+func (h *AdminAPIHandler) GetAuditLog(c *gin.Context) {
+	page, limit, ok := apiPagination(c)
+	if !ok {
+		return
+	}
+
+	filter, err := parseAuditFilter(c, (page-1)*limit, limit)
+	if err != nil {
+		c.Error(fmt.Errorf("invalid filter: %w", err))
+		return
+	}
+
+	entries, err := h.auditService.List(c, filter)
+	if err != nil {
+		c.Error(fmt.Errorf("failed to fetch audit log: %w", err))
+		return
+	}
+
+	total, err := h.auditService.Count(c, filter)
+	if err != nil {
+		c.Error(fmt.Errorf("failed to count audit log: %w", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.AuditList{
+		Entries:    dto.NewAuditEntries(entries),
+		TotalCount: total,
+		Page:       page,
+		TotalPages: dto.TotalPages(total, limit),
+	})
+}
+
+// findUser loads the user from the id path parameter, it writes
+// the error response and reports false if there is no such user
+func (h *AdminAPIHandler) findUser(c *gin.Context) (*models.User, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.Error(errors.Invalid("id", "invalid user ID"))
+		return nil, false
+	}
+
+	user, err := h.adminService.GetUser(c, id)
+	if err != nil {
+		c.Error(fmt.Errorf("failed to fetch user: %w", err))
+		return nil, false
+	}
+
+	if user == nil {
+		c.Error(errors.NotFound("user"))
+		return nil, false
+	}
+
+	return user, true
+}
+
+// findQuiz loads the quiz from the id path parameter
+func (h *AdminAPIHandler) findQuiz(c *gin.Context) (*models.Quiz, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.Error(errors.Invalid("id", "invalid quiz ID"))
+		return nil, false
+	}
+
+	quiz, err := h.adminService.GetQuiz(c, id)
+	if err != nil {
+		c.Error(fmt.Errorf("failed to fetch quiz: %w", err))
+		return nil, false
+	}
+
+	if quiz == nil {
+		c.Error(errors.NotFound("quiz"))
+		return nil, false
+	}
+
+	return quiz, true
+}
+
+// findSession loads the session from the id path parameter
+func (h *AdminAPIHandler) findSession(c *gin.Context) (*models.GameSession, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.Error(errors.Invalid("id", "invalid session ID"))
+		return nil, false
+	}
+
+	session, err := h.adminService.GetSession(c, id)
+	if err != nil {
+		c.Error(fmt.Errorf("failed to fetch session: %w", err))
+		return nil, false
+	}
+
+	if session == nil {
+		c.Error(errors.NotFound("session"))
+		return nil, false
+	}
+
+	return session, true
+}
+
+// apiPagination parses the page and limit query parameters
+func apiPagination(c *gin.Context) (page, limit int, ok bool) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		c.Error(errors.Invalid("page", "invalid page"))
+		return 0, 0, false
+	}
+
+	if limit, ok = apiLimit(c); !ok {
+		return 0, 0, false
+	}
+
+	return page, limit, true
+}
+
+// apiLimit reads the page size from the query, capped at apiMaxLimit
+func apiLimit(c *gin.Context) (int, bool) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(apiDefaultLimit)))
+	if err != nil || limit < 1 {
+		c.Error(errors.Invalid("limit", "invalid limit"))
+		return 0, false
+	}
+
+	if limit > apiMaxLimit {
+		limit = apiMaxLimit
+	}
+
+	return limit, true
+}
@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"bsu-quiz/quiz/internal/domain/errors"
+	"bsu-quiz/quiz/internal/domain/models"
+	"bsu-quiz/quiz/internal/infra/service"
+	"bsu-quiz/quiz/internal/interfaces/http/dto"
+	"bsu-quiz/quiz/internal/interfaces/http/middleware"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AuthAPIHandler issues the bearer tokens of the API
+type AuthAPIHandler struct {
+	tokenService service.TokenProvider
+}
+
+func NewAuthAPIHandler(tokenService service.TokenProvider) *AuthAPIHandler {
+	return &AuthAPIHandler{
+		tokenService: tokenService,
+	}
+}
+
+// Login issues a token pair for the login and password
+func (h *AuthAPIHandler) Login(c *gin.Context) {
+	var req dto.TokenLogin
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(errors.Validation("invalid request body: " + err.Error()))
+		return
+	}
+
+	pair, err := h.tokenService.Login(c, req.Login, req.Password)
+	if err != nil {
+		c.Error(fmt.Errorf("failed to sign in: %w", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewTokenPair(pair))
+}
+
+// Refresh trades a refresh token for a new token pair
+func (h *AuthAPIHandler) Refresh(c *gin.Context) {
+	var req dto.TokenRefresh
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(errors.Validation("invalid request body: " + err.Error()))
+		return
+	}
+
+	pair, err := h.tokenService.Refresh(c, req.RefreshToken)
+	if err != nil {
+		c.Error(fmt.Errorf("failed to refresh token: %w", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewTokenPair(pair))
+}
+
+// Logout revokes the refresh token and, when the request carries a valid
+// one, the access token
+func (h *AuthAPIHandler) Logout(c *gin.Context) {
+	var req dto.TokenRefresh
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(errors.Validation("invalid request body: " + err.Error()))
+		return
+	}
+
+	var claims *models.AccessClaims
+	if token, ok := middleware.BearerToken(c); ok {
+		// An expired or revoked access token has nothing left to revoke
+		claims, _ = h.tokenService.Verify(c, token)
+	}
+
+	if err := h.tokenService.Logout(c, req.RefreshToken, claims); err != nil {
+		c.Error(fmt.Errorf("failed to sign out: %w", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Status{Status: "success"})
+}
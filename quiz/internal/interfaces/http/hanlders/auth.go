@@ -0,0 +1,253 @@
+package handlers
+
+import (
+	"bsu-quiz/quiz/internal/domain/errors"
+	"bsu-quiz/quiz/internal/infra/service"
+	"bsu-quiz/quiz/internal/infra/telegram"
+	"bsu-quiz/quiz/internal/interfaces/http/middleware"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AuthHandler struct {
+	authService service.AuthProvider
+	// secureCookie sends the session cookie over HTTPS only
+	secureCookie bool
+	// Telegram Login Widget, the login page offers it when the bot username is set
+	botUsername  string
+	botToken     string
+	widgetMaxAge time.Duration
+}
+
+func NewAuthHandler(
+	authService service.AuthProvider,
+	secureCookie bool,
+	botUsername string,
+	botToken string,
+	widgetMaxAge time.Duration,
+) *AuthHandler {
+	return &AuthHandler{
+		authService:  authService,
+		secureCookie: secureCookie,
+		botUsername:  botUsername,
+		botToken:     botToken,
+		widgetMaxAge: widgetMaxAge,
+	}
+}
+
+// LoginForm displays the sign in page
+func (h *AuthHandler) LoginForm(c *gin.Context) {
+	h.renderForm(c, http.StatusOK, "login.html", "Sign in", nil)
+}
+
+// Login signs the user in and sends them back to the page they came from
+func (h *AuthHandler) Login(c *gin.Context) {
+	user, err := h.authService.Login(c, c.PostForm("login"), c.PostForm("password"))
+	if errors.Is(err, errors.ErrUnauthorized) || errors.Is(err, errors.ErrForbidden) {
+		h.renderForm(c, http.StatusUnauthorized, "login.html", "Sign in", err)
+		return
+	}
+	if err != nil {
+		c.Error(fmt.Errorf("failed to sign in: %w", err))
+		return
+	}
+
+	h.startSession(c, user.ID, c.PostForm("next"))
+}
+
+// TelegramLogin signs in the user whose Telegram account the Login Widget
+// vouched for, the widget redirects here with its data in the query
+func (h *AuthHandler) TelegramLogin(c *gin.Context) {
+	tgUser, err := telegram.ValidateLoginWidget(c.Request.URL.Query(), h.botToken, h.widgetMaxAge, time.Now())
+	if err != nil {
+		h.renderForm(c, http.StatusUnauthorized, "login.html", "Sign in", err)
+		return
+	}
+
+	user, err := h.authService.AuthenticateTelegram(c, tgUser)
+	if errors.Is(err, errors.ErrForbidden) {
+		h.renderForm(c, http.StatusUnauthorized, "login.html", "Sign in", err)
+		return
+	}
+	if err != nil {
+		c.Error(fmt.Errorf("failed to sign in with telegram: %w", err))
+		return
+	}
+
+	h.startSession(c, user.ID, c.Query("next"))
+}
+
+// registrationNotice tells the user what happens after signing up,
+// it's the same whether the login exists or not
+const registrationNotice = "A link to choose your password has been sent to the BSU mailbox of the login."
+
+// RegisterForm displays the sign up page
+func (h *AuthHandler) RegisterForm(c *gin.Context) {
+	h.renderForm(c, http.StatusOK, "register.html", "Sign up", nil)
+}
+
+// Register emails a link to finish signing up to the BSU mailbox of the login
+func (h *AuthHandler) Register(c *gin.Context) {
+	err := h.authService.Register(c, c.PostForm("login"))
+	if errors.Is(err, errors.ErrValidation) {
+		h.renderForm(c, http.StatusBadRequest, "register.html", "Sign up", err)
+		return
+	}
+	if err != nil {
+		c.Error(fmt.Errorf("failed to register: %w", err))
+		return
+	}
+
+	c.HTML(http.StatusOK, "register.html", gin.H{
+		"Title":  "Sign up",
+		"Notice": registrationNotice,
+	})
+}
+
+// ConfirmRegistrationForm displays the page to choose the password of the
+// new account, the token comes from the link of the email
+func (h *AuthHandler) ConfirmRegistrationForm(c *gin.Context) {
+	c.HTML(http.StatusOK, "register_confirm.html", gin.H{
+		"Title": "Finish signing up",
+		"Token": c.Query("token"),
+	})
+}
+
+// ConfirmRegistration creates the account with the chosen password and signs it in
+func (h *AuthHandler) ConfirmRegistration(c *gin.Context) {
+	id, err := h.authService.CompleteRegistration(c, c.PostForm("token"), c.PostForm("password"))
+	if errors.Is(err, errors.ErrValidation) || errors.Is(err, errors.ErrForbidden) {
+		c.HTML(http.StatusBadRequest, "register_confirm.html", gin.H{
+			"Title":        "Finish signing up",
+			"Token":        c.PostForm("token"),
+			"ErrorMessage": err.Error(),
+			"Fields":       errors.Fields(err),
+		})
+		return
+	}
+	if err != nil {
+		c.Error(fmt.Errorf("failed to finish registration: %w", err))
+		return
+	}
+
+	h.startSession(c, id, "")
+}
+
+// Logout ends the session of the browser
+func (h *AuthHandler) Logout(c *gin.Context) {
+	if token, err := c.Cookie(middleware.SessionCookie); err == nil && token != "" {
+		if err := h.authService.EndSession(c, token); err != nil {
+			c.Error(fmt.Errorf("failed to sign out: %w", err))
+			return
+		}
+	}
+
+	h.setCookie(c, "", -1)
+	c.Redirect(http.StatusSeeOther, middleware.LoginPath)
+}
+
+// passwordResetNotice tells the user what happens after asking for a reset,
+// it's the same whether the login exists or not
+const passwordResetNotice = "If the account exists, a reset link has been sent to its BSU mailbox."
+
+// ForgotPasswordForm displays the page to ask for a password reset link
+func (h *AuthHandler) ForgotPasswordForm(c *gin.Context) {
+	h.renderForm(c, http.StatusOK, "password_forgot.html", "Forgot password", nil)
+}
+
+// ForgotPassword emails a password reset link to the BSU mailbox of the login
+func (h *AuthHandler) ForgotPassword(c *gin.Context) {
+	if err := h.authService.RequestPasswordReset(c, c.PostForm("login")); err != nil {
+		c.Error(fmt.Errorf("failed to request password reset: %w", err))
+		return
+	}
+
+	c.HTML(http.StatusOK, "password_forgot.html", gin.H{
+		"Title":  "Forgot password",
+		"Notice": passwordResetNotice,
+	})
+}
+
+// ResetPasswordForm displays the page to choose a new password, the token
+// comes from the link of the email
+func (h *AuthHandler) ResetPasswordForm(c *gin.Context) {
+	c.HTML(http.StatusOK, "password_reset.html", gin.H{
+		"Title": "Choose a new password",
+		"Token": c.Query("token"),
+	})
+}
+
+// ResetPassword sets the new password and sends the user to sign in with it
+func (h *AuthHandler) ResetPassword(c *gin.Context) {
+	err := h.authService.ResetPassword(c, c.PostForm("token"), c.PostForm("password"))
+	if errors.Is(err, errors.ErrValidation) {
+		c.HTML(http.StatusBadRequest, "password_reset.html", gin.H{
+			"Title":        "Choose a new password",
+			"Token":        c.PostForm("token"),
+			"ErrorMessage": err.Error(),
+			"Fields":       errors.Fields(err),
+		})
+		return
+	}
+	if err != nil {
+		c.Error(fmt.Errorf("failed to reset password: %w", err))
+		return
+	}
+
+	c.Redirect(http.StatusSeeOther, middleware.LoginPath+"?reset=1")
+}
+
+func (h *AuthHandler) startSession(c *gin.Context, userID int64, next string) {
+	session, err := h.authService.StartSession(c, userID)
+	if err != nil {
+		c.Error(fmt.Errorf("failed to start session: %w", err))
+		return
+	}
+
+	h.setCookie(c, session.Token, int(time.Until(session.ExpiresAt).Seconds()))
+	c.Redirect(http.StatusSeeOther, nextPath(next))
+}
+
+func (h *AuthHandler) setCookie(c *gin.Context, value string, maxAge int) {
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(middleware.SessionCookie, value, maxAge, "/", "", h.secureCookie, true)
+}
+
+func (h *AuthHandler) renderForm(c *gin.Context, status int, name, title string, err error) {
+	data := gin.H{
+		"Title": title,
+		"Next":  c.Query("next"),
+		"Login": c.PostForm("login"),
+		// The Login Widget is shown for the bot
+		"TelegramBot": h.botUsername,
+	}
+
+	if c.Query("reset") != "" {
+		data["Notice"] = "Your password has been changed, sign in with the new one."
+	}
+
+	if next := c.PostForm("next"); next != "" {
+		data["Next"] = next
+	}
+
+	if err != nil {
+		data["ErrorMessage"] = err.Error()
+		data["Fields"] = errors.Fields(err)
+	}
+
+	c.HTML(status, name, data)
+}
+
+// nextPath returns where to go after signing in, only paths of the panel
+// are followed so the login page can't redirect elsewhere
+func nextPath(next string) string {
+	if !strings.HasPrefix(next, "/") || strings.HasPrefix(next, "//") || strings.HasPrefix(next, "/\\") {
+		return "/dashboard"
+	}
+
+	return next
+}
@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"bsu-quiz/quiz/internal/domain/errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// eventStreamPingInterval keeps proxies from closing an idle stream
+	eventStreamPingInterval = 15 * time.Second
+	// eventStreamWriteTimeout bounds a single write of the stream, the server
+	// write timeout would otherwise cut the stream
+	eventStreamWriteTimeout = 10 * time.Second
+)
+
+// openEventStream starts a stream of server-sent events and returns the
+// function writing to it, it reports false once the client is gone
+func openEventStream(c *gin.Context) func(payload string) bool {
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	rc := http.NewResponseController(c.Writer)
+	return func(payload string) bool {
+		if err := rc.SetWriteDeadline(time.Now().Add(eventStreamWriteTimeout)); err != nil && !errors.Is(err, http.ErrNotSupported) {
+			return false
+		}
+		if _, err := c.Writer.WriteString(payload); err != nil {
+			return false
+		}
+		return rc.Flush() == nil
+	}
+}
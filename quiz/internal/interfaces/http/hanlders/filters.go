@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"bsu-quiz/quiz/internal/domain/errors"
+	"bsu-quiz/quiz/internal/domain/models"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const dateLayout = "2006-01-02"
+
+// parseSessionFilter reads the session listing filter from the query:
+// status, host, quiz, from, to (dates or RFC 3339 times), sort, order and cursor
+func parseSessionFilter(c *gin.Context, limit int) (models.SessionFilter, error) {
+	filter := models.SessionFilter{Limit: limit}
+
+	if value := c.Query("status"); value != "" {
+		status, err := strconv.Atoi(value)
+		if err != nil || status < 0 {
+			return filter, errors.Invalid("status", "invalid status")
+		}
+		filter.StatusFlags = status
+	}
+
+	if value := c.Query("host"); value != "" {
+		hostID, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return filter, errors.Invalid("host", "invalid host ID")
+		}
+		filter.HostID = &hostID
+	}
+
+	if value := c.Query("quiz"); value != "" {
+		quizID, err := uuid.Parse(value)
+		if err != nil {
+			return filter, errors.Invalid("quiz", "invalid quiz ID")
+		}
+		filter.QuizID = &quizID
+	}
+
+	if value := c.Query("from"); value != "" {
+		from, _, err := parseDateOrTime(value)
+		if err != nil {
+			return filter, errors.Invalid("from", "invalid from date")
+		}
+		filter.From = &from
+	}
+
+	if value := c.Query("to"); value != "" {
+		to, isDate, err := parseDateOrTime(value)
+		if err != nil {
+			return filter, errors.Invalid("to", "invalid to date")
+		}
+		// A date includes the whole day
+		if isDate {
+			to = to.AddDate(0, 0, 1)
+		}
+		filter.To = &to
+	}
+
+	sort, err := models.ParseSort(c.Query("sort"), c.Query("order"), models.SessionSortFields, models.DefaultSessionSort)
+	if err != nil {
+		return filter, err
+	}
+	filter.Sort = sort
+
+	if value := c.Query("cursor"); value != "" {
+		cursor, err := models.DecodeSessionCursor(value)
+		if err != nil {
+			return filter, err
+		}
+		filter.After = cursor
+	}
+
+	return filter, nil
+}
+
+// parseListOptions reads the page of a listing paginated by offset from the
+// query: cursor, sort (one of sortFields) and order (asc or desc)
+func parseListOptions(c *gin.Context, limit int, sortFields []string, def models.Sort) (models.ListOptions, error) {
+	opts := models.ListOptions{Limit: limit}
+
+	sort, err := models.ParseSort(c.Query("sort"), c.Query("order"), sortFields, def)
+	if err != nil {
+		return opts, err
+	}
+	opts.Sort = sort
+
+	if value := c.Query("cursor"); value != "" {
+		if opts.Offset, err = models.DecodeOffsetCursor(value); err != nil {
+			return opts, err
+		}
+	}
+
+	return opts, nil
+}
+
+// parseAuditFilter reads the audit log filter from the query:
+// actor, action, target_type, target_id, from and to (dates or RFC 3339 times)
+func parseAuditFilter(c *gin.Context, offset, limit int) (models.AuditFilter, error) {
+	filter := models.AuditFilter{
+		Action:     c.Query("action"),
+		TargetType: c.Query("target_type"),
+		TargetID:   c.Query("target_id"),
+		Offset:     offset,
+		Limit:      limit,
+	}
+
+	if value := c.Query("actor"); value != "" {
+		actorID, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return filter, errors.Invalid("actor", "invalid actor ID")
+		}
+		filter.ActorID = &actorID
+	}
+
+	if value := c.Query("from"); value != "" {
+		from, _, err := parseDateOrTime(value)
+		if err != nil {
+			return filter, errors.Invalid("from", "invalid from date")
+		}
+		filter.From = &from
+	}
+
+	if value := c.Query("to"); value != "" {
+		to, isDate, err := parseDateOrTime(value)
+		if err != nil {
+			return filter, errors.Invalid("to", "invalid to date")
+		}
+		// A date includes the whole day
+		if isDate {
+			to = to.AddDate(0, 0, 1)
+		}
+		filter.To = &to
+	}
+
+	return filter, nil
+}
+
+// parseDateOrTime parses a date such as 2025-06-22 or an RFC 3339 time
+func parseDateOrTime(value string) (time.Time, bool, error) {
+	if t, err := time.Parse(dateLayout, value); err == nil {
+		return t, true, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	return t, false, err
+}
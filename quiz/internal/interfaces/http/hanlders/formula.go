@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"bsu-quiz/quiz/internal/infra/service"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FormulaHandler serves the formula images of rendered texts, see
+// markup.Renderer. It's public, Telegram fetches the images the bot sends
+type FormulaHandler struct {
+	renderService service.RenderProvider
+}
+
+func NewFormulaHandler(renderService service.RenderProvider) *FormulaHandler {
+	return &FormulaHandler{
+		renderService: renderService,
+	}
+}
+
+// Image draws the formula in the tex query parameter as a PNG image,
+// display=1 sets it in display mode
+func (h *FormulaHandler) Image(c *gin.Context) {
+	img, err := h.renderService.FormulaImage(c, c.Query("tex"), c.Query("display") == "1")
+	if err != nil {
+		c.Error(fmt.Errorf("failed to draw formula: %w", err))
+		return
+	}
+
+	// The image only depends on the query
+	c.Header("Cache-Control", "public, max-age=31536000, immutable")
+	c.Data(http.StatusOK, "image/png", img)
+}
@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"bsu-quiz/quiz/internal/infra/health"
+	"bsu-quiz/quiz/internal/interfaces/http/dto"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HealthHandler serves the probes of the deployment
+type HealthHandler struct {
+	checkers []health.Checker
+	timeout  time.Duration
+	draining atomic.Bool
+}
+
+func NewHealthHandler(timeout time.Duration, checkers ...health.Checker) *HealthHandler {
+	return &HealthHandler{
+		checkers: checkers,
+		timeout:  timeout,
+	}
+}
+
+// Liveness reports that the process serves requests. Dependencies aren't
+// checked, a restart wouldn't bring them back
+func (h *HealthHandler) Liveness(c *gin.Context) {
+	c.JSON(http.StatusOK, dto.Health{Status: "ok"})
+}
+
+// Readiness checks Postgres and Redis. It fails with 503 when a critical
+// dependency is down or the server is shutting down, non-critical ones
+// only degrade the panel
+func (h *HealthHandler) Readiness(c *gin.Context) {
+	results := health.Run(c, h.timeout, h.checkers...)
+
+	status := "ready"
+	code := http.StatusOK
+	for _, result := range results {
+		if result.Healthy {
+			continue
+		}
+
+		if result.Critical {
+			status = "unavailable"
+			code = http.StatusServiceUnavailable
+			break
+		}
+		status = "degraded"
+	}
+
+	if h.draining.Load() {
+		status = "draining"
+		code = http.StatusServiceUnavailable
+	}
+
+	c.JSON(code, dto.Health{
+		Status: status,
+		Checks: dto.NewHealthChecks(results),
+	})
+}
+
+// Drain makes Readiness fail, so no new traffic is sent while requests in
+// flight finish
+func (h *HealthHandler) Drain() {
+	h.draining.Store(true)
+}
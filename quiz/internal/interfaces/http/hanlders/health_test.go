@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"bsu-quiz/quiz/internal/domain/errors"
+	"bsu-quiz/quiz/internal/infra/health"
+	"bsu-quiz/quiz/internal/interfaces/http/dto"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type fakeChecker struct {
+	name     string
+	critical bool
+	down     bool
+}
+
+func (c *fakeChecker) Name() string {
+	return c.name
+}
+
+func (c *fakeChecker) Critical() bool {
+	return c.critical
+}
+
+func (c *fakeChecker) Check(_ context.Context) error {
+	if c.down {
+		return errors.New("connection refused")
+	}
+	return nil
+}
+
+// probe serves the handler and decodes its body
+func probe(t *testing.T, handler gin.HandlerFunc) (int, dto.Health) {
+	t.Helper()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/probe", handler)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/probe", nil))
+
+	var body dto.Health
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("failed to decode the body: %v", err)
+	}
+
+	return rec.Code, body
+}
+
+func TestReadiness(t *testing.T) {
+	tests := []struct {
+		name       string
+		critical   bool
+		optional   bool
+		draining   bool
+		wantCode   int
+		wantStatus string
+	}{
+		{name: "ready", wantCode: http.StatusOK, wantStatus: "ready"},
+		{name: "optional dependency down", optional: true, wantCode: http.StatusOK, wantStatus: "degraded"},
+		{name: "critical dependency down", critical: true, optional: true, wantCode: http.StatusServiceUnavailable, wantStatus: "unavailable"},
+		{name: "draining", draining: true, wantCode: http.StatusServiceUnavailable, wantStatus: "draining"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHealthHandler(time.Second,
+				&fakeChecker{name: health.PostgresName, critical: true, down: tt.critical},
+				&fakeChecker{name: "mail", down: tt.optional},
+			)
+			if tt.draining {
+				h.Drain()
+			}
+
+			code, body := probe(t, h.Readiness)
+
+			if code != tt.wantCode || body.Status != tt.wantStatus {
+				t.Errorf("got %d %s, want %d %s", code, body.Status, tt.wantCode, tt.wantStatus)
+			}
+			if len(body.Checks) != 2 {
+				t.Fatalf("got %d checks, want 2", len(body.Checks))
+			}
+			if postgres := body.Checks[0]; postgres.Healthy == tt.critical || (postgres.Error != "") != tt.critical {
+				t.Errorf("got check %+v", postgres)
+			}
+		})
+	}
+}
+
+// Liveness doesn't depend on dependencies or draining
+func TestLiveness(t *testing.T) {
+	h := NewHealthHandler(time.Second, &fakeChecker{name: health.PostgresName, critical: true, down: true})
+	h.Drain()
+
+	code, body := probe(t, h.Liveness)
+
+	if code != http.StatusOK || body.Status != "ok" || len(body.Checks) != 0 {
+		t.Errorf("got %d %+v, want 200 ok without checks", code, body)
+	}
+}
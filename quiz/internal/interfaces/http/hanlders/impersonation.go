@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"bsu-quiz/quiz/internal/domain/errors"
+	"bsu-quiz/quiz/internal/infra/service"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ImpersonationHandler struct {
+	impersonationService service.ImpersonationProvider
+}
+
+func NewImpersonationHandler(impersonationService service.ImpersonationProvider) *ImpersonationHandler {
+	return &ImpersonationHandler{
+		impersonationService: impersonationService,
+	}
+}
+
+// Start lets an admin view the panel as the user
+func (h *ImpersonationHandler) Start(c *gin.Context) {
+	// Get admin ID from context
+	adminID, _ := c.Get("userID")
+
+	// Parse user ID from request
+	userID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.Error(errors.Invalid("id", "invalid user ID"))
+		return
+	}
+
+	_, err = h.impersonationService.Start(c, adminID.(int64), userID)
+	if err != nil {
+		c.Error(fmt.Errorf("failed to start impersonation: %w", err))
+		return
+	}
+
+	// Show the quizzes the user sees
+	c.Redirect(http.StatusSeeOther, "/quizzes/")
+}
+
+// Stop returns the admin to their own account
+func (h *ImpersonationHandler) Stop(c *gin.Context) {
+	// Get admin ID from context, it's only set while impersonating
+	adminID, ok := c.Get("impersonatorID")
+	if !ok {
+		c.Redirect(http.StatusSeeOther, "/dashboard")
+		return
+	}
+
+	err := h.impersonationService.Stop(c, adminID.(int64))
+	if err != nil {
+		c.Error(fmt.Errorf("failed to stop impersonation: %w", err))
+		return
+	}
+
+	c.Redirect(http.StatusSeeOther, "/admin/users")
+}
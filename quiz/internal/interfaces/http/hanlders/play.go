@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"bsu-quiz/quiz/internal/domain/errors"
+	"bsu-quiz/quiz/internal/domain/models"
+	"bsu-quiz/quiz/internal/infra/service"
+	"bsu-quiz/quiz/internal/interfaces/http/dto"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PlayHandler serves players of the Telegram Web App, they're signed in
+// by middleware.TelegramWebApp
+type PlayHandler struct {
+	authService    service.AuthProvider
+	sessionService service.SessionProvider
+}
+
+func NewPlayHandler(authService service.AuthProvider, sessionService service.SessionProvider) *PlayHandler {
+	return &PlayHandler{
+		authService:    authService,
+		sessionService: sessionService,
+	}
+}
+
+// Me returns the BSU login the Telegram account of the player is linked to
+func (h *PlayHandler) Me(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	tgUser, _ := c.Get("telegramUser")
+
+	user, err := h.authService.GetUserByID(c, userID.(int64))
+	if err != nil {
+		c.Error(fmt.Errorf("failed to get player: %w", err))
+		return
+	}
+
+	if user == nil {
+		c.Error(errors.NotFound("user"))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewPlayer(user, tgUser.(*models.TelegramUser)))
+}
+
+// JoinSession adds the player to the session with the join code
+func (h *PlayHandler) JoinSession(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	var req dto.JoinSession
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(errors.Validation("invalid request body: " + err.Error()))
+		return
+	}
+
+	participant, err := h.sessionService.JoinSession(c, req.JoinCode, userID.(int64))
+	if err != nil {
+		c.Error(fmt.Errorf("failed to join session: %w", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewParticipant(participant))
+}
@@ -0,0 +1,177 @@
+package handlers
+
+import (
+	"bsu-quiz/quiz/internal/domain/errors"
+	"bsu-quiz/quiz/internal/infra/service"
+	"bsu-quiz/quiz/internal/interfaces/http/dto"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// PlayFeedbackHandler shows players the explanations and option feedback
+// of the questions they answered, signed in by middleware.TelegramWebApp.
+// Texts are rendered for the Web App and for the bot, see dto.QuestionReview
+type PlayFeedbackHandler struct {
+	sessionService service.SessionProvider
+	renderService  service.RenderProvider
+	interval       time.Duration
+	log            *slog.Logger
+
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func NewPlayFeedbackHandler(
+	sessionService service.SessionProvider,
+	renderService service.RenderProvider,
+	interval time.Duration,
+	log *slog.Logger,
+) *PlayFeedbackHandler {
+	return &PlayFeedbackHandler{
+		sessionService: sessionService,
+		renderService:  renderService,
+		interval:       interval,
+		log:            log,
+		done:           make(chan struct{}),
+	}
+}
+
+// Close ends the open streams, the server waits for them on shutdown
+func (h *PlayFeedbackHandler) Close() {
+	h.closeOnce.Do(func() {
+		close(h.done)
+	})
+}
+
+// Review returns the questions whose feedback is revealed to the player so
+// far, once the session is finished it's the review of every question
+func (h *PlayFeedbackHandler) Review(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	sessionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.Error(errors.Invalid("id", "invalid session ID"))
+		return
+	}
+
+	feedback, err := h.sessionService.GetPlayerFeedback(c, sessionID, userID.(int64))
+	if err != nil {
+		c.Error(fmt.Errorf("failed to get feedback: %w", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewPlayerFeedback(feedback, h.renderService.Render))
+}
+
+// QuestionFeedback returns the explanation and option feedback of a closed
+// question with the player's answer
+func (h *PlayFeedbackHandler) QuestionFeedback(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	sessionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.Error(errors.Invalid("id", "invalid session ID"))
+		return
+	}
+
+	questionID, err := uuid.Parse(c.Param("questionId"))
+	if err != nil {
+		c.Error(errors.Invalid("questionId", "invalid question ID"))
+		return
+	}
+
+	feedback, err := h.sessionService.GetQuestionFeedback(c, sessionID, questionID, userID.(int64))
+	if err != nil {
+		c.Error(fmt.Errorf("failed to get feedback: %w", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewQuestionReview(*feedback, h.renderService.Render))
+}
+
+// Events polls the session every interval and sends a "feedback" event with
+// the review of every question as soon as its feedback is revealed, that is
+// when the question closes or, in exam mode, when the session ends. A
+// "finished" event ends the stream with the session, an "unavailable" event
+// tells the app the session can't be followed anymore
+func (h *PlayFeedbackHandler) Events(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	sessionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.Error(errors.Invalid("id", "invalid session ID"))
+		return
+	}
+
+	// Errors before the stream starts are rendered as usual
+	feedback, err := h.sessionService.GetPlayerFeedback(c, sessionID, userID.(int64))
+	if err != nil {
+		c.Error(fmt.Errorf("failed to get feedback: %w", err))
+		return
+	}
+
+	send := openEventStream(c)
+
+	ticker := time.NewTicker(h.interval)
+	defer ticker.Stop()
+
+	sent := make(map[uuid.UUID]bool)
+	lastWrite := time.Now()
+	for {
+		for _, review := range feedback.Questions {
+			if sent[review.QuestionID] {
+				continue
+			}
+
+			payload, err := json.Marshal(dto.NewQuestionReview(review, h.renderService.Render))
+			if err != nil {
+				h.log.Error("failed to encode question feedback", slog.String("error", err.Error()))
+				send("event: unavailable\ndata: {}\n\n")
+				return
+			}
+
+			if !send("event: feedback\ndata: " + string(payload) + "\n\n") {
+				return
+			}
+			sent[review.QuestionID] = true
+			lastWrite = time.Now()
+		}
+
+		if feedback.Finished {
+			send("event: finished\ndata: {}\n\n")
+			return
+		}
+
+		if time.Since(lastWrite) >= eventStreamPingInterval {
+			if !send(": ping\n\n") {
+				return
+			}
+			lastWrite = time.Now()
+		}
+
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case <-h.done:
+			return
+		case <-ticker.C:
+		}
+
+		feedback, err = h.sessionService.GetPlayerFeedback(c, sessionID, userID.(int64))
+		if err != nil {
+			h.log.Warn("player feedback stream stopped",
+				slog.String("session_id", sessionID.String()),
+				slog.String("error", err.Error()),
+			)
+			send("event: unavailable\ndata: {}\n\n")
+			return
+		}
+	}
+}
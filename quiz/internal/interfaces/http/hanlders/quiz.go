@@ -1,22 +1,30 @@
 package handlers
 
 import (
+	"bsu-quiz/quiz/internal/domain/errors"
 	"bsu-quiz/quiz/internal/domain/models"
 	"bsu-quiz/quiz/internal/infra/service"
+	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
 
 type QuizHandler struct {
-	quizService service.QuizProvider
+	quizService   service.QuizProvider
+	renderService service.RenderProvider
 }
 
-func NewQuizHandler(quizService service.QuizProvider) *QuizHandler {
+func NewQuizHandler(
+	quizService service.QuizProvider,
+	renderService service.RenderProvider,
+) *QuizHandler {
 	return &QuizHandler{
-		quizService: quizService,
+		quizService:   quizService,
+		renderService: renderService,
 	}
 }
 
@@ -25,33 +33,31 @@ func (h *QuizHandler) ListQuizzes(c *gin.Context) {
 	// Get user ID from context
 	userID, _ := c.Get("userID")
 	
-	// Get page parameters
-	pageStr := c.DefaultQuery("page", "1")
-	page, _ := strconv.Atoi(pageStr)
-	if page < 1 {
-		page = 1
+	// Get cursor and sort parameters, recently edited quizzes come first
+	defaultSort := models.Sort{Field: "updated_at", Direction: models.SortDesc}
+	opts, err := parseListOptions(c, 10, models.QuizSortFields, defaultSort)
+	if err != nil {
+		c.Error(fmt.Errorf("invalid query: %w", err))
+		return
 	}
-	limit := 10
-	offset := (page - 1) * limit
 	
 	// Get quizzes
-	quizzes, err := h.quizService.ListQuizzes(c, userID.(int64), offset, limit)
+	quizzes, err := h.quizService.ListQuizzes(c, userID.(int64), opts)
 	if err != nil {
-		c.HTML(http.StatusInternalServerError, "error.html", gin.H{
-			"ErrorMessage": "Failed to fetch quizzes: " + err.Error(),
-		})
+		c.Error(fmt.Errorf("failed to fetch quizzes: %w", err))
 		return
 	}
 	
 	username, _ := c.Get("username")
 	
-	c.HTML(http.StatusOK, "quiz/list.html", gin.H{
+	render(c, "quiz/list.html", gin.H{
 		"Title":      "My Quizzes",
 		"Username":   username,
-		"Quizzes":    quizzes,
+		"Quizzes":    quizzes.Items,
 		"CurrentNav": "quizzes",
-		"Page":       page,
-		"CurrentTime": "2025-06-22 12:51:00", // Using provided timestamp
+		"List":       quizzes,
+		"Query":      c.Request.URL.Query(),
+		"Sort":       opts.Sort,
 	})
 }
 
@@ -59,11 +65,10 @@ func (h *QuizHandler) ListQuizzes(c *gin.Context) {
 func (h *QuizHandler) NewQuizForm(c *gin.Context) {
 	username, _ := c.Get("username")
 	
-	c.HTML(http.StatusOK, "quiz/new.html", gin.H{
+	render(c, "quiz/new.html", gin.H{
 		"Title":      "Create New Quiz",
 		"Username":   username,
 		"CurrentNav": "quizzes",
-		"CurrentTime": "2025-06-22 12:51:00", // Using provided timestamp
 	})
 }
 
@@ -83,9 +88,7 @@ func (h *QuizHandler) CreateQuiz(c *gin.Context) {
 	
 	quizID, err := h.quizService.CreateQuiz(c, quiz)
 	if err != nil {
-		c.HTML(http.StatusInternalServerError, "error.html", gin.H{
-			"ErrorMessage": "Failed to create quiz: " + err.Error(),
-		})
+		c.Error(fmt.Errorf("failed to create quiz: %w", err))
 		return
 	}
 	
@@ -101,44 +104,44 @@ func (h *QuizHandler) EditQuizForm(c *gin.Context) {
 	// Parse quiz ID from request
 	quizID, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		c.HTML(http.StatusBadRequest, "error.html", gin.H{
-			"ErrorMessage": "Invalid quiz ID",
-		})
+		c.Error(errors.Invalid("id", "invalid quiz ID"))
 		return
 	}
 	
 	// Get quiz
 	quiz, err := h.quizService.GetQuiz(c, quizID)
 	if err != nil {
-		c.HTML(http.StatusInternalServerError, "error.html", gin.H{
-			"ErrorMessage": "Failed to fetch quiz: " + err.Error(),
-		})
+		c.Error(fmt.Errorf("failed to fetch quiz: %w", err))
 		return
 	}
 	
 	if quiz == nil {
-		c.HTML(http.StatusNotFound, "error.html", gin.H{
-			"ErrorMessage": "Quiz not found",
-		})
+		c.Error(errors.NotFound("quiz"))
 		return
 	}
 	
-	// Check if user is the owner
-	if quiz.UserID != userID.(int64) {
-		c.HTML(http.StatusForbidden, "error.html", gin.H{
-			"ErrorMessage": "You don't have permission to edit this quiz",
-		})
+	// Check if user is an owner or a collaborator
+	role, err := h.quizService.GetQuizRole(c, quizID, userID.(int64))
+	if err != nil {
+		c.Error(fmt.Errorf("failed to fetch quiz: %w", err))
+		return
+	}
+	
+	if !role.CanView() {
+		c.Error(errors.Forbidden("you don't have permission to edit this quiz"))
 		return
 	}
 	
 	username, _ := c.Get("username")
 	
-	c.HTML(http.StatusOK, "quiz/edit.html", gin.H{
+	render(c, "quiz/edit.html", gin.H{
 		"Title":      "Edit Quiz: " + quiz.Title,
 		"Username":   username,
 		"Quiz":       quiz,
+		"Role":       role,
+		"CanEdit":    role.CanEdit(),
+		"CanManage":  role.CanManage(),
 		"CurrentNav": "quizzes",
-		"CurrentTime": "2025-06-22 12:51:00", // Using provided timestamp
 	})
 }
 
@@ -150,7 +153,7 @@ func (h *QuizHandler) UpdateQuiz(c *gin.Context) {
 	// Parse quiz ID from request
 	quizID, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid quiz ID"})
+		c.Error(errors.Invalid("id", "invalid quiz ID"))
 		return
 	}
 	
@@ -165,7 +168,7 @@ func (h *QuizHandler) UpdateQuiz(c *gin.Context) {
 	// Update quiz
 	err = h.quizService.UpdateQuiz(c, quiz)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update quiz: " + err.Error()})
+		c.Error(fmt.Errorf("failed to update quiz: %w", err))
 		return
 	}
 	
@@ -181,14 +184,14 @@ func (h *QuizHandler) DeleteQuiz(c *gin.Context) {
 	// Parse quiz ID from request
 	quizID, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid quiz ID"})
+		c.Error(errors.Invalid("id", "invalid quiz ID"))
 		return
 	}
 	
 	// Delete quiz
 	err = h.quizService.DeleteQuiz(c, quizID, userID.(int64))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete quiz: " + err.Error()})
+		c.Error(fmt.Errorf("failed to delete quiz: %w", err))
 		return
 	}
 	
@@ -196,21 +199,202 @@ func (h *QuizHandler) DeleteQuiz(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"status": "success"})
 }
 
+// ChangeStatus handles publishing, scheduling, archiving and unpublishing a quiz
+func (h *QuizHandler) ChangeStatus(c *gin.Context) {
+	// Get user ID from context
+	userID, _ := c.Get("userID")
+	
+	// Parse quiz ID from request
+	quizID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.Error(errors.Invalid("id", "invalid quiz ID"))
+		return
+	}
+	
+	status := models.QuizStatus(c.PostForm("status"))
+	
+	// Parse publish time for scheduled quizzes
+	var publishAt *time.Time
+	if value := c.PostForm("publish_at"); value != "" {
+		at, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			c.Error(errors.Invalid("publish_at", "invalid publish time, expected RFC 3339"))
+			return
+		}
+		publishAt = &at
+	}
+	
+	err = h.quizService.ChangeQuizStatus(c, quizID, userID.(int64), status, publishAt)
+	if err != nil {
+		c.Error(fmt.Errorf("failed to change quiz status: %w", err))
+		return
+	}
+	
+	c.JSON(http.StatusOK, gin.H{"status": "success"})
+}
+
+// Collaborator AJAX endpoints
+
+// ListCollaborators returns the collaborators of a quiz
+func (h *QuizHandler) ListCollaborators(c *gin.Context) {
+	// Get user ID from context
+	userID, _ := c.Get("userID")
+	
+	// Parse quiz ID from request
+	quizID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.Error(errors.Invalid("id", "invalid quiz ID"))
+		return
+	}
+	
+	collaborators, err := h.quizService.ListCollaborators(c, quizID, userID.(int64))
+	if err != nil {
+		c.Error(fmt.Errorf("failed to fetch collaborators: %w", err))
+		return
+	}
+	
+	c.JSON(http.StatusOK, gin.H{"collaborators": collaborators})
+}
+
+// InviteCollaborator invites a user by login or changes their role
+func (h *QuizHandler) InviteCollaborator(c *gin.Context) {
+	// Get user ID from context
+	userID, _ := c.Get("userID")
+	
+	// Parse quiz ID from request
+	quizID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.Error(errors.Invalid("id", "invalid quiz ID"))
+		return
+	}
+	
+	login := c.PostForm("login")
+	role := models.QuizRole(c.PostForm("role"))
+	
+	err = h.quizService.InviteCollaborator(c, quizID, userID.(int64), login, role)
+	if err != nil {
+		c.Error(fmt.Errorf("failed to invite collaborator: %w", err))
+		return
+	}
+	
+	c.JSON(http.StatusOK, gin.H{"status": "success"})
+}
+
+// RemoveCollaborator removes a collaborator from a quiz
+func (h *QuizHandler) RemoveCollaborator(c *gin.Context) {
+	// Get user ID from context
+	userID, _ := c.Get("userID")
+	
+	// Parse quiz ID from request
+	quizID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.Error(errors.Invalid("id", "invalid quiz ID"))
+		return
+	}
+	
+	// Parse collaborator ID from request
+	collaboratorID, err := strconv.ParseInt(c.Param("userId"), 10, 64)
+	if err != nil {
+		c.Error(errors.Invalid("userId", "invalid user ID"))
+		return
+	}
+	
+	err = h.quizService.RemoveCollaborator(c, quizID, userID.(int64), collaboratorID)
+	if err != nil {
+		c.Error(fmt.Errorf("failed to remove collaborator: %w", err))
+		return
+	}
+	
+	c.JSON(http.StatusOK, gin.H{"status": "success"})
+}
+
+// ExportQuiz downloads a quiz with its questions and options as JSON
+func (h *QuizHandler) ExportQuiz(c *gin.Context) {
+	// Get user ID from context
+	userID, _ := c.Get("userID")
+	
+	// Parse quiz ID from request
+	quizID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.Error(errors.Invalid("id", "invalid quiz ID"))
+		return
+	}
+	
+	// Export quiz
+	quiz, err := h.quizService.ExportQuiz(c, quizID, userID.(int64))
+	if err != nil {
+		c.Error(fmt.Errorf("failed to export quiz: %w", err))
+		return
+	}
+	
+	c.Header("Content-Disposition", "attachment; filename=quiz-"+quizID.String()+".json")
+	c.JSON(http.StatusOK, quiz)
+}
+
+// ImportQuiz creates a new quiz from an exported JSON file
+func (h *QuizHandler) ImportQuiz(c *gin.Context) {
+	// Get user ID from context
+	userID, _ := c.Get("userID")
+	
+	// Parse request body
+	var quiz models.Quiz
+	if err := c.ShouldBindJSON(&quiz); err != nil {
+		c.Error(errors.Validation("invalid request body: " + err.Error()))
+		return
+	}
+	
+	// The importing user becomes the owner
+	quiz.UserID = userID.(int64)
+	
+	// Import quiz
+	quizID, err := h.quizService.ImportQuiz(c, &quiz)
+	if err != nil {
+		c.Error(fmt.Errorf("failed to import quiz: %w", err))
+		return
+	}
+	
+	// Return new quiz ID
+	c.JSON(http.StatusOK, gin.H{"id": quizID})
+}
+
+// Preview renders Markdown and LaTeX of a question or option text for the editor
+func (h *QuizHandler) Preview(c *gin.Context) {
+	// Parse request body
+	var req struct {
+		Text string `json:"text"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(errors.Validation("invalid request body: " + err.Error()))
+		return
+	}
+	
+	rendered, err := h.renderService.Preview(c, req.Text)
+	if err != nil {
+		c.Error(fmt.Errorf("failed to render text: %w", err))
+		return
+	}
+	
+	c.JSON(http.StatusOK, rendered)
+}
+
 // Question AJAX endpoints
 
 // AddQuestion handles adding a question to a quiz
 func (h *QuizHandler) AddQuestion(c *gin.Context) {
+	// Get user ID from context
+	userID, _ := c.Get("userID")
+	
 	// Parse quiz ID from request
 	quizID, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid quiz ID"})
+		c.Error(errors.Invalid("id", "invalid quiz ID"))
 		return
 	}
 	
 	// Parse request body
 	var question models.Question
-	if err := c.BindJSON(&question); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+	if err := c.ShouldBindJSON(&question); err != nil {
+		c.Error(errors.Validation("invalid request body: " + err.Error()))
 		return
 	}
 	
@@ -218,9 +402,9 @@ func (h *QuizHandler) AddQuestion(c *gin.Context) {
 	question.QuizID = quizID
 	
 	// Add question
-	questionID, err := h.quizService.AddQuestion(c, &question)
+	questionID, err := h.quizService.AddQuestion(c, userID.(int64), &question)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add question: " + err.Error()})
+		c.Error(fmt.Errorf("failed to add question: %w", err))
 		return
 	}
 	
@@ -231,17 +415,20 @@ func (h *QuizHandler) AddQuestion(c *gin.Context) {
 
 // UpdateQuestion handles updating a question
 func (h *QuizHandler) UpdateQuestion(c *gin.Context) {
+	// Get user ID from context
+	userID, _ := c.Get("userID")
+	
 	// Parse question ID from request
 	questionID, err := uuid.Parse(c.Param("questionId"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid question ID"})
+		c.Error(errors.Invalid("questionId", "invalid question ID"))
 		return
 	}
 	
 	// Parse request body
 	var question models.Question
-	if err := c.BindJSON(&question); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+	if err := c.ShouldBindJSON(&question); err != nil {
+		c.Error(errors.Validation("invalid request body: " + err.Error()))
 		return
 	}
 	
@@ -249,9 +436,9 @@ func (h *QuizHandler) UpdateQuestion(c *gin.Context) {
 	question.ID = questionID
 	
 	// Update question
-	err = h.quizService.UpdateQuestion(c, &question)
+	err = h.quizService.UpdateQuestion(c, userID.(int64), &question)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update question: " + err.Error()})
+		c.Error(fmt.Errorf("failed to update question: %w", err))
 		return
 	}
 	
@@ -261,17 +448,20 @@ func (h *QuizHandler) UpdateQuestion(c *gin.Context) {
 
 // DeleteQuestion handles deleting a question
 func (h *QuizHandler) DeleteQuestion(c *gin.Context) {
+	// Get user ID from context
+	userID, _ := c.Get("userID")
+	
 	// Parse question ID from request
 	questionID, err := uuid.Parse(c.Param("questionId"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid question ID"})
+		c.Error(errors.Invalid("questionId", "invalid question ID"))
 		return
 	}
 	
 	// Delete question
-	err = h.quizService.DeleteQuestion(c, questionID)
+	err = h.quizService.DeleteQuestion(c, userID.(int64), questionID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete question: " + err.Error()})
+		c.Error(fmt.Errorf("failed to delete question: %w", err))
 		return
 	}
 	
@@ -283,17 +473,20 @@ func (h *QuizHandler) DeleteQuestion(c *gin.Context) {
 
 // AddOption handles adding an option to a question
 func (h *QuizHandler) AddOption(c *gin.Context) {
+	// Get user ID from context
+	userID, _ := c.Get("userID")
+	
 	// Parse question ID from request
 	questionID, err := uuid.Parse(c.Param("questionId"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid question ID"})
+		c.Error(errors.Invalid("questionId", "invalid question ID"))
 		return
 	}
 	
 	// Parse request body
 	var option models.Option
-	if err := c.BindJSON(&option); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+	if err := c.ShouldBindJSON(&option); err != nil {
+		c.Error(errors.Validation("invalid request body: " + err.Error()))
 		return
 	}
 	
@@ -301,9 +494,9 @@ func (h *QuizHandler) AddOption(c *gin.Context) {
 	option.QuestionID = questionID
 	
 	// Add option
-	optionID, err := h.quizService.AddOption(c, &option)
+	optionID, err := h.quizService.AddOption(c, userID.(int64), &option)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add option: " + err.Error()})
+		c.Error(fmt.Errorf("failed to add option: %w", err))
 		return
 	}
 	
@@ -314,17 +507,20 @@ func (h *QuizHandler) AddOption(c *gin.Context) {
 
 // UpdateOption handles updating an option
 func (h *QuizHandler) UpdateOption(c *gin.Context) {
+	// Get user ID from context
+	userID, _ := c.Get("userID")
+	
 	// Parse option ID from request
 	optionID, err := uuid.Parse(c.Param("optionId"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid option ID"})
+		c.Error(errors.Invalid("optionId", "invalid option ID"))
 		return
 	}
 	
 	// Parse request body
 	var option models.Option
-	if err := c.BindJSON(&option); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+	if err := c.ShouldBindJSON(&option); err != nil {
+		c.Error(errors.Validation("invalid request body: " + err.Error()))
 		return
 	}
 	
@@ -332,9 +528,9 @@ func (h *QuizHandler) UpdateOption(c *gin.Context) {
 	option.ID = optionID
 	
 	// Update option
-	err = h.quizService.UpdateOption(c, &option)
+	err = h.quizService.UpdateOption(c, userID.(int64), &option)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update option: " + err.Error()})
+		c.Error(fmt.Errorf("failed to update option: %w", err))
 		return
 	}
 	
@@ -344,17 +540,20 @@ func (h *QuizHandler) UpdateOption(c *gin.Context) {
 
 // DeleteOption handles deleting an option
 func (h *QuizHandler) DeleteOption(c *gin.Context) {
+	// Get user ID from context
+	userID, _ := c.Get("userID")
+	
 	// Parse option ID from request
 	optionID, err := uuid.Parse(c.Param("optionId"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid option ID"})
+		c.Error(errors.Invalid("optionId", "invalid option ID"))
 		return
 	}
 	
 	// Delete option
-	err = h.quizService.DeleteOption(c, optionID)
+	err = h.quizService.DeleteOption(c, userID.(int64), optionID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete option: " + err.Error()})
+		c.Error(fmt.Errorf("failed to delete option: %w", err))
 		return
 	}
 	
@@ -0,0 +1,29 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// serverTimeLayout is how the layout footer shows the server time
+const serverTimeLayout = "2006-01-02 15:04:05"
+
+// render renders a page adding what the layout needs from the request,
+// such as the impersonation banner and the roles the navigation depends on
+func render(c *gin.Context, name string, data gin.H) {
+	if _, ok := data["CurrentTime"]; !ok {
+		data["CurrentTime"] = time.Now().Format(serverTimeLayout)
+	}
+
+	if impersonation, ok := c.Get("impersonation"); ok {
+		data["Impersonation"] = impersonation
+	}
+
+	if roles, ok := c.Get("roles"); ok {
+		data["Roles"] = roles
+	}
+
+	c.HTML(http.StatusOK, name, data)
+}
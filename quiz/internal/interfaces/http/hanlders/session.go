@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"bsu-quiz/quiz/internal/domain/errors"
+	"bsu-quiz/quiz/internal/infra/service"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type SessionHandler struct {
+	sessionService service.SessionProvider
+}
+
+func NewSessionHandler(sessionService service.SessionProvider) *SessionHandler {
+	return &SessionHandler{
+		sessionService: sessionService,
+	}
+}
+
+// CreateSession starts a new game session from a quiz
+func (h *SessionHandler) CreateSession(c *gin.Context) {
+	// Get user ID from context
+	userID, _ := c.Get("userID")
+
+	// Parse quiz ID from form
+	quizID, err := uuid.Parse(c.PostForm("quiz_id"))
+	if err != nil {
+		c.Error(errors.Invalid("quiz_id", "invalid quiz ID"))
+		return
+	}
+
+	examMode, _ := strconv.ParseBool(c.DefaultPostForm("exam_mode", "false"))
+
+	session, err := h.sessionService.CreateSession(c, quizID, userID.(int64), examMode)
+	if err != nil {
+		c.Error(fmt.Errorf("failed to create session: %w", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, session)
+}
+
+// Results returns the participants of a session with their scores and answers
+func (h *SessionHandler) Results(c *gin.Context) {
+	// Get user ID from context
+	userID, _ := c.Get("userID")
+
+	// Parse session ID from request
+	sessionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.Error(errors.Invalid("id", "invalid session ID"))
+		return
+	}
+
+	session, err := h.sessionService.GetSessionResults(c, sessionID, userID.(int64))
+	if err != nil {
+		c.Error(fmt.Errorf("failed to fetch results: %w", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, session)
+}
+
+// QuestionFeedback returns the explanation and option feedback of a closed question
+func (h *SessionHandler) QuestionFeedback(c *gin.Context) {
+	// Get user ID from context
+	userID, _ := c.Get("userID")
+
+	// Parse session ID from request
+	sessionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.Error(errors.Invalid("id", "invalid session ID"))
+		return
+	}
+
+	// Parse question ID from request
+	questionID, err := uuid.Parse(c.Param("questionId"))
+	if err != nil {
+		c.Error(errors.Invalid("questionId", "invalid question ID"))
+		return
+	}
+
+	// Get feedback
+	feedback, err := h.sessionService.GetQuestionFeedback(c, sessionID, questionID, userID.(int64))
+	if err != nil {
+		c.Error(fmt.Errorf("failed to get feedback: %w", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, feedback)
+}
+
+// Review returns the post-session review for a participant
+func (h *SessionHandler) Review(c *gin.Context) {
+	// Get user ID from context
+	userID, _ := c.Get("userID")
+
+	// Parse session ID from request
+	sessionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.Error(errors.Invalid("id", "invalid session ID"))
+		return
+	}
+
+	// Parse participant ID from request
+	participantID, err := uuid.Parse(c.Param("participantId"))
+	if err != nil {
+		c.Error(errors.Invalid("participantId", "invalid participant ID"))
+		return
+	}
+
+	// Get review
+	review, err := h.sessionService.GetSessionReview(c, sessionID, participantID, userID.(int64))
+	if err != nil {
+		c.Error(fmt.Errorf("failed to get review: %w", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"questions": review})
+}
+
+// DeleteSession moves a session to the trash
+func (h *SessionHandler) DeleteSession(c *gin.Context) {
+	// Get user ID from context
+	userID, _ := c.Get("userID")
+
+	// Parse session ID from request
+	sessionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.Error(errors.Invalid("id", "invalid session ID"))
+		return
+	}
+
+	err = h.sessionService.DeleteSession(c, sessionID, userID.(int64))
+	if err != nil {
+		c.Error(fmt.Errorf("failed to delete session: %w", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "success"})
+}
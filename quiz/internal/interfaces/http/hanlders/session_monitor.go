@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"bsu-quiz/quiz/internal/domain/errors"
+	"bsu-quiz/quiz/internal/infra/service"
+	"bsu-quiz/quiz/internal/interfaces/http/dto"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// SessionMonitorHandler streams snapshots of a live session to the admin
+// monitor as server-sent events
+type SessionMonitorHandler struct {
+	sessionService service.SessionProvider
+	interval       time.Duration
+	log            *slog.Logger
+
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func NewSessionMonitorHandler(sessionService service.SessionProvider, interval time.Duration, log *slog.Logger) *SessionMonitorHandler {
+	return &SessionMonitorHandler{
+		sessionService: sessionService,
+		interval:       interval,
+		log:            log,
+		done:           make(chan struct{}),
+	}
+}
+
+// Close ends the open streams, the server waits for them on shutdown
+func (h *SessionMonitorHandler) Close() {
+	h.closeOnce.Do(func() {
+		close(h.done)
+	})
+}
+
+// Events polls the session every interval and sends a "snapshot" event
+// whenever it changes. The stream ends once the session is finished, an
+// "unavailable" event tells the page the session can't be followed anymore
+func (h *SessionMonitorHandler) Events(c *gin.Context) {
+	// Parse session ID from request
+	sessionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.Error(errors.Invalid("id", "invalid session ID"))
+		return
+	}
+
+	// Get user ID from context
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.Error(errors.Unauthorized("unauthorized"))
+		return
+	}
+
+	// Errors before the stream starts are rendered as usual
+	monitor, err := h.sessionService.MonitorSession(c, sessionID, userID.(int64))
+	if err != nil {
+		c.Error(fmt.Errorf("failed to fetch session: %w", err))
+		return
+	}
+
+	send := openEventStream(c)
+
+	ticker := time.NewTicker(h.interval)
+	defer ticker.Stop()
+
+	var last []byte
+	lastWrite := time.Now()
+	for {
+		snapshot, err := json.Marshal(dto.NewSessionMonitor(monitor))
+		if err != nil {
+			h.log.Error("failed to encode session snapshot", slog.String("error", err.Error()))
+			send("event: unavailable\ndata: {}\n\n")
+			return
+		}
+
+		switch {
+		case !bytes.Equal(snapshot, last):
+			if !send("event: snapshot\ndata: " + string(snapshot) + "\n\n") {
+				return
+			}
+			last = snapshot
+			lastWrite = time.Now()
+		case time.Since(lastWrite) >= eventStreamPingInterval:
+			if !send(": ping\n\n") {
+				return
+			}
+			lastWrite = time.Now()
+		}
+
+		if monitor.Session.IsFinished() {
+			return
+		}
+
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case <-h.done:
+			return
+		case <-ticker.C:
+		}
+
+		monitor, err = h.sessionService.MonitorSession(c, sessionID, userID.(int64))
+		if err != nil {
+			h.log.Warn("session monitor stopped",
+				slog.String("session_id", sessionID.String()),
+				slog.String("error", err.Error()),
+			)
+			send("event: unavailable\ndata: {}\n\n")
+			return
+		}
+	}
+}
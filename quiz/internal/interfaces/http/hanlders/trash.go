@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"bsu-quiz/quiz/internal/domain/errors"
+	"bsu-quiz/quiz/internal/infra/service"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type TrashHandler struct {
+	trashService service.TrashProvider
+}
+
+func NewTrashHandler(trashService service.TrashProvider) *TrashHandler {
+	return &TrashHandler{
+		trashService: trashService,
+	}
+}
+
+// Trash displays deleted quizzes and sessions that can still be restored
+func (h *TrashHandler) Trash(c *gin.Context) {
+	// Get user ID from context
+	userID, _ := c.Get("userID")
+
+	// Get page parameters
+	pageStr := c.DefaultQuery("page", "1")
+	page, _ := strconv.Atoi(pageStr)
+	if page < 1 {
+		page = 1
+	}
+	limit := 10
+	offset := (page - 1) * limit
+
+	quizzes, err := h.trashService.ListDeletedQuizzes(c, userID.(int64), offset, limit)
+	if err != nil {
+		c.Error(fmt.Errorf("failed to fetch deleted quizzes: %w", err))
+		return
+	}
+
+	sessions, err := h.trashService.ListDeletedSessions(c, userID.(int64), offset, limit)
+	if err != nil {
+		c.Error(fmt.Errorf("failed to fetch deleted sessions: %w", err))
+		return
+	}
+
+	render(c, "trash.html", gin.H{
+		"Title":         "Trash",
+		"Quizzes":       quizzes,
+		"Sessions":      sessions,
+		"RetentionDays": int(h.trashService.Retention().Hours() / 24),
+		"Page":          page,
+		"CurrentNav":    "trash",
+	})
+}
+
+// RestoreQuiz moves a quiz out of the trash
+func (h *TrashHandler) RestoreQuiz(c *gin.Context) {
+	// Get user ID from context
+	userID, _ := c.Get("userID")
+
+	// Parse quiz ID from request
+	quizID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.Error(errors.Invalid("id", "invalid quiz ID"))
+		return
+	}
+
+	err = h.trashService.RestoreQuiz(c, quizID, userID.(int64))
+	if err != nil {
+		c.Error(fmt.Errorf("failed to restore quiz: %w", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "success"})
+}
+
+// RestoreSession moves a session out of the trash
+func (h *TrashHandler) RestoreSession(c *gin.Context) {
+	// Get user ID from context
+	userID, _ := c.Get("userID")
+
+	// Parse session ID from request
+	sessionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.Error(errors.Invalid("id", "invalid session ID"))
+		return
+	}
+
+	err = h.trashService.RestoreSession(c, sessionID, userID.(int64))
+	if err != nil {
+		c.Error(fmt.Errorf("failed to restore session: %w", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "success"})
+}
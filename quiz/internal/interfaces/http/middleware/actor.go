@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"bsu-quiz/quiz/internal/domain/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Actor stores the signed in user with the client address and user agent
+// in the request context, services read it when writing the audit log.
+// It must run after the authentication middleware that sets userID
+func Actor() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, ok := c.Get("userID")
+		if id, isID := userID.(int64); ok && isID {
+			ctx := models.WithActor(c.Request.Context(), models.Actor{
+				UserID:    id,
+				IP:        c.ClientIP(),
+				UserAgent: c.Request.UserAgent(),
+			})
+			c.Request = c.Request.WithContext(ctx)
+		}
+
+		c.Next()
+	}
+}
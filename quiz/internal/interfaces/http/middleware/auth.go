@@ -0,0 +1,91 @@
+package middleware
+
+import (
+	"bsu-quiz/quiz/internal/domain/errors"
+	"bsu-quiz/quiz/internal/domain/models"
+	"bsu-quiz/quiz/internal/infra/service"
+	"net/http"
+	"net/url"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SessionCookie holds the token of the admin panel session
+const SessionCookie = "bsu_quiz_session"
+
+// LoginPath is where pages send visitors who aren't signed in
+const LoginPath = "/login"
+
+// Auth signs the request in with the session cookie: userID, username and
+// roles (the role flags of the user) are set for handlers and the guards.
+// Pages redirect visitors without a session to the login page, other
+// requests fail with 401. It must run before Impersonation and Actor
+func Auth(authService service.AuthProvider) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token, err := c.Cookie(SessionCookie)
+		if err != nil || token == "" {
+			unauthorized(c, errors.Unauthorized("sign in first"))
+			return
+		}
+
+		user, err := authService.Authenticate(c, token)
+		if errors.Is(err, errors.ErrUnauthorized) {
+			unauthorized(c, err)
+			return
+		}
+		if err != nil {
+			c.Error(err)
+			c.Abort()
+			return
+		}
+
+		c.Set("userID", user.ID)
+		c.Set("username", user.Login)
+		c.Set("roles", user.RoleFlags)
+
+		c.Next()
+	}
+}
+
+func unauthorized(c *gin.Context, err error) {
+	if c.Request.Method == http.MethodGet && wantsHTML(c) {
+		c.Redirect(http.StatusSeeOther, LoginPath+"?next="+url.QueryEscape(c.Request.URL.RequestURI()))
+		c.Abort()
+		return
+	}
+
+	c.Error(err)
+	c.Abort()
+}
+
+// RequireRole lets the request through if the user has any of the roles,
+// it must run after Auth and Impersonation
+func RequireRole(roles ...int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		value, _ := c.Get("roles")
+		flags, _ := value.(int)
+		user := models.User{RoleFlags: flags}
+
+		if !user.IsBlocked() {
+			for _, role := range roles {
+				if user.HasRole(role) {
+					c.Next()
+					return
+				}
+			}
+		}
+
+		c.Error(errors.Forbidden("you don't have access to this page"))
+		c.Abort()
+	}
+}
+
+// RequireAdmin lets only admins through
+func RequireAdmin() gin.HandlerFunc {
+	return RequireRole(models.RoleAdmin)
+}
+
+// RequireTeacher lets teachers and admins through
+func RequireTeacher() gin.HandlerFunc {
+	return RequireRole(models.RoleTeacher, models.RoleAdmin)
+}
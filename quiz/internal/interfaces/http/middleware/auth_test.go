@@ -0,0 +1,119 @@
+package middleware
+
+import (
+	"bsu-quiz/quiz/internal/domain/errors"
+	"bsu-quiz/quiz/internal/domain/models"
+	"bsu-quiz/quiz/internal/infra/service"
+	"context"
+	"html/template"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// fakeAuthService signs in the users of its sessions
+type fakeAuthService struct {
+	service.AuthProvider
+	sessions map[string]*models.User
+}
+
+func (s *fakeAuthService) Authenticate(_ context.Context, token string) (*models.User, error) {
+	user, ok := s.sessions[token]
+	if !ok {
+		return nil, errors.Unauthorized("your session has expired, sign in again")
+	}
+	return user, nil
+}
+
+// newAuthRouter serves /quizzes to teachers and /admin to admins
+func newAuthRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	auth := &fakeAuthService{sessions: map[string]*models.User{
+		"student": {ID: 1, Login: "student", RoleFlags: models.RoleUser},
+		"teacher": {ID: 2, Login: "teacher", RoleFlags: models.RoleUser | models.RoleTeacher},
+		"admin":   {ID: 3, Login: "admin", RoleFlags: models.RoleUser | models.RoleAdmin},
+		// Blocked users get no session from the service, the guards refuse them anyway
+		"blocked": {ID: 4, Login: "blocked", RoleFlags: models.RoleAdmin | models.RoleBlocked},
+	}}
+
+	router := gin.New()
+	router.SetHTMLTemplate(template.Must(template.New("error.html").Parse(`{{.Status}} {{.ErrorMessage}}`)))
+	router.Use(Errors(slog.New(slog.NewTextHandler(io.Discard, nil))))
+	router.Use(Auth(auth))
+
+	serve := func(c *gin.Context) {
+		c.String(http.StatusOK, c.GetString("username"))
+	}
+	router.GET("/quizzes", RequireTeacher(), serve)
+	router.POST("/quizzes", RequireTeacher(), serve)
+	router.GET("/admin", RequireAdmin(), serve)
+
+	return router
+}
+
+func TestAuth(t *testing.T) {
+	tests := []struct {
+		name         string
+		method       string
+		path         string
+		session      string
+		accept       string
+		wantStatus   int
+		wantLocation string
+	}{
+		{name: "teacher", method: http.MethodGet, path: "/quizzes", session: "teacher", wantStatus: http.StatusOK},
+		{name: "admin on teacher pages", method: http.MethodGet, path: "/quizzes", session: "admin", wantStatus: http.StatusOK},
+		{name: "student", method: http.MethodGet, path: "/quizzes", session: "student", wantStatus: http.StatusForbidden},
+		{name: "teacher on admin pages", method: http.MethodGet, path: "/admin", session: "teacher", wantStatus: http.StatusForbidden},
+		{name: "blocked admin", method: http.MethodGet, path: "/admin", session: "blocked", wantStatus: http.StatusForbidden},
+		{
+			name:         "browser without a session",
+			method:       http.MethodGet,
+			path:         "/quizzes?page=2",
+			accept:       "text/html",
+			wantStatus:   http.StatusSeeOther,
+			wantLocation: "/login?next=%2Fquizzes%3Fpage%3D2",
+		},
+		{
+			name:         "browser with an expired session",
+			method:       http.MethodGet,
+			path:         "/admin",
+			session:      "expired",
+			accept:       "text/html",
+			wantStatus:   http.StatusSeeOther,
+			wantLocation: "/login?next=%2Fadmin",
+		},
+		{name: "AJAX without a session", method: http.MethodGet, path: "/quizzes", accept: "application/json", wantStatus: http.StatusUnauthorized},
+		// Forms aren't replayed after the login, they fail
+		{name: "form without a session", method: http.MethodPost, path: "/quizzes", accept: "text/html", wantStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := newAuthRouter()
+
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			req.Header.Set("Accept", tt.accept)
+			if tt.session != "" {
+				req.AddCookie(&http.Cookie{Name: SessionCookie, Value: tt.session})
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("got status %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if got := rec.Header().Get("Location"); got != tt.wantLocation {
+				t.Errorf("got location %q, want %q", got, tt.wantLocation)
+			}
+			if tt.wantStatus == http.StatusOK && rec.Body.String() != tt.session {
+				t.Errorf("served as %q, want %q", rec.Body.String(), tt.session)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"bsu-quiz/quiz/internal/domain/errors"
+	"bsu-quiz/quiz/internal/infra/service"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Bearer signs API requests in with the access token of the Authorization
+// header: userID, username and roles are set like Auth does and the
+// claims are kept in tokenClaims. Requests without a valid token fail
+// with 401. It must run before OpenAPI, so anonymous requests learn
+// nothing about the API
+func Bearer(tokenService service.TokenProvider) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token, ok := BearerToken(c)
+		if !ok {
+			c.Header("WWW-Authenticate", "Bearer")
+			c.Error(errors.Unauthorized("access token is required"))
+			c.Abort()
+			return
+		}
+
+		claims, err := tokenService.Verify(c, token)
+		if errors.Is(err, errors.ErrUnauthorized) {
+			c.Header("WWW-Authenticate", `Bearer error="invalid_token"`)
+			c.Error(err)
+			c.Abort()
+			return
+		}
+		if err != nil {
+			c.Error(err)
+			c.Abort()
+			return
+		}
+
+		c.Set("userID", claims.UserID)
+		c.Set("username", claims.Login)
+		c.Set("roles", claims.RoleFlags)
+		c.Set("tokenClaims", claims)
+
+		c.Next()
+	}
+}
+
+// BearerToken returns the token of the Authorization header
+func BearerToken(c *gin.Context) (string, bool) {
+	scheme, token, ok := strings.Cut(c.GetHeader("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") || token == "" {
+		return "", false
+	}
+
+	return token, true
+}
@@ -0,0 +1,96 @@
+package middleware
+
+import (
+	"bsu-quiz/quiz/internal/domain/errors"
+	"bsu-quiz/quiz/internal/interfaces/http/dto"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Errors renders the last error handlers attached with c.Error. API routes
+// and AJAX requests get an RFC 7807 problem+json body, pages the error page.
+// The status code follows the error kind, details of internal errors are
+// logged and hidden from the client
+func Errors(log *slog.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+
+		err := c.Errors.Last().Err
+		status := errorStatus(err)
+
+		detail := err.Error()
+		if status == http.StatusInternalServerError {
+			log.Error("request failed",
+				slog.String("method", c.Request.Method),
+				slog.String("path", c.Request.URL.Path),
+				slog.String("error", err.Error()),
+			)
+			detail = "Internal server error"
+		}
+
+		if wantsHTML(c) {
+			impersonation, _ := c.Get("impersonation")
+			username, _ := c.Get("username")
+			roles, _ := c.Get("roles")
+			c.HTML(status, "error.html", gin.H{
+				"Title":        http.StatusText(status),
+				"Status":       status,
+				"ErrorMessage": detail,
+				"Fields":       errors.Fields(err),
+				// Keeps the navigation of the signed in user
+				"Username": username,
+				"Roles":    roles,
+				// Keeps the banner with the stop button on refused changes
+				"Impersonation": impersonation,
+			})
+			return
+		}
+
+		c.Header("Content-Type", "application/problem+json")
+		c.JSON(status, dto.Problem{
+			Type:     "about:blank",
+			Title:    http.StatusText(status),
+			Status:   status,
+			Detail:   detail,
+			Instance: c.Request.URL.Path,
+			Errors:   errors.Fields(err),
+		})
+	}
+}
+
+// errorStatus maps an error kind to an HTTP status code
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, errors.ErrValidation):
+		return http.StatusBadRequest
+	case errors.Is(err, errors.ErrUnauthorized):
+		return http.StatusUnauthorized
+	case errors.Is(err, errors.ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, errors.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, errors.ErrConflict):
+		return http.StatusConflict
+	case errors.Is(err, errors.ErrTooManyRequests):
+		return http.StatusTooManyRequests
+	}
+
+	return http.StatusInternalServerError
+}
+
+// wantsHTML reports whether the error should be rendered as a page:
+// the request isn't an API call and the client accepts HTML
+func wantsHTML(c *gin.Context) bool {
+	if strings.HasPrefix(c.Request.URL.Path, "/v1/") {
+		return false
+	}
+
+	return strings.Contains(c.GetHeader("Accept"), "text/html")
+}
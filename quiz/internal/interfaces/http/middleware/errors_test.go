@@ -0,0 +1,150 @@
+package middleware
+
+import (
+	"bsu-quiz/quiz/internal/domain/errors"
+	"bsu-quiz/quiz/internal/interfaces/http/dto"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// newErrorsRouter serves GET path failing with err
+func newErrorsRouter(path string, err error) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.SetHTMLTemplate(template.Must(template.New("error.html").Parse(`{{.Status}} {{.ErrorMessage}}`)))
+	router.Use(Errors(slog.New(slog.NewTextHandler(io.Discard, nil))))
+	router.GET(path, func(c *gin.Context) {
+		c.Error(err)
+	})
+
+	return router
+}
+
+func TestErrorsProblem(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantDetail string
+	}{
+		{name: "validation", err: errors.Invalid("title", "title is required"), wantStatus: http.StatusBadRequest, wantDetail: "title is required"},
+		{name: "unauthorized", err: errors.Unauthorized("sign in"), wantStatus: http.StatusUnauthorized, wantDetail: "sign in"},
+		{name: "forbidden", err: errors.Forbidden("not yours"), wantStatus: http.StatusForbidden, wantDetail: "not yours"},
+		{name: "not found", err: errors.NotFound("quiz"), wantStatus: http.StatusNotFound, wantDetail: "quiz not found"},
+		{name: "conflict", err: errors.Conflict("login is taken"), wantStatus: http.StatusConflict, wantDetail: "login is taken"},
+		{name: "too many requests", err: errors.TooManyRequests("slow down"), wantStatus: http.StatusTooManyRequests, wantDetail: "slow down"},
+		{
+			name:       "wrapped",
+			err:        fmt.Errorf("failed to get quiz: %w", errors.NotFound("quiz")),
+			wantStatus: http.StatusNotFound,
+			wantDetail: "failed to get quiz: quiz not found",
+		},
+		// Details of internal errors stay in the log
+		{name: "internal", err: errors.New("connection refused"), wantStatus: http.StatusInternalServerError, wantDetail: "Internal server error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := newErrorsRouter("/v1/quizzes", tt.err)
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/quizzes", nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("got status %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if got := rec.Header().Get("Content-Type"); !strings.HasPrefix(got, "application/problem+json") {
+				t.Errorf("got content type %q, want problem+json", got)
+			}
+
+			var problem dto.Problem
+			if err := json.Unmarshal(rec.Body.Bytes(), &problem); err != nil {
+				t.Fatalf("failed to decode the problem: %v", err)
+			}
+			if problem.Status != tt.wantStatus || problem.Detail != tt.wantDetail || problem.Instance != "/v1/quizzes" {
+				t.Errorf("got problem %+v, want status %d and detail %q", problem, tt.wantStatus, tt.wantDetail)
+			}
+		})
+	}
+}
+
+func TestErrorsProblemFields(t *testing.T) {
+	err := errors.Invalid("title", "title is required").WithField("status", "invalid status")
+	router := newErrorsRouter("/v1/quizzes", err)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/quizzes", nil))
+
+	var problem dto.Problem
+	if err := json.Unmarshal(rec.Body.Bytes(), &problem); err != nil {
+		t.Fatalf("failed to decode the problem: %v", err)
+	}
+	if len(problem.Errors) != 2 || problem.Errors["title"] != "title is required" || problem.Errors["status"] != "invalid status" {
+		t.Errorf("got fields %v, want title and status", problem.Errors)
+	}
+}
+
+func TestErrorsPage(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     string
+		accept   string
+		wantHTML bool
+	}{
+		{name: "browser", path: "/admin/quizzes", accept: "text/html,application/xhtml+xml", wantHTML: true},
+		{name: "AJAX", path: "/admin/quizzes", accept: "application/json"},
+		// API clients get problems whatever they accept
+		{name: "API in a browser", path: "/v1/quizzes", accept: "text/html"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := newErrorsRouter(tt.path, errors.NotFound("quiz"))
+
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			req.Header.Set("Accept", tt.accept)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != http.StatusNotFound {
+				t.Errorf("got status %d, want 404", rec.Code)
+			}
+			gotHTML := strings.HasPrefix(rec.Header().Get("Content-Type"), "text/html")
+			if gotHTML != tt.wantHTML {
+				t.Errorf("got content type %q, want HTML %v", rec.Header().Get("Content-Type"), tt.wantHTML)
+			}
+			if tt.wantHTML && rec.Body.String() != "404 quiz not found" {
+				t.Errorf("got page %q", rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestErrorsWrittenResponse(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(Errors(slog.New(slog.NewTextHandler(io.Discard, nil))))
+	router.GET("/stream", func(c *gin.Context) {
+		c.String(http.StatusOK, "partial")
+		c.Error(errors.New("connection reset"))
+	})
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/stream", nil))
+
+	// The response is already sent, the error can't replace it
+	if rec.Code != http.StatusOK || rec.Body.String() != "partial" {
+		t.Errorf("got %d %q, want the handler's response", rec.Code, rec.Body.String())
+	}
+}
@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"bsu-quiz/quiz/internal/domain/errors"
+	"bsu-quiz/quiz/internal/infra/service"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ImpersonationStopPath is the only change allowed while impersonating
+const ImpersonationStopPath = "/impersonation/stop"
+
+// Impersonation serves the request as the user the signed in admin is viewing
+// the panel as: userID, username and roles are replaced, the admin is kept in
+// impersonatorID and the impersonation in impersonation for the banner.
+// Changes are refused until the admin stops. It must run after Auth and
+// before Actor
+func Impersonation(impersonationService service.ImpersonationProvider, log *slog.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		adminID, ok := c.Get("userID")
+		id, isID := adminID.(int64)
+		if !ok || !isID {
+			c.Next()
+			return
+		}
+
+		impersonation, err := impersonationService.Active(c, id)
+		if err != nil {
+			// Without Redis the admin simply acts as themselves
+			log.Warn("failed to check impersonation", slog.String("error", err.Error()))
+		}
+
+		if impersonation == nil {
+			c.Next()
+			return
+		}
+
+		c.Set("impersonation", impersonation)
+		c.Set("impersonatorID", impersonation.AdminID)
+		c.Set("userID", impersonation.UserID)
+		c.Set("username", impersonation.UserLogin)
+		c.Set("roles", impersonation.UserRoles)
+
+		if !isSafeMethod(c.Request.Method) && c.Request.URL.Path != ImpersonationStopPath {
+			c.Error(errors.Forbidden("changes aren't allowed while viewing as another user, stop viewing first"))
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+func isSafeMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"bsu-quiz/quiz/internal/domain/errors"
+	"bsu-quiz/quiz/internal/domain/models"
+	"bsu-quiz/quiz/internal/infra/service"
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+const impersonatingAdmin int64 = 1
+
+type fakeImpersonationService struct {
+	service.ImpersonationProvider
+	active *models.Impersonation
+	err    error
+}
+
+func (s *fakeImpersonationService) Active(_ context.Context, adminID int64) (*models.Impersonation, error) {
+	if s.active == nil || s.active.AdminID != adminID {
+		return nil, s.err
+	}
+	return s.active, s.err
+}
+
+// newImpersonationRouter signs every request in as the admin and
+// answers with the user the request is served as
+func newImpersonationRouter(impersonations service.ImpersonationProvider) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	router := gin.New()
+	router.Use(Errors(log))
+	router.Use(func(c *gin.Context) {
+		c.Set("userID", impersonatingAdmin)
+		c.Set("username", "admin")
+	})
+	router.Use(Impersonation(impersonations, log))
+
+	serve := func(c *gin.Context) {
+		c.String(http.StatusOK, c.GetString("username"))
+	}
+	router.GET("/quizzes", serve)
+	router.POST("/quizzes", serve)
+	router.POST(ImpersonationStopPath, serve)
+
+	return router
+}
+
+func TestImpersonation(t *testing.T) {
+	active := &models.Impersonation{AdminID: impersonatingAdmin, UserID: 2, UserLogin: "teacher", UserRoles: models.RoleUser | models.RoleTeacher}
+
+	tests := []struct {
+		name       string
+		service    *fakeImpersonationService
+		method     string
+		path       string
+		wantStatus int
+		wantUser   string
+	}{
+		{name: "not impersonating", service: &fakeImpersonationService{}, method: http.MethodGet, path: "/quizzes", wantStatus: http.StatusOK, wantUser: "admin"},
+		{name: "view", service: &fakeImpersonationService{active: active}, method: http.MethodGet, path: "/quizzes", wantStatus: http.StatusOK, wantUser: "teacher"},
+		{name: "change", service: &fakeImpersonationService{active: active}, method: http.MethodPost, path: "/quizzes", wantStatus: http.StatusForbidden},
+		{name: "stop", service: &fakeImpersonationService{active: active}, method: http.MethodPost, path: ImpersonationStopPath, wantStatus: http.StatusOK, wantUser: "teacher"},
+		// Without Redis the admin acts as themselves
+		{name: "check failed", service: &fakeImpersonationService{err: errors.New("connection refused")}, method: http.MethodPost, path: "/quizzes", wantStatus: http.StatusOK, wantUser: "admin"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := newImpersonationRouter(tt.service)
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("got status %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if tt.wantUser != "" && rec.Body.String() != tt.wantUser {
+				t.Errorf("served as %q, want %q", rec.Body.String(), tt.wantUser)
+			}
+		})
+	}
+}
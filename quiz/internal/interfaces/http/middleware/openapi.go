@@ -0,0 +1,156 @@
+package middleware
+
+import (
+	"bsu-quiz/quiz/internal/domain/errors"
+	"bsu-quiz/quiz/internal/infra/openapi"
+	"bsu-quiz/quiz/internal/interfaces/http/dto"
+	"bytes"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// OpenAPI validates API requests against the spec, mismatches are rejected
+// with 400 and the invalid fields. With validateResponses the responses are
+// buffered and checked too: a response that drifted from the spec is logged
+// and replaced with 500, so it's caught in development and tests
+func OpenAPI(doc *openapi.Document, validateResponses bool, log *slog.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		route, params := doc.FindRoute(c.Request.Method, c.Request.URL.Path)
+		if route == nil {
+			// CheckRoutes makes sure it doesn't happen for registered routes
+			c.Next()
+			return
+		}
+
+		if err := route.Operation.ValidateRequest(c.Request, params); err != nil {
+			c.Error(err)
+			c.Abort()
+			return
+		}
+
+		if !validateResponses {
+			c.Next()
+			return
+		}
+
+		writer := &bufferedWriter{ResponseWriter: c.Writer, status: http.StatusOK}
+		c.Writer = writer
+		c.Next()
+		c.Writer = writer.ResponseWriter
+
+		// Errors are rendered later by the Errors middleware
+		if len(c.Errors) > 0 && !writer.written {
+			return
+		}
+
+		err := route.Operation.ValidateResponse(writer.status, writer.Header(), writer.body.Bytes())
+		if err != nil {
+			log.Error("response doesn't match the API spec",
+				slog.String("operation", route.Operation.OperationID),
+				slog.Int("status", writer.status),
+				slog.String("error", err.Error()),
+				slog.Any("fields", errors.Fields(err)),
+			)
+
+			c.Header("Content-Type", "application/problem+json")
+			c.JSON(http.StatusInternalServerError, dto.Problem{
+				Type:     "about:blank",
+				Title:    http.StatusText(http.StatusInternalServerError),
+				Status:   http.StatusInternalServerError,
+				Detail:   err.Error(),
+				Instance: c.Request.URL.Path,
+				Errors:   errors.Fields(err),
+			})
+			return
+		}
+
+		writer.flush()
+	}
+}
+
+// CheckRoutes returns an error listing the API routes under the base path
+// of the spec that have no operation in it
+func CheckRoutes(doc *openapi.Document, routes gin.RoutesInfo) error {
+	var missing []error
+	for _, route := range routes {
+		path, ok := strings.CutPrefix(route.Path, doc.BasePath()+"/")
+		if !ok {
+			continue
+		}
+
+		if doc.Operation(route.Method, "/"+specPath(path)) == nil {
+			missing = append(missing, fmt.Errorf("%s %s has no operation in the API spec", route.Method, route.Path))
+		}
+	}
+
+	return errors.Join(missing...)
+}
+
+// specPath converts Gin parameters, :id and *path, to the {id} form of the spec
+func specPath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+			segments[i] = "{" + segment[1:] + "}"
+		}
+	}
+
+	return strings.Join(segments, "/")
+}
+
+// bufferedWriter holds the response until it's validated
+type bufferedWriter struct {
+	gin.ResponseWriter
+	status  int
+	written bool
+	body    bytes.Buffer
+}
+
+func (w *bufferedWriter) WriteHeader(code int) {
+	if code > 0 && !w.written {
+		w.status = code
+	}
+}
+
+func (w *bufferedWriter) WriteHeaderNow() {
+	w.written = true
+}
+
+func (w *bufferedWriter) Write(data []byte) (int, error) {
+	w.written = true
+	return w.body.Write(data)
+}
+
+func (w *bufferedWriter) WriteString(s string) (int, error) {
+	w.written = true
+	return w.body.WriteString(s)
+}
+
+func (w *bufferedWriter) Status() int {
+	return w.status
+}
+
+func (w *bufferedWriter) Size() int {
+	if !w.written {
+		return -1
+	}
+
+	return w.body.Len()
+}
+
+func (w *bufferedWriter) Written() bool {
+	return w.written
+}
+
+// flush sends the buffered response to the client
+func (w *bufferedWriter) flush() {
+	w.ResponseWriter.WriteHeader(w.status)
+	w.ResponseWriter.WriteHeaderNow()
+	if w.body.Len() > 0 {
+		w.ResponseWriter.Write(w.body.Bytes())
+	}
+}
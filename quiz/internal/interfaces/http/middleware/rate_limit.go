@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"bsu-quiz/quiz/internal/domain/errors"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RateLimit lets every client address make limit requests per window,
+// the rest fail with 429 until the next window starts
+func RateLimit(limit int, window time.Duration) gin.HandlerFunc {
+	limiter := newRateLimiter(limit, window, time.Now)
+
+	return func(c *gin.Context) {
+		if wait, ok := limiter.allow(c.ClientIP()); !ok {
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+			c.Error(errors.TooManyRequests("too many requests, try again later"))
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// rateLimiter counts requests of clients in fixed windows. The counts are
+// dropped when a window ends, so it keeps only the clients of one window
+type rateLimiter struct {
+	mu     sync.Mutex
+	limit  int
+	window time.Duration
+	now    func() time.Time
+	start  time.Time
+	counts map[string]int
+}
+
+func newRateLimiter(limit int, window time.Duration, now func() time.Time) *rateLimiter {
+	return &rateLimiter{
+		limit:  limit,
+		window: window,
+		now:    now,
+		start:  now(),
+		counts: make(map[string]int),
+	}
+}
+
+// allow counts the request of the client, it returns false and the time
+// until the next window when the client is over the limit
+func (l *rateLimiter) allow(client string) (time.Duration, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	if elapsed := now.Sub(l.start); elapsed >= l.window {
+		l.start = now.Add(-elapsed % l.window)
+		clear(l.counts)
+	}
+
+	if l.counts[client] >= l.limit {
+		return l.start.Add(l.window).Sub(now), false
+	}
+	l.counts[client]++
+
+	return 0, true
+}
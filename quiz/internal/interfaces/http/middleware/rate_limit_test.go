@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRateLimiter(t *testing.T) {
+	now := time.Date(2025, 6, 22, 10, 0, 0, 0, time.UTC)
+	limiter := newRateLimiter(2, time.Minute, func() time.Time { return now })
+
+	for i := range 2 {
+		if _, ok := limiter.allow("10.0.0.1"); !ok {
+			t.Fatalf("request %d is over the limit", i+1)
+		}
+	}
+
+	now = now.Add(20 * time.Second)
+	wait, ok := limiter.allow("10.0.0.1")
+	if ok {
+		t.Fatalf("the third request is allowed")
+	}
+	if wait != 40*time.Second {
+		t.Errorf("got wait %v, want 40s until the next window", wait)
+	}
+
+	// Other clients have their own limit
+	if _, ok := limiter.allow("10.0.0.2"); !ok {
+		t.Errorf("another client is limited")
+	}
+
+	// Windows keep their boundaries when a window passes without requests
+	now = now.Add(2*time.Minute + 30*time.Second)
+	if _, ok := limiter.allow("10.0.0.1"); !ok {
+		t.Fatalf("the client is limited in the next window")
+	}
+	if len(limiter.counts) != 1 {
+		t.Errorf("got %d counted clients, want the counts of the last window dropped", len(limiter.counts))
+	}
+	if want := time.Date(2025, 6, 22, 10, 2, 0, 0, time.UTC); !limiter.start.Equal(want) {
+		t.Errorf("got window start %v, want %v", limiter.start, want)
+	}
+}
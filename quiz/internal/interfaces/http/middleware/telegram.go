@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"bsu-quiz/quiz/internal/domain/errors"
+	"bsu-quiz/quiz/internal/infra/service"
+	"bsu-quiz/quiz/internal/infra/telegram"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// InitDataHeader carries the init data of the Web App when the
+// Authorization header is taken
+const InitDataHeader = "X-Telegram-Init-Data"
+
+// TelegramWebApp signs players of the Telegram Web App in with the init data
+// Telegram passed to the app, sent as "Authorization: tma <init data>" or in
+// InitDataHeader. The data must be signed with the bot token and at most
+// maxAge old. userID, username and roles are set like Auth does and the
+// Telegram account is kept in telegramUser
+func TelegramWebApp(authService service.AuthProvider, botToken string, maxAge time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		initData, ok := initData(c)
+		if !ok {
+			c.Header("WWW-Authenticate", "tma")
+			c.Error(errors.Unauthorized("open the quiz from the Telegram bot"))
+			c.Abort()
+			return
+		}
+
+		tgUser, err := telegram.ValidateInitData(initData, botToken, maxAge, time.Now())
+		if err != nil {
+			c.Header("WWW-Authenticate", "tma")
+			c.Error(err)
+			c.Abort()
+			return
+		}
+
+		user, err := authService.AuthenticateTelegram(c, tgUser)
+		if err != nil {
+			c.Error(err)
+			c.Abort()
+			return
+		}
+
+		c.Set("userID", user.ID)
+		c.Set("username", user.Login)
+		c.Set("roles", user.RoleFlags)
+		c.Set("telegramUser", tgUser)
+
+		c.Next()
+	}
+}
+
+// initData returns the init data of the request
+func initData(c *gin.Context) (string, bool) {
+	scheme, data, ok := strings.Cut(c.GetHeader("Authorization"), " ")
+	if ok && strings.EqualFold(scheme, "tma") && data != "" {
+		return data, true
+	}
+
+	data = c.GetHeader(InitDataHeader)
+	return data, data != ""
+}
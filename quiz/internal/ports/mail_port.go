@@ -0,0 +1,9 @@
+package ports
+
+import "context"
+
+type Mailer interface {
+	// Send emails the BSU mailbox of the login, the body is the email
+	// template with the name executed with data
+	Send(ctx context.Context, login, subject, template string, data map[string]any) error
+}
@@ -0,0 +1,13 @@
+package ports
+
+import (
+	"bsu-quiz/quiz/internal/domain/models"
+	"context"
+)
+
+type AuditRepositorier interface {
+	Create(ctx context.Context, entry *models.AuditEntry) (int64, error)
+	// List returns entries matching the filter, newest first
+	List(ctx context.Context, filter models.AuditFilter) ([]*models.AuditEntry, error)
+	Count(ctx context.Context, filter models.AuditFilter) (int, error)
+}
@@ -0,0 +1,17 @@
+package ports
+
+import (
+	"bsu-quiz/quiz/internal/domain/models"
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type CollaboratorRepositorier interface {
+	// Upsert adds a collaborator or changes the role of an existing one
+	Upsert(ctx context.Context, collaborator *models.Collaborator) error
+	// GetRole returns models.QuizRoleNone if the user isn't a collaborator
+	GetRole(ctx context.Context, quizID uuid.UUID, userID int64) (models.QuizRole, error)
+	List(ctx context.Context, quizID uuid.UUID) ([]models.Collaborator, error)
+	Remove(ctx context.Context, quizID uuid.UUID, userID int64) error
+}
@@ -3,6 +3,7 @@ package ports
 import (
 	"bsu-quiz/quiz/internal/domain/models"
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -10,20 +11,50 @@ import (
 type QuizRepositorier interface {
 	Create(ctx context.Context, quiz *models.Quiz) (uuid.UUID, error)
 	GetByID(ctx context.Context, id uuid.UUID) (*models.Quiz, error)
+	// GetHeader returns the quiz without its questions, nil if it doesn't exist.
+	// Authorization needs only the owner, so it doesn't load the questions
+	GetHeader(ctx context.Context, id uuid.UUID) (*models.Quiz, error)
 	GetQuestions(ctx context.Context, quizID uuid.UUID) ([]models.Question, error)
 	GetOptions(ctx context.Context, questionID uuid.UUID) ([]models.Option, error)
 	Update(ctx context.Context, quiz *models.Quiz) error
-	Delete(ctx context.Context, id uuid.UUID) error
-	List(ctx context.Context, userID int64, offset, limit int) ([]*models.Quiz, error)
+	// Delete moves the quiz to the trash, it's ignored by other queries until restored
+	Delete(ctx context.Context, id uuid.UUID, deletedBy int64) error
+	// List returns quizzes owned by the user or shared with them in the order
+	// of opts.Sort, see models.QuizSortFields
+	List(ctx context.Context, userID int64, opts models.ListOptions) ([]*models.Quiz, error)
+	// CountByUser returns the number of quizzes List returns for the user
+	CountByUser(ctx context.Context, userID int64) (int, error)
 	ListPublic(ctx context.Context, offset, limit int) ([]*models.Quiz, error)
+	// ListAll returns quizzes of all users in the order of opts.Sort
+	ListAll(ctx context.Context, opts models.ListOptions) ([]*models.Quiz, error)
+	Count(ctx context.Context) (int, error)
+
+	// Lifecycle methods
+	UpdateStatus(ctx context.Context, id uuid.UUID, status models.QuizStatus, publishAt *time.Time) error
+	// PublishScheduled publishes scheduled quizzes with questions whose publish time has come
+	PublishScheduled(ctx context.Context, now time.Time) (int64, error)
+
+	// Trash methods
+	GetDeleted(ctx context.Context, id uuid.UUID) (*models.Quiz, error)
+	ListDeleted(ctx context.Context, userID int64, offset, limit int) ([]*models.Quiz, error)
+	ListAllDeleted(ctx context.Context, offset, limit int) ([]*models.Quiz, error)
+	Restore(ctx context.Context, id uuid.UUID) error
+	// HardDelete removes the quiz with all its questions, options and sessions
+	HardDelete(ctx context.Context, id uuid.UUID) error
+	// PurgeDeleted removes quizzes deleted before the given time
+	PurgeDeleted(ctx context.Context, before time.Time) (int64, error)
 	
 	// Question methods
 	CreateQuestion(ctx context.Context, question *models.Question) (uuid.UUID, error)
 	UpdateQuestion(ctx context.Context, question *models.Question) error
 	DeleteQuestion(ctx context.Context, id uuid.UUID) error
+	// GetQuestionQuizID returns uuid.Nil if the question doesn't exist
+	GetQuestionQuizID(ctx context.Context, questionID uuid.UUID) (uuid.UUID, error)
 	
 	// Option methods
 	CreateOption(ctx context.Context, option *models.Option) (uuid.UUID, error)
 	UpdateOption(ctx context.Context, option *models.Option) error
 	DeleteOption(ctx context.Context, id uuid.UUID) error
+	// GetOptionQuizID returns uuid.Nil if the option doesn't exist
+	GetOptionQuizID(ctx context.Context, optionID uuid.UUID) (uuid.UUID, error)
 }
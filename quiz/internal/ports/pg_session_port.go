@@ -3,6 +3,7 @@ package ports
 import (
 	"bsu-quiz/quiz/internal/domain/models"
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -11,18 +12,41 @@ import (
 type SessionRepositorier interface {
 	Create(ctx context.Context, session *models.GameSession) (uuid.UUID, error)
 	GetByID(ctx context.Context, id uuid.UUID) (*models.GameSession, error)
+	// GetForUpdate returns the session without participants and locks its row
+	// until the transaction of ctx ends, it must be called within one
+	GetForUpdate(ctx context.Context, id uuid.UUID) (*models.GameSession, error)
 	GetByJoinCode(ctx context.Context, joinCode string) (*models.GameSession, error)
 	Update(ctx context.Context, session *models.GameSession) error
 	UpdateStatus(ctx context.Context, id uuid.UUID, statusFlags int) error
 	UpdateCurrentQuestion(ctx context.Context, id uuid.UUID, index int) error
-	Delete(ctx context.Context, id uuid.UUID) error
+	// Delete moves the session to the trash, it's ignored by other queries until restored
+	Delete(ctx context.Context, id uuid.UUID, deletedBy int64) error
 	ListByHost(ctx context.Context, hostID int64, offset, limit int) ([]*models.GameSession, error)
+	// List returns sessions of all hosts matching the filter with their quiz title
+	// and participant count in the order of filter.Sort, ties are broken by id
+	List(ctx context.Context, filter models.SessionFilter) ([]*models.SessionListItem, error)
+	// Count returns the number of sessions matching the filter, the cursor is ignored
+	Count(ctx context.Context, filter models.SessionFilter) (int, error)
+
+	// Trash methods
+	GetDeleted(ctx context.Context, id uuid.UUID) (*models.GameSession, error)
+	ListDeleted(ctx context.Context, hostID int64, offset, limit int) ([]*models.GameSession, error)
+	ListAllDeleted(ctx context.Context, offset, limit int) ([]*models.GameSession, error)
+	Restore(ctx context.Context, id uuid.UUID) error
+	// PurgeDeleted removes sessions deleted before the given time
+	PurgeDeleted(ctx context.Context, before time.Time) (int64, error)
 	
 	// Participant methods
 	AddParticipant(ctx context.Context, participant *models.Participant) (uuid.UUID, error)
 	GetParticipants(ctx context.Context, sessionID uuid.UUID) ([]models.Participant, error)
+	// GetParticipantByUser returns the participant of the user in the session,
+	// kicked ones included, without answers
+	GetParticipantByUser(ctx context.Context, sessionID uuid.UUID, userID int64) (*models.Participant, error)
 	UpdateParticipantScore(ctx context.Context, id uuid.UUID, score int) error
 	RemoveParticipant(ctx context.Context, id uuid.UUID) error
+	// KickParticipant removes the participant from the session keeping their
+	// answers, GetParticipants leaves kicked participants out
+	KickParticipant(ctx context.Context, id uuid.UUID) error
 	
 	// Answer methods
 	RecordAnswer(ctx context.Context, answer *models.Answer) (uuid.UUID, error)
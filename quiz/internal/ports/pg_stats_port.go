@@ -0,0 +1,15 @@
+package ports
+
+import (
+	"bsu-quiz/quiz/internal/domain/models"
+	"context"
+	"time"
+)
+
+type StatsRepositorier interface {
+	CountUsersByRole(ctx context.Context) (*models.UsersByRole, error)
+	// SessionsPerDay counts sessions started on every day since the given date
+	SessionsPerDay(ctx context.Context, since time.Time) ([]models.DailyCount, error)
+	// AnswersPerDay counts answers given on every day since the given date
+	AnswersPerDay(ctx context.Context, since time.Time) ([]models.DailyCount, error)
+}
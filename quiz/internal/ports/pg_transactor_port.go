@@ -0,0 +1,9 @@
+package ports
+
+import "context"
+
+// Transactor runs a function in a database transaction. Repositories called
+// with the ctx passed to fn take part in the transaction
+type Transactor interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
@@ -8,10 +8,26 @@ import (
 
 
 type UserRepositorier interface {
+	Create(ctx context.Context, user *models.User) (int64, error)
 	GetByID(ctx context.Context, id int64) (*models.User, error)
+	// GetByLogin also returns the password hash, other getters leave it empty
 	GetByLogin(ctx context.Context, login string) (*models.User, error)
+	// GetByTelegramID returns the user the Telegram account is linked to
+	GetByTelegramID(ctx context.Context, telegramUserID int64) (*models.User, error)
+	GetByIDs(ctx context.Context, ids []int64) ([]*models.User, error)
+	GetByLogins(ctx context.Context, logins []string) ([]*models.User, error)
 	Update(ctx context.Context, user *models.User) error
+	UpdatePassword(ctx context.Context, userID int64, hash string) error
 	UpdateRole(ctx context.Context, userID int64, roleFlags int) error
+	// UpdateRoles sets and clears role flags of the users, it returns the number of updated users
+	UpdateRoles(ctx context.Context, ids []int64, set, clear int) (int64, error)
 	Delete(ctx context.Context, id int64) error
-	List(ctx context.Context, offset, limit int) ([]*models.User, error)
+	// CountOwned returns the number of quizzes and sessions of the user,
+	// the ones in the trash included
+	CountOwned(ctx context.Context, id int64) (quizzes, sessions int, err error)
+	// List returns a page of users in the order of opts.Sort, see models.UserSortFields
+	List(ctx context.Context, opts models.ListOptions) ([]*models.User, error)
+	// ListRecent returns the most recently registered users
+	ListRecent(ctx context.Context, limit int) ([]*models.User, error)
+	Count(ctx context.Context) (int, error)
 }
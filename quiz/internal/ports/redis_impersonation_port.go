@@ -0,0 +1,15 @@
+package ports
+
+import (
+	"bsu-quiz/quiz/internal/domain/models"
+	"context"
+)
+
+type ImpersonationRepositorier interface {
+	// Save stores the impersonation until it expires, replacing the
+	// previous one of the admin
+	Save(ctx context.Context, impersonation *models.Impersonation) error
+	// Get returns the active impersonation of the admin, nil if there's none
+	Get(ctx context.Context, adminID int64) (*models.Impersonation, error)
+	Delete(ctx context.Context, adminID int64) error
+}
@@ -0,0 +1,14 @@
+package ports
+
+import (
+	"context"
+	"time"
+)
+
+type PasswordResetRepositorier interface {
+	// Save stores the reset token of the user for ttl
+	Save(ctx context.Context, token string, userID int64, ttl time.Duration) error
+	// Take returns the user of the token and deletes it, so a token works
+	// once. It returns false if the token is unknown or expired
+	Take(ctx context.Context, token string) (int64, bool, error)
+}
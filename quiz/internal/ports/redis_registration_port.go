@@ -0,0 +1,14 @@
+package ports
+
+import (
+	"context"
+	"time"
+)
+
+type RegistrationRepositorier interface {
+	// Save stores the registration token of the login for ttl
+	Save(ctx context.Context, token, login string, ttl time.Duration) error
+	// Take returns the login of the token and deletes it, so a token works
+	// once. It returns false if the token is unknown or expired
+	Take(ctx context.Context, token string) (string, bool, error)
+}
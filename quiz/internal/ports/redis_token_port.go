@@ -0,0 +1,20 @@
+package ports
+
+import (
+	"bsu-quiz/quiz/internal/domain/models"
+	"context"
+	"time"
+)
+
+type TokenRepositorier interface {
+	// SaveRefreshToken stores the refresh token until it expires
+	SaveRefreshToken(ctx context.Context, token *models.RefreshToken) error
+	// TakeRefreshToken returns the refresh token and deletes it, so a token
+	// is used once. It returns nil if the token is unknown or expired
+	TakeRefreshToken(ctx context.Context, token string) (*models.RefreshToken, error)
+	// DeleteRefreshTokens deletes every refresh token of the user
+	DeleteRefreshTokens(ctx context.Context, userID int64) error
+	// RevokeAccessToken puts the access token on the revocation list until it expires
+	RevokeAccessToken(ctx context.Context, id string, expiresAt time.Time) error
+	IsAccessTokenRevoked(ctx context.Context, id string) (bool, error)
+}
@@ -0,0 +1,16 @@
+package ports
+
+import (
+	"bsu-quiz/quiz/internal/domain/models"
+	"context"
+)
+
+type WebSessionRepositorier interface {
+	// Save stores the session until it expires
+	Save(ctx context.Context, session *models.WebSession) error
+	// Get returns the session with the token, nil if there's none
+	Get(ctx context.Context, token string) (*models.WebSession, error)
+	Delete(ctx context.Context, token string) error
+	// DeleteByUser ends every session of the user
+	DeleteByUser(ctx context.Context, userID int64) error
+}
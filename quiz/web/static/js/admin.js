@@ -0,0 +1,252 @@
+// Admin panel helpers.
+//
+// Buttons with data-action="METHOD" and data-url send a request without a body,
+// forms with data-submit="METHOD" send their fields as form data or, with
+// data-json, as a JSON object. data-confirm asks first, data-reload reloads
+// the page on success. Errors are read from application/problem+json.
+(function () {
+  "use strict";
+
+  function flash(message) {
+    var el = document.createElement("div");
+    el.className = "flash";
+    el.textContent = message;
+    document.body.appendChild(el);
+    setTimeout(function () { el.remove(); }, 5000);
+  }
+
+  async function send(method, url, body, headers) {
+    var response = await fetch(url, {
+      method: method,
+      body: body,
+      headers: Object.assign({ Accept: "application/json" }, headers || {}),
+      credentials: "same-origin",
+    });
+
+    if (!response.ok) {
+      var message = response.statusText;
+      try {
+        var problem = await response.json();
+        message = problem.detail || problem.title || message;
+        if (problem.errors) {
+          message += ": " + Object.keys(problem.errors).map(function (k) {
+            return k + " " + problem.errors[k];
+          }).join(", ");
+        }
+      } catch (e) {}
+      throw new Error(message);
+    }
+
+    var type = response.headers.get("Content-Type") || "";
+    return type.indexOf("json") >= 0 ? response.json() : null;
+  }
+
+  function formJSON(form) {
+    var data = {};
+    Array.prototype.forEach.call(form.elements, function (el) {
+      if (!el.name) return;
+      if (el.type === "checkbox") data[el.name] = el.checked;
+      else if (el.type === "number") data[el.name] = el.value === "" ? 0 : Number(el.value);
+      else data[el.name] = el.value;
+    });
+    return data;
+  }
+
+  function formBody(form) {
+    var data = new URLSearchParams();
+    Array.prototype.forEach.call(form.elements, function (el) {
+      if (!el.name || el.disabled) return;
+      if (el.type === "checkbox") {
+        if (el.checked) data.append(el.name, "on");
+      } else if (el.type === "datetime-local") {
+        if (el.value) data.append(el.name, new Date(el.value).toISOString());
+      } else {
+        data.append(el.name, el.value);
+      }
+    });
+    return data;
+  }
+
+  function done(el) {
+    if (el.hasAttribute("data-reload")) window.location.reload();
+  }
+
+  document.addEventListener("click", function (event) {
+    var button = event.target.closest("[data-action]");
+    if (!button) return;
+    event.preventDefault();
+
+    var question = button.getAttribute("data-confirm");
+    if (question && !window.confirm(question)) return;
+
+    button.disabled = true;
+    send(button.getAttribute("data-action"), button.getAttribute("data-url"))
+      .then(function () { done(button); })
+      .catch(function (err) { flash(err.message); })
+      .finally(function () { button.disabled = false; });
+  });
+
+  document.addEventListener("submit", function (event) {
+    var form = event.target.closest("form[data-submit]");
+    if (!form) return;
+    event.preventDefault();
+
+    var body, headers;
+    if (form.hasAttribute("data-json")) {
+      body = JSON.stringify(formJSON(form));
+      headers = { "Content-Type": "application/json" };
+    } else {
+      body = formBody(form);
+    }
+
+    send(form.getAttribute("data-submit"), form.getAttribute("data-url"), body, headers)
+      .then(function () { done(form); })
+      .catch(function (err) { flash(err.message); });
+  });
+
+  // Select-all checkboxes
+  document.addEventListener("change", function (event) {
+    var name = event.target.getAttribute("data-select-all");
+    if (!name) return;
+    document.querySelectorAll('input[name="' + name + '"]').forEach(function (el) {
+      el.checked = event.target.checked;
+    });
+  });
+
+  // Scale dashboard bars to the largest value of their chart
+  document.querySelectorAll(".chart").forEach(function (chart) {
+    var bars = chart.querySelectorAll(".bar");
+    var max = 0;
+    bars.forEach(function (bar) { max = Math.max(max, Number(bar.dataset.count)); });
+    bars.forEach(function (bar) {
+      bar.style.height = max ? (Number(bar.dataset.count) / max * 100) + "%" : "0";
+    });
+  });
+
+  // Collaborator list of the quiz editor
+  var collaborators = document.getElementById("collaborators");
+  if (collaborators) {
+    send("GET", collaborators.dataset.url).then(function (data) {
+      (data.collaborators || []).forEach(function (c) {
+        var li = document.createElement("li");
+        li.textContent = c.login + " (" + c.role + ") ";
+        var remove = document.createElement("button");
+        remove.className = "danger";
+        remove.textContent = "Remove";
+        remove.setAttribute("data-action", "DELETE");
+        remove.setAttribute("data-url", collaborators.dataset.url + "/" + c.user_id);
+        remove.setAttribute("data-reload", "");
+        li.appendChild(remove);
+        collaborators.appendChild(li);
+      });
+    }).catch(function (err) { flash(err.message); });
+  }
+
+  // Live Markdown and LaTeX preview of question text
+  document.querySelectorAll("textarea[data-preview]").forEach(function (area) {
+    var preview = area.parentNode.querySelector(".preview");
+    if (!preview) return;
+    var timer;
+    area.addEventListener("input", function () {
+      clearTimeout(timer);
+      timer = setTimeout(function () {
+        send("POST", "/quizzes/preview", JSON.stringify({ text: area.value }), { "Content-Type": "application/json" })
+          .then(function (rendered) {
+            preview.innerHTML = rendered.html;
+            preview.hidden = false;
+          })
+          .catch(function (err) { flash(err.message); });
+      }, 300);
+    });
+  });
+
+  // Live session monitor, snapshots come from the server as they change,
+  // the timer counts down locally in between
+  var monitor = document.getElementById("monitor");
+  if (monitor) {
+    var field = function (name) { return monitor.querySelector('[data-field="' + name + '"]'); };
+    var status = monitor.dataset.status;
+    var deadline = monitor.dataset.timeLeft ? Date.now() + Number(monitor.dataset.timeLeft) : null;
+
+    var tick = function () {
+      var timer = field("timer");
+      if (deadline === null) {
+        timer.textContent = "—";
+        return;
+      }
+      var left = status === "paused" ? Number(monitor.dataset.timeLeft) : Math.max(0, deadline - Date.now());
+      timer.textContent = Math.ceil(left / 1000) + "s";
+    };
+
+    var cell = function (row, text) {
+      var td = document.createElement("td");
+      td.textContent = text;
+      row.appendChild(td);
+      return td;
+    };
+
+    var update = function (snapshot) {
+      status = snapshot.status;
+      monitor.dataset.status = status;
+      monitor.dataset.timeLeft = snapshot.timeLeftMs === undefined ? "" : snapshot.timeLeftMs;
+      deadline = snapshot.timeLeftMs === undefined ? null : Date.now() + snapshot.timeLeftMs;
+
+      var badge = field("status");
+      badge.textContent = status;
+      badge.className = "badge badge-" + status;
+      field("question").textContent = snapshot.question
+        ? (snapshot.questionIndex + 1) + " of " + snapshot.questionCount
+        : "—";
+      field("text").textContent = snapshot.question ? snapshot.question.text : "";
+      field("answered").textContent = snapshot.answered + "/" + snapshot.participants.length;
+
+      monitor.querySelectorAll("[data-show]").forEach(function (button) {
+        button.hidden = button.dataset.show.split(" ").indexOf(status) < 0;
+      });
+
+      var rows = field("participants");
+      rows.textContent = "";
+      snapshot.participants.forEach(function (p) {
+        var row = document.createElement("tr");
+        cell(row, p.login);
+        cell(row, p.score);
+        cell(row, new Date(p.joinedAt).toLocaleString());
+        cell(row, p.answered ? "yes" : "—");
+        var kick = document.createElement("button");
+        kick.className = "danger";
+        kick.textContent = "Kick";
+        kick.setAttribute("data-action", "POST");
+        kick.setAttribute("data-url", "/admin/sessions/" + snapshot.id + "/participants/" + p.id + "/kick");
+        kick.setAttribute("data-confirm", "Remove " + p.login + " from the session?");
+        cell(row, "").appendChild(kick);
+        rows.appendChild(row);
+      });
+      if (!snapshot.participants.length) {
+        var empty = document.createElement("tr");
+        cell(empty, "No participants yet.").colSpan = 5;
+        rows.appendChild(empty);
+      }
+
+      tick();
+    };
+
+    tick();
+    var clock = setInterval(tick, 250);
+
+    if (status !== "finished") {
+      var events = new EventSource(monitor.dataset.events);
+      var stop = function () {
+        events.close();
+        clearInterval(clock);
+      };
+      events.addEventListener("snapshot", function (event) {
+        update(JSON.parse(event.data));
+        if (status === "finished") stop();
+      });
+      events.addEventListener("unavailable", function () {
+        stop();
+        flash("The session is no longer available");
+      });
+    }
+  }
+})();
@@ -0,0 +1,178 @@
+// Package web embeds the admin panel templates, static assets and emails,
+// so the admin binary doesn't depend on the working directory
+package web
+
+import (
+	"bsu-quiz/quiz/internal/domain/models"
+	"embed"
+	"html/template"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+//go:embed template
+var templates embed.FS
+
+//go:embed static
+var static embed.FS
+
+//go:embed email
+var emails embed.FS
+
+// Templates parses every template, pages are named by their path under
+// template/, e.g. "admin/users.html", so handlers can render them by that name
+func Templates() (*template.Template, error) {
+	root := template.New("").Funcs(funcs)
+
+	err := fs.WalkDir(templates, "template", func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !strings.HasSuffix(path, ".html") {
+			return err
+		}
+
+		content, err := templates.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		_, err = root.New(strings.TrimPrefix(path, "template/")).Parse(string(content))
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return root, nil
+}
+
+// EmailTemplates parses the email templates, they're named by their file
+// name without the extension, e.g. "password_reset"
+func EmailTemplates() (*template.Template, error) {
+	root := template.New("")
+
+	entries, err := emails.ReadDir("email")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		content, err := emails.ReadFile("email/" + entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err := root.New(strings.TrimSuffix(entry.Name(), ".html")).Parse(string(content)); err != nil {
+			return nil, err
+		}
+	}
+
+	return root, nil
+}
+
+// Static returns the assets served under /static
+func Static() http.FileSystem {
+	sub, err := fs.Sub(static, "static")
+	if err != nil {
+		// The directory is embedded, so it always exists
+		panic(err)
+	}
+
+	return http.FS(sub)
+}
+
+var funcs = template.FuncMap{
+	"formatTime":    formatTime,
+	"formatDate":    formatDate,
+	"hasRole":       hasRole,
+	"sessionStatus": sessionStatus,
+	"sortURL":       sortURL,
+	"sortMark":      sortMark,
+	"pageURL":       pageURL,
+	"add":           func(a, b int) int { return a + b },
+	"sub":           func(a, b int) int { return a - b },
+}
+
+const timeLayout = "2006-01-02 15:04"
+
+// formatTime formats a time or a time pointer, nil and zero times are a dash
+func formatTime(value any) string {
+	switch t := value.(type) {
+	case time.Time:
+		if !t.IsZero() {
+			return t.Format(timeLayout)
+		}
+	case *time.Time:
+		if t != nil && !t.IsZero() {
+			return t.Format(timeLayout)
+		}
+	}
+
+	return "—"
+}
+
+func formatDate(t time.Time) string {
+	return t.Format("02.01")
+}
+
+// hasRole checks the role flags for the role, flags of a visitor who isn't
+// signed in are missing and have no roles
+func hasRole(flags any, role int) bool {
+	value, _ := flags.(int)
+	return value&role == role
+}
+
+// sessionStatus names the status flags of a game session
+func sessionStatus(flags int) string {
+	return models.StatusName(flags)
+}
+
+// sortURL links to the first page of the listing sorted by the field,
+// following the link of the current sort field reverses the order
+func sortURL(query url.Values, current models.Sort, field string) string {
+	query = clone(query)
+	query.Del("cursor")
+	query.Set("sort", field)
+
+	order := models.SortAsc
+	if current.Field == field && !current.Desc() {
+		order = models.SortDesc
+	}
+	query.Set("order", string(order))
+
+	return "?" + query.Encode()
+}
+
+// sortMark returns the arrow shown next to the current sort field
+func sortMark(current models.Sort, field string) string {
+	switch {
+	case current.Field != field:
+		return ""
+	case current.Desc():
+		return " ↓"
+	}
+
+	return " ↑"
+}
+
+// pageURL links to the page at the cursor keeping the filter and sort,
+// an empty cursor is the first page
+func pageURL(query url.Values, cursor string) string {
+	query = clone(query)
+	query.Del("cursor")
+	if cursor != "" {
+		query.Set("cursor", cursor)
+	}
+
+	return "?" + query.Encode()
+}
+
+func clone(query url.Values) url.Values {
+	result := make(url.Values, len(query))
+	for key, values := range query {
+		result[key] = append([]string(nil), values...)
+	}
+
+	return result
+}
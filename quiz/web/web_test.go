@@ -0,0 +1,180 @@
+package web
+
+import (
+	"bsu-quiz/quiz/internal/domain/models"
+	"io/fs"
+	"net/url"
+	"strings"
+	"testing"
+	"text/template/parse"
+	"time"
+)
+
+func TestTemplates(t *testing.T) {
+	root, err := Templates()
+	if err != nil {
+		t.Fatalf("failed to parse templates: %v", err)
+	}
+
+	// Pages are named by their path under template/
+	err = fs.WalkDir(templates, "template", func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !strings.HasSuffix(path, ".html") {
+			return err
+		}
+
+		if name := strings.TrimPrefix(path, "template/"); root.Lookup(name) == nil {
+			t.Errorf("page %s isn't defined", name)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// html/template reports calls of undefined templates only when the
+	// page is rendered
+	calls := 0
+	for _, tmpl := range root.Templates() {
+		if tmpl.Tree == nil {
+			continue
+		}
+		for _, name := range calledTemplates(tmpl.Tree.Root) {
+			calls++
+			if root.Lookup(name) == nil {
+				t.Errorf("%s calls undefined template %q", tmpl.Name(), name)
+			}
+		}
+	}
+	if calls == 0 {
+		t.Errorf("no template calls found, the check checked nothing")
+	}
+}
+
+// calledTemplates returns the names of templates the node calls
+func calledTemplates(node parse.Node) []string {
+	var names []string
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return nil
+		}
+		for _, child := range n.Nodes {
+			names = append(names, calledTemplates(child)...)
+		}
+	case *parse.TemplateNode:
+		names = append(names, n.Name)
+	case *parse.IfNode:
+		names = append(names, calledTemplates(n.List)...)
+		names = append(names, calledTemplates(n.ElseList)...)
+	case *parse.RangeNode:
+		names = append(names, calledTemplates(n.List)...)
+		names = append(names, calledTemplates(n.ElseList)...)
+	case *parse.WithNode:
+		names = append(names, calledTemplates(n.List)...)
+		names = append(names, calledTemplates(n.ElseList)...)
+	}
+
+	return names
+}
+
+func TestEmailTemplates(t *testing.T) {
+	root, err := EmailTemplates()
+	if err != nil {
+		t.Fatalf("failed to parse email templates: %v", err)
+	}
+
+	for _, name := range []string{"password_reset", "registration"} {
+		t.Run(name, func(t *testing.T) {
+			var body strings.Builder
+			err := root.ExecuteTemplate(&body, name, map[string]any{
+				"Login":     "ivanov",
+				"URL":       "https://quiz.bsu.by/confirm?token=abc",
+				"ExpiresIn": "30 minutes",
+			})
+			if err != nil {
+				t.Fatalf("failed to render: %v", err)
+			}
+
+			for _, want := range []string{"ivanov", "https://quiz.bsu.by/confirm?token=abc", "30 minutes"} {
+				if !strings.Contains(body.String(), want) {
+					t.Errorf("the email doesn't contain %q", want)
+				}
+			}
+		})
+	}
+}
+
+func TestStatic(t *testing.T) {
+	for _, name := range []string{"/css/admin.css", "/js/admin.js"} {
+		file, err := Static().Open(name)
+		if err != nil {
+			t.Errorf("failed to open %s: %v", name, err)
+			continue
+		}
+		file.Close()
+	}
+}
+
+func TestFormatTime(t *testing.T) {
+	at := time.Date(2025, 6, 22, 10, 5, 0, 0, time.UTC)
+	var missing *time.Time
+
+	tests := []struct {
+		name  string
+		value any
+		want  string
+	}{
+		{name: "time", value: at, want: "2025-06-22 10:05"},
+		{name: "pointer", value: &at, want: "2025-06-22 10:05"},
+		{name: "zero", value: time.Time{}, want: "—"},
+		{name: "nil pointer", value: missing, want: "—"},
+		{name: "not a time", value: "yesterday", want: "—"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatTime(tt.value); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSortURL(t *testing.T) {
+	query := url.Values{"q": {"math"}, "cursor": {"abc"}, "sort": {"login"}, "order": {"asc"}}
+
+	tests := []struct {
+		name    string
+		current models.Sort
+		field   string
+		want    string
+	}{
+		{name: "another field", current: models.Sort{Field: "login", Direction: models.SortAsc}, field: "id", want: "?order=asc&q=math&sort=id"},
+		{name: "reverse ascending", current: models.Sort{Field: "login", Direction: models.SortAsc}, field: "login", want: "?order=desc&q=math&sort=login"},
+		{name: "reverse descending", current: models.Sort{Field: "login", Direction: models.SortDesc}, field: "login", want: "?order=asc&q=math&sort=login"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sortURL(query, tt.current, tt.field); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	// The query of the page isn't changed
+	if query.Get("cursor") != "abc" || query.Get("sort") != "login" {
+		t.Errorf("the page query was changed: %v", query)
+	}
+}
+
+func TestPageURL(t *testing.T) {
+	query := url.Values{"q": {"math"}, "cursor": {"abc"}}
+
+	if got := pageURL(query, "def"); got != "?cursor=def&q=math" {
+		t.Errorf("got %q for the next page", got)
+	}
+	if got := pageURL(query, ""); got != "?q=math" {
+		t.Errorf("got %q for the first page", got)
+	}
+}
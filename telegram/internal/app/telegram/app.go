@@ -67,14 +67,16 @@ func NewAppTelegram() (
 	
 	startHandler := handlers.NewStartCommand(telegramBot)
 	helpHandler := handlers.NewHelpCommand(telegramBot)
-	registerHandler := handlers.NewRegisterCommand(telegramBot)
+	registerHandler := handlers.NewRegisterCommand(telegramBot, userRepo, log)
+	unlinkHandler := handlers.NewUnlinkCommand(telegramBot, userRepo, log)
 	// NOTE: add to env variables
-	quizCommnad := handlers.NewQuizCommand(telegramBot, "https://api.telegram.org/bot")
+	quizCommnad := handlers.NewQuizCommand(telegramBot, userRepo, log, "https://api.telegram.org/bot")
 
 	// Register commands (handlers will be implemented later)
 	commandRouter.Register("start", startHandler.Execute)
 	commandRouter.Register("help", helpHandler.Execute)
 	commandRouter.Register("register", registerHandler.Execute)
+	commandRouter.Register("unlink", unlinkHandler.Execute)
 	commandRouter.Register("quiz", quizCommnad.Execute)
 
 	app = &AppTelegram{
@@ -22,7 +22,8 @@ type StorageConfig struct {
 }
 
 type BotConfig struct {
-	Token   string `yaml:"token" env-required:"true"`
+	// Token is a secret, it's only read from the environment
+	Token   string `env:"BOT_TOKEN" env-required:"true"`
 	Timeout int    `yaml:"timeout" env-default:"60"`
 	Debug   bool   `yaml:"debug" env-default:"false"`
 }
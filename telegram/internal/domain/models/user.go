@@ -4,4 +4,9 @@ type User struct {
 	ID    int64  `json:"id"`
 	Login string `json:"login"`
 	Role  int64  `json:"role"`
+	// Telegram account the login is linked to, the login was verified
+	// through the BSU mailbox from it
+	TelegramUserID   int64  `json:"telegram_user_id"`
+	TelegramChatID   int64  `json:"telegram_chat_id"`
+	TelegramUsername string `json:"telegram_username"`
 }
@@ -62,46 +62,57 @@ func (p *pgUserRepository) Update(ctx context.Context, userID int64, updateFn fu
 	return nil
 }
 
-// UpdateOrCreate updates an existing user or creates a new one if it doesn't exist
-func (p *pgUserRepository) UpdateOrCreate(ctx context.Context, user *models.User) error {
+// GetByTelegramID returns the user linked to the Telegram account
+func (p *pgUserRepository) GetByTelegramID(ctx context.Context, telegramUserID int64) (*models.User, error) {
+	user := &models.User{}
+	err := p.conn.QueryRow(ctx,
+		`SELECT id, login, role_flags, telegram_user_id, COALESCE(telegram_chat_id, 0), COALESCE(telegram_username, '')
+		FROM users WHERE telegram_user_id = $1`,
+		telegramUserID,
+	).Scan(&user.ID, &user.Login, &user.Role, &user.TelegramUserID, &user.TelegramChatID, &user.TelegramUsername)
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to query user: %w", err)
+	}
+
+	return user, nil
+}
+
+// LinkTelegram links the Telegram account to the login, creating the user if it doesn't exist
+func (p *pgUserRepository) LinkTelegram(ctx context.Context, user *models.User) error {
 	tx, err := p.conn.Begin(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
 	defer tx.Rollback(ctx) // Will be no-op if transaction is committed
 
-	// Check if user exists by login
-	var existingID int64
-	err = tx.QueryRow(ctx, 
-		"SELECT id FROM users WHERE login = $1", 
-		user.Login,
-	).Scan(&existingID)
+	// Re-linking moves the account, the unique index allows one login per account
+	_, err = tx.Exec(ctx,
+		`UPDATE users SET telegram_user_id = NULL, telegram_chat_id = NULL, telegram_username = NULL
+		WHERE telegram_user_id = $1 AND login <> $2`,
+		user.TelegramUserID, user.Login,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to unlink previous login: %w", err)
+	}
 
+	// The login was verified through its mailbox, so it takes over the
+	// account even if another one was linked to it
+	err = tx.QueryRow(ctx,
+		`INSERT INTO users (login, role_flags, telegram_user_id, telegram_chat_id, telegram_username)
+		VALUES ($1, $2, $3, $4, NULLIF($5, ''))
+		ON CONFLICT (login) DO UPDATE SET
+			telegram_user_id = EXCLUDED.telegram_user_id,
+			telegram_chat_id = EXCLUDED.telegram_chat_id,
+			telegram_username = EXCLUDED.telegram_username
+		RETURNING id, role_flags`,
+		user.Login, user.Role, user.TelegramUserID, user.TelegramChatID, user.TelegramUsername,
+	).Scan(&user.ID, &user.Role)
 	if err != nil {
-		if err == pgx.ErrNoRows {
-			// User doesn't exist, create a new one
-			err = tx.QueryRow(ctx,
-				"INSERT INTO users (login, role_flags) VALUES ($1, $2) RETURNING id",
-				user.Login, user.Role,
-			).Scan(&user.ID)
-			
-			if err != nil {
-				return fmt.Errorf("failed to create user: %w", err)
-			}
-		} else {
-			return fmt.Errorf("failed to check if user exists: %w", err)
-		}
-	} else {
-		// User exists, update it
-		user.ID = existingID
-		_, err = tx.Exec(ctx,
-			"UPDATE users SET role_flags = $1 WHERE id = $2",
-			user.Role, user.ID,
-		)
-		
-		if err != nil {
-			return fmt.Errorf("failed to update existing user: %w", err)
-		}
+		return fmt.Errorf("failed to link user: %w", err)
 	}
 
 	// Commit the transaction
@@ -110,4 +121,18 @@ func (p *pgUserRepository) UpdateOrCreate(ctx context.Context, user *models.User
 	}
 
 	return nil
-}
\ No newline at end of file
+}
+
+// UnlinkTelegram removes the Telegram account from the login it's linked to
+func (p *pgUserRepository) UnlinkTelegram(ctx context.Context, telegramUserID int64) (bool, error) {
+	commandTag, err := p.conn.Exec(ctx,
+		`UPDATE users SET telegram_user_id = NULL, telegram_chat_id = NULL, telegram_username = NULL
+		WHERE telegram_user_id = $1`,
+		telegramUserID,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to unlink user: %w", err)
+	}
+
+	return commandTag.RowsAffected() > 0, nil
+}
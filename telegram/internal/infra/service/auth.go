@@ -2,14 +2,17 @@ package service
 
 import (
 	"fmt"
+
+	"bsu-quiz/pkg/authz"
 	"bsu-quiz/telegram/internal/domain/models"
 )
 
+// Role flags are shared with the quiz services, see package authz
 const (
-	RoleUser    int = 1 << iota // 1 (0001) - Regular User
-	RoleAdmin                   // 2 (0010) - Administrator
-	RoleTeacher                 // 4 (0100) - Teacher
-	RoleBlocked                 // 8 (1000) - Blocked User
+	RoleUser    int = authz.RoleUser    // 1 (0001) - Regular User
+	RoleAdmin   int = authz.RoleAdmin   // 2 (0010) - Administrator
+	RoleTeacher int = authz.RoleTeacher // 4 (0100) - Teacher
+	RoleBlocked int = authz.RoleBlocked // 8 (1000) - Blocked User
 )
 
 // Role names for display purposes
@@ -29,6 +32,11 @@ func New(user *models.User) *Auth {
 		user: user}
 }
 
+// Can checks if the roles of the user grant the permission, blocked users have none
+func (a *Auth) Can(permission authz.Permission) bool {
+	return authz.Can(int(a.user.Role), permission)
+}
+
 // HasRole checks if user has a specific role
 func (a *Auth) HasRole(role int) bool {
 	return int(a.user.Role)&role == role
@@ -94,18 +102,12 @@ func (a *Auth) IsBlocked() bool {
 
 // CanCreateQuiz checks if the user has permission to create quizzes
 func (a *Auth) CanCreateQuiz() bool {
-	// Blocked users can't create quizzes
-	if a.IsBlocked() {
-		return false
-	}
-
-	// Admins and teachers can create quizzes
-	return a.HasAnyRole(RoleAdmin, RoleTeacher)
+	return a.Can(authz.QuizCreate)
 }
 
 // CanManageUsers checks if the user has permission to manage other users
 func (a *Auth) CanManageUsers() bool {
-	return a.IsAdmin()
+	return a.Can(authz.UserManage)
 }
 
 // String returns a string representation of the user's roles
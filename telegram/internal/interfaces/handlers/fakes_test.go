@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"bsu-quiz/telegram/internal/domain/models"
+	"bsu-quiz/telegram/internal/ports"
+	"context"
+	"io"
+	"net/http"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// fakeTelegram answers the Bot API requests and keeps the sent texts
+type fakeTelegram struct {
+	texts []string
+}
+
+func (t *fakeTelegram) Do(req *http.Request) (*http.Response, error) {
+	if err := req.ParseForm(); err != nil {
+		return nil, err
+	}
+	t.texts = append(t.texts, req.Form.Get("text"))
+
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": {"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(`{"ok":true,"result":{"message_id":1}}`)),
+	}, nil
+}
+
+func newFakeBot() (*models.Bot, *fakeTelegram) {
+	telegram := &fakeTelegram{}
+	api := &tgbotapi.BotAPI{Token: "token", Client: telegram}
+	api.SetAPIEndpoint(tgbotapi.APIEndpoint)
+
+	return &models.Bot{Telegram: api}, telegram
+}
+
+// fakeStorage keeps the states and data of the conversations
+type fakeStorage struct {
+	state models.State
+	data  map[string]any
+}
+
+func newFakeStorage() *fakeStorage {
+	return &fakeStorage{data: make(map[string]any)}
+}
+
+func (s *fakeStorage) Get(_ context.Context, _, _ int64) (models.State, error) {
+	return s.state, nil
+}
+
+func (s *fakeStorage) Set(_ context.Context, _, _ int64, state models.State) error {
+	s.state = state
+	return nil
+}
+
+func (s *fakeStorage) Delete(_ context.Context, _, _ int64) error {
+	s.state = models.DefaultState
+	return nil
+}
+
+func (s *fakeStorage) GetData(_ context.Context, _, _ int64, key string) (interface{}, error) {
+	return s.data[key], nil
+}
+
+func (s *fakeStorage) SetData(_ context.Context, _, _ int64, key string, value interface{}) error {
+	s.data[key] = value
+	return nil
+}
+
+func (s *fakeStorage) ClearData(_ context.Context, _, _ int64) error {
+	clear(s.data)
+	return nil
+}
+
+// fakeUserRepo keeps the users by their Telegram account
+type fakeUserRepo struct {
+	ports.UserRepositorier
+	users map[int64]*models.User
+	err   error
+}
+
+func (r *fakeUserRepo) GetByTelegramID(_ context.Context, telegramUserID int64) (*models.User, error) {
+	user, ok := r.users[telegramUserID]
+	if !ok {
+		return nil, r.err
+	}
+
+	copied := *user
+	return &copied, r.err
+}
+
+func (r *fakeUserRepo) LinkTelegram(_ context.Context, user *models.User) error {
+	if r.err != nil {
+		return r.err
+	}
+
+	copied := *user
+	r.users[user.TelegramUserID] = &copied
+	return nil
+}
+
+func (r *fakeUserRepo) UnlinkTelegram(_ context.Context, telegramUserID int64) (bool, error) {
+	if r.err != nil {
+		return false, r.err
+	}
+
+	_, ok := r.users[telegramUserID]
+	delete(r.users, telegramUserID)
+	return ok, nil
+}
@@ -31,6 +31,7 @@ func NewFSMHandler(
 	bot *models.Bot,
 ) *fSMHandler {
 	return &fSMHandler{
+		log:          log,
 		emailService: emailService,
 		userRepo:     userRepo,
 		otpGenerator: otpGenerator,
@@ -110,6 +111,22 @@ func (h *fSMHandler) HandleOTP(ctx context.Context, message *tgbotapi.Message, f
 		return
 	}
 
+	// The login is verified, it's linked to the Telegram account that verified it
+	user := &models.User{
+		Login:            login,
+		Role:             int64(service.RoleUser),
+		TelegramUserID:   message.From.ID,
+		TelegramChatID:   message.Chat.ID,
+		TelegramUsername: message.From.UserName,
+	}
+
+	if err := h.userRepo.LinkTelegram(ctx, user); err != nil {
+		h.log.ErrorContext(ctx, "Failed to link user", "error", err)
+		msg := tgbotapi.NewMessage(message.Chat.ID, "Не удалось завершить регистрацию. Попробуйте ещё раз позже.")
+		_, _ = h.bot.Telegram.Send(msg)
+		return
+	}
+
 	msg := tgbotapi.NewMessage(message.Chat.ID, "Регастрация завершена! Добро пожаловать, "+login+"!"+"\nВы можете перейти к сервису прохождения викторин кликнув на /quiz")
 	_, _ = h.bot.Telegram.Send(msg)
 	_ = fsm.Set(models.StateRegistered)
@@ -121,22 +138,32 @@ func (h *fSMHandler) HandleRegistered(ctx context.Context, message *tgbotapi.Mes
 		slog.String("op", op),
 	)
 
-	// note: login was verifided in endpoint above
-	loginInterface, _ := fsm.GetData("login")
-	login, _ := loginInterface.(string)
-	user := &models.User{
-		ID:    h.bot.Telegram.Self.ID,
-		Login: login,
-		Role:  int64(service.RoleUser),
+	// The user is found by the sender, the login may have been unlinked meanwhile
+	user, err := h.userRepo.GetByTelegramID(ctx, message.From.ID)
+	if err != nil {
+		h.log.ErrorContext(ctx, "Failed to get user", "error", err)
+		return
 	}
 
-	err := h.userRepo.UpdateOrCreate(ctx, user)
-	if err != nil {
-		h.log.ErrorContext(ctx, "Failed update user %w", "error", err)
+	if user == nil {
+		msg := tgbotapi.NewMessage(message.Chat.ID, "Ваш Telegram не привязан к логину. Пожалуйста, зарегистрируйтесь, отправив команду /register.")
+		_, _ = h.bot.Telegram.Send(msg)
+		_ = fsm.Finish()
 		return
 	}
 
-	msg := tgbotapi.NewMessage(message.Chat.ID, "Привет "+login+"! Вы уже зарегистрированы.")
+	// Keep the chat and username up to date, the username may change any time
+	if user.TelegramChatID != message.Chat.ID || user.TelegramUsername != message.From.UserName {
+		user.TelegramChatID = message.Chat.ID
+		user.TelegramUsername = message.From.UserName
+
+		if err := h.userRepo.LinkTelegram(ctx, user); err != nil {
+			h.log.ErrorContext(ctx, "Failed update user", "error", err)
+			return
+		}
+	}
+
+	msg := tgbotapi.NewMessage(message.Chat.ID, "Привет "+user.Login+"! Вы уже зарегистрированы.")
 	_, _ = h.bot.Telegram.Send(msg)
 }
 
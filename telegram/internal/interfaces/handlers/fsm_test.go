@@ -0,0 +1,174 @@
+package handlers
+
+import (
+	"bsu-quiz/telegram/internal/domain/models"
+	"bsu-quiz/telegram/internal/infra/service"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const (
+	testTelegramUserID int64 = 100
+	testChatID         int64 = 200
+)
+
+type fsmFixture struct {
+	handler  *fSMHandler
+	telegram *fakeTelegram
+	storage  *fakeStorage
+	users    *fakeUserRepo
+	fsm      *service.FSMContext
+}
+
+func newFSMFixture() *fsmFixture {
+	bot, telegram := newFakeBot()
+	storage := newFakeStorage()
+	users := &fakeUserRepo{users: make(map[int64]*models.User)}
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	return &fsmFixture{
+		handler:  NewFSMHandler(log, nil, users, nil, bot),
+		telegram: telegram,
+		storage:  storage,
+		users:    users,
+		fsm:      service.NewFSMContext(context.Background(), storage, testChatID, testTelegramUserID),
+	}
+}
+
+func message(text, username string) *tgbotapi.Message {
+	return &tgbotapi.Message{
+		Text: text,
+		From: &tgbotapi.User{ID: testTelegramUserID, UserName: username},
+		Chat: &tgbotapi.Chat{ID: testChatID},
+	}
+}
+
+func TestHandleOTPLinksTheSender(t *testing.T) {
+	f := newFSMFixture()
+	f.storage.state = models.StateAwaitingOTP
+	f.storage.data["login"] = "ivanov"
+	f.storage.data["code"] = "123456"
+
+	f.handler.HandleOTP(context.Background(), message("123456", "ivan"), f.fsm)
+
+	user := f.users.users[testTelegramUserID]
+	if user == nil {
+		t.Fatalf("the account wasn't linked")
+	}
+	if user.Login != "ivanov" || user.TelegramChatID != testChatID || user.TelegramUsername != "ivan" {
+		t.Errorf("got user %+v, want ivanov linked to the sender", user)
+	}
+	if f.storage.state != models.StateRegistered {
+		t.Errorf("got state %q, want registered", f.storage.state)
+	}
+}
+
+func TestHandleOTPWrongCode(t *testing.T) {
+	f := newFSMFixture()
+	f.storage.state = models.StateAwaitingOTP
+	f.storage.data["login"] = "ivanov"
+	f.storage.data["code"] = "123456"
+
+	f.handler.HandleOTP(context.Background(), message("654321", "ivan"), f.fsm)
+
+	if len(f.users.users) != 0 {
+		t.Errorf("the account was linked with a wrong code")
+	}
+	if f.storage.state != models.StateAwaitingOTP {
+		t.Errorf("got state %q, want the code asked again", f.storage.state)
+	}
+}
+
+func TestHandleOTPLinkFailed(t *testing.T) {
+	f := newFSMFixture()
+	f.storage.state = models.StateAwaitingOTP
+	f.storage.data["login"] = "ivanov"
+	f.storage.data["code"] = "123456"
+	f.users.err = errors.New("connection refused")
+
+	f.handler.HandleOTP(context.Background(), message("123456", "ivan"), f.fsm)
+
+	// The code still works for another try
+	if f.storage.state != models.StateAwaitingOTP {
+		t.Errorf("got state %q after a failed link, want awaiting the code", f.storage.state)
+	}
+	if len(f.telegram.texts) != 1 || !strings.Contains(f.telegram.texts[0], "Не удалось") {
+		t.Errorf("got messages %q, want the failure reported", f.telegram.texts)
+	}
+}
+
+func TestHandleRegistered(t *testing.T) {
+	f := newFSMFixture()
+	f.storage.state = models.StateRegistered
+	f.users.users[testTelegramUserID] = &models.User{
+		Login:            "ivanov",
+		TelegramUserID:   testTelegramUserID,
+		TelegramChatID:   testChatID,
+		TelegramUsername: "ivan",
+	}
+
+	// The sender changed their username
+	f.handler.HandleRegistered(context.Background(), message("hi", "ivan_ivanov"), f.fsm)
+
+	if got := f.users.users[testTelegramUserID].TelegramUsername; got != "ivan_ivanov" {
+		t.Errorf("got username %q, want the new one", got)
+	}
+	if len(f.telegram.texts) != 1 || !strings.Contains(f.telegram.texts[0], "ivanov") {
+		t.Errorf("got messages %q, want a greeting of ivanov", f.telegram.texts)
+	}
+}
+
+func TestHandleRegisteredUnlinked(t *testing.T) {
+	f := newFSMFixture()
+	f.storage.state = models.StateRegistered
+
+	f.handler.HandleRegistered(context.Background(), message("hi", "ivan"), f.fsm)
+
+	if f.storage.state != models.DefaultState {
+		t.Errorf("got state %q, want the conversation finished", f.storage.state)
+	}
+	if len(f.telegram.texts) != 1 || !strings.Contains(f.telegram.texts[0], "/register") {
+		t.Errorf("got messages %q, want a hint to register", f.telegram.texts)
+	}
+}
+
+func TestUnlinkCommand(t *testing.T) {
+	tests := []struct {
+		name     string
+		linked   bool
+		wantText string
+	}{
+		{name: "linked", linked: true, wantText: "Telegram отвязан"},
+		{name: "not linked", wantText: "не привязан"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFSMFixture()
+			f.storage.state = models.StateRegistered
+			if tt.linked {
+				f.users.users[testTelegramUserID] = &models.User{Login: "ivanov", TelegramUserID: testTelegramUserID}
+			}
+			bot := f.handler.bot
+			command := NewUnlinkCommand(bot, f.users, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+			command.Execute(context.Background(), message("/unlink", "ivan"), f.fsm)
+
+			if len(f.users.users) != 0 {
+				t.Errorf("the account is still linked")
+			}
+			if f.storage.state != models.DefaultState {
+				t.Errorf("got state %q, want the conversation finished", f.storage.state)
+			}
+			if len(f.telegram.texts) != 1 || !strings.Contains(f.telegram.texts[0], tt.wantText) {
+				t.Errorf("got messages %q, want %q", f.telegram.texts, tt.wantText)
+			}
+		})
+	}
+}
@@ -1,29 +1,57 @@
 package handlers
 
 import (
+	"bsu-quiz/pkg/authz"
 	"bsu-quiz/telegram/internal/domain/models"
 	"bsu-quiz/telegram/internal/infra/service"
+	"bsu-quiz/telegram/internal/ports"
 	"context"
+	"log/slog"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 )
 
 type QuizComand struct {
 	bot       *models.Bot
+	userRepo  ports.UserRepositorier
+	log       *slog.Logger
 	webAppUrl string
 }
 
 func NewQuizCommand(
 	bot *models.Bot,
+	userRepo ports.UserRepositorier,
+	log *slog.Logger,
 	webAppUrl string,
 ) *QuizComand {
 	return &QuizComand{
 		bot:       bot,
+		userRepo:  userRepo,
+		log:       log,
 		webAppUrl: webAppUrl,
 	}
 }
 
+// Execute opens the Web App to users allowed to play, others are told why not
 func (c *QuizComand) Execute(ctx context.Context, message *tgbotapi.Message, fsm *service.FSMContext) {
+	user, err := c.userRepo.GetByTelegramID(ctx, message.From.ID)
+	if err != nil {
+		c.log.ErrorContext(ctx, "Failed to get user", "error", err)
+		return
+	}
+
+	if user == nil {
+		msg := tgbotapi.NewMessage(message.Chat.ID, "Пожалуйста, сначала зарегистрируйтесь, отправив команду /register.")
+		_, _ = c.bot.Telegram.Send(msg)
+		return
+	}
+
+	if !service.New(user).Can(authz.SessionJoin) {
+		msg := tgbotapi.NewMessage(message.Chat.ID, "Участие в викторинах для вашего аккаунта недоступно.")
+		_, _ = c.bot.Telegram.Send(msg)
+		return
+	}
+
 	kahootMsgText := "Нажмите на кнопку ниже, чтобы запустить приложение"
 	kbRow := tgbotapi.NewInlineKeyboardRow(
 		// tgbotapi.NewInlineKeyboardButtonWebApp("Kahoot!", tgbotapi.WebAppInfo{URL: h.WebAppUrl}),
@@ -3,25 +3,46 @@ package handlers
 import (
 	"bsu-quiz/telegram/internal/domain/models"
 	"bsu-quiz/telegram/internal/infra/service"
+	"bsu-quiz/telegram/internal/ports"
 	"context"
+	"log/slog"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 )
 
 type RegisterCommand struct {
-	bot *models.Bot
+	bot      *models.Bot
+	userRepo ports.UserRepositorier
+	log      *slog.Logger
 }
 
 func NewRegisterCommand(
 	bot *models.Bot,
+	userRepo ports.UserRepositorier,
+	log *slog.Logger,
 ) *RegisterCommand {
 	return &RegisterCommand{
-		bot: bot,
+		bot:      bot,
+		userRepo: userRepo,
+		log:      log,
 	}
 }
 
+// Execute asks for the login to verify, registering again with a linked
+// account re-links it to the new login once that one is verified
 func (c *RegisterCommand) Execute(ctx context.Context, message *tgbotapi.Message, fsm *service.FSMContext) {
-	msg := tgbotapi.NewMessage(message.Chat.ID, "🔑 Пожалуйста, введите ваш login для регистрации.")
+	text := "🔑 Пожалуйста, введите ваш login для регистрации."
+
+	user, err := c.userRepo.GetByTelegramID(ctx, message.From.ID)
+	if err != nil {
+		c.log.ErrorContext(ctx, "Failed to get user", "error", err)
+	}
+
+	if user != nil {
+		text = "🔑 Ваш Telegram привязан к логину " + user.Login + ". Введите другой login, чтобы привязать аккаунт к нему."
+	}
+
+	msg := tgbotapi.NewMessage(message.Chat.ID, text)
 
 	_, _ = c.bot.Telegram.Send(msg)
 	_ = fsm.Set(models.StateAwaitingLogin)
@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"bsu-quiz/telegram/internal/domain/models"
+	"bsu-quiz/telegram/internal/infra/service"
+	"bsu-quiz/telegram/internal/ports"
+	"context"
+	"log/slog"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// UnlinkCommand removes the Telegram account of the sender from their login,
+// the login stays and can be linked again with /register
+type UnlinkCommand struct {
+	bot      *models.Bot
+	userRepo ports.UserRepositorier
+	log      *slog.Logger
+}
+
+func NewUnlinkCommand(
+	bot *models.Bot,
+	userRepo ports.UserRepositorier,
+	log *slog.Logger,
+) *UnlinkCommand {
+	return &UnlinkCommand{
+		bot:      bot,
+		userRepo: userRepo,
+		log:      log,
+	}
+}
+
+func (c *UnlinkCommand) Execute(ctx context.Context, message *tgbotapi.Message, fsm *service.FSMContext) {
+	unlinked, err := c.userRepo.UnlinkTelegram(ctx, message.From.ID)
+	if err != nil {
+		c.log.ErrorContext(ctx, "Failed to unlink user", "error", err)
+		msg := tgbotapi.NewMessage(message.Chat.ID, "Не удалось отвязать аккаунт. Попробуйте ещё раз позже.")
+		_, _ = c.bot.Telegram.Send(msg)
+		return
+	}
+
+	text := "Ваш Telegram не привязан к логину."
+	if unlinked {
+		text = "Telegram отвязан от вашего логина. Чтобы привязать его снова, отправьте /register."
+	}
+
+	msg := tgbotapi.NewMessage(message.Chat.ID, text)
+	_, _ = c.bot.Telegram.Send(msg)
+	_ = fsm.Finish()
+	_ = fsm.ClearData()
+}
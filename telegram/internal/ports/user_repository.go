@@ -12,5 +12,15 @@ type UserRepositorier interface {
 		updateFn func(innerCtx context.Context, user *models.User) error,
 	) error
 	
-	UpdateOrCreate(ctx context.Context, user *models.User) error
+	// GetByTelegramID returns the user linked to the Telegram account, nil if none
+	GetByTelegramID(ctx context.Context, telegramUserID int64) (*models.User, error)
+	
+	// LinkTelegram links the Telegram account of the user to its login, the
+	// user is created if the login is new. The account is unlinked from the
+	// login it was linked to before, roles of existing users are kept
+	LinkTelegram(ctx context.Context, user *models.User) error
+	
+	// UnlinkTelegram removes the Telegram account from its login,
+	// it reports whether the account was linked
+	UnlinkTelegram(ctx context.Context, telegramUserID int64) (bool, error)
 }